@@ -130,19 +130,13 @@ func buildCLI() *cli.App {
 				if err != nil {
 					cli.Exit(fmt.Sprintf("Unable to instantiate authorizer: %v.", err), 1)
 				}
-				claimMapper, err := authorization.GetClaimMapperFromConfig(cfg)
-				if err != nil {
-					cli.Exit(fmt.Sprintf("Unable to instantiate claim mapper: %v.", err), 1)
-				}
 
+				// the claim mapper of the config is created by the frontend service, with its metrics client
 				s := temporal.NewServer(
 					temporal.ForServices(services),
 					temporal.WithConfig(cfg),
 					temporal.InterruptOn(temporal.InterruptCh()),
 					temporal.WithAuthorizer(authorizer),
-					temporal.WithClaimMapper(func(cfg *config.Config) authorization.ClaimMapper {
-						return claimMapper
-					}),
 				)
 
 				err = s.Start()
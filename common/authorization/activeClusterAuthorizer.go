@@ -0,0 +1,53 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+)
+
+type activeClusterAuthorizer struct {
+	authorizer Authorizer
+}
+
+var _ Authorizer = (*activeClusterAuthorizer)(nil)
+
+// NewActiveClusterAuthorizer creates an authorizer that denies mutating APIs targeting a global namespace
+// that is not active in the current cluster, instead of letting them fail downstream. Read-only APIs and
+// all calls to namespaces active in the current cluster are decided by authorizer. Whether the namespace
+// is active is read from CallTarget.IsNamespacePassive, see WithNamespaceActivityLookup.
+// It must not be used in clusters forwarding calls to the active cluster of a namespace,
+// as the calls would be denied before they are forwarded.
+func NewActiveClusterAuthorizer(authorizer Authorizer) Authorizer {
+	return &activeClusterAuthorizer{authorizer: authorizer}
+}
+
+func (a *activeClusterAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if target.IsNamespacePassive && IsMutatingAPI(target.APIName) {
+		return NewDenyResult(NewDenyReason(ReasonNamespaceNotActive,
+			"Namespace is not active in this cluster, mutating requests must be sent to its active cluster.")), nil
+	}
+	return a.authorizer.Authorize(ctx, claims, target)
+}
@@ -0,0 +1,220 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"reflect"
+
+	"go.temporal.io/api/workflowservice/v1"
+
+	"go.temporal.io/server/api/adminservice/v1"
+)
+
+const (
+	workflowServicePrefix = "/temporal.api.workflowservice.v1.WorkflowService/"
+	adminServicePrefix    = "/temporal.server.api.adminservice.v1.AdminService/"
+)
+
+const (
+	// APIGroupRead contains the APIs that don't change state
+	APIGroupRead APIGroup = "read"
+	// APIGroupWrite contains the APIs that change the state of workflows or task queues, including the polls
+	// that dispatch tasks to workers
+	APIGroupWrite APIGroup = "write"
+	// APIGroupAdmin contains the APIs that manage namespaces
+	APIGroupAdmin APIGroup = "admin"
+	// APIGroupUnknown is the group of the APIs that are not classified, such as the APIs of other services.
+	// No "group:" policy entry matches it, so unclassified APIs are only granted by their name.
+	APIGroupUnknown APIGroup = "unknown"
+)
+
+// APIGroup is a semantic group of APIs that policies can refer to instead of individual API names
+type APIGroup string
+
+// APIGroups maps the full names of all workflow service APIs to their group.
+// New APIs must be added here, the tests verify that every API is classified.
+var APIGroups = map[string]APIGroup{
+	workflowServicePrefix + "RegisterNamespace":                APIGroupAdmin,
+	workflowServicePrefix + "UpdateNamespace":                  APIGroupAdmin,
+	workflowServicePrefix + "DeprecateNamespace":               APIGroupAdmin,
+	workflowServicePrefix + "DescribeNamespace":                APIGroupRead,
+	workflowServicePrefix + "ListNamespaces":                   APIGroupRead,
+	workflowServicePrefix + "GetClusterInfo":                   APIGroupRead,
+	workflowServicePrefix + "GetSearchAttributes":              APIGroupRead,
+	workflowServicePrefix + "StartWorkflowExecution":           APIGroupWrite,
+	workflowServicePrefix + "SignalWorkflowExecution":          APIGroupWrite,
+	workflowServicePrefix + "SignalWithStartWorkflowExecution": APIGroupWrite,
+	workflowServicePrefix + "RequestCancelWorkflowExecution":   APIGroupWrite,
+	workflowServicePrefix + "TerminateWorkflowExecution":       APIGroupWrite,
+	workflowServicePrefix + "ResetWorkflowExecution":           APIGroupWrite,
+	workflowServicePrefix + "DescribeWorkflowExecution":        APIGroupRead,
+	workflowServicePrefix + "GetWorkflowExecutionHistory":      APIGroupRead,
+	workflowServicePrefix + "QueryWorkflow":                    APIGroupRead,
+	workflowServicePrefix + "CountWorkflowExecutions":          APIGroupRead,
+	workflowServicePrefix + "ListWorkflowExecutions":           APIGroupRead,
+	workflowServicePrefix + "ListOpenWorkflowExecutions":       APIGroupRead,
+	workflowServicePrefix + "ListClosedWorkflowExecutions":     APIGroupRead,
+	workflowServicePrefix + "ListArchivedWorkflowExecutions":   APIGroupRead,
+	workflowServicePrefix + "ScanWorkflowExecutions":           APIGroupRead,
+	workflowServicePrefix + "DescribeTaskQueue":                APIGroupRead,
+	workflowServicePrefix + "ListTaskQueuePartitions":          APIGroupRead,
+	workflowServicePrefix + "PollWorkflowTaskQueue":            APIGroupWrite,
+	workflowServicePrefix + "PollActivityTaskQueue":            APIGroupWrite,
+	workflowServicePrefix + "RespondWorkflowTaskCompleted":     APIGroupWrite,
+	workflowServicePrefix + "RespondWorkflowTaskFailed":        APIGroupWrite,
+	workflowServicePrefix + "RecordActivityTaskHeartbeat":      APIGroupWrite,
+	workflowServicePrefix + "RecordActivityTaskHeartbeatById":  APIGroupWrite,
+	workflowServicePrefix + "RespondActivityTaskCompleted":     APIGroupWrite,
+	workflowServicePrefix + "RespondActivityTaskCompletedById": APIGroupWrite,
+	workflowServicePrefix + "RespondActivityTaskFailed":        APIGroupWrite,
+	workflowServicePrefix + "RespondActivityTaskFailedById":    APIGroupWrite,
+	workflowServicePrefix + "RespondActivityTaskCanceled":      APIGroupWrite,
+	workflowServicePrefix + "RespondActivityTaskCanceledById":  APIGroupWrite,
+	workflowServicePrefix + "RespondQueryTaskCompleted":        APIGroupWrite,
+	workflowServicePrefix + "ResetStickyTaskQueue":             APIGroupWrite,
+}
+
+// MutatingAPIs contains full names of the APIs that change the state of a namespace, its workflows or task queues,
+// i.e. the APIs of the write and admin groups. All other APIs are considered read-only.
+var MutatingAPIs = mutatingAPIs()
+
+func mutatingAPIs() map[string]struct{} {
+	apis := make(map[string]struct{})
+	for api, group := range APIGroups {
+		if group != APIGroupRead {
+			apis[api] = struct{}{}
+		}
+	}
+	for api, operatorAPI := range OperatorAPIs {
+		if operatorAPI.Group != APIGroupRead {
+			apis[api] = struct{}{}
+		}
+	}
+	for api, group := range ReplicationAPIs {
+		if group != APIGroupRead {
+			apis[api] = struct{}{}
+		}
+	}
+	return apis
+}
+
+// IsMutatingAPI checks if the API with the given full name changes state
+func IsMutatingAPI(apiName string) bool {
+	_, ok := MutatingAPIs[apiName]
+	return ok
+}
+
+// GetAPIGroup returns the group of the API with the given full name, see APIGroups, OperatorAPIs and
+// ReplicationAPIs. All other APIs are in APIGroupUnknown.
+func GetAPIGroup(apiName string) APIGroup {
+	if group, ok := APIGroups[apiName]; ok {
+		return group
+	}
+	if api, ok := OperatorAPIs[apiName]; ok {
+		return api.Group
+	}
+	if group, ok := ReplicationAPIs[apiName]; ok {
+		return group
+	}
+	return APIGroupUnknown
+}
+
+// ReplicationAPIs contains full names of the admin service APIs that remote clusters call to replicate
+// namespaces and workflows. They should be authorized by cluster identity rather than by user roles,
+// see NewReplicationAuthorizer. They are mapped to their group like OperatorAPIs.
+var ReplicationAPIs = map[string]APIGroup{
+	adminServicePrefix + "DescribeCluster":                  APIGroupRead,
+	adminServicePrefix + "GetReplicationMessages":           APIGroupRead,
+	adminServicePrefix + "GetNamespaceReplicationMessages":  APIGroupRead,
+	adminServicePrefix + "GetDLQReplicationMessages":        APIGroupRead,
+	adminServicePrefix + "GetWorkflowExecutionRawHistoryV2": APIGroupRead,
+	adminServicePrefix + "ReapplyEvents":                    APIGroupAdmin,
+}
+
+// IsReplicationAPI checks if the API with the given full name is called by remote clusters for replication
+func IsReplicationAPI(apiName string) bool {
+	_, ok := ReplicationAPIs[apiName]
+	return ok
+}
+
+const (
+	// OperatorScopeNamespace is the scope of operator APIs managing the workflows of the namespace of the request
+	OperatorScopeNamespace OperatorScope = "namespace"
+	// OperatorScopeCluster is the scope of operator APIs managing the cluster, such as its shards, queues
+	// and search attributes. Their calls target no namespace, so only system roles apply to them.
+	OperatorScopeCluster OperatorScope = "cluster"
+)
+
+type (
+	// OperatorScope is the scope of the resources an operator API manages
+	OperatorScope string
+
+	// OperatorAPI classifies an admin service API operators call to manage the cluster
+	OperatorAPI struct {
+		// Group is the semantic group of the API, APIGroupRead for APIs that don't change state
+		// and APIGroupAdmin for all others
+		Group APIGroup
+		Scope OperatorScope
+	}
+)
+
+// OperatorAPIs classifies the full names of the admin service APIs called by operators, i.e. all APIs
+// of the admin service but the ReplicationAPIs. New APIs must be added here, the tests verify that every API
+// is classified.
+var OperatorAPIs = map[string]OperatorAPI{
+	adminServicePrefix + "AddSearchAttribute":     {Group: APIGroupAdmin, Scope: OperatorScopeCluster},
+	adminServicePrefix + "CloseShard":             {Group: APIGroupAdmin, Scope: OperatorScopeCluster},
+	adminServicePrefix + "DescribeHistoryHost":    {Group: APIGroupRead, Scope: OperatorScopeCluster},
+	adminServicePrefix + "DescribeMutableState":   {Group: APIGroupRead, Scope: OperatorScopeNamespace},
+	adminServicePrefix + "GetDLQMessages":         {Group: APIGroupRead, Scope: OperatorScopeCluster},
+	adminServicePrefix + "MergeDLQMessages":       {Group: APIGroupAdmin, Scope: OperatorScopeCluster},
+	adminServicePrefix + "PurgeDLQMessages":       {Group: APIGroupAdmin, Scope: OperatorScopeCluster},
+	adminServicePrefix + "RefreshWorkflowTasks":   {Group: APIGroupAdmin, Scope: OperatorScopeNamespace},
+	adminServicePrefix + "RemoveTask":             {Group: APIGroupAdmin, Scope: OperatorScopeCluster},
+	adminServicePrefix + "ResendReplicationTasks": {Group: APIGroupAdmin, Scope: OperatorScopeCluster},
+}
+
+// IsClusterScopedAPI checks if the API with the given full name is an operator API managing the cluster
+func IsClusterScopedAPI(apiName string) bool {
+	api, ok := OperatorAPIs[apiName]
+	return ok && api.Scope == OperatorScopeCluster
+}
+
+// WorkflowServiceAPIs contains full names of all APIs of the workflow service, sorted by name
+var WorkflowServiceAPIs = serviceAPIs(workflowServicePrefix, (*workflowservice.WorkflowServiceServer)(nil))
+
+// AdminServiceAPIs contains full names of all APIs of the admin service, sorted by name
+var AdminServiceAPIs = serviceAPIs(adminServicePrefix, (*adminservice.AdminServiceServer)(nil))
+
+// serviceAPIs returns the full names of the methods of server, a nil pointer to a gRPC service server interface
+func serviceAPIs(prefix string, server interface{}) []string {
+	serviceType := reflect.TypeOf(server).Elem()
+	apis := make([]string, serviceType.NumMethod())
+	for i := range apis {
+		// methods of interface types are sorted by name
+		apis[i] = prefix + serviceType.Method(i).Name
+	}
+	return apis
+}
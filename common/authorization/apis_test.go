@@ -0,0 +1,120 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIGroupsCoverAllAPIs(t *testing.T) {
+	for _, api := range WorkflowServiceAPIs {
+		_, ok := APIGroups[api]
+		require.True(t, ok, "API %v has no group", api)
+	}
+	require.Len(t, APIGroups, len(WorkflowServiceAPIs))
+}
+
+func TestGetAPIGroup(t *testing.T) {
+	testCases := []struct {
+		api      string
+		expected APIGroup
+	}{
+		{api: "DescribeNamespace", expected: APIGroupRead},
+		{api: "ListWorkflowExecutions", expected: APIGroupRead},
+		{api: "GetWorkflowExecutionHistory", expected: APIGroupRead},
+		{api: "QueryWorkflow", expected: APIGroupRead},
+		{api: "PollWorkflowTaskQueue", expected: APIGroupWrite},
+		{api: "PollActivityTaskQueue", expected: APIGroupWrite},
+		{api: "StartWorkflowExecution", expected: APIGroupWrite},
+		{api: "SignalWithStartWorkflowExecution", expected: APIGroupWrite},
+		{api: "TerminateWorkflowExecution", expected: APIGroupWrite},
+		{api: "RespondActivityTaskCompleted", expected: APIGroupWrite},
+		{api: "RegisterNamespace", expected: APIGroupAdmin},
+		{api: "UpdateNamespace", expected: APIGroupAdmin},
+		{api: "DeprecateNamespace", expected: APIGroupAdmin},
+	}
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, GetAPIGroup(workflowServicePrefix+tc.api), tc.api)
+	}
+	require.Equal(t, APIGroupRead, GetAPIGroup(adminServicePrefix+"DescribeCluster"))
+	require.Equal(t, APIGroupAdmin, GetAPIGroup(adminServicePrefix+"ReapplyEvents"))
+	require.Equal(t, APIGroupUnknown, GetAPIGroup("/grpc.health.v1.Health/Check"))
+	require.Equal(t, APIGroupUnknown, GetAPIGroup(adminServicePrefix+"NotYetClassified"))
+}
+
+func TestMutatingAPIs(t *testing.T) {
+	for api, group := range APIGroups {
+		require.Equal(t, group != APIGroupRead, IsMutatingAPI(api), api)
+	}
+	for api, group := range ReplicationAPIs {
+		require.Equal(t, group != APIGroupRead, IsMutatingAPI(api), api)
+	}
+	require.True(t, IsMutatingAPI(adminServicePrefix+"ReapplyEvents"))
+	// polls dispatch tasks and record them as started
+	require.True(t, IsMutatingAPI(workflowServicePrefix+"PollWorkflowTaskQueue"))
+	require.True(t, IsMutatingAPI(workflowServicePrefix+"PollActivityTaskQueue"))
+}
+
+func TestReplicationAPIs(t *testing.T) {
+	for api := range ReplicationAPIs {
+		require.Contains(t, AdminServiceAPIs, api)
+		require.True(t, IsReplicationAPI(api))
+	}
+	require.False(t, IsReplicationAPI(adminServicePrefix+"CloseShard"))
+	require.False(t, IsReplicationAPI(workflowServicePrefix+"GetWorkflowExecutionHistory"))
+}
+
+func TestOperatorAPIsCoverAdminAPIs(t *testing.T) {
+	for _, api := range AdminServiceAPIs {
+		_, isOperatorAPI := OperatorAPIs[api]
+		require.NotEqual(t, IsReplicationAPI(api), isOperatorAPI, "API %v must be either a replication or an operator API", api)
+	}
+	require.Len(t, OperatorAPIs, len(AdminServiceAPIs)-len(ReplicationAPIs))
+}
+
+func TestOperatorAPIs(t *testing.T) {
+	testCases := []struct {
+		api      string
+		group    APIGroup
+		mutating bool
+		cluster  bool
+	}{
+		{api: "AddSearchAttribute", group: APIGroupAdmin, mutating: true, cluster: true},
+		{api: "CloseShard", group: APIGroupAdmin, mutating: true, cluster: true},
+		{api: "GetDLQMessages", group: APIGroupRead, mutating: false, cluster: true},
+		{api: "DescribeMutableState", group: APIGroupRead, mutating: false, cluster: false},
+		{api: "RefreshWorkflowTasks", group: APIGroupAdmin, mutating: true, cluster: false},
+	}
+	for _, tc := range testCases {
+		api := adminServicePrefix + tc.api
+		require.Equal(t, tc.group, GetAPIGroup(api), tc.api)
+		require.Equal(t, tc.mutating, IsMutatingAPI(api), tc.api)
+		require.Equal(t, tc.cluster, IsClusterScopedAPI(api), tc.api)
+	}
+	require.False(t, IsClusterScopedAPI(adminServicePrefix+"DescribeCluster"))
+	require.False(t, IsClusterScopedAPI(workflowServicePrefix+"DescribeNamespace"))
+}
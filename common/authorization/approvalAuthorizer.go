@@ -0,0 +1,110 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"time"
+
+	"go.temporal.io/server/common/clock"
+)
+
+type (
+	// ApprovalKey identifies the calls an approval was granted for
+	ApprovalKey struct {
+		Subject    string
+		APIName    string
+		Namespace  string
+		WorkflowID string
+		RunID      string
+	}
+
+	// Approval is a pre-recorded approval of a call, e.g. granted by a second operator
+	Approval struct {
+		// Approver is the subject who granted the approval
+		Approver  string
+		ExpiresAt time.Time
+	}
+
+	// ApprovalStore looks up the approvals recorded for destructive calls
+	ApprovalStore interface {
+		// GetApproval returns the approval recorded for key, and false if there is none
+		GetApproval(ctx context.Context, key ApprovalKey) (Approval, bool, error)
+	}
+
+	approvalAuthorizer struct {
+		authorizer Authorizer
+		apis       map[string]struct{}
+		store      ApprovalStore
+		timeSource clock.TimeSource
+	}
+)
+
+var _ Authorizer = (*approvalAuthorizer)(nil)
+
+// NewApprovalAuthorizer creates an authorizer that denies calls to the given APIs, e.g. destructive APIs such as
+// TerminateWorkflowExecution, unless store holds an unexpired approval for the caller and the exact target.
+// Approved calls and calls to other APIs are decided by authorizer.
+func NewApprovalAuthorizer(
+	authorizer Authorizer,
+	apis []string,
+	store ApprovalStore,
+	timeSource clock.TimeSource,
+) Authorizer {
+	apiSet := make(map[string]struct{}, len(apis))
+	for _, api := range apis {
+		apiSet[api] = struct{}{}
+	}
+	return &approvalAuthorizer{
+		authorizer: authorizer,
+		apis:       apiSet,
+		store:      store,
+		timeSource: timeSource,
+	}
+}
+
+func (a *approvalAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if _, ok := a.apis[target.APIName]; !ok {
+		return a.authorizer.Authorize(ctx, claims, target)
+	}
+	if claims == nil || claims.Subject == "" {
+		return Result{Decision: DecisionDeny, Reason: ReasonNoClaims}, nil
+	}
+
+	approval, ok, err := a.store.GetApproval(ctx, ApprovalKey{
+		Subject:    claims.Subject,
+		APIName:    target.APIName,
+		Namespace:  target.Namespace,
+		WorkflowID: target.WorkflowID,
+		RunID:      target.RunID,
+	})
+	if err != nil {
+		return Result{Decision: DecisionDeny}, err
+	}
+	if !ok || !a.timeSource.Now().Before(approval.ExpiresAt) {
+		return Result{Decision: DecisionDeny, Reason: ReasonApprovalRequired}, nil
+	}
+	return a.authorizer.Authorize(ctx, claims, target)
+}
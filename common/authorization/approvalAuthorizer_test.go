@@ -0,0 +1,169 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"go.temporal.io/server/common/clock"
+)
+
+var terminateWorkflowExecutionTarget = &CallTarget{
+	APIName:    workflowServicePrefix + "TerminateWorkflowExecution",
+	APIGroup:   APIGroupWrite,
+	Namespace:  testNamespace,
+	WorkflowID: "wid",
+	RunID:      "rid",
+}
+
+type (
+	approvalAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		mockAuthorizer *MockAuthorizer
+		store          testApprovalStore
+		timeSource     *clock.EventTimeSource
+		authorizer     Authorizer
+	}
+
+	testApprovalStore map[ApprovalKey]Approval
+
+	failingApprovalStore struct{}
+)
+
+func TestApprovalAuthorizerSuite(t *testing.T) {
+	s := new(approvalAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *approvalAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.store = testApprovalStore{}
+	s.timeSource = clock.NewEventTimeSource().Update(time.Unix(0, 0))
+	s.authorizer = NewApprovalAuthorizer(
+		s.mockAuthorizer, []string{terminateWorkflowExecutionTarget.APIName}, s.store, s.timeSource)
+}
+
+func (s *approvalAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *approvalAuthorizerSuite) TestApproved() {
+	s.store[s.approvalKey(testSubject)] = Approval{Approver: "approver", ExpiresAt: time.Unix(60, 0)}
+	claims := &Claims{Subject: testSubject}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, terminateWorkflowExecutionTarget).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, terminateWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *approvalAuthorizerSuite) TestApprovedButDeniedByAuthorizer() {
+	s.store[s.approvalKey(testSubject)] = Approval{Approver: "approver", ExpiresAt: time.Unix(60, 0)}
+	claims := &Claims{Subject: testSubject}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, terminateWorkflowExecutionTarget).
+		Return(Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, terminateWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(ReasonInsufficientRole, result.Reason)
+}
+
+func (s *approvalAuthorizerSuite) TestUnapproved() {
+	// approvals are specific to the subject and the target
+	s.store[s.approvalKey("other-user")] = Approval{ExpiresAt: time.Unix(60, 0)}
+	otherRun := s.approvalKey(testSubject)
+	otherRun.RunID = "other-run"
+	s.store[otherRun] = Approval{ExpiresAt: time.Unix(60, 0)}
+
+	result, err := s.authorizer.Authorize(ctx, &Claims{Subject: testSubject}, terminateWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonApprovalRequired, result.Reason)
+}
+
+func (s *approvalAuthorizerSuite) TestExpiredApproval() {
+	s.store[s.approvalKey(testSubject)] = Approval{ExpiresAt: time.Unix(60, 0)}
+	s.timeSource.Update(time.Unix(60, 0))
+
+	result, err := s.authorizer.Authorize(ctx, &Claims{Subject: testSubject}, terminateWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonApprovalRequired, result.Reason)
+}
+
+func (s *approvalAuthorizerSuite) TestNoClaims() {
+	result, err := s.authorizer.Authorize(ctx, nil, terminateWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(ReasonNoClaims, result.Reason)
+}
+
+func (s *approvalAuthorizerSuite) TestStoreError() {
+	authorizer := NewApprovalAuthorizer(
+		s.mockAuthorizer, []string{terminateWorkflowExecutionTarget.APIName}, failingApprovalStore{}, s.timeSource)
+
+	result, err := authorizer.Authorize(ctx, &Claims{Subject: testSubject}, terminateWorkflowExecutionTarget)
+	s.Error(err)
+	s.Equal(DecisionDeny, result.Decision)
+}
+
+func (s *approvalAuthorizerSuite) TestOtherAPIs() {
+	claims := &Claims{Subject: testSubject}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, startWorkflowExecutionTarget).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, startWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *approvalAuthorizerSuite) approvalKey(subject string) ApprovalKey {
+	return ApprovalKey{
+		Subject:    subject,
+		APIName:    terminateWorkflowExecutionTarget.APIName,
+		Namespace:  testNamespace,
+		WorkflowID: "wid",
+		RunID:      "rid",
+	}
+}
+
+func (s testApprovalStore) GetApproval(_ context.Context, key ApprovalKey) (Approval, bool, error) {
+	approval, ok := s[key]
+	return approval, ok, nil
+}
+
+func (failingApprovalStore) GetApproval(context.Context, ApprovalKey) (Approval, bool, error) {
+	return Approval{}, false, errors.New("store unavailable")
+}
@@ -0,0 +1,81 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+)
+
+type (
+	audienceAuthorizer struct {
+		authorizer         Authorizer
+		namespaceAudiences map[string]string
+		clusterAudience    string
+	}
+)
+
+var _ Authorizer = (*audienceAuthorizer)(nil)
+
+// NewAudienceAuthorizer creates an authorizer that denies calls unless the caller's token was issued
+// for the target: the audience of a namespace is the one namespaceAudiences maps it to, or the namespace name
+// itself if it is not mapped, and the audience of cluster APIs, the calls without a namespace, is clusterAudience.
+// Cluster APIs are not checked if clusterAudience is empty. Callers granted roles without an audience, such as
+// callers identified by a TLS certificate, are denied as well. All other calls are decided by authorizer.
+func NewAudienceAuthorizer(authorizer Authorizer, namespaceAudiences map[string]string, clusterAudience string) Authorizer {
+	return &audienceAuthorizer{
+		authorizer:         authorizer,
+		namespaceAudiences: namespaceAudiences,
+		clusterAudience:    clusterAudience,
+	}
+}
+
+func (a *audienceAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if !isAnonymous(claims) {
+		if audience, ok := a.audience(target); ok && !hasAudience(claims, audience) {
+			return Result{Decision: DecisionDeny, Reason: ReasonAudienceMismatch}, nil
+		}
+	}
+	return a.authorizer.Authorize(ctx, claims, target)
+}
+
+// audience returns the audience a token must be issued for to call target, and false if no audience is required
+func (a *audienceAuthorizer) audience(target *CallTarget) (string, bool) {
+	if target.Namespace == "" {
+		return a.clusterAudience, a.clusterAudience != ""
+	}
+	if audience, ok := a.namespaceAudiences[target.Namespace]; ok {
+		return audience, true
+	}
+	return target.Namespace, true
+}
+
+func hasAudience(claims *Claims, audience string) bool {
+	for _, aud := range claims.Audience {
+		if aud == audience {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	audienceAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		mockAuthorizer *MockAuthorizer
+		authorizer     Authorizer
+	}
+)
+
+var listNamespacesTarget = &CallTarget{APIName: workflowServicePrefix + "ListNamespaces", APIGroup: APIGroupRead}
+
+func TestAudienceAuthorizerSuite(t *testing.T) {
+	s := new(audienceAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *audienceAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.authorizer = NewAudienceAuthorizer(
+		s.mockAuthorizer,
+		map[string]string{"mapped": "https://mapped.example.com"},
+		"temporal-cluster")
+}
+
+func (s *audienceAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *audienceAuthorizerSuite) TestMatchingAudience() {
+	claims := &Claims{Subject: testSubject, Audience: []string{testNamespace}}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, describeNamespaceTarget).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *audienceAuthorizerSuite) TestMismatchingAudience() {
+	claims := &Claims{Subject: testSubject, System: RoleAdmin, Audience: []string{"other-namespace"}}
+
+	result, err := s.authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonAudienceMismatch, result.Reason)
+}
+
+func (s *audienceAuthorizerSuite) TestMultipleAudiences() {
+	claims := &Claims{Subject: testSubject, Audience: []string{"other-namespace", testNamespace}}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, describeNamespaceTarget).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+
+	result, err = s.authorizer.Authorize(ctx, claims, &CallTarget{APIName: describeNamespaceTarget.APIName, Namespace: "third"})
+	s.NoError(err)
+	s.Equal(ReasonAudienceMismatch, result.Reason)
+}
+
+func (s *audienceAuthorizerSuite) TestMappedAudience() {
+	target := &CallTarget{APIName: describeNamespaceTarget.APIName, Namespace: "mapped"}
+	claims := &Claims{Subject: testSubject, Audience: []string{"https://mapped.example.com"}}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, target).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, target)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+
+	// the namespace name is not accepted for a mapped namespace
+	result, err = s.authorizer.Authorize(ctx, &Claims{Subject: testSubject, Audience: []string{"mapped"}}, target)
+	s.NoError(err)
+	s.Equal(ReasonAudienceMismatch, result.Reason)
+}
+
+func (s *audienceAuthorizerSuite) TestClusterAudience() {
+	claims := &Claims{Subject: testSubject, Audience: []string{"temporal-cluster"}}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, listNamespacesTarget).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, listNamespacesTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+
+	result, err = s.authorizer.Authorize(ctx, &Claims{Subject: testSubject, Audience: []string{testNamespace}}, listNamespacesTarget)
+	s.NoError(err)
+	s.Equal(ReasonAudienceMismatch, result.Reason)
+}
+
+func (s *audienceAuthorizerSuite) TestClusterAudienceNotConfigured() {
+	authorizer := NewAudienceAuthorizer(s.mockAuthorizer, nil, "")
+	claims := &Claims{Subject: testSubject, Audience: []string{testNamespace}}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, listNamespacesTarget).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := authorizer.Authorize(ctx, claims, listNamespacesTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *audienceAuthorizerSuite) TestNoAudience() {
+	result, err := s.authorizer.Authorize(ctx, &Claims{Subject: testSubject, System: RoleAdmin}, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(ReasonAudienceMismatch, result.Reason)
+}
+
+func (s *audienceAuthorizerSuite) TestNoClaims() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).Return(Result{Decision: DecisionDeny, Reason: ReasonNoClaims}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, nil, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(ReasonNoClaims, result.Reason)
+}
+
+func (s *audienceAuthorizerSuite) TestNoSubject() {
+	claims := &Claims{Namespaces: map[string]Role{testNamespace: RoleAdmin}, Audience: []string{"other-namespace"}}
+
+	result, err := s.authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(ReasonAudienceMismatch, result.Reason)
+}
+
+func (s *audienceAuthorizerSuite) TestAnonymous() {
+	claims := &Claims{}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, describeNamespaceTarget).Return(Result{Decision: DecisionDeny, Reason: ReasonAnonymous}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(ReasonAnonymous, result.Reason)
+}
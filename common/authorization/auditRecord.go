@@ -0,0 +1,143 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+)
+
+const (
+	// RequestIDHeaderName is the header carrying the ID of the request, copied into audit records
+	RequestIDHeaderName = "x-request-id"
+	// TraceParentHeaderName is the W3C trace context header, the trace ID of which is copied into audit records
+	TraceParentHeaderName = "traceparent"
+)
+
+type (
+	// AuthorizationAuditRecord is the serialized form of an authorization decision. Field names follow
+	// the JSON mapping of Temporal's structured events so that records can share their ingestion pipeline.
+	AuthorizationAuditRecord struct {
+		Time       time.Time `json:"time"`
+		RequestID  string    `json:"requestId,omitempty"`
+		TraceID    string    `json:"traceId,omitempty"`
+		Subject    string    `json:"subject,omitempty"`
+		Issuer     string    `json:"issuer,omitempty"`
+		APIName    string    `json:"apiName"`
+		Namespace  string    `json:"namespace,omitempty"`
+		WorkflowID string    `json:"workflowId,omitempty"`
+		RunID      string    `json:"runId,omitempty"`
+		Decision   string    `json:"decision"`
+		Reason     string    `json:"reason,omitempty"`
+	}
+
+	// AuditSink writes serialized audit records to their destination
+	AuditSink interface {
+		Write(record []byte) error
+	}
+)
+
+// NewAuditRecordEmitter creates a DecisionObserver that serializes each decision into an AuthorizationAuditRecord
+// and writes it to sink. Records carry the request ID and trace ID of the call, if set in its headers.
+// Failures to write are logged, except for ErrAuditBufferFull, which buffered sinks already count.
+func NewAuditRecordEmitter(sink AuditSink, timeSource clock.TimeSource, logger log.Logger) DecisionObserver {
+	return func(ctx context.Context, claims *Claims, target *CallTarget, result Result) {
+		record := newAuthorizationAuditRecord(ctx, timeSource.Now(), claims, target, result)
+		data, err := record.Marshal()
+		if err != nil {
+			logger.Error("unable to serialize authorization audit record", tag.Error(err))
+			return
+		}
+		if err := sink.Write(data); err != nil && err != ErrAuditBufferFull {
+			logger.Warn("unable to write authorization audit record", tag.Error(err))
+		}
+	}
+}
+
+func newAuthorizationAuditRecord(
+	ctx context.Context,
+	now time.Time,
+	claims *Claims,
+	target *CallTarget,
+	result Result,
+) *AuthorizationAuditRecord {
+	record := &AuthorizationAuditRecord{
+		Time:       now.UTC(),
+		APIName:    target.APIName,
+		Namespace:  target.Namespace,
+		WorkflowID: target.WorkflowID,
+		RunID:      target.RunID,
+		Decision:   decisionName(result.Decision),
+		Reason:     string(result.Reason),
+	}
+	if claims != nil {
+		record.Subject = claims.Subject
+		record.Issuer = claims.Issuer
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(RequestIDHeaderName); len(values) > 0 {
+			record.RequestID = values[0]
+		}
+	}
+	record.TraceID = traceIDFromContext(ctx)
+	return record
+}
+
+// traceIDFromContext returns the trace ID of the traceparent header of the incoming call, empty if there is none
+func traceIDFromContext(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(TraceParentHeaderName); len(values) > 0 {
+			return traceID(values[0])
+		}
+	}
+	return ""
+}
+
+// traceID extracts the trace ID from a traceparent header value of the form "version-traceid-parentid-flags"
+func traceID(traceParent string) string {
+	parts := strings.Split(traceParent, "-")
+	if len(parts) < 4 {
+		return ""
+	}
+	return parts[1]
+}
+
+// Marshal serializes the record
+func (r *AuthorizationAuditRecord) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// Unmarshal deserializes the record from data
+func (r *AuthorizationAuditRecord) Unmarshal(data []byte) error {
+	return json.Unmarshal(data, r)
+}
@@ -0,0 +1,155 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/metadata"
+
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/log"
+)
+
+type (
+	auditRecordSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		sink       *recordingAuditSink
+		timeSource *clock.EventTimeSource
+		emitter    DecisionObserver
+	}
+
+	// recordingAuditSink retains the records written to it
+	recordingAuditSink struct {
+		sync.Mutex
+		records [][]byte
+		err     error
+	}
+)
+
+func TestAuditRecordSuite(t *testing.T) {
+	s := new(auditRecordSuite)
+	suite.Run(t, s)
+}
+
+func (s *auditRecordSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.sink = &recordingAuditSink{}
+	s.timeSource = clock.NewEventTimeSource().Update(time.Unix(1600000000, 0))
+	s.emitter = NewAuditRecordEmitter(s.sink, s.timeSource, log.NewNoop())
+}
+
+func (s *auditRecordSuite) TestAllow() {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		RequestIDHeaderName, "request-1",
+		TraceParentHeaderName, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"))
+	claims := &Claims{Subject: testSubject, Issuer: "issuer"}
+	target := &CallTarget{APIName: startWorkflowExecutionTarget.APIName, Namespace: testNamespace, WorkflowID: "wid"}
+
+	s.emitter(ctx, claims, target, Result{Decision: DecisionAllow})
+	s.Equal(&AuthorizationAuditRecord{
+		Time:       time.Unix(1600000000, 0).UTC(),
+		RequestID:  "request-1",
+		TraceID:    "4bf92f3577b34da6a3ce929d0e0e4736",
+		Subject:    testSubject,
+		Issuer:     "issuer",
+		APIName:    startWorkflowExecutionTarget.APIName,
+		Namespace:  testNamespace,
+		WorkflowID: "wid",
+		Decision:   decisionNameAllow,
+	}, s.lastRecord())
+}
+
+func (s *auditRecordSuite) TestDeny() {
+	target := &CallTarget{APIName: describeNamespaceTarget.APIName, Namespace: testNamespace}
+
+	s.emitter(context.Background(), nil, target, Result{Decision: DecisionDeny, Reason: ReasonNoClaims})
+	s.Equal(&AuthorizationAuditRecord{
+		Time:      time.Unix(1600000000, 0).UTC(),
+		APIName:   describeNamespaceTarget.APIName,
+		Namespace: testNamespace,
+		Decision:  decisionNameDeny,
+		Reason:    string(ReasonNoClaims),
+	}, s.lastRecord())
+}
+
+func (s *auditRecordSuite) TestSerializedFieldNames() {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDHeaderName, "request-1"))
+	target := &CallTarget{APIName: "API", Namespace: testNamespace}
+
+	s.emitter(ctx, &Claims{Subject: testSubject}, target, Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole})
+	s.JSONEq(`{
+		"time": "2020-09-13T12:26:40Z",
+		"requestId": "request-1",
+		"subject": "test-user",
+		"apiName": "API",
+		"namespace": "test-namespace",
+		"decision": "deny",
+		"reason": "insufficient_role"
+	}`, string(s.sink.records[0]))
+}
+
+func (s *auditRecordSuite) TestMalformedTraceParent() {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(TraceParentHeaderName, "malformed"))
+
+	s.emitter(ctx, nil, describeNamespaceTarget, Result{Decision: DecisionAllow})
+	s.Empty(s.lastRecord().TraceID)
+}
+
+func (s *auditRecordSuite) TestSinkError() {
+	s.sink.err = errors.New("sink unavailable")
+	s.NotPanics(func() {
+		s.emitter(context.Background(), nil, describeNamespaceTarget, Result{Decision: DecisionAllow})
+	})
+}
+
+func (s *auditRecordSuite) lastRecord() *AuthorizationAuditRecord {
+	s.sink.Lock()
+	defer s.sink.Unlock()
+
+	s.NotEmpty(s.sink.records)
+	record := &AuthorizationAuditRecord{}
+	s.NoError(record.Unmarshal(s.sink.records[len(s.sink.records)-1]))
+	return record
+}
+
+func (r *recordingAuditSink) Write(record []byte) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.err != nil {
+		return r.err
+	}
+	r.records = append(r.records, record)
+	return nil
+}
@@ -0,0 +1,172 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"go.temporal.io/server/common/clock"
+)
+
+type (
+	// AuthorizationEvent is a summary of a single authorization decision. It copies only the identifiers of
+	// the target, not the request content such as memos, labels or sub-targets, to keep retained events small.
+	AuthorizationEvent struct {
+		Time       time.Time
+		Subject    string
+		APIName    string
+		Namespace  string
+		WorkflowID string
+		RunID      string
+		Decision   Decision
+		Reason     ReasonCode
+		// Err is the error returned by the authorizer, if any
+		Err error
+	}
+
+	// AuditingAuthorizer is an Authorizer that retains the most recent decisions for each subject
+	AuditingAuthorizer interface {
+		Authorizer
+		// RecentDecisions returns the retained decisions for the subject, oldest first
+		RecentDecisions(subject string) []AuthorizationEvent
+	}
+
+	auditingAuthorizer struct {
+		authorizer       Authorizer
+		timeSource       clock.TimeSource
+		eventsPerSubject int
+		maxSubjects      int
+
+		sync.Mutex
+		subjects map[string]*list.Element // values are *decisionRing
+		byAccess *list.List               // most recently active subject first
+	}
+
+	// decisionRing is a fixed size ring buffer of the authorization events of a subject
+	decisionRing struct {
+		subject string
+		events  []AuthorizationEvent
+		next    int
+		full    bool
+	}
+)
+
+var _ AuditingAuthorizer = (*auditingAuthorizer)(nil)
+
+// NewAuditingAuthorizer creates an authorizer that delegates to authorizer and retains the last eventsPerSubject
+// decisions for up to maxSubjects subjects, delegated calls under the subject of the end user. The least recently
+// active subjects are evicted first, so memory use is bounded by eventsPerSubject * maxSubjects events.
+func NewAuditingAuthorizer(
+	authorizer Authorizer,
+	eventsPerSubject int,
+	maxSubjects int,
+	timeSource clock.TimeSource,
+) AuditingAuthorizer {
+	return &auditingAuthorizer{
+		authorizer:       authorizer,
+		timeSource:       timeSource,
+		eventsPerSubject: eventsPerSubject,
+		maxSubjects:      maxSubjects,
+		subjects:         make(map[string]*list.Element),
+		byAccess:         list.New(),
+	}
+}
+
+func (a *auditingAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	result, err := a.authorizer.Authorize(ctx, claims, target)
+
+	var subject string
+	if claims != nil {
+		subject = claims.EffectiveClaims().Subject
+	}
+	a.record(AuthorizationEvent{
+		Time:       a.timeSource.Now(),
+		Subject:    subject,
+		APIName:    target.APIName,
+		Namespace:  target.Namespace,
+		WorkflowID: target.WorkflowID,
+		RunID:      target.RunID,
+		Decision:   result.Decision,
+		Reason:     result.Reason,
+		Err:        err,
+	})
+	return result, err
+}
+
+func (a *auditingAuthorizer) RecentDecisions(subject string) []AuthorizationEvent {
+	a.Lock()
+	defer a.Unlock()
+
+	element, ok := a.subjects[subject]
+	if !ok {
+		return nil
+	}
+	return element.Value.(*decisionRing).list()
+}
+
+func (a *auditingAuthorizer) record(event AuthorizationEvent) {
+	if a.eventsPerSubject <= 0 || a.maxSubjects <= 0 {
+		return
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	element, ok := a.subjects[event.Subject]
+	if ok {
+		a.byAccess.MoveToFront(element)
+	} else {
+		if len(a.subjects) >= a.maxSubjects {
+			oldest := a.byAccess.Remove(a.byAccess.Back()).(*decisionRing)
+			delete(a.subjects, oldest.subject)
+		}
+		element = a.byAccess.PushFront(&decisionRing{
+			subject: event.Subject,
+			events:  make([]AuthorizationEvent, a.eventsPerSubject),
+		})
+		a.subjects[event.Subject] = element
+	}
+	element.Value.(*decisionRing).add(event)
+}
+
+func (r *decisionRing) add(event AuthorizationEvent) {
+	r.events[r.next] = event
+	r.next = (r.next + 1) % len(r.events)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+func (r *decisionRing) list() []AuthorizationEvent {
+	if !r.full {
+		return append([]AuthorizationEvent(nil), r.events[:r.next]...)
+	}
+	events := make([]AuthorizationEvent, 0, len(r.events))
+	events = append(events, r.events[r.next:]...)
+	return append(events, r.events[:r.next]...)
+}
@@ -0,0 +1,152 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"go.temporal.io/server/common/clock"
+)
+
+type (
+	auditingAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		mockAuthorizer *MockAuthorizer
+		timeSource     *clock.EventTimeSource
+	}
+)
+
+func TestAuditingAuthorizerSuite(t *testing.T) {
+	s := new(auditingAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *auditingAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.timeSource = clock.NewEventTimeSource().Update(time.Unix(0, 0))
+}
+
+func (s *auditingAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *auditingAuthorizerSuite) TestRecordsDecisions() {
+	authorizer := NewAuditingAuthorizer(s.mockAuthorizer, 3, 10, s.timeSource)
+	claims := &Claims{Subject: testSubject}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, describeNamespaceTarget).Return(Result{Decision: DecisionAllow}, nil)
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, startWorkflowExecutionTarget).Return(Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil)
+
+	_, err := authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+	s.NoError(err)
+	_, err = authorizer.Authorize(ctx, claims, startWorkflowExecutionTarget)
+	s.NoError(err)
+
+	s.Equal([]AuthorizationEvent{
+		{Time: time.Unix(0, 0), Subject: testSubject, APIName: describeNamespaceTarget.APIName, Namespace: testNamespace,
+			Decision: DecisionAllow},
+		{Time: time.Unix(0, 0), Subject: testSubject, APIName: startWorkflowExecutionTarget.APIName, Namespace: testNamespace,
+			Decision: DecisionDeny, Reason: ReasonInsufficientRole},
+	}, authorizer.RecentDecisions(testSubject))
+	s.Nil(authorizer.RecentDecisions("other"))
+}
+
+func (s *auditingAuthorizerSuite) TestDelegatedCalls() {
+	authorizer := NewAuditingAuthorizer(s.mockAuthorizer, 3, 10, s.timeSource)
+	claims := &Claims{Subject: "gateway", OnBehalfOf: &Claims{Subject: testSubject}}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, describeNamespaceTarget).Return(Result{Decision: DecisionAllow}, nil)
+
+	_, err := authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+	s.NoError(err)
+
+	s.Len(authorizer.RecentDecisions(testSubject), 1)
+	s.Nil(authorizer.RecentDecisions("gateway"))
+}
+
+func (s *auditingAuthorizerSuite) TestCapacity() {
+	authorizer := NewAuditingAuthorizer(s.mockAuthorizer, 3, 10, s.timeSource)
+	claims := &Claims{Subject: testSubject}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, gomock.Any()).Return(Result{Decision: DecisionAllow}, nil).Times(5)
+
+	for i := 0; i < 5; i++ {
+		_, err := authorizer.Authorize(ctx, claims, &CallTarget{APIName: fmt.Sprintf("API%d", i)})
+		s.NoError(err)
+	}
+
+	events := authorizer.RecentDecisions(testSubject)
+	s.Len(events, 3)
+	for i, event := range events {
+		s.Equal(fmt.Sprintf("API%d", i+2), event.APIName)
+	}
+}
+
+func (s *auditingAuthorizerSuite) TestPerSubjectIsolation() {
+	authorizer := NewAuditingAuthorizer(s.mockAuthorizer, 3, 2, s.timeSource)
+	s.mockAuthorizer.EXPECT().Authorize(ctx, gomock.Any(), describeNamespaceTarget).Return(Result{Decision: DecisionAllow}, nil).Times(4)
+
+	for _, subject := range []string{"a", "b", "a", "c"} {
+		_, err := authorizer.Authorize(ctx, &Claims{Subject: subject}, describeNamespaceTarget)
+		s.NoError(err)
+	}
+
+	s.Len(authorizer.RecentDecisions("a"), 2)
+	for _, event := range authorizer.RecentDecisions("c") {
+		s.Equal("c", event.Subject)
+	}
+	s.Len(authorizer.RecentDecisions("c"), 1)
+	// least recently active subject is evicted to bound memory
+	s.Nil(authorizer.RecentDecisions("b"))
+}
+
+func (s *auditingAuthorizerSuite) TestConcurrentAccess() {
+	authorizer := NewAuditingAuthorizer(s.mockAuthorizer, 5, 3, clock.NewRealTimeSource())
+	s.mockAuthorizer.EXPECT().Authorize(ctx, gomock.Any(), describeNamespaceTarget).Return(Result{Decision: DecisionAllow}, nil).AnyTimes()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			subject := fmt.Sprintf("subject-%d", i%4)
+			for j := 0; j < 100; j++ {
+				_, err := authorizer.Authorize(ctx, &Claims{Subject: subject}, describeNamespaceTarget)
+				s.NoError(err)
+				s.LessOrEqual(len(authorizer.RecentDecisions(subject)), 5)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
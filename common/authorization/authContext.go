@@ -0,0 +1,84 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+)
+
+// AuthContext describes the authorized caller of a call, so that handlers can attribute their work,
+// e.g. tag their metrics with the caller's identity
+type AuthContext struct {
+	// Subject of the caller, or of the end user of delegated calls, empty for anonymous callers
+	Subject string
+	// Roles of Subject at the system level and in the target namespace combined
+	Roles Role
+	// Decision of the authorizer
+	Decision Decision
+}
+
+// newAuthContext creates the AuthContext of a call authorized with result
+func newAuthContext(claims *Claims, target *CallTarget, result Result) *AuthContext {
+	authContext := &AuthContext{Decision: result.Decision}
+	if claims != nil {
+		effective := claims.EffectiveClaims()
+		authContext.Subject = effective.Subject
+		authContext.Roles = effective.System | effective.Namespaces[target.Namespace]
+	}
+	return authContext
+}
+
+// AuthContextFromContext returns the AuthContext of an authorized call, or nil if the call was not authorized
+// by the authorization interceptor
+func AuthContextFromContext(ctx context.Context) *AuthContext {
+	authContext, _ := ctx.Value(ContextKeyAuthContext).(*AuthContext)
+	return authContext
+}
+
+// IsElevated checks if the caller holds the admin role
+func (c *AuthContext) IsElevated() bool {
+	return c != nil && c.Roles&RoleAdmin != 0
+}
+
+// EffectiveRoles are the roles of the caller resolved for the target namespace of a call
+type EffectiveRoles struct {
+	// Role is the effective role of the caller, see Claims.EffectiveRole
+	Role Role
+	// Scope is the scope Role was granted in
+	Scope RoleScope
+}
+
+// newEffectiveRoles resolves the effective roles of the subject the call is made for, see Claims.EffectiveClaims
+func newEffectiveRoles(claims *Claims, namespace string) *EffectiveRoles {
+	role, scope := claims.EffectiveClaims().EffectiveRole(namespace)
+	return &EffectiveRoles{Role: role, Scope: scope}
+}
+
+// EffectiveRolesFromContext returns the effective roles of the caller of an authorized call, or nil if the
+// authorization interceptor is not configured with WithEffectiveRoles
+func EffectiveRolesFromContext(ctx context.Context) *EffectiveRoles {
+	roles, _ := ctx.Value(ContextKeyEffectiveRoles).(*EffectiveRoles)
+	return roles
+}
@@ -0,0 +1,91 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthContextFromContext(t *testing.T) {
+	require.Nil(t, AuthContextFromContext(context.Background()))
+
+	claims := &Claims{Subject: testSubject, System: RoleReader, Namespaces: map[string]Role{testNamespace: RoleWriter}}
+	authContext := newAuthContext(claims, &CallTarget{Namespace: testNamespace}, Result{Decision: DecisionAllow})
+	ctx := context.WithValue(context.Background(), ContextKeyAuthContext, authContext)
+	require.Equal(t, &AuthContext{Subject: testSubject, Roles: RoleReader | RoleWriter, Decision: DecisionAllow}, AuthContextFromContext(ctx))
+}
+
+func TestAuthContextOfDelegatedCall(t *testing.T) {
+	claims := &Claims{Subject: "gateway", OnBehalfOf: &Claims{Subject: testSubject, Namespaces: map[string]Role{testNamespace: RoleWriter}}}
+	authContext := newAuthContext(claims, &CallTarget{Namespace: testNamespace}, Result{Decision: DecisionAllow})
+	require.Equal(t, &AuthContext{Subject: testSubject, Roles: RoleWriter, Decision: DecisionAllow}, authContext)
+}
+
+func TestAuthContextWithoutClaims(t *testing.T) {
+	authContext := newAuthContext(nil, &CallTarget{Namespace: testNamespace}, Result{Decision: DecisionAllow})
+	require.Equal(t, &AuthContext{Decision: DecisionAllow}, authContext)
+	require.False(t, authContext.IsElevated())
+}
+
+func TestAuthContextIsElevated(t *testing.T) {
+	var authContext *AuthContext
+	require.False(t, authContext.IsElevated())
+	require.False(t, (&AuthContext{Roles: RoleWriter}).IsElevated())
+	require.True(t, (&AuthContext{Roles: RoleWriter | RoleAdmin}).IsElevated())
+}
+
+func TestEffectiveRolesFromContext(t *testing.T) {
+	require.Nil(t, EffectiveRolesFromContext(context.Background()))
+
+	testCases := []struct {
+		claims   *Claims
+		expected EffectiveRoles
+	}{
+		{claims: nil, expected: EffectiveRoles{Role: RoleUndefined, Scope: RoleScopeNone}},
+		{
+			claims:   &Claims{System: RoleReader, Namespaces: map[string]Role{testNamespace: RoleWorker}},
+			expected: EffectiveRoles{Role: RoleWorker, Scope: RoleScopeNamespace},
+		},
+		{
+			claims:   &Claims{System: RoleWriter, Namespaces: map[string]Role{testNamespace: RoleAdmin}},
+			expected: EffectiveRoles{Role: RoleWriter, Scope: RoleScopeSystem},
+		},
+		{
+			claims:   &Claims{Namespaces: map[string]Role{testNamespace: RoleReader | RoleWriter}},
+			expected: EffectiveRoles{Role: RoleWriter, Scope: RoleScopeNamespace},
+		},
+		{
+			claims:   &Claims{System: RoleAdmin, OnBehalfOf: &Claims{Namespaces: map[string]Role{testNamespace: RoleReader}}},
+			expected: EffectiveRoles{Role: RoleReader, Scope: RoleScopeNamespace},
+		},
+	}
+	for _, tc := range testCases {
+		ctx := context.WithValue(context.Background(), ContextKeyEffectiveRoles, newEffectiveRoles(tc.claims, testNamespace))
+		require.Equal(t, &tc.expected, EffectiveRolesFromContext(ctx))
+	}
+}
@@ -28,19 +28,31 @@ package authorization
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"strings"
+	"time"
+
+	enumspb "go.temporal.io/api/enums/v1"
 
 	"go.temporal.io/server/common/service/config"
 )
 
 const (
+	// DecisionUnknown means the authorizer did not classify the call, it is the zero value of Decision and
+	// denies the call like DecisionDeny, see ValidateCoverage
+	DecisionUnknown Decision = iota
 	// DecisionDeny means auth decision is deny
-	DecisionDeny Decision = iota + 1
+	DecisionDeny
 	// DecisionAllow means auth decision is allow
 	DecisionAllow
 )
 
+const (
+	decisionNameAllow = "allow"
+	decisionNameDeny  = "deny"
+)
+
 // @@@SNIPSTART temporal-common-authorization-authorizer-calltarget
 // CallTarget is contains information for Authorizer to make a decision.
 // It can be extended to include resources like WorkflowType and TaskQueue
@@ -48,8 +60,83 @@ type CallTarget struct {
 	// APIName must be the full API function name.
 	// Example: "/temporal.api.workflowservice.v1.WorkflowService/StartWorkflowExecution".
 	APIName string
+	// APIGroup is the semantic group of the API, see APIGroups
+	APIGroup APIGroup
 	// If a Namespace is not being targeted this be set to an empty string.
 	Namespace string
+	// IsGlobalNamespace is set if Namespace is replicated across clusters, in which case the call
+	// may be forwarded to the namespace's active cluster. Always false if the interceptor
+	// is not configured with a GlobalNamespaceLookup.
+	IsGlobalNamespace bool
+	// IsNamespacePassive is set if Namespace is global and the current cluster is not its active cluster.
+	// Always false if the interceptor is not configured with a NamespaceActivityLookup.
+	IsNamespacePassive bool
+	// NamespaceLabels are the labels of Namespace, e.g. its data classification. Always nil if the interceptor
+	// is not configured with a NamespaceLabelsLookup.
+	NamespaceLabels map[string]string
+	// NamespaceRegion is the region the data of Namespace resides in, the value of its RegionLabel.
+	// Empty if the namespace has no region or the interceptor is not configured with a NamespaceLabelsLookup.
+	NamespaceRegion string
+	// NamespaceTier is the quota tier Namespace is provisioned at, the value of its TierLabel, e.g. "pro".
+	// Empty if the namespace has no tier or the interceptor is not configured with a NamespaceLabelsLookup.
+	NamespaceTier string
+	// NamespaceLabelsResolved is true if the labels of Namespace were looked up, i.e. the interceptor is configured
+	// with a NamespaceLabelsLookup and the call targets a namespace. Authorizers that check labels deny calls
+	// to namespaces whose labels weren't resolved.
+	NamespaceLabelsResolved bool
+	// WorkflowID, RunID and ActivityID are set for APIs that identify a specific workflow execution
+	// or activity in the request. APIs identifying their target with an opaque task token leave them empty,
+	// unless the interceptor is configured with a TaskTokenDecoder. Authorizers can also extract the IDs from the
+	// token themselves with DecodeTaskToken.
+	WorkflowID string
+	RunID      string
+	ActivityID string
+	// TaskQueue is the name of the task queue targeted by the request, e.g. the queue a worker polls
+	// or the queue an embedded command schedules an activity on. Empty for APIs that don't target one.
+	TaskQueue string
+	// Command is the type of the workflow task command a sub-target was extracted from, see CommandAPIs.
+	// Unspecified for targets that are not commands.
+	Command enumspb.CommandType
+	// Cost of the request as computed by the interceptor's request cost function, e.g. its serialized size.
+	// Zero if the interceptor is not configured to compute costs.
+	Cost int
+	// ExecutionTimeout is the workflow execution timeout requested by calls starting a workflow,
+	// RetentionPeriod the workflow execution retention requested by calls registering or updating a namespace,
+	// and SignalInputCount the number of payloads in the input of calls signaling a workflow. They are zero for
+	// calls that don't carry them. NewRequestLimitsAuthorizer limits them by role.
+	ExecutionTimeout time.Duration
+	RetentionPeriod  time.Duration
+	SignalInputCount int
+	// SearchAttributes are the sorted keys of the search attributes set by the request, or, for calls listing
+	// or counting workflows, the identifiers their query refers to. SearchAttributePolicy is the policy of
+	// Namespace restricting their use, nil if the namespace has none, the call uses no search attributes or
+	// the interceptor is not configured with a NamespaceSearchAttributePolicyLookup.
+	// SearchAttributes are nil unless the interceptor is configured WithRequestContentExtraction.
+	SearchAttributes      []string
+	SearchAttributePolicy *SearchAttributePolicy
+	// Memo holds the memo fields set by calls starting a workflow, with their values decoded as strings,
+	// see NewMemoAuthorizer. Nil for calls that set no memo, or if the interceptor is not configured
+	// WithRequestContentExtraction.
+	Memo map[string]string
+	// ContentExtracted is true if SearchAttributes, Memo and the commands of RespondWorkflowTaskCompleted were
+	// extracted from the request, i.e. the interceptor is configured WithRequestContentExtraction.
+	// Authorizers that check them deny calls that may carry them if it is false.
+	ContentExtracted bool
+	// SourceNamespace and SourceWorkflowID identify the workflow that made the call, e.g. a workflow signaling
+	// another workflow, as asserted in the SourceNamespaceHeaderName and SourceWorkflowIDHeaderName headers.
+	// Both are empty for calls not made on behalf of a workflow.
+	SourceNamespace  string
+	SourceWorkflowID string
+	// ClientName and ClientVersion identify the SDK or tool that made the call, as reported
+	// in the "client-name" and "client-version" headers. Empty if the client didn't report them.
+	ClientName    string
+	ClientVersion string
+	// TLSState is the state of the TLS connection the call arrived on, nil for plaintext connections
+	TLSState *tls.ConnectionState
+	// SubTargets are the operations embedded in a composite API, e.g. the start and the signal
+	// of SignalWithStartWorkflowExecution, or the commands of RespondWorkflowTaskCompleted if the interceptor
+	// is configured WithRequestContentExtraction. Authorizers created by NewSubTargetAuthorizer check each of them.
+	SubTargets []*CallTarget
 }
 
 // @@@SNIPEND
@@ -58,12 +145,41 @@ type (
 	// Result is result from authority.
 	Result struct {
 		Decision Decision
+		// Reason optionally explains the decision, typically set on deny
+		Reason ReasonCode
+		// DenyReason optionally explains a deny in detail, including the reasons of the inner authorizers it
+		// derives from. Its Code should match Reason, see NewDenyResult.
+		DenyReason *DenyReason
+		// CacheTTL is how long the decision may be reused for identical calls, zero means callers' default
+		CacheTTL time.Duration
+		// Constraints optionally limit the results an allowed call may return, nil means unconstrained
+		Constraints *Constraints
 	}
 
 	// Decision is enum type for auth decision
 	Decision int
+
+	// ReasonCode is a machine-readable explanation of an auth decision
+	ReasonCode string
 )
 
+// decisionName returns the name of the decision in audit records and metadata, any decision but allow is a deny
+func decisionName(decision Decision) string {
+	if decision == DecisionAllow {
+		return decisionNameAllow
+	}
+	return decisionNameDeny
+}
+
+// Equal reports whether both results carry the same decision, reason, deny reason, cache TTL and constraints
+func (r Result) Equal(other Result) bool {
+	return r.Decision == other.Decision &&
+		r.Reason == other.Reason &&
+		r.DenyReason.Equal(other.DenyReason) &&
+		r.CacheTTL == other.CacheTTL &&
+		r.Constraints.Equal(other.Constraints)
+}
+
 // @@@SNIPSTART temporal-common-authorization-authorizer-interface
 // Authorizer is an interface for implementing authorization logic
 type Authorizer interface {
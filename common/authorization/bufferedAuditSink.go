@@ -0,0 +1,137 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.temporal.io/server/common"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/metrics"
+)
+
+// ErrAuditBufferFull is returned by buffered audit sinks when a record is dropped because the buffer is full
+var ErrAuditBufferFull = errors.New("authorization audit buffer is full")
+
+type (
+	// BufferedAuditSink is an AuditSink that writes records to another sink in the background
+	BufferedAuditSink interface {
+		common.Daemon
+		AuditSink
+	}
+
+	bufferedAuditSink struct {
+		status        int32
+		sink          AuditSink
+		metricsClient metrics.Client
+		logger        log.Logger
+		records       chan []byte
+		shutdownCh    chan struct{}
+		shutdownWG    sync.WaitGroup
+	}
+)
+
+var _ BufferedAuditSink = (*bufferedAuditSink)(nil)
+
+// NewBufferedAuditSink creates an AuditSink that queues up to bufferSize records and writes them to sink
+// from a background goroutine, so that slow destinations never delay calls. Records written while
+// the buffer is full are dropped and counted. Remaining records are flushed when the sink is stopped.
+func NewBufferedAuditSink(
+	sink AuditSink,
+	bufferSize int,
+	metricsClient metrics.Client,
+	logger log.Logger,
+) BufferedAuditSink {
+	return &bufferedAuditSink{
+		status:        common.DaemonStatusInitialized,
+		sink:          sink,
+		metricsClient: metricsClient,
+		logger:        logger,
+		records:       make(chan []byte, bufferSize),
+		shutdownCh:    make(chan struct{}),
+	}
+}
+
+func (s *bufferedAuditSink) Start() {
+	if !atomic.CompareAndSwapInt32(&s.status, common.DaemonStatusInitialized, common.DaemonStatusStarted) {
+		return
+	}
+
+	s.shutdownWG.Add(1)
+	go s.writeLoop()
+}
+
+func (s *bufferedAuditSink) Stop() {
+	if !atomic.CompareAndSwapInt32(&s.status, common.DaemonStatusStarted, common.DaemonStatusStopped) {
+		return
+	}
+
+	close(s.shutdownCh)
+	if success := common.AwaitWaitGroup(&s.shutdownWG, time.Minute); !success {
+		s.logger.Warn("authorization audit sink timed out on shutdown")
+	}
+}
+
+func (s *bufferedAuditSink) Write(record []byte) error {
+	select {
+	case s.records <- record:
+		s.metricsClient.UpdateGauge(metrics.AuthorizationScope, metrics.ServiceAuthorizationAuditBufferSizeGauge, float64(len(s.records)))
+		return nil
+	default:
+		s.metricsClient.IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationAuditDroppedCounter)
+		return ErrAuditBufferFull
+	}
+}
+
+func (s *bufferedAuditSink) writeLoop() {
+	defer s.shutdownWG.Done()
+
+	for {
+		select {
+		case record := <-s.records:
+			s.write(record)
+		case <-s.shutdownCh:
+			for {
+				select {
+				case record := <-s.records:
+					s.write(record)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *bufferedAuditSink) write(record []byte) {
+	if err := s.sink.Write(record); err != nil {
+		s.metricsClient.IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationAuditSinkFailedCounter)
+		s.logger.Warn("unable to write authorization audit record", tag.Error(err))
+	}
+}
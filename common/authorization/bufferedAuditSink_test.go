@@ -0,0 +1,109 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/metrics"
+)
+
+type (
+	bufferedAuditSinkSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller        *gomock.Controller
+		mockMetricsClient *metrics.MockClient
+		sink              *recordingAuditSink
+	}
+)
+
+func TestBufferedAuditSinkSuite(t *testing.T) {
+	s := new(bufferedAuditSinkSuite)
+	suite.Run(t, s)
+}
+
+func (s *bufferedAuditSinkSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockMetricsClient = metrics.NewMockClient(s.controller)
+	s.sink = &recordingAuditSink{}
+}
+
+func (s *bufferedAuditSinkSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *bufferedAuditSinkSuite) TestWritesInBackground() {
+	s.mockMetricsClient.EXPECT().
+		UpdateGauge(metrics.AuthorizationScope, metrics.ServiceAuthorizationAuditBufferSizeGauge, gomock.Any()).AnyTimes()
+	sink := NewBufferedAuditSink(s.sink, 10, s.mockMetricsClient, log.NewNoop())
+	sink.Start()
+	s.NoError(sink.Write([]byte("record-1")))
+	s.NoError(sink.Write([]byte("record-2")))
+	sink.Stop()
+
+	s.Equal([][]byte{[]byte("record-1"), []byte("record-2")}, s.sink.records)
+}
+
+func (s *bufferedAuditSinkSuite) TestDropsWhenFull() {
+	sink := NewBufferedAuditSink(s.sink, 2, s.mockMetricsClient, log.NewNoop())
+	gomock.InOrder(
+		s.mockMetricsClient.EXPECT().
+			UpdateGauge(metrics.AuthorizationScope, metrics.ServiceAuthorizationAuditBufferSizeGauge, float64(1)),
+		s.mockMetricsClient.EXPECT().
+			UpdateGauge(metrics.AuthorizationScope, metrics.ServiceAuthorizationAuditBufferSizeGauge, float64(2)),
+	)
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationAuditDroppedCounter)
+
+	// not started, so nothing drains the buffer
+	s.NoError(sink.Write([]byte("record-1")))
+	s.NoError(sink.Write([]byte("record-2")))
+	s.Equal(ErrAuditBufferFull, sink.Write([]byte("record-3")))
+
+	// buffered records are flushed on stop
+	sink.Start()
+	sink.Stop()
+	s.Equal([][]byte{[]byte("record-1"), []byte("record-2")}, s.sink.records)
+}
+
+func (s *bufferedAuditSinkSuite) TestSinkFailure() {
+	s.mockMetricsClient.EXPECT().
+		UpdateGauge(metrics.AuthorizationScope, metrics.ServiceAuthorizationAuditBufferSizeGauge, gomock.Any()).AnyTimes()
+	s.sink.err = errors.New("sink unavailable")
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationAuditSinkFailedCounter)
+
+	sink := NewBufferedAuditSink(s.sink, 10, s.mockMetricsClient, log.NewNoop())
+	sink.Start()
+	s.NoError(sink.Write([]byte("record-1")))
+	sink.Stop()
+}
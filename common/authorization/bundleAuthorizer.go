@@ -0,0 +1,259 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"go.temporal.io/server/common"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+)
+
+const (
+	// BundlePoliciesFileName is the name of the file of a policy bundle that holds its policies
+	BundlePoliciesFileName = "policies.yaml"
+	// BundleSignatureSuffix is appended to the path of a policy bundle to get the path of its detached signature
+	BundleSignatureSuffix = ".sig"
+
+	defaultBundleReloadInterval = 10 * time.Second
+)
+
+// ErrInvalidBundleSignature is returned by bundle verifiers when the signature of a policy bundle is invalid
+var ErrInvalidBundleSignature = errors.New("invalid policy bundle signature")
+
+type (
+	// BundleVerifier verifies the signature of a policy bundle
+	BundleVerifier interface {
+		// Verify checks that signature is a valid signature of bundle, the content of the bundle file
+		Verify(bundle []byte, signature []byte) error
+	}
+
+	// BundleAuthorizer is an Authorizer deciding calls by the policies of a policy bundle,
+	// which it reloads while it is started whenever the bundle file changes
+	BundleAuthorizer interface {
+		Authorizer
+		PolicySnapshotter
+		common.Daemon
+	}
+
+	bundleAuthorizer struct {
+		status         int32
+		bundlePath     string
+		verifier       BundleVerifier
+		logger         log.Logger
+		authorizer     StaticAuthorizer
+		reloadInterval time.Duration
+		shutdownCh     chan struct{}
+		shutdownWG     sync.WaitGroup
+
+		// modification times of the bundle and its signature when they were last loaded
+		bundleModTime    time.Time
+		signatureModTime time.Time
+	}
+
+	ed25519BundleVerifier struct {
+		publicKey ed25519.PublicKey
+	}
+)
+
+var _ BundleAuthorizer = (*bundleAuthorizer)(nil)
+
+// NewBundleAuthorizer creates an authorizer from the policy bundle at bundlePath, a gzipped tarball
+// whose BundlePoliciesFileName maps role names, as used in the permissions of JWT tokens ("read", "write",
+// "worker" and "admin"), to the API names granted to the role, with the syntax of NewStaticAuthorizer.
+// The detached signature of the bundle, at bundlePath followed by BundleSignatureSuffix, is checked by verifier.
+// An error is returned if the bundle can't be loaded, verified or compiled. Once started, the authorizer
+// polls the bundle for changes and atomically switches to the policies of a changed bundle; changed bundles that
+// fail to load are logged and the previous policies are kept.
+func NewBundleAuthorizer(bundlePath string, verifier BundleVerifier, logger log.Logger) (BundleAuthorizer, error) {
+	a := &bundleAuthorizer{
+		status:         common.DaemonStatusInitialized,
+		bundlePath:     bundlePath,
+		verifier:       verifier,
+		logger:         logger,
+		authorizer:     NewStaticAuthorizer(nil),
+		reloadInterval: defaultBundleReloadInterval,
+		shutdownCh:     make(chan struct{}),
+	}
+	if _, err := a.reload(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// NewEd25519BundleVerifier creates a BundleVerifier checking Ed25519 signatures made with the private key of publicKey
+func NewEd25519BundleVerifier(publicKey ed25519.PublicKey) BundleVerifier {
+	return &ed25519BundleVerifier{publicKey: publicKey}
+}
+
+func (v *ed25519BundleVerifier) Verify(bundle []byte, signature []byte) error {
+	if !ed25519.Verify(v.publicKey, bundle, signature) {
+		return ErrInvalidBundleSignature
+	}
+	return nil
+}
+
+func (a *bundleAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	return a.authorizer.Authorize(ctx, claims, target)
+}
+
+func (a *bundleAuthorizer) PolicySnapshot() *PolicySnapshot {
+	return a.authorizer.PolicySnapshot()
+}
+
+func (a *bundleAuthorizer) Start() {
+	if !atomic.CompareAndSwapInt32(&a.status, common.DaemonStatusInitialized, common.DaemonStatusStarted) {
+		return
+	}
+
+	a.shutdownWG.Add(1)
+	go a.reloadLoop()
+}
+
+func (a *bundleAuthorizer) Stop() {
+	if !atomic.CompareAndSwapInt32(&a.status, common.DaemonStatusStarted, common.DaemonStatusStopped) {
+		return
+	}
+
+	close(a.shutdownCh)
+	if success := common.AwaitWaitGroup(&a.shutdownWG, time.Minute); !success {
+		a.logger.Warn("policy bundle authorizer timed out on shutdown")
+	}
+}
+
+func (a *bundleAuthorizer) reloadLoop() {
+	defer a.shutdownWG.Done()
+
+	ticker := time.NewTicker(a.reloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			reloaded, err := a.reload()
+			if err != nil {
+				a.logger.Error("failed to reload policy bundle, keeping the previous policies", tag.Error(err))
+			} else if reloaded {
+				a.logger.Info("reloaded policy bundle")
+			}
+		case <-a.shutdownCh:
+			return
+		}
+	}
+}
+
+// reload loads the bundle if it or its signature changed since they were last loaded, and reports if it did
+func (a *bundleAuthorizer) reload() (bool, error) {
+	signaturePath := a.bundlePath + BundleSignatureSuffix
+	bundleInfo, err := os.Stat(a.bundlePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to get status of policy bundle: %w", err)
+	}
+	signatureInfo, err := os.Stat(signaturePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to get status of policy bundle signature: %w", err)
+	}
+	if bundleInfo.ModTime().Equal(a.bundleModTime) && signatureInfo.ModTime().Equal(a.signatureModTime) {
+		return false, nil
+	}
+
+	bundle, err := ioutil.ReadFile(a.bundlePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to read policy bundle: %w", err)
+	}
+	signature, err := ioutil.ReadFile(signaturePath)
+	if err != nil {
+		return false, fmt.Errorf("failed to read policy bundle signature: %w", err)
+	}
+	if err := a.verifier.Verify(bundle, signature); err != nil {
+		return false, fmt.Errorf("failed to verify policy bundle %v: %w", a.bundlePath, err)
+	}
+	policies, err := compileBundle(bundle)
+	if err != nil {
+		return false, fmt.Errorf("failed to compile policy bundle %v: %w", a.bundlePath, err)
+	}
+
+	a.authorizer.UpdatePolicies(policies)
+	a.bundleModTime = bundleInfo.ModTime()
+	a.signatureModTime = signatureInfo.ModTime()
+	return true, nil
+}
+
+// compileBundle extracts the policies of a policy bundle
+func compileBundle(bundle []byte) (map[Role][]string, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(bundle))
+	if err != nil {
+		return nil, err
+	}
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("bundle has no %v", BundlePoliciesFileName)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg || path.Clean(header.Name) != BundlePoliciesFileName {
+			continue
+		}
+		content, err := ioutil.ReadAll(archive)
+		if err != nil {
+			return nil, err
+		}
+		return parseBundlePolicies(content)
+	}
+}
+
+func parseBundlePolicies(content []byte) (map[Role][]string, error) {
+	var rolePolicies map[string][]string
+	if err := yaml.UnmarshalStrict(content, &rolePolicies); err != nil {
+		return nil, err
+	}
+	policies := make(map[Role][]string, len(rolePolicies))
+	for roleName, apiNames := range rolePolicies {
+		role := permissionToRole(roleName)
+		if role == RoleUndefined {
+			return nil, fmt.Errorf("unknown role %q", roleName)
+		}
+		policies[role] = append(policies[role], apiNames...)
+	}
+	return policies, nil
+}
@@ -0,0 +1,176 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"go.temporal.io/server/common/log"
+)
+
+type (
+	bundleAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		dir        string
+		bundlePath string
+		privateKey ed25519.PrivateKey
+		verifier   BundleVerifier
+	}
+)
+
+const (
+	readerBundlePolicies = "read: [\"" + workflowServicePrefix + "DescribeNamespace\"]\n"
+	writerBundlePolicies = "write: [\"group:write\"]\n"
+)
+
+func TestBundleAuthorizerSuite(t *testing.T) {
+	s := new(bundleAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *bundleAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	dir, err := ioutil.TempDir("", "bundleAuthorizerTest")
+	s.NoError(err)
+	s.dir = dir
+	s.bundlePath = filepath.Join(dir, "bundle.tar.gz")
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	s.NoError(err)
+	s.privateKey = privateKey
+	s.verifier = NewEd25519BundleVerifier(publicKey)
+}
+
+func (s *bundleAuthorizerSuite) TearDownTest() {
+	s.NoError(os.RemoveAll(s.dir))
+}
+
+func (s *bundleAuthorizerSuite) TestValidBundle() {
+	s.writeBundle(readerBundlePolicies, time.Unix(1, 0))
+
+	authorizer, err := NewBundleAuthorizer(s.bundlePath, s.verifier, log.NewNoop())
+	s.NoError(err)
+	claims := &Claims{Subject: testSubject, Namespaces: map[string]Role{testNamespace: RoleReader}}
+	result, err := authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+	result, err = authorizer.Authorize(ctx, claims, startWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(DecisionUnknown, result.Decision)
+}
+
+func (s *bundleAuthorizerSuite) TestTamperedBundle() {
+	s.writeBundle(readerBundlePolicies, time.Unix(1, 0))
+	signature, err := ioutil.ReadFile(s.bundlePath + BundleSignatureSuffix)
+	s.NoError(err)
+	s.writeBundle(writerBundlePolicies, time.Unix(2, 0))
+	s.NoError(ioutil.WriteFile(s.bundlePath+BundleSignatureSuffix, signature, 0600))
+
+	_, err = NewBundleAuthorizer(s.bundlePath, s.verifier, log.NewNoop())
+	s.True(errors.Is(err, ErrInvalidBundleSignature))
+}
+
+func (s *bundleAuthorizerSuite) TestInvalidBundle() {
+	s.writeBundle("unknown: [\"*\"]\n", time.Unix(1, 0))
+	_, err := NewBundleAuthorizer(s.bundlePath, s.verifier, log.NewNoop())
+	s.Error(err)
+
+	s.NoError(os.Remove(s.bundlePath))
+	_, err = NewBundleAuthorizer(s.bundlePath, s.verifier, log.NewNoop())
+	s.Error(err)
+}
+
+func (s *bundleAuthorizerSuite) TestHotReload() {
+	s.writeBundle(readerBundlePolicies, time.Unix(1, 0))
+	authorizer, err := NewBundleAuthorizer(s.bundlePath, s.verifier, log.NewNoop())
+	s.NoError(err)
+	authorizer.(*bundleAuthorizer).reloadInterval = 10 * time.Millisecond
+	authorizer.Start()
+	defer authorizer.Stop()
+
+	claims := &Claims{Subject: testSubject, Namespaces: map[string]Role{testNamespace: RoleWriter}}
+	result, err := authorizer.Authorize(ctx, claims, startWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(DecisionUnknown, result.Decision)
+
+	s.writeBundle(writerBundlePolicies, time.Unix(2, 0))
+	s.Eventually(func() bool {
+		result, err := authorizer.Authorize(ctx, claims, startWorkflowExecutionTarget)
+		return err == nil && result.Decision == DecisionAllow
+	}, time.Second, 10*time.Millisecond)
+}
+
+func (s *bundleAuthorizerSuite) TestHotReloadKeepsPoliciesOfInvalidBundle() {
+	s.writeBundle(readerBundlePolicies, time.Unix(1, 0))
+	authorizer, err := NewBundleAuthorizer(s.bundlePath, s.verifier, log.NewNoop())
+	s.NoError(err)
+
+	s.NoError(ioutil.WriteFile(s.bundlePath, []byte("tampered"), 0600))
+	s.NoError(os.Chtimes(s.bundlePath, time.Unix(2, 0), time.Unix(2, 0)))
+	_, err = authorizer.(*bundleAuthorizer).reload()
+	s.Error(err)
+
+	claims := &Claims{Subject: testSubject, Namespaces: map[string]Role{testNamespace: RoleReader}}
+	result, err := authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+// writeBundle writes a signed bundle with the given policies, setting the modification time of the files to modTime
+func (s *bundleAuthorizerSuite) writeBundle(policies string, modTime time.Time) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	archive := tar.NewWriter(gz)
+	s.NoError(archive.WriteHeader(&tar.Header{
+		Name:     BundlePoliciesFileName,
+		Typeflag: tar.TypeReg,
+		Mode:     0600,
+		Size:     int64(len(policies)),
+	}))
+	_, err := archive.Write([]byte(policies))
+	s.NoError(err)
+	s.NoError(archive.Close())
+	s.NoError(gz.Close())
+
+	signaturePath := s.bundlePath + BundleSignatureSuffix
+	s.NoError(ioutil.WriteFile(s.bundlePath, buf.Bytes(), 0600))
+	s.NoError(ioutil.WriteFile(signaturePath, ed25519.Sign(s.privateKey, buf.Bytes()), 0600))
+	s.NoError(os.Chtimes(s.bundlePath, modTime, modTime))
+	s.NoError(os.Chtimes(signaturePath, modTime, modTime))
+}
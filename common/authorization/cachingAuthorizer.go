@@ -0,0 +1,95 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"time"
+
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+)
+
+type cachingAuthorizer struct {
+	authorizer  Authorizer
+	cache       DecisionCache
+	defaultTTL  time.Duration
+	isCacheable func(target *CallTarget) bool
+	logger      log.Logger
+}
+
+var _ Authorizer = (*cachingAuthorizer)(nil)
+
+// NewCachingAuthorizer creates an authorizer that reuses the decisions of authorizer stored in cache.
+// Decisions are stored for their Result.CacheTTL, or defaultTTL if it is zero, and are keyed by the caller's
+// subject, issuer and tenant and the API, namespace, workflow and activity of the call, so authorizer must not
+// base cacheable decisions on anything else. Calls of callers without a subject are never cached. Calls for which isCacheable returns false, such as calls decided on the content
+// of the request, the time or the cluster admin role, are always decided by authorizer; a nil isCacheable
+// makes all calls cacheable. Errors of the cache are logged and the call is decided by authorizer,
+// they never fail the call.
+func NewCachingAuthorizer(
+	authorizer Authorizer,
+	cache DecisionCache,
+	defaultTTL time.Duration,
+	isCacheable func(target *CallTarget) bool,
+	logger log.Logger,
+) Authorizer {
+	return &cachingAuthorizer{
+		authorizer:  authorizer,
+		cache:       cache,
+		defaultTTL:  defaultTTL,
+		isCacheable: isCacheable,
+		logger:      logger,
+	}
+}
+
+func (a *cachingAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	// callers without a subject can't be told apart, their decisions would be shared with anonymous callers
+	if claims == nil || claims.Subject == "" || claims.EffectiveClaims().Subject == "" || (a.isCacheable != nil && !a.isCacheable(target)) {
+		return a.authorizer.Authorize(ctx, claims, target)
+	}
+	key := newDecisionCacheKey(claims, target)
+	result, found, err := a.cache.Get(ctx, key)
+	if err != nil {
+		a.logger.Warn("failed to get authorization decision from cache", tag.Error(err))
+	} else if found {
+		return result, nil
+	}
+
+	result, err = a.authorizer.Authorize(ctx, claims, target)
+	if err != nil {
+		return result, err
+	}
+	ttl := result.CacheTTL
+	if ttl == 0 {
+		ttl = a.defaultTTL
+	}
+	if ttl > 0 {
+		if err := a.cache.Set(ctx, key, result, ttl); err != nil {
+			a.logger.Warn("failed to store authorization decision in cache", tag.Error(err))
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,258 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/log"
+)
+
+var errDecisionCacheUnavailable = errors.New("decision cache unavailable")
+
+type (
+	cachingAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		mockAuthorizer *MockAuthorizer
+		cache          *fakeDecisionCache
+		authorizer     Authorizer
+		claims         *Claims
+	}
+
+	// fakeDecisionCache is a DecisionCache that stands in for a shared backend
+	fakeDecisionCache struct {
+		entries map[DecisionCacheKey]Result
+		ttls    map[DecisionCacheKey]time.Duration
+		err     error
+	}
+)
+
+func TestCachingAuthorizerSuite(t *testing.T) {
+	s := new(cachingAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *cachingAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.cache = &fakeDecisionCache{entries: make(map[DecisionCacheKey]Result), ttls: make(map[DecisionCacheKey]time.Duration)}
+	s.authorizer = NewCachingAuthorizer(s.mockAuthorizer, s.cache, time.Minute, nil, log.NewNoop())
+	s.claims = &Claims{Subject: testSubject}
+}
+
+func (s *cachingAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *cachingAuthorizerSuite) TestCachesDecision() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, s.claims, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	for i := 0; i < 2; i++ {
+		result, err := s.authorizer.Authorize(ctx, s.claims, describeNamespaceTarget)
+		s.NoError(err)
+		s.Equal(DecisionAllow, result.Decision)
+	}
+	s.Equal(time.Minute, s.cache.ttls[newDecisionCacheKey(s.claims, describeNamespaceTarget)])
+}
+
+func (s *cachingAuthorizerSuite) TestResultCacheTTL() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, s.claims, describeNamespaceTarget).
+		Return(Result{Decision: DecisionDeny, CacheTTL: time.Second}, nil).Times(1)
+
+	result, err := s.authorizer.Authorize(ctx, s.claims, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(time.Second, s.cache.ttls[newDecisionCacheKey(s.claims, describeNamespaceTarget)])
+}
+
+func (s *cachingAuthorizerSuite) TestDistinctCallsNotShared() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, s.claims, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+	s.mockAuthorizer.EXPECT().Authorize(ctx, s.claims, startWorkflowExecutionTarget).
+		Return(Result{Decision: DecisionDeny}, nil).Times(1)
+	other := &Claims{Subject: "other"}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, other, describeNamespaceTarget).
+		Return(Result{Decision: DecisionDeny}, nil).Times(1)
+
+	result, _ := s.authorizer.Authorize(ctx, s.claims, describeNamespaceTarget)
+	s.Equal(DecisionAllow, result.Decision)
+	result, _ = s.authorizer.Authorize(ctx, s.claims, startWorkflowExecutionTarget)
+	s.Equal(DecisionDeny, result.Decision)
+	result, _ = s.authorizer.Authorize(ctx, other, describeNamespaceTarget)
+	s.Equal(DecisionDeny, result.Decision)
+}
+
+func (s *cachingAuthorizerSuite) TestIssuersAndTenantsNotShared() {
+	otherIssuer := &Claims{Subject: testSubject, Issuer: "other-issuer"}
+	otherTenant := &Claims{Subject: testSubject, TenantID: "other-tenant"}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, s.claims, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+	s.mockAuthorizer.EXPECT().Authorize(ctx, otherIssuer, describeNamespaceTarget).
+		Return(Result{Decision: DecisionDeny}, nil).Times(1)
+	s.mockAuthorizer.EXPECT().Authorize(ctx, otherTenant, describeNamespaceTarget).
+		Return(Result{Decision: DecisionDeny}, nil).Times(1)
+
+	result, _ := s.authorizer.Authorize(ctx, s.claims, describeNamespaceTarget)
+	s.Equal(DecisionAllow, result.Decision)
+	result, _ = s.authorizer.Authorize(ctx, otherIssuer, describeNamespaceTarget)
+	s.Equal(DecisionDeny, result.Decision)
+	result, _ = s.authorizer.Authorize(ctx, otherTenant, describeNamespaceTarget)
+	s.Equal(DecisionDeny, result.Decision)
+}
+
+func (s *cachingAuthorizerSuite) TestEndUsersBehindOneActorNotShared() {
+	authorizer := NewCachingAuthorizer(NewOnBehalfOfAuthorizer(NewDefaultAuthorizer(), []string{"gateway"}),
+		NewLRUDecisionCache(10, clock.NewEventTimeSource()), time.Minute, nil, log.NewNoop())
+	target := &CallTarget{APIName: describeNamespaceTarget.APIName, Namespace: testNamespace}
+	alice := &Claims{Subject: "gateway", OnBehalfOf: &Claims{Subject: "alice", Namespaces: map[string]Role{testNamespace: RoleReader}}}
+	mallory := &Claims{Subject: "gateway", OnBehalfOf: &Claims{Subject: "mallory"}}
+
+	result, err := authorizer.Authorize(ctx, alice, target)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+	result, err = authorizer.Authorize(ctx, mallory, target)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonInsufficientRole, result.Reason)
+
+	untrusted := &Claims{Subject: "untrusted", OnBehalfOf: alice.OnBehalfOf}
+	result, err = authorizer.Authorize(ctx, untrusted, target)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonUntrustedActor, result.Reason)
+}
+
+func (s *cachingAuthorizerSuite) TestCallersWithoutSubjectNotCached() {
+	noSubject := &Claims{System: RoleAdmin}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, noSubject, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionDeny, Reason: ReasonNoClaims}, nil).Times(1)
+
+	result, _ := s.authorizer.Authorize(ctx, noSubject, describeNamespaceTarget)
+	s.Equal(DecisionAllow, result.Decision)
+	result, _ = s.authorizer.Authorize(ctx, nil, describeNamespaceTarget)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Empty(s.cache.entries)
+}
+
+func (s *cachingAuthorizerSuite) TestErrorsNotCached() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, s.claims, describeNamespaceTarget).
+		Return(Result{}, errUnauthorized).Times(2)
+
+	for i := 0; i < 2; i++ {
+		_, err := s.authorizer.Authorize(ctx, s.claims, describeNamespaceTarget)
+		s.Equal(errUnauthorized, err)
+	}
+	s.Empty(s.cache.entries)
+}
+
+func (s *cachingAuthorizerSuite) TestBackendErrorDegradesToDelegate() {
+	s.cache.err = errDecisionCacheUnavailable
+	s.mockAuthorizer.EXPECT().Authorize(ctx, s.claims, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(2)
+
+	for i := 0; i < 2; i++ {
+		result, err := s.authorizer.Authorize(ctx, s.claims, describeNamespaceTarget)
+		s.NoError(err)
+		s.Equal(DecisionAllow, result.Decision)
+	}
+}
+
+func (s *cachingAuthorizerSuite) TestExcludedTargetsBypassCache() {
+	// decisions on starting workflows depend on the content of the request
+	authorizer := NewCachingAuthorizer(s.mockAuthorizer, s.cache, time.Minute, func(target *CallTarget) bool {
+		return target.APIName != startWorkflowExecutionTarget.APIName
+	}, log.NewNoop())
+	s.mockAuthorizer.EXPECT().Authorize(ctx, s.claims, startWorkflowExecutionTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(2)
+	s.mockAuthorizer.EXPECT().Authorize(ctx, s.claims, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	for i := 0; i < 2; i++ {
+		result, err := authorizer.Authorize(ctx, s.claims, startWorkflowExecutionTarget)
+		s.NoError(err)
+		s.Equal(DecisionAllow, result.Decision)
+		result, err = authorizer.Authorize(ctx, s.claims, describeNamespaceTarget)
+		s.NoError(err)
+		s.Equal(DecisionAllow, result.Decision)
+	}
+	s.Len(s.cache.entries, 1)
+	s.Contains(s.cache.entries, newDecisionCacheKey(s.claims, describeNamespaceTarget))
+}
+
+func (s *cachingAuthorizerSuite) TestExcludedTargetsIgnoreCachedDecisions() {
+	s.cache.entries[newDecisionCacheKey(s.claims, describeNamespaceTarget)] = Result{Decision: DecisionAllow}
+	authorizer := NewCachingAuthorizer(s.mockAuthorizer, s.cache, time.Minute,
+		func(*CallTarget) bool { return false }, log.NewNoop())
+	s.mockAuthorizer.EXPECT().Authorize(ctx, s.claims, describeNamespaceTarget).
+		Return(Result{Decision: DecisionDeny}, nil).Times(1)
+
+	result, err := authorizer.Authorize(ctx, s.claims, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+}
+
+func (c *fakeDecisionCache) Get(_ context.Context, key DecisionCacheKey) (Result, bool, error) {
+	if c.err != nil {
+		return Result{}, false, c.err
+	}
+	result, ok := c.entries[key]
+	return result, ok, nil
+}
+
+func (c *fakeDecisionCache) Set(_ context.Context, key DecisionCacheKey, result Result, ttl time.Duration) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.entries[key] = result
+	c.ttls[key] = ttl
+	return nil
+}
+
+func (c *fakeDecisionCache) Invalidate(_ context.Context, subject string) error {
+	if c.err != nil {
+		return c.err
+	}
+	for key := range c.entries {
+		if key.Subject == subject {
+			delete(c.entries, key)
+		}
+	}
+	return nil
+}
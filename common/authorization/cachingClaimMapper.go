@@ -0,0 +1,111 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"crypto/sha256"
+	"time"
+
+	"go.temporal.io/server/common/cache"
+	"go.temporal.io/server/common/clock"
+)
+
+type (
+	cachingClaimMapper struct {
+		claimMapper ClaimMapper
+		ttl         time.Duration
+		negativeTTL time.Duration
+		timeSource  clock.TimeSource
+		cache       cache.Cache
+	}
+
+	claimsCacheEntry struct {
+		claims    *Claims
+		err       error
+		expiresAt time.Time
+	}
+)
+
+var _ ClaimMapper = (*cachingClaimMapper)(nil)
+
+// NewCachingClaimMapper creates a claim mapper that reuses the claims claimMapper resolved for a token for ttl,
+// keeping the entries of at most maxSize tokens. If negativeTTL is positive, errors of claimMapper, such as
+// validation failures of the token, are reused for negativeTTL as well, so that retries with the same bad token
+// do not validate it again; negativeTTL should be short, as it delays the acceptance of a fixed token.
+// Entries are keyed by the token and extra data, callers without a token are never cached.
+// Claims are never reused past the expiry of their token, see Claims.ExpiresAt.
+func NewCachingClaimMapper(
+	claimMapper ClaimMapper,
+	maxSize int,
+	ttl time.Duration,
+	negativeTTL time.Duration,
+	timeSource clock.TimeSource,
+) ClaimMapper {
+	return &cachingClaimMapper{
+		claimMapper: claimMapper,
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		timeSource:  timeSource,
+		cache:       cache.New(maxSize, nil),
+	}
+}
+
+func (a *cachingClaimMapper) GetClaims(ctx context.Context, authInfo *AuthInfo) (*Claims, error) {
+	if authInfo == nil || authInfo.AuthToken == "" {
+		return a.claimMapper.GetClaims(ctx, authInfo)
+	}
+
+	key := claimsCacheKey(authInfo)
+	now := a.timeSource.Now()
+	if value := a.cache.Get(key); value != nil {
+		entry := value.(*claimsCacheEntry)
+		if now.Before(entry.expiresAt) {
+			return entry.claims, entry.err
+		}
+		a.cache.Delete(key)
+	}
+
+	claims, err := a.claimMapper.GetClaims(ctx, authInfo)
+	switch {
+	case err == nil:
+		expiresAt := now.Add(a.ttl)
+		if claims != nil && !claims.ExpiresAt.IsZero() && claims.ExpiresAt.Before(expiresAt) {
+			expiresAt = claims.ExpiresAt
+		}
+		if expiresAt.After(now) {
+			a.cache.Put(key, &claimsCacheEntry{claims: claims, expiresAt: expiresAt})
+		}
+	case a.negativeTTL > 0 && ctx.Err() == nil:
+		// failures caused by the cancellation of the call say nothing about the token
+		a.cache.Put(key, &claimsCacheEntry{err: err, expiresAt: now.Add(a.negativeTTL)})
+	}
+	return claims, err
+}
+
+// claimsCacheKey hashes the credentials of the caller, so that the cache does not hold tokens
+func claimsCacheKey(authInfo *AuthInfo) [sha256.Size]byte {
+	return sha256.Sum256([]byte(authInfo.AuthToken + "\x00" + authInfo.ExtraData))
+}
@@ -0,0 +1,174 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"go.temporal.io/server/common/clock"
+)
+
+type (
+	cachingClaimMapperSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		claimMapper *countingClaimMapper
+		timeSource  *clock.EventTimeSource
+		cached      ClaimMapper
+	}
+
+	// countingClaimMapper counts the tokens it maps, it rejects the tokens it has no claims for
+	countingClaimMapper struct {
+		claims   map[string]*Claims
+		mappings int
+	}
+)
+
+var errTestInvalidToken = errors.New("invalid token")
+
+func (m *countingClaimMapper) GetClaims(_ context.Context, authInfo *AuthInfo) (*Claims, error) {
+	m.mappings++
+	if claims, ok := m.claims[authInfo.AuthToken]; ok {
+		return claims, nil
+	}
+	return nil, errTestInvalidToken
+}
+
+func TestCachingClaimMapperSuite(t *testing.T) {
+	s := new(cachingClaimMapperSuite)
+	suite.Run(t, s)
+}
+
+func (s *cachingClaimMapperSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.claimMapper = &countingClaimMapper{claims: map[string]*Claims{
+		"good": {Subject: testSubject},
+	}}
+	s.timeSource = clock.NewEventTimeSource().Update(time.Unix(0, 0))
+	s.cached = NewCachingClaimMapper(s.claimMapper, 10, time.Minute, 5*time.Second, s.timeSource)
+}
+
+func (s *cachingClaimMapperSuite) TestClaimsCachedForTTL() {
+	s.assertClaims("good")
+	s.assertClaims("good")
+	s.Equal(1, s.claimMapper.mappings)
+
+	s.timeSource.Update(time.Unix(60, 0))
+	s.assertClaims("good")
+	s.Equal(2, s.claimMapper.mappings)
+}
+
+func (s *cachingClaimMapperSuite) TestClaimsNotCachedPastTokenExpiry() {
+	s.claimMapper.claims["expiring"] = &Claims{Subject: testSubject, ExpiresAt: time.Unix(10, 0)}
+	s.assertClaims("expiring")
+	s.timeSource.Update(time.Unix(9, 0))
+	s.assertClaims("expiring")
+	s.Equal(1, s.claimMapper.mappings)
+
+	s.timeSource.Update(time.Unix(10, 0))
+	s.assertClaims("expiring")
+	s.Equal(2, s.claimMapper.mappings)
+	// expired tokens are mapped, e.g. to tolerate clock skew, but never cached
+	s.assertClaims("expiring")
+	s.Equal(3, s.claimMapper.mappings)
+}
+
+func (s *cachingClaimMapperSuite) TestFailureCachedForNegativeTTL() {
+	for i := 0; i < 3; i++ {
+		s.assertRejected("bad")
+	}
+	s.Equal(1, s.claimMapper.mappings)
+
+	s.timeSource.Update(time.Unix(4, 0))
+	s.assertRejected("bad")
+	s.Equal(1, s.claimMapper.mappings)
+
+	// the token was fixed, e.g. its key was published, and is accepted once its failure expires
+	s.claimMapper.claims["bad"] = &Claims{Subject: "fixed"}
+	s.timeSource.Update(time.Unix(5, 0))
+	claims, err := s.cached.GetClaims(ctx, &AuthInfo{AuthToken: "bad"})
+	s.NoError(err)
+	s.Equal("fixed", claims.Subject)
+	s.Equal(2, s.claimMapper.mappings)
+}
+
+func (s *cachingClaimMapperSuite) TestFailureDoesNotAffectOtherTokens() {
+	s.assertRejected("bad")
+	s.assertClaims("good")
+	s.assertRejected("bad")
+	s.assertClaims("good")
+	s.Equal(2, s.claimMapper.mappings)
+
+	_, err := s.cached.GetClaims(ctx, &AuthInfo{AuthToken: "good", ExtraData: "extras"})
+	s.NoError(err)
+	s.Equal(3, s.claimMapper.mappings)
+}
+
+func (s *cachingClaimMapperSuite) TestNegativeCachingDisabled() {
+	s.cached = NewCachingClaimMapper(s.claimMapper, 10, time.Minute, 0, s.timeSource)
+	s.assertRejected("bad")
+	s.assertRejected("bad")
+	s.Equal(2, s.claimMapper.mappings)
+}
+
+func (s *cachingClaimMapperSuite) TestCanceledCallNotCached() {
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err := s.cached.GetClaims(canceled, &AuthInfo{AuthToken: "bad"})
+	s.Error(err)
+
+	s.claimMapper.claims["bad"] = &Claims{Subject: "fixed"}
+	claims, err := s.cached.GetClaims(ctx, &AuthInfo{AuthToken: "bad"})
+	s.NoError(err)
+	s.Equal("fixed", claims.Subject)
+}
+
+func (s *cachingClaimMapperSuite) TestWithoutTokenNotCached() {
+	s.claimMapper.claims[""] = &Claims{}
+	for i := 0; i < 2; i++ {
+		_, err := s.cached.GetClaims(ctx, &AuthInfo{PeerAddress: "10.0.0.1:7233"})
+		s.NoError(err)
+	}
+	s.Equal(2, s.claimMapper.mappings)
+}
+
+func (s *cachingClaimMapperSuite) assertClaims(token string) {
+	claims, err := s.cached.GetClaims(ctx, &AuthInfo{AuthToken: token})
+	s.NoError(err)
+	s.Equal(testSubject, claims.Subject)
+}
+
+func (s *cachingClaimMapperSuite) assertRejected(token string) {
+	claims, err := s.cached.GetClaims(ctx, &AuthInfo{AuthToken: token})
+	s.Equal(errTestInvalidToken, err)
+	s.Nil(claims)
+}
@@ -0,0 +1,285 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"time"
+
+	commandpb "go.temporal.io/api/command/v1"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	taskqueuepb "go.temporal.io/api/taskqueue/v1"
+	"go.temporal.io/api/workflowservice/v1"
+
+	tokenspb "go.temporal.io/server/api/token/v1"
+	"go.temporal.io/server/common"
+	"go.temporal.io/server/common/payload"
+)
+
+type (
+	requestWithExecution interface {
+		GetExecution() *commonpb.WorkflowExecution
+	}
+
+	requestWithWorkflowExecution interface {
+		GetWorkflowExecution() *commonpb.WorkflowExecution
+	}
+
+	requestWithWorkflowID interface {
+		GetWorkflowId() string
+	}
+
+	requestWithRunID interface {
+		GetRunId() string
+	}
+
+	requestWithActivityID interface {
+		GetActivityId() string
+	}
+
+	requestWithTaskQueue interface {
+		GetTaskQueue() *taskqueuepb.TaskQueue
+	}
+
+	requestWithSearchAttributes interface {
+		GetSearchAttributes() *commonpb.SearchAttributes
+	}
+
+	requestWithMemo interface {
+		GetMemo() *commonpb.Memo
+	}
+
+	requestWithVisibilityQuery interface {
+		GetQuery() string
+	}
+
+	requestWithWorkflowExecutionTimeout interface {
+		GetWorkflowExecutionTimeout() *time.Duration
+	}
+)
+
+var taskTokenSerializer = common.NewProtoTaskTokenSerializer()
+
+// CommandAPIs maps the types of the RespondWorkflowTaskCompleted commands that are extracted as sub-targets
+// to the full name of the API the sub-target is authorized as. Commands with the effect of an API a client
+// can call directly are authorized like that API, e.g. starting a child workflow like StartWorkflowExecution
+// in the child's namespace. Scheduling an activity and upserting search attributes have no such API and are
+// authorized as the completion itself, authorizers tell them apart by CallTarget.Command and check e.g. their
+// TaskQueue. Other commands only affect the workflow completing the task and are not extracted.
+var CommandAPIs = map[enumspb.CommandType]string{
+	enumspb.COMMAND_TYPE_SCHEDULE_ACTIVITY_TASK:                     workflowServicePrefix + "RespondWorkflowTaskCompleted",
+	enumspb.COMMAND_TYPE_START_CHILD_WORKFLOW_EXECUTION:             workflowServicePrefix + "StartWorkflowExecution",
+	enumspb.COMMAND_TYPE_CONTINUE_AS_NEW_WORKFLOW_EXECUTION:         workflowServicePrefix + "StartWorkflowExecution",
+	enumspb.COMMAND_TYPE_SIGNAL_EXTERNAL_WORKFLOW_EXECUTION:         workflowServicePrefix + "SignalWorkflowExecution",
+	enumspb.COMMAND_TYPE_REQUEST_CANCEL_EXTERNAL_WORKFLOW_EXECUTION: workflowServicePrefix + "RequestCancelWorkflowExecution",
+	enumspb.COMMAND_TYPE_UPSERT_WORKFLOW_SEARCH_ATTRIBUTES:          workflowServicePrefix + "RespondWorkflowTaskCompleted",
+}
+
+// newCallTarget creates a CallTarget for the given API, extracting the targeted namespace, workflow execution
+// and activity from the request when the request carries them. The search attributes, memo and commands of the
+// request are costly to extract, they are extracted only if withContent, see WithRequestContentExtraction.
+func newCallTarget(apiName string, req interface{}, withContent bool) *CallTarget {
+	target := &CallTarget{APIName: apiName, APIGroup: GetAPIGroup(apiName)}
+	// the namespace of calls to cluster scoped APIs, such as the namespace DescribeHistoryHost locates a shard by,
+	// is not the namespace they manage
+	if r, ok := req.(requestWithNamespace); ok && !IsClusterScopedAPI(apiName) {
+		target.Namespace = r.GetNamespace()
+	}
+
+	var execution *commonpb.WorkflowExecution
+	switch r := req.(type) {
+	case requestWithExecution:
+		execution = r.GetExecution()
+	case requestWithWorkflowExecution:
+		execution = r.GetWorkflowExecution()
+	}
+	if execution != nil {
+		target.WorkflowID = execution.GetWorkflowId()
+		target.RunID = execution.GetRunId()
+	}
+	if r, ok := req.(requestWithWorkflowID); ok {
+		target.WorkflowID = r.GetWorkflowId()
+	}
+	if r, ok := req.(requestWithRunID); ok {
+		target.RunID = r.GetRunId()
+	}
+	if r, ok := req.(requestWithActivityID); ok {
+		target.ActivityID = r.GetActivityId()
+	}
+	if r, ok := req.(requestWithTaskQueue); ok {
+		target.TaskQueue = r.GetTaskQueue().GetName()
+	}
+	setRequestParameters(target, req)
+	if withContent {
+		setRequestContent(target, req)
+		target.ContentExtracted = true
+	}
+	target.SubTargets = newSubTargets(target, req, withContent)
+	return target
+}
+
+// setRequestParameters extracts the parameters of requests that authorizers limit by role, see CallTarget
+func setRequestParameters(target *CallTarget, req interface{}) {
+	if r, ok := req.(requestWithWorkflowExecutionTimeout); ok && r.GetWorkflowExecutionTimeout() != nil {
+		target.ExecutionTimeout = *r.GetWorkflowExecutionTimeout()
+	}
+	switch r := req.(type) {
+	case *workflowservice.SignalWorkflowExecutionRequest:
+		target.SignalInputCount = len(r.GetInput().GetPayloads())
+	case *workflowservice.SignalWithStartWorkflowExecutionRequest:
+		target.SignalInputCount = len(r.GetSignalInput().GetPayloads())
+	case *workflowservice.RegisterNamespaceRequest:
+		if r.GetWorkflowExecutionRetentionPeriod() != nil {
+			target.RetentionPeriod = *r.GetWorkflowExecutionRetentionPeriod()
+		}
+	case *workflowservice.UpdateNamespaceRequest:
+		if r.GetConfig().GetWorkflowExecutionRetentionTtl() != nil {
+			target.RetentionPeriod = *r.GetConfig().GetWorkflowExecutionRetentionTtl()
+		}
+	}
+}
+
+// setRequestContent extracts the search attributes and the memo set by the request, or the identifiers
+// of its visibility query
+func setRequestContent(target *CallTarget, req interface{}) {
+	if r, ok := req.(requestWithSearchAttributes); ok {
+		target.SearchAttributes = searchAttributeKeys(r.GetSearchAttributes())
+	}
+	if r, ok := req.(requestWithVisibilityQuery); ok {
+		target.SearchAttributes = queryKeys(r.GetQuery())
+	}
+	if r, ok := req.(requestWithMemo); ok {
+		target.Memo = memoFields(r.GetMemo())
+	}
+}
+
+// newSubTargets creates the CallTargets of the operations embedded in requests of composite APIs,
+// the commands of workflow task completions only if withContent
+func newSubTargets(target *CallTarget, req interface{}, withContent bool) []*CallTarget {
+	switch r := req.(type) {
+	case *workflowservice.SignalWithStartWorkflowExecutionRequest:
+		return []*CallTarget{
+			{APIName: workflowServicePrefix + "StartWorkflowExecution", APIGroup: APIGroupWrite, Namespace: target.Namespace, WorkflowID: target.WorkflowID,
+				TaskQueue: target.TaskQueue, ExecutionTimeout: target.ExecutionTimeout, SearchAttributes: target.SearchAttributes, Memo: target.Memo,
+				ContentExtracted: target.ContentExtracted},
+			{APIName: workflowServicePrefix + "SignalWorkflowExecution", APIGroup: APIGroupWrite, Namespace: target.Namespace, WorkflowID: target.WorkflowID,
+				SignalInputCount: target.SignalInputCount, ContentExtracted: target.ContentExtracted},
+		}
+	case *workflowservice.RespondWorkflowTaskCompletedRequest:
+		if withContent {
+			return newCommandTargets(target, r.GetCommands())
+		}
+	}
+	return nil
+}
+
+// newCommandTargets creates the CallTargets of the commands of a workflow task completion, see CommandAPIs.
+// Commands that don't name a namespace target the namespace of the completion.
+func newCommandTargets(target *CallTarget, commands []*commandpb.Command) []*CallTarget {
+	var subTargets []*CallTarget
+	for _, command := range commands {
+		apiName, ok := CommandAPIs[command.GetCommandType()]
+		if !ok {
+			continue
+		}
+		subTarget := &CallTarget{APIName: apiName, APIGroup: GetAPIGroup(apiName), Command: command.GetCommandType(), ContentExtracted: true}
+		var namespace string
+		switch command.GetCommandType() {
+		case enumspb.COMMAND_TYPE_SCHEDULE_ACTIVITY_TASK:
+			attributes := command.GetScheduleActivityTaskCommandAttributes()
+			namespace = attributes.GetNamespace()
+			subTarget.ActivityID = attributes.GetActivityId()
+			subTarget.TaskQueue = attributes.GetTaskQueue().GetName()
+		case enumspb.COMMAND_TYPE_START_CHILD_WORKFLOW_EXECUTION:
+			attributes := command.GetStartChildWorkflowExecutionCommandAttributes()
+			namespace = attributes.GetNamespace()
+			subTarget.WorkflowID = attributes.GetWorkflowId()
+			subTarget.TaskQueue = attributes.GetTaskQueue().GetName()
+			if attributes.GetWorkflowExecutionTimeout() != nil {
+				subTarget.ExecutionTimeout = *attributes.GetWorkflowExecutionTimeout()
+			}
+			subTarget.SearchAttributes = searchAttributeKeys(attributes.GetSearchAttributes())
+			subTarget.Memo = memoFields(attributes.GetMemo())
+		case enumspb.COMMAND_TYPE_CONTINUE_AS_NEW_WORKFLOW_EXECUTION:
+			attributes := command.GetContinueAsNewWorkflowExecutionCommandAttributes()
+			subTarget.TaskQueue = attributes.GetTaskQueue().GetName()
+			subTarget.SearchAttributes = searchAttributeKeys(attributes.GetSearchAttributes())
+			subTarget.Memo = memoFields(attributes.GetMemo())
+		case enumspb.COMMAND_TYPE_UPSERT_WORKFLOW_SEARCH_ATTRIBUTES:
+			attributes := command.GetUpsertWorkflowSearchAttributesCommandAttributes()
+			subTarget.SearchAttributes = searchAttributeKeys(attributes.GetSearchAttributes())
+		case enumspb.COMMAND_TYPE_SIGNAL_EXTERNAL_WORKFLOW_EXECUTION:
+			attributes := command.GetSignalExternalWorkflowExecutionCommandAttributes()
+			namespace = attributes.GetNamespace()
+			subTarget.WorkflowID = attributes.GetExecution().GetWorkflowId()
+			subTarget.RunID = attributes.GetExecution().GetRunId()
+			subTarget.SignalInputCount = len(attributes.GetInput().GetPayloads())
+		case enumspb.COMMAND_TYPE_REQUEST_CANCEL_EXTERNAL_WORKFLOW_EXECUTION:
+			attributes := command.GetRequestCancelExternalWorkflowExecutionCommandAttributes()
+			namespace = attributes.GetNamespace()
+			subTarget.WorkflowID = attributes.GetWorkflowId()
+			subTarget.RunID = attributes.GetRunId()
+		}
+		if namespace == "" {
+			namespace = target.Namespace
+		}
+		subTarget.Namespace = namespace
+		subTargets = append(subTargets, subTarget)
+	}
+	return subTargets
+}
+
+// searchAttributeKeys returns the sorted keys of searchAttributes, nil if there are none
+func searchAttributeKeys(searchAttributes *commonpb.SearchAttributes) []string {
+	keys := make(map[string]struct{}, len(searchAttributes.GetIndexedFields()))
+	for key := range searchAttributes.GetIndexedFields() {
+		keys[key] = struct{}{}
+	}
+	return sortedKeys(keys)
+}
+
+// memoFields returns the fields of memo with their values decoded as strings. Values that are not strings,
+// such as numbers or objects, are formatted as by the default data converter. Returns nil if there are no fields.
+func memoFields(memo *commonpb.Memo) map[string]string {
+	if len(memo.GetFields()) == 0 {
+		return nil
+	}
+	fields := make(map[string]string, len(memo.GetFields()))
+	for name, value := range memo.GetFields() {
+		var s string
+		if err := payload.Decode(value, &s); err != nil {
+			s = payload.ToString(value)
+		}
+		fields[name] = s
+	}
+	return fields
+}
+
+// DecodeTaskToken decodes the opaque task token carried by APIs such as RespondActivityTaskCompleted
+// or RecordActivityTaskHeartbeat. Authorizers can use the workflow, run and activity IDs embedded in it
+// to check that the caller is bound to the run the task belongs to.
+func DecodeTaskToken(taskToken []byte) (*tokenspb.Task, error) {
+	return taskTokenSerializer.Deserialize(taskToken)
+}
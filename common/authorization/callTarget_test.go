@@ -0,0 +1,285 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	commandpb "go.temporal.io/api/command/v1"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	namespacepb "go.temporal.io/api/namespace/v1"
+	taskqueuepb "go.temporal.io/api/taskqueue/v1"
+	"go.temporal.io/api/workflowservice/v1"
+
+	"go.temporal.io/server/api/adminservice/v1"
+	tokenspb "go.temporal.io/server/api/token/v1"
+	"go.temporal.io/server/common/payload"
+)
+
+func TestNewCallTarget(t *testing.T) {
+	execution := &commonpb.WorkflowExecution{WorkflowId: "wid", RunId: "rid"}
+	timeout := 24 * time.Hour
+	payloads := &commonpb.Payloads{Payloads: []*commonpb.Payload{{}, {}}}
+	count, err := payload.Encode(42)
+	require.NoError(t, err)
+	memo := &commonpb.Memo{Fields: map[string]*commonpb.Payload{"owner": payload.EncodeString("alice"), "count": count}}
+	testCases := []struct {
+		name     string
+		request  interface{}
+		expected CallTarget
+	}{
+		{
+			name:     "no run-scoped identifiers",
+			request:  &workflowservice.DescribeNamespaceRequest{Namespace: testNamespace},
+			expected: CallTarget{Namespace: testNamespace},
+		},
+		{
+			name:     "workflow ID",
+			request:  &workflowservice.StartWorkflowExecutionRequest{Namespace: testNamespace, WorkflowId: "wid"},
+			expected: CallTarget{Namespace: testNamespace, WorkflowID: "wid"},
+		},
+		{
+			name:     "execution",
+			request:  &workflowservice.DescribeWorkflowExecutionRequest{Namespace: testNamespace, Execution: execution},
+			expected: CallTarget{Namespace: testNamespace, WorkflowID: "wid", RunID: "rid"},
+		},
+		{
+			name:     "workflow execution",
+			request:  &workflowservice.SignalWorkflowExecutionRequest{Namespace: testNamespace, WorkflowExecution: execution},
+			expected: CallTarget{Namespace: testNamespace, WorkflowID: "wid", RunID: "rid"},
+		},
+		{
+			name: "activity",
+			request: &workflowservice.RespondActivityTaskCompletedByIdRequest{
+				Namespace: testNamespace, WorkflowId: "wid", RunId: "rid", ActivityId: "aid"},
+			expected: CallTarget{Namespace: testNamespace, WorkflowID: "wid", RunID: "rid", ActivityID: "aid"},
+		},
+		{
+			name:    "signal with start",
+			request: &workflowservice.SignalWithStartWorkflowExecutionRequest{Namespace: testNamespace, WorkflowId: "wid"},
+			expected: CallTarget{Namespace: testNamespace, WorkflowID: "wid", SubTargets: []*CallTarget{
+				{APIName: startWorkflowExecutionTarget.APIName, APIGroup: APIGroupWrite, Namespace: testNamespace, WorkflowID: "wid"},
+				{APIName: workflowServicePrefix + "SignalWorkflowExecution", APIGroup: APIGroupWrite, Namespace: testNamespace, WorkflowID: "wid"},
+			}},
+		},
+		{
+			name: "execution timeout",
+			request: &workflowservice.StartWorkflowExecutionRequest{
+				Namespace: testNamespace, WorkflowId: "wid", WorkflowExecutionTimeout: &timeout},
+			expected: CallTarget{Namespace: testNamespace, WorkflowID: "wid", ExecutionTimeout: timeout},
+		},
+		{
+			name: "signal input",
+			request: &workflowservice.SignalWorkflowExecutionRequest{
+				Namespace: testNamespace, WorkflowExecution: execution, Input: payloads},
+			expected: CallTarget{Namespace: testNamespace, WorkflowID: "wid", RunID: "rid", SignalInputCount: 2},
+		},
+		{
+			name: "signal with start parameters",
+			request: &workflowservice.SignalWithStartWorkflowExecutionRequest{
+				Namespace: testNamespace, WorkflowId: "wid", WorkflowExecutionTimeout: &timeout, SignalInput: payloads},
+			expected: CallTarget{Namespace: testNamespace, WorkflowID: "wid", ExecutionTimeout: timeout, SignalInputCount: 2,
+				SubTargets: []*CallTarget{
+					{APIName: startWorkflowExecutionTarget.APIName, APIGroup: APIGroupWrite, Namespace: testNamespace, WorkflowID: "wid",
+						ExecutionTimeout: timeout},
+					{APIName: workflowServicePrefix + "SignalWorkflowExecution", APIGroup: APIGroupWrite, Namespace: testNamespace, WorkflowID: "wid",
+						SignalInputCount: 2},
+				}},
+		},
+		{
+			name: "register namespace retention",
+			request: &workflowservice.RegisterNamespaceRequest{
+				Namespace: testNamespace, WorkflowExecutionRetentionPeriod: &timeout},
+			expected: CallTarget{Namespace: testNamespace, RetentionPeriod: timeout},
+		},
+		{
+			name: "update namespace retention",
+			request: &workflowservice.UpdateNamespaceRequest{
+				Namespace: testNamespace, Config: &namespacepb.NamespaceConfig{WorkflowExecutionRetentionTtl: &timeout}},
+			expected: CallTarget{Namespace: testNamespace, RetentionPeriod: timeout},
+		},
+		{
+			name:     "update namespace without config",
+			request:  &workflowservice.UpdateNamespaceRequest{Namespace: testNamespace},
+			expected: CallTarget{Namespace: testNamespace},
+		},
+		{
+			name: "task queue",
+			request: &workflowservice.StartWorkflowExecutionRequest{
+				Namespace: testNamespace, WorkflowId: "wid", TaskQueue: &taskqueuepb.TaskQueue{Name: "queue"}},
+			expected: CallTarget{Namespace: testNamespace, WorkflowID: "wid", TaskQueue: "queue"},
+		},
+		{
+			name: "workflow task commands",
+			request: &workflowservice.RespondWorkflowTaskCompletedRequest{Namespace: testNamespace, Commands: []*commandpb.Command{
+				{CommandType: enumspb.COMMAND_TYPE_START_TIMER},
+				{CommandType: enumspb.COMMAND_TYPE_SCHEDULE_ACTIVITY_TASK, Attributes: &commandpb.Command_ScheduleActivityTaskCommandAttributes{
+					ScheduleActivityTaskCommandAttributes: &commandpb.ScheduleActivityTaskCommandAttributes{
+						ActivityId: "aid", TaskQueue: &taskqueuepb.TaskQueue{Name: "activities"}}}},
+				{CommandType: enumspb.COMMAND_TYPE_START_CHILD_WORKFLOW_EXECUTION, Attributes: &commandpb.Command_StartChildWorkflowExecutionCommandAttributes{
+					StartChildWorkflowExecutionCommandAttributes: &commandpb.StartChildWorkflowExecutionCommandAttributes{
+						Namespace: "other", WorkflowId: "child", TaskQueue: &taskqueuepb.TaskQueue{Name: "children"}, WorkflowExecutionTimeout: &timeout}}},
+				{CommandType: enumspb.COMMAND_TYPE_CONTINUE_AS_NEW_WORKFLOW_EXECUTION, Attributes: &commandpb.Command_ContinueAsNewWorkflowExecutionCommandAttributes{
+					ContinueAsNewWorkflowExecutionCommandAttributes: &commandpb.ContinueAsNewWorkflowExecutionCommandAttributes{
+						TaskQueue: &taskqueuepb.TaskQueue{Name: "queue"}}}},
+				{CommandType: enumspb.COMMAND_TYPE_SIGNAL_EXTERNAL_WORKFLOW_EXECUTION, Attributes: &commandpb.Command_SignalExternalWorkflowExecutionCommandAttributes{
+					SignalExternalWorkflowExecutionCommandAttributes: &commandpb.SignalExternalWorkflowExecutionCommandAttributes{
+						Execution: execution, Input: payloads}}},
+				{CommandType: enumspb.COMMAND_TYPE_REQUEST_CANCEL_EXTERNAL_WORKFLOW_EXECUTION, Attributes: &commandpb.Command_RequestCancelExternalWorkflowExecutionCommandAttributes{
+					RequestCancelExternalWorkflowExecutionCommandAttributes: &commandpb.RequestCancelExternalWorkflowExecutionCommandAttributes{
+						Namespace: "other", WorkflowId: "wid"}}},
+			}},
+			expected: CallTarget{Namespace: testNamespace, SubTargets: []*CallTarget{
+				{APIName: workflowServicePrefix + "RespondWorkflowTaskCompleted", APIGroup: APIGroupWrite, Namespace: testNamespace,
+					ActivityID: "aid", TaskQueue: "activities", Command: enumspb.COMMAND_TYPE_SCHEDULE_ACTIVITY_TASK},
+				{APIName: startWorkflowExecutionTarget.APIName, APIGroup: APIGroupWrite, Namespace: "other",
+					WorkflowID: "child", TaskQueue: "children", ExecutionTimeout: timeout, Command: enumspb.COMMAND_TYPE_START_CHILD_WORKFLOW_EXECUTION},
+				{APIName: startWorkflowExecutionTarget.APIName, APIGroup: APIGroupWrite, Namespace: testNamespace,
+					TaskQueue: "queue", Command: enumspb.COMMAND_TYPE_CONTINUE_AS_NEW_WORKFLOW_EXECUTION},
+				{APIName: workflowServicePrefix + "SignalWorkflowExecution", APIGroup: APIGroupWrite, Namespace: testNamespace,
+					WorkflowID: "wid", RunID: "rid", SignalInputCount: 2, Command: enumspb.COMMAND_TYPE_SIGNAL_EXTERNAL_WORKFLOW_EXECUTION},
+				{APIName: workflowServicePrefix + "RequestCancelWorkflowExecution", APIGroup: APIGroupWrite, Namespace: "other",
+					WorkflowID: "wid", Command: enumspb.COMMAND_TYPE_REQUEST_CANCEL_EXTERNAL_WORKFLOW_EXECUTION},
+			}},
+		},
+		{
+			name: "search attributes",
+			request: &workflowservice.StartWorkflowExecutionRequest{Namespace: testNamespace, WorkflowId: "wid",
+				SearchAttributes: &commonpb.SearchAttributes{IndexedFields: map[string]*commonpb.Payload{"OrderId": {}, "CustomerId": {}}}},
+			expected: CallTarget{Namespace: testNamespace, WorkflowID: "wid", SearchAttributes: []string{"CustomerId", "OrderId"}},
+		},
+		{
+			name:     "visibility query",
+			request:  &workflowservice.ListWorkflowExecutionsRequest{Namespace: testNamespace, Query: "CustomerId = 'acme'"},
+			expected: CallTarget{Namespace: testNamespace, SearchAttributes: []string{"CustomerId"}},
+		},
+		{
+			name: "upsert search attributes command",
+			request: &workflowservice.RespondWorkflowTaskCompletedRequest{Namespace: testNamespace, Commands: []*commandpb.Command{
+				{CommandType: enumspb.COMMAND_TYPE_UPSERT_WORKFLOW_SEARCH_ATTRIBUTES, Attributes: &commandpb.Command_UpsertWorkflowSearchAttributesCommandAttributes{
+					UpsertWorkflowSearchAttributesCommandAttributes: &commandpb.UpsertWorkflowSearchAttributesCommandAttributes{
+						SearchAttributes: &commonpb.SearchAttributes{IndexedFields: map[string]*commonpb.Payload{"CustomerId": {}}}}}},
+			}},
+			expected: CallTarget{Namespace: testNamespace, SubTargets: []*CallTarget{
+				{APIName: workflowServicePrefix + "RespondWorkflowTaskCompleted", APIGroup: APIGroupWrite, Namespace: testNamespace,
+					SearchAttributes: []string{"CustomerId"}, Command: enumspb.COMMAND_TYPE_UPSERT_WORKFLOW_SEARCH_ATTRIBUTES},
+			}},
+		},
+		{
+			name:     "memo",
+			request:  &workflowservice.StartWorkflowExecutionRequest{Namespace: testNamespace, WorkflowId: "wid", Memo: memo},
+			expected: CallTarget{Namespace: testNamespace, WorkflowID: "wid", Memo: map[string]string{"owner": "alice", "count": "42"}},
+		},
+		{
+			name: "child workflow memo",
+			request: &workflowservice.RespondWorkflowTaskCompletedRequest{Namespace: testNamespace, Commands: []*commandpb.Command{
+				{CommandType: enumspb.COMMAND_TYPE_START_CHILD_WORKFLOW_EXECUTION, Attributes: &commandpb.Command_StartChildWorkflowExecutionCommandAttributes{
+					StartChildWorkflowExecutionCommandAttributes: &commandpb.StartChildWorkflowExecutionCommandAttributes{
+						WorkflowId: "child", Memo: memo}}},
+			}},
+			expected: CallTarget{Namespace: testNamespace, SubTargets: []*CallTarget{
+				{APIName: startWorkflowExecutionTarget.APIName, APIGroup: APIGroupWrite, Namespace: testNamespace, WorkflowID: "child",
+					Memo: map[string]string{"owner": "alice", "count": "42"}, Command: enumspb.COMMAND_TYPE_START_CHILD_WORKFLOW_EXECUTION},
+			}},
+		},
+		{
+			name:     "task token",
+			request:  &workflowservice.RespondActivityTaskCompletedRequest{Namespace: testNamespace, TaskToken: []byte("token")},
+			expected: CallTarget{Namespace: testNamespace},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.expected.APIName = "API"
+			tc.expected.APIGroup = APIGroupUnknown
+			tc.expected.ContentExtracted = true
+			for _, subTarget := range tc.expected.SubTargets {
+				subTarget.ContentExtracted = true
+			}
+			require.Equal(t, &tc.expected, newCallTarget("API", tc.request, true))
+		})
+	}
+}
+
+func TestNewCallTargetWithoutContent(t *testing.T) {
+	memo := &commonpb.Memo{Fields: map[string]*commonpb.Payload{"owner": payload.EncodeString("alice")}}
+	searchAttributes := &commonpb.SearchAttributes{IndexedFields: map[string]*commonpb.Payload{"CustomerId": {}}}
+
+	target := newCallTarget(startWorkflowExecutionTarget.APIName, &workflowservice.StartWorkflowExecutionRequest{
+		Namespace: testNamespace, WorkflowId: "wid", Memo: memo, SearchAttributes: searchAttributes}, false)
+	require.Equal(t, &CallTarget{APIName: startWorkflowExecutionTarget.APIName, APIGroup: APIGroupWrite,
+		Namespace: testNamespace, WorkflowID: "wid"}, target)
+
+	target = newCallTarget(workflowServicePrefix+"ListWorkflowExecutions",
+		&workflowservice.ListWorkflowExecutionsRequest{Namespace: testNamespace, Query: "CustomerId = 'acme'"}, false)
+	require.Nil(t, target.SearchAttributes)
+
+	target = newCallTarget(workflowServicePrefix+"RespondWorkflowTaskCompleted", &workflowservice.RespondWorkflowTaskCompletedRequest{
+		Namespace: testNamespace, Commands: []*commandpb.Command{
+			{CommandType: enumspb.COMMAND_TYPE_START_CHILD_WORKFLOW_EXECUTION, Attributes: &commandpb.Command_StartChildWorkflowExecutionCommandAttributes{
+				StartChildWorkflowExecutionCommandAttributes: &commandpb.StartChildWorkflowExecutionCommandAttributes{
+					WorkflowId: "child", Memo: memo}}},
+		}}, false)
+	require.Nil(t, target.SubTargets)
+
+	// the start and the signal of composite APIs are still extracted
+	target = newCallTarget(workflowServicePrefix+"SignalWithStartWorkflowExecution", &workflowservice.SignalWithStartWorkflowExecutionRequest{
+		Namespace: testNamespace, WorkflowId: "wid", Memo: memo}, false)
+	require.Len(t, target.SubTargets, 2)
+	require.Nil(t, target.SubTargets[0].Memo)
+	require.False(t, target.SubTargets[0].ContentExtracted)
+}
+
+func TestNewCallTargetOfOperatorAPI(t *testing.T) {
+	execution := &commonpb.WorkflowExecution{WorkflowId: "wid", RunId: "rid"}
+	target := newCallTarget(adminServicePrefix+"RefreshWorkflowTasks",
+		&adminservice.RefreshWorkflowTasksRequest{Namespace: testNamespace, Execution: execution}, true)
+	require.Equal(t, &CallTarget{APIName: adminServicePrefix + "RefreshWorkflowTasks", APIGroup: APIGroupAdmin,
+		Namespace: testNamespace, WorkflowID: "wid", RunID: "rid", ContentExtracted: true}, target)
+
+	// cluster scoped, the namespace only locates the shard
+	target = newCallTarget(adminServicePrefix+"DescribeHistoryHost",
+		&adminservice.DescribeHistoryHostRequest{Namespace: testNamespace, WorkflowExecution: execution}, true)
+	require.Equal(t, &CallTarget{APIName: adminServicePrefix + "DescribeHistoryHost", APIGroup: APIGroupRead,
+		WorkflowID: "wid", RunID: "rid", ContentExtracted: true}, target)
+}
+
+func TestDecodeTaskToken(t *testing.T) {
+	token := &tokenspb.Task{NamespaceId: "nid", WorkflowId: "wid", RunId: "rid", ActivityId: "aid"}
+	data, err := taskTokenSerializer.Serialize(token)
+	require.NoError(t, err)
+
+	decoded, err := DecodeTaskToken(data)
+	require.NoError(t, err)
+	require.Equal(t, "wid", decoded.GetWorkflowId())
+	require.Equal(t, "rid", decoded.GetRunId())
+	require.Equal(t, "aid", decoded.GetActivityId())
+
+	_, err = DecodeTaskToken([]byte("malformed"))
+	require.Error(t, err)
+}
@@ -0,0 +1,87 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+
+	"go.temporal.io/server/common/metrics"
+)
+
+type (
+	// CasbinEnforcer is the subset of *casbin.Enforcer used by the Casbin authorizer, which keeps
+	// the module independent of a particular Casbin version
+	CasbinEnforcer interface {
+		Enforce(rvals ...interface{}) (bool, error)
+	}
+
+	// CasbinRequestMapper maps a call to the request values of a Casbin Enforce call,
+	// typically to a (subject, object, action) triple
+	CasbinRequestMapper func(claims *Claims, target *CallTarget) []interface{}
+
+	casbinAuthorizer struct {
+		enforcer      CasbinEnforcer
+		requestMapper CasbinRequestMapper
+		metricsClient metrics.Client
+	}
+)
+
+var _ Authorizer = (*casbinAuthorizer)(nil)
+
+// NewCasbinAuthorizer creates an authorizer that allows a call if enforcer allows the request requestMapper maps
+// the call to. A nil requestMapper maps calls to (subject, namespace, API name), see DefaultCasbinRequest.
+// Errors of enforcer fail closed: the call is denied and counted as a failed authorization.
+func NewCasbinAuthorizer(enforcer CasbinEnforcer, requestMapper CasbinRequestMapper, metricsClient metrics.Client) Authorizer {
+	if requestMapper == nil {
+		requestMapper = DefaultCasbinRequest
+	}
+	return &casbinAuthorizer{
+		enforcer:      enforcer,
+		requestMapper: requestMapper,
+		metricsClient: metricsClient,
+	}
+}
+
+// DefaultCasbinRequest maps a call to the subject of the caller, the target namespace and the full API name,
+// matching Casbin models with a "r = sub, obj, act" request definition
+func DefaultCasbinRequest(claims *Claims, target *CallTarget) []interface{} {
+	var subject string
+	if claims != nil {
+		subject = claims.Subject
+	}
+	return []interface{}{subject, target.Namespace, target.APIName}
+}
+
+func (a *casbinAuthorizer) Authorize(_ context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	allowed, err := a.enforcer.Enforce(a.requestMapper(claims, target)...)
+	if err != nil {
+		a.metricsClient.IncCounter(metrics.AuthorizationScope, metrics.ServiceErrAuthorizeFailedCounter)
+		return Result{Decision: DecisionDeny}, nil
+	}
+	if !allowed {
+		return Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil
+	}
+	return Result{Decision: DecisionAllow}, nil
+}
@@ -0,0 +1,132 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"go.temporal.io/server/common/metrics"
+)
+
+type (
+	casbinAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller        *gomock.Controller
+		mockMetricsClient *metrics.MockClient
+		enforcer          *testCasbinEnforcer
+	}
+
+	// testCasbinEnforcer allows the requests in its policy, mirroring a Casbin model with
+	// "r = sub, obj, act", "p = sub, obj, act" and "m = r.sub == p.sub && r.obj == p.obj && r.act == p.act"
+	testCasbinEnforcer struct {
+		policy   map[string]struct{}
+		err      error
+		requests [][]interface{}
+	}
+)
+
+func TestCasbinAuthorizerSuite(t *testing.T) {
+	s := new(casbinAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *casbinAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockMetricsClient = metrics.NewMockClient(s.controller)
+	s.enforcer = &testCasbinEnforcer{policy: map[string]struct{}{
+		casbinPolicyKey([]interface{}{testSubject, testNamespace, describeNamespaceTarget.APIName}): {},
+	}}
+}
+
+func (s *casbinAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *casbinAuthorizerSuite) TestAllow() {
+	authorizer := NewCasbinAuthorizer(s.enforcer, nil, s.mockMetricsClient)
+	result, err := authorizer.Authorize(ctx, &Claims{Subject: testSubject}, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+	s.Equal([][]interface{}{{testSubject, testNamespace, describeNamespaceTarget.APIName}}, s.enforcer.requests)
+}
+
+func (s *casbinAuthorizerSuite) TestDeny() {
+	authorizer := NewCasbinAuthorizer(s.enforcer, nil, s.mockMetricsClient)
+	result, err := authorizer.Authorize(ctx, &Claims{Subject: testSubject}, startWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonInsufficientRole, result.Reason)
+
+	result, err = authorizer.Authorize(ctx, nil, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+}
+
+func (s *casbinAuthorizerSuite) TestCustomRequestMapper() {
+	s.enforcer.policy = map[string]struct{}{casbinPolicyKey([]interface{}{"group:developers", testNamespace, string(APIGroupRead)}): {}}
+	authorizer := NewCasbinAuthorizer(s.enforcer, func(claims *Claims, target *CallTarget) []interface{} {
+		return []interface{}{"group:" + claims.Groups[0], target.Namespace, string(target.APIGroup)}
+	}, s.mockMetricsClient)
+	claims := &Claims{Subject: testSubject, Groups: []string{"developers"}}
+
+	result, err := authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+	result, err = authorizer.Authorize(ctx, claims, startWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+}
+
+func (s *casbinAuthorizerSuite) TestEnforcerErrorFailsClosed() {
+	s.enforcer.err = errors.New("policy adapter unavailable")
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceErrAuthorizeFailedCounter)
+	authorizer := NewCasbinAuthorizer(s.enforcer, nil, s.mockMetricsClient)
+
+	result, err := authorizer.Authorize(ctx, &Claims{Subject: testSubject}, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+}
+
+func (e *testCasbinEnforcer) Enforce(rvals ...interface{}) (bool, error) {
+	e.requests = append(e.requests, rvals)
+	if e.err != nil {
+		return false, e.err
+	}
+	_, ok := e.policy[casbinPolicyKey(rvals)]
+	return ok, nil
+}
+
+func casbinPolicyKey(values []interface{}) string {
+	return fmt.Sprintf("%v", values)
+}
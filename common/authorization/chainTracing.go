@@ -0,0 +1,130 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+)
+
+// contextKeyChainTrace holds the *chainTrace of a call evaluated by traced authorizers
+const contextKeyChainTrace = "auth-chain-trace"
+
+type (
+	// ChainTracing is a switch for the verbose logging of the evaluation of authorizer chains, whose members are
+	// wrapped with NewTracedAuthorizer. While it is on, the decision and latency of every traced member are logged
+	// at debug level for a sample of the calls; while it is off, which it initially is, nothing is logged and
+	// traced members only check the switch before delegating. It is safe for concurrent use.
+	ChainTracing struct {
+		logger  log.Logger
+		enabled *atomic.Bool
+		sampler atomic.Value // *metricsSampler
+	}
+
+	// chainTrace is the state of the tracing of a call, shared by the traced members of the chain
+	chainTrace struct {
+		sampled bool
+		depth   int
+	}
+
+	tracedAuthorizer struct {
+		name       string
+		authorizer Authorizer
+		tracing    *ChainTracing
+	}
+)
+
+var _ Authorizer = (*tracedAuthorizer)(nil)
+
+// NewChainTracing creates a chain tracing switch that is initially off and logs to logger
+func NewChainTracing(logger log.Logger) *ChainTracing {
+	return &ChainTracing{
+		logger:  logger,
+		enabled: atomic.NewBool(false),
+	}
+}
+
+// Enable turns tracing on for a share of the calls given by sampleRate, between 0 and 1.
+// A call is sampled as a whole, either all traced members of the chain log their decision or none does.
+func (t *ChainTracing) Enable(sampleRate float64) {
+	t.sampler.Store(newMetricsSampler(sampleRate))
+	t.enabled.Store(true)
+}
+
+// Disable turns tracing off
+func (t *ChainTracing) Disable() {
+	t.enabled.Store(false)
+}
+
+// NewTracedAuthorizer creates an authorizer that decides calls with authorizer and, while tracing is on, logs the
+// decision and latency of authorizer as member name of the chain. The decision is returned unchanged.
+func NewTracedAuthorizer(name string, authorizer Authorizer, tracing *ChainTracing) Authorizer {
+	return &tracedAuthorizer{
+		name:       name,
+		authorizer: authorizer,
+		tracing:    tracing,
+	}
+}
+
+func (a *tracedAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if !a.tracing.enabled.Load() {
+		return a.authorizer.Authorize(ctx, claims, target)
+	}
+	trace, ok := ctx.Value(contextKeyChainTrace).(*chainTrace)
+	if !ok {
+		// the outermost traced member samples the call for the whole chain
+		trace = &chainTrace{sampled: a.tracing.sampler.Load().(*metricsSampler).sample()}
+		if !trace.sampled {
+			return a.authorizer.Authorize(context.WithValue(ctx, contextKeyChainTrace, trace), claims, target)
+		}
+	}
+	if !trace.sampled {
+		return a.authorizer.Authorize(ctx, claims, target)
+	}
+
+	start := time.Now()
+	result, err := a.authorizer.Authorize(
+		context.WithValue(ctx, contextKeyChainTrace, &chainTrace{sampled: true, depth: trace.depth + 1}), claims, target)
+	latency := time.Since(start)
+
+	tags := []tag.Tag{
+		tag.AuthChainMember(a.name),
+		tag.AuthChainDepth(trace.depth),
+		tag.AuthAPIName(target.APIName),
+		tag.AuthLatency(latency),
+	}
+	if err != nil {
+		tags = append(tags, tag.Error(err))
+	} else {
+		tags = append(tags, tag.AuthDecision(decisionName(result.Decision)), tag.AuthReason(string(result.Reason)))
+	}
+	a.tracing.logger.Debug("authorization chain member evaluated", tags...)
+	return result, err
+}
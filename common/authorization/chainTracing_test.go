@@ -0,0 +1,156 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+)
+
+type (
+	chainTracingSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		logger     *log.MockLogger
+		tracing    *ChainTracing
+		authorizer Authorizer
+		entries    []map[string]interface{}
+	}
+)
+
+func TestChainTracingSuite(t *testing.T) {
+	s := new(chainTracingSuite)
+	suite.Run(t, s)
+}
+
+func (s *chainTracingSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.logger = &log.MockLogger{}
+	s.entries = nil
+	s.logger.On("Debug", "authorization chain member evaluated", mock.Anything).Run(func(args mock.Arguments) {
+		entry := make(map[string]interface{})
+		for _, t := range args.Get(1).([]tag.Tag) {
+			field := t.Field()
+			switch {
+			case field.String != "":
+				entry[field.Key] = field.String
+			case field.Interface != nil:
+				entry[field.Key] = field.Interface
+			default:
+				entry[field.Key] = field.Integer
+			}
+		}
+		s.entries = append(s.entries, entry)
+	})
+	s.tracing = NewChainTracing(s.logger)
+	static := NewStaticAuthorizer(map[Role][]string{RoleReader: {describeNamespaceTarget.APIName}})
+	s.authorizer = NewTracedAuthorizer("limits", NewRequestLimitsAuthorizer(
+		NewTracedAuthorizer("static", static, s.tracing),
+		map[Role]RequestLimits{RoleReader: {MaxSignalInputCount: 1}}), s.tracing)
+}
+
+func (s *chainTracingSuite) TestPerMemberEntriesWhenEnabled() {
+	s.tracing.Enable(1)
+	claims := &Claims{Subject: testSubject, Namespaces: map[string]Role{testNamespace: RoleReader}}
+	s.assertDecision(DecisionAllow, claims, describeNamespaceTarget)
+
+	s.Len(s.entries, 2)
+	s.Equal("static", s.entries[0]["auth-chain-member"])
+	s.Equal(int64(1), s.entries[0]["auth-chain-depth"])
+	s.Equal("allow", s.entries[0]["auth-decision"])
+	s.Equal(describeNamespaceTarget.APIName, s.entries[0]["auth-api-name"])
+	s.Contains(s.entries[0], "auth-latency")
+	s.Equal("limits", s.entries[1]["auth-chain-member"])
+	s.Equal(int64(0), s.entries[1]["auth-chain-depth"])
+	s.Equal("allow", s.entries[1]["auth-decision"])
+}
+
+func (s *chainTracingSuite) TestDenyByOuterMember() {
+	s.tracing.Enable(1)
+	claims := &Claims{Subject: testSubject, Namespaces: map[string]Role{testNamespace: RoleReader}}
+	s.assertDecision(DecisionDeny, claims, &CallTarget{APIName: describeNamespaceTarget.APIName, Namespace: testNamespace, SignalInputCount: 2})
+
+	s.Len(s.entries, 1)
+	s.Equal("limits", s.entries[0]["auth-chain-member"])
+	s.Equal("deny", s.entries[0]["auth-decision"])
+	s.Equal(string(ReasonRequestLimit), s.entries[0]["auth-reason"])
+}
+
+func (s *chainTracingSuite) TestMemberError() {
+	s.tracing.Enable(1)
+	failing := NewTracedAuthorizer("failing", &failingAuthorizer{}, s.tracing)
+	_, err := failing.Authorize(ctx, nil, describeNamespaceTarget)
+	s.Error(err)
+
+	s.Len(s.entries, 1)
+	s.NotContains(s.entries[0], "auth-decision")
+	s.Contains(s.entries[0], "error")
+}
+
+func (s *chainTracingSuite) TestNothingWhenDisabled() {
+	claims := &Claims{Subject: testSubject, Namespaces: map[string]Role{testNamespace: RoleReader}}
+	s.assertDecision(DecisionAllow, claims, describeNamespaceTarget)
+	s.assertDecision(DecisionUnknown, claims, startWorkflowExecutionTarget)
+	s.Empty(s.entries)
+
+	s.tracing.Enable(1)
+	s.tracing.Disable()
+	s.assertDecision(DecisionAllow, claims, describeNamespaceTarget)
+	s.Empty(s.entries)
+}
+
+func (s *chainTracingSuite) TestSampledPerCall() {
+	s.tracing.Enable(0.5)
+	claims := &Claims{Subject: testSubject, Namespaces: map[string]Role{testNamespace: RoleReader}}
+	for i := 0; i < 4; i++ {
+		s.assertDecision(DecisionAllow, claims, describeNamespaceTarget)
+	}
+	// two of the calls are sampled, each with the entries of both members
+	s.Len(s.entries, 4)
+	for i, member := range []string{"static", "limits", "static", "limits"} {
+		s.Equal(member, s.entries[i]["auth-chain-member"])
+	}
+}
+
+func (s *chainTracingSuite) assertDecision(expected Decision, claims *Claims, target *CallTarget) {
+	result, err := s.authorizer.Authorize(ctx, claims, target)
+	s.NoError(err)
+	s.Equal(expected, result.Decision)
+}
+
+type failingAuthorizer struct{}
+
+func (a *failingAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	return Result{}, errors.New("policy store unavailable")
+}
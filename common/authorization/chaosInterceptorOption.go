@@ -0,0 +1,84 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+//go:build authzchaos
+// +build authzchaos
+
+// Chaos injection is only compiled with the authzchaos build tag so that it can never be
+// enabled in a production build by configuration alone.
+// To run its tests use `go test -tags authzchaos ./common/authorization`.
+
+package authorization
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+type (
+	// ChaosConfig describes the faults injected into the authorize path
+	ChaosConfig struct {
+		// Delay added to every authorization call
+		Delay time.Duration
+		// ErrorRate is the fraction, between 0 and 1, of authorization calls that fail with ErrChaosInjected
+		ErrorRate float64
+	}
+
+	chaosAuthorizer struct {
+		authorizer Authorizer
+		config     ChaosConfig
+	}
+)
+
+// ErrChaosInjected is returned from authorization calls failed by chaos injection
+var ErrChaosInjected = errors.New("authorization failure injected by chaos testing")
+
+var _ Authorizer = (*chaosAuthorizer)(nil)
+
+// WithChaos injects an artificial delay and error rate into the authorize path of the interceptor
+func WithChaos(config ChaosConfig) InterceptorOption {
+	return func(a *interceptor) {
+		if a.authorizer != nil {
+			a.authorizer = &chaosAuthorizer{authorizer: a.authorizer, config: config}
+		}
+	}
+}
+
+func (a *chaosAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if a.config.Delay > 0 {
+		timer := time.NewTimer(a.config.Delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return Result{}, ctx.Err()
+		}
+	}
+	if a.config.ErrorRate > 0 && rand.Float64() < a.config.ErrorRate {
+		return Result{}, ErrChaosInjected
+	}
+	return a.authorizer.Authorize(ctx, claims, target)
+}
@@ -0,0 +1,106 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+//go:build authzchaos
+// +build authzchaos
+
+package authorization
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	chaosAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		mockAuthorizer *MockAuthorizer
+	}
+)
+
+func TestChaosAuthorizerSuite(t *testing.T) {
+	s := new(chaosAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *chaosAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+}
+
+func (s *chaosAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *chaosAuthorizerSuite) newAuthorizer(config ChaosConfig) Authorizer {
+	a := &interceptor{authorizer: s.mockAuthorizer}
+	WithChaos(config)(a)
+	return a.authorizer
+}
+
+func (s *chaosAuthorizerSuite) TestDelay() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).Return(Result{Decision: DecisionAllow}, nil)
+	authorizer := s.newAuthorizer(ChaosConfig{Delay: 50 * time.Millisecond})
+
+	start := time.Now()
+	result, err := authorizer.Authorize(ctx, nil, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+	s.GreaterOrEqual(int64(time.Since(start)), int64(50*time.Millisecond))
+}
+
+func (s *chaosAuthorizerSuite) TestDelayCanceled() {
+	authorizer := s.newAuthorizer(ChaosConfig{Delay: time.Minute})
+	cancelCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+
+	_, err := authorizer.Authorize(cancelCtx, nil, describeNamespaceTarget)
+	s.Equal(context.DeadlineExceeded, err)
+}
+
+func (s *chaosAuthorizerSuite) TestErrorRate() {
+	authorizer := s.newAuthorizer(ChaosConfig{ErrorRate: 1})
+
+	_, err := authorizer.Authorize(ctx, nil, describeNamespaceTarget)
+	s.Equal(ErrChaosInjected, err)
+}
+
+func (s *chaosAuthorizerSuite) TestNoErrors() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).Return(Result{Decision: DecisionAllow}, nil).Times(10)
+	authorizer := s.newAuthorizer(ChaosConfig{ErrorRate: 0})
+
+	for i := 0; i < 10; i++ {
+		_, err := authorizer.Authorize(ctx, nil, describeNamespaceTarget)
+		s.NoError(err)
+	}
+}
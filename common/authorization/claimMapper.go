@@ -27,12 +27,16 @@
 package authorization
 
 import (
+	"context"
 	"crypto/x509/pkix"
 	"fmt"
 	"strings"
 
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 
+	"go.temporal.io/server/common/metrics"
 	"go.temporal.io/server/common/service/config"
 )
 
@@ -43,6 +47,10 @@ type AuthInfo struct {
 	TLSSubject    *pkix.Name
 	TLSConnection *credentials.TLSInfo
 	ExtraData     string
+	// Cookie is the value of the cookie headers, e.g. forwarded by grpc-web from browsers
+	Cookie string
+	// Network address of the caller, empty if unknown
+	PeerAddress string
 }
 
 // @@@SNIPEND
@@ -50,11 +58,86 @@ type AuthInfo struct {
 // @@@SNIPSTART temporal-common-authorization-claimmapper-interface
 // ClaimMapper converts authorization info of a subject into Temporal claims (permissions) for authorization
 type ClaimMapper interface {
-	GetClaims(authInfo *AuthInfo) (*Claims, error)
+	GetClaims(ctx context.Context, authInfo *AuthInfo) (*Claims, error)
 }
 
 // @@@SNIPEND
 
+// LegacyClaimMapper is the ClaimMapper interface prior to passing the call context to GetClaims.
+// Deprecated: implement ClaimMapper instead, existing implementations can be adapted with NewClaimMapperFromLegacy.
+type LegacyClaimMapper interface {
+	GetClaims(authInfo *AuthInfo) (*Claims, error)
+}
+
+type legacyClaimMapper struct {
+	claimMapper LegacyClaimMapper
+}
+
+var _ ClaimMapper = (*legacyClaimMapper)(nil)
+
+// NewClaimMapperFromLegacy adapts a claim mapper implementing the legacy GetClaims signature to ClaimMapper
+func NewClaimMapperFromLegacy(claimMapper LegacyClaimMapper) ClaimMapper {
+	return &legacyClaimMapper{claimMapper: claimMapper}
+}
+
+func (a *legacyClaimMapper) GetClaims(_ context.Context, authInfo *AuthInfo) (*Claims, error) {
+	return a.claimMapper.GetClaims(authInfo)
+}
+
+// NewAuthInfoFromContext extracts the authorization and cookie headers and TLS connection state from the metadata
+// and peer of an incoming call. It returns nil if the caller presented neither a header nor a verified certificate.
+func NewAuthInfoFromContext(ctx context.Context) *AuthInfo {
+	var tlsSubject *pkix.Name
+	var authHeaders []string
+	var authExtraHeaders []string
+	var cookieHeaders []string
+	var tlsConnection *credentials.TLSInfo
+	var peerAddress string
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		authHeaders = headerValues(md, "authorization")
+		authExtraHeaders = headerValues(md, "authorization-extras")
+		cookieHeaders = headerValues(md, "cookie")
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if p.Addr != nil {
+			peerAddress = p.Addr.String()
+		}
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			tlsConnection = &tlsInfo
+			if len(tlsInfo.State.VerifiedChains) > 0 && len(tlsInfo.State.VerifiedChains[0]) > 0 {
+				// The assumption here is that we only expect a single verified chain of certs (first[0]).
+				// It's unclear how we should handle a situation when more than one chain is presented,
+				// which subject to use. It's okay for us to limit ourselves to one chain.
+				// We can always extend this logic later.
+				// We tale the first element in the chain ([0]) because that's the client cert
+				// (at the beginning of the chain), not intermediary CAs or the root CA (at the end of the chain).
+				tlsSubject = &tlsInfo.State.VerifiedChains[0][0].Subject
+			}
+		}
+	}
+	if tlsSubject == nil && len(authHeaders) == 0 && len(cookieHeaders) == 0 {
+		return nil
+	}
+
+	var authHeader string
+	var authExtraHeader string
+	if len(authHeaders) > 0 {
+		authHeader = authHeaders[0]
+	}
+	if len(authExtraHeaders) > 0 {
+		authExtraHeader = authExtraHeaders[0]
+	}
+	return &AuthInfo{
+		AuthToken:     authHeader,
+		TLSSubject:    tlsSubject,
+		TLSConnection: tlsConnection,
+		ExtraData:     authExtraHeader,
+		Cookie:        strings.Join(cookieHeaders, "; "),
+		PeerAddress:   peerAddress,
+	}
+}
+
 // No-op claim mapper that gives system level admin permission to everybody
 type noopClaimMapper struct{}
 
@@ -64,17 +147,34 @@ func NewNoopClaimMapper(_ *config.Config) ClaimMapper {
 	return &noopClaimMapper{}
 }
 
-func (*noopClaimMapper) GetClaims(_ *AuthInfo) (*Claims, error) {
+func (*noopClaimMapper) GetClaims(_ context.Context, _ *AuthInfo) (*Claims, error) {
 	return &Claims{System: RoleAdmin}, nil
 }
 
-func GetClaimMapperFromConfig(config *config.Config) (ClaimMapper, error) {
+// GetClaimMapperFromConfig creates the claim mapper named by the authorization config, reporting to metricsClient,
+// the metrics client of the frontend service
+func GetClaimMapperFromConfig(config *config.Config, metricsClient metrics.Client) (ClaimMapper, error) {
 
 	switch strings.ToLower(config.Global.Authorization.ClaimMapper) {
 	case "":
 		return NewNoopClaimMapper(config), nil
 	case "default":
-		return NewDefaultJWTClaimMapper(NewDefaultTokenKeyProvider(config), config), nil
+		return NewDefaultJWTClaimMapperWithMetrics(NewDefaultTokenKeyProviderWithMetrics(config, metricsClient), config, metricsClient), nil
 	}
 	return nil, fmt.Errorf("unknown claim mapper: %s", config.Global.Authorization.ClaimMapper)
 }
+
+// headerValues returns the values of the header with the given lowercase name. gRPC lowercases header names,
+// but metadata populated by proxies such as grpc-web gateways may keep the original casing,
+// so names are matched case-insensitively.
+func headerValues(md metadata.MD, name string) []string {
+	if values, ok := md[name]; ok {
+		return values
+	}
+	for key, values := range md {
+		if strings.EqualFold(key, name) {
+			return values
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+var (
+	testPeerAddr = &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 7233}
+	testTLSInfo  = credentials.TLSInfo{State: tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: testSubject}}}},
+	}}
+)
+
+func TestAuthInfoFromContextWithHeaders(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"authorization", "Bearer token",
+		"authorization-extras", "extras"))
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: testPeerAddr})
+
+	authInfo := NewAuthInfoFromContext(ctx)
+	require.NotNil(t, authInfo)
+	require.Equal(t, "Bearer token", authInfo.AuthToken)
+	require.Equal(t, "extras", authInfo.ExtraData)
+	require.Equal(t, "10.0.0.1:7233", authInfo.PeerAddress)
+	require.Nil(t, authInfo.TLSSubject)
+	require.Nil(t, authInfo.TLSConnection)
+}
+
+func TestAuthInfoFromContextWithMixedCaseHeaders(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{
+		"Authorization":        []string{"Bearer token"},
+		"AUTHORIZATION-Extras": []string{"extras"},
+	})
+
+	authInfo := NewAuthInfoFromContext(ctx)
+	require.NotNil(t, authInfo)
+	require.Equal(t, "Bearer token", authInfo.AuthToken)
+	require.Equal(t, "extras", authInfo.ExtraData)
+}
+
+func TestAuthInfoFromContextPrefersLowercaseHeader(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.MD{
+		"Authorization": []string{"Bearer other"},
+		"authorization": []string{"Bearer token"},
+	})
+
+	authInfo := NewAuthInfoFromContext(ctx)
+	require.NotNil(t, authInfo)
+	require.Equal(t, "Bearer token", authInfo.AuthToken)
+}
+
+func TestAuthInfoFromContextWithTLS(t *testing.T) {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: testPeerAddr, AuthInfo: testTLSInfo})
+
+	authInfo := NewAuthInfoFromContext(ctx)
+	require.NotNil(t, authInfo)
+	require.Empty(t, authInfo.AuthToken)
+	require.Equal(t, testSubject, authInfo.TLSSubject.CommonName)
+	require.Equal(t, &testTLSInfo, authInfo.TLSConnection)
+	require.Equal(t, "10.0.0.1:7233", authInfo.PeerAddress)
+}
+
+func TestAuthInfoFromContextWithCookies(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"cookie", "a=1",
+		"cookie", "b=2"))
+
+	authInfo := NewAuthInfoFromContext(ctx)
+	require.NotNil(t, authInfo)
+	require.Equal(t, "a=1; b=2", authInfo.Cookie)
+	require.Empty(t, authInfo.AuthToken)
+}
+
+func TestAuthInfoFromContextWithoutAuthInfo(t *testing.T) {
+	require.Nil(t, NewAuthInfoFromContext(context.Background()))
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("other", "value"))
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: testPeerAddr, AuthInfo: credentials.TLSInfo{}})
+	require.Nil(t, NewAuthInfoFromContext(ctx))
+}
+
+func TestClaimMapperFromLegacy(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	authInfo := &AuthInfo{AuthToken: "token"}
+	expected := &Claims{Subject: testSubject}
+	legacyClaimMapper := NewMockLegacyClaimMapper(controller)
+	legacyClaimMapper.EXPECT().GetClaims(authInfo).Return(expected, nil)
+
+	claims, err := NewClaimMapperFromLegacy(legacyClaimMapper).GetClaims(context.Background(), authInfo)
+	require.NoError(t, err)
+	require.Equal(t, expected, claims)
+}
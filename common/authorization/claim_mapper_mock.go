@@ -29,6 +29,7 @@
 package authorization
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -58,7 +59,45 @@ func (m *MockClaimMapper) EXPECT() *MockClaimMapperMockRecorder {
 }
 
 // GetClaims mocks base method.
-func (m *MockClaimMapper) GetClaims(authInfo *AuthInfo) (*Claims, error) {
+func (m *MockClaimMapper) GetClaims(ctx context.Context, authInfo *AuthInfo) (*Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClaims", ctx, authInfo)
+	ret0, _ := ret[0].(*Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClaims indicates an expected call of GetClaims.
+func (mr *MockClaimMapperMockRecorder) GetClaims(ctx, authInfo interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockClaimMapper)(nil).GetClaims), ctx, authInfo)
+}
+
+// MockLegacyClaimMapper is a mock of LegacyClaimMapper interface.
+type MockLegacyClaimMapper struct {
+	ctrl     *gomock.Controller
+	recorder *MockLegacyClaimMapperMockRecorder
+}
+
+// MockLegacyClaimMapperMockRecorder is the mock recorder for MockLegacyClaimMapper.
+type MockLegacyClaimMapperMockRecorder struct {
+	mock *MockLegacyClaimMapper
+}
+
+// NewMockLegacyClaimMapper creates a new mock instance.
+func NewMockLegacyClaimMapper(ctrl *gomock.Controller) *MockLegacyClaimMapper {
+	mock := &MockLegacyClaimMapper{ctrl: ctrl}
+	mock.recorder = &MockLegacyClaimMapperMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLegacyClaimMapper) EXPECT() *MockLegacyClaimMapperMockRecorder {
+	return m.recorder
+}
+
+// GetClaims mocks base method.
+func (m *MockLegacyClaimMapper) GetClaims(authInfo *AuthInfo) (*Claims, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetClaims", authInfo)
 	ret0, _ := ret[0].(*Claims)
@@ -67,7 +106,7 @@ func (m *MockClaimMapper) GetClaims(authInfo *AuthInfo) (*Claims, error) {
 }
 
 // GetClaims indicates an expected call of GetClaims.
-func (mr *MockClaimMapperMockRecorder) GetClaims(authInfo interface{}) *gomock.Call {
+func (mr *MockLegacyClaimMapperMockRecorder) GetClaims(authInfo interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockClaimMapper)(nil).GetClaims), authInfo)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClaims", reflect.TypeOf((*MockLegacyClaimMapper)(nil).GetClaims), authInfo)
 }
@@ -0,0 +1,116 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ClaimsBuilder constructs Claims, validating them on Build.
+// Claim mappers should use it rather than populating Claims by hand.
+type ClaimsBuilder struct {
+	claims Claims
+	err    error
+}
+
+// NewClaimsBuilder creates a builder of empty claims
+func NewClaimsBuilder() *ClaimsBuilder {
+	return &ClaimsBuilder{}
+}
+
+// WithSubject sets the subject of the claims, which must not be empty
+func (b *ClaimsBuilder) WithSubject(subject string) *ClaimsBuilder {
+	b.claims.Subject = subject
+	return b
+}
+
+// WithSystemRole grants role at the system level in addition to the roles granted so far
+func (b *ClaimsBuilder) WithSystemRole(role Role) *ClaimsBuilder {
+	if !role.IsValid() {
+		b.setErr(fmt.Errorf("invalid system role: %d", role))
+		return b
+	}
+	b.claims.System |= role
+	return b
+}
+
+// WithNamespaceRole grants role within the namespace, which must not be empty,
+// in addition to the roles granted within it so far
+func (b *ClaimsBuilder) WithNamespaceRole(namespace string, role Role) *ClaimsBuilder {
+	if namespace == "" {
+		b.setErr(errors.New("namespace role granted for an empty namespace"))
+		return b
+	}
+	if !role.IsValid() {
+		b.setErr(fmt.Errorf("invalid role for namespace %s: %d", namespace, role))
+		return b
+	}
+	if b.claims.Namespaces == nil {
+		b.claims.Namespaces = make(map[string]Role)
+	}
+	b.claims.Namespaces[namespace] |= role
+	return b
+}
+
+// WithAttribute adds values to the attribute of the claims named name
+func (b *ClaimsBuilder) WithAttribute(name string, values ...string) *ClaimsBuilder {
+	if b.claims.Attributes == nil {
+		b.claims.Attributes = make(map[string][]string)
+	}
+	b.claims.Attributes[name] = append(b.claims.Attributes[name], values...)
+	return b
+}
+
+// Build returns the claims, or the first error found while building them
+func (b *ClaimsBuilder) Build() (*Claims, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.claims.Subject == "" {
+		return nil, errors.New("claims have no subject")
+	}
+	claims := b.claims
+	if b.claims.Namespaces != nil {
+		// copy so that the built claims are not changed by further use of the builder
+		claims.Namespaces = make(map[string]Role, len(b.claims.Namespaces))
+		for namespace, role := range b.claims.Namespaces {
+			claims.Namespaces[namespace] = role
+		}
+	}
+	if b.claims.Attributes != nil {
+		claims.Attributes = make(map[string][]string, len(b.claims.Attributes))
+		for name, values := range b.claims.Attributes {
+			claims.Attributes[name] = append([]string(nil), values...)
+		}
+	}
+	return &claims, nil
+}
+
+func (b *ClaimsBuilder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
@@ -0,0 +1,95 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaimsBuilder(t *testing.T) {
+	claims, err := NewClaimsBuilder().
+		WithSubject(testSubject).
+		WithSystemRole(RoleReader).
+		WithNamespaceRole(testNamespace, RoleWriter).
+		WithNamespaceRole(testNamespace, RoleWorker).
+		WithNamespaceRole("other", RoleAdmin).
+		Build()
+	require.NoError(t, err)
+	require.Equal(t, &Claims{
+		Subject: testSubject,
+		System:  RoleReader,
+		Namespaces: map[string]Role{
+			testNamespace: RoleWriter | RoleWorker,
+			"other":       RoleAdmin,
+		},
+	}, claims)
+}
+
+func TestClaimsBuilderAttributes(t *testing.T) {
+	builder := NewClaimsBuilder().
+		WithSubject(testSubject).
+		WithAttribute("regions", "eu").
+		WithAttribute("regions", "us")
+	claims, err := builder.Build()
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"regions": {"eu", "us"}}, claims.Attributes)
+
+	// built claims are not changed by further use of the builder
+	builder.WithAttribute("regions", "apac")
+	require.Equal(t, []string{"eu", "us"}, claims.Attributes["regions"])
+}
+
+func TestClaimsBuilderInvalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		builder *ClaimsBuilder
+	}{
+		{
+			name:    "no subject",
+			builder: NewClaimsBuilder().WithSystemRole(RoleAdmin),
+		},
+		{
+			name:    "empty namespace",
+			builder: NewClaimsBuilder().WithSubject(testSubject).WithNamespaceRole("", RoleReader),
+		},
+		{
+			name:    "unknown namespace role",
+			builder: NewClaimsBuilder().WithSubject(testSubject).WithNamespaceRole(testNamespace, Role(1<<10)),
+		},
+		{
+			name:    "unknown system role",
+			builder: NewClaimsBuilder().WithSubject(testSubject).WithSystemRole(Role(-1)),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			claims, err := tc.builder.Build()
+			require.Error(t, err)
+			require.Nil(t, claims)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+)
+
+const (
+	// ClassificationLabel is the namespace label holding the data classification tier of the namespace
+	ClassificationLabel = "classification"
+	// DefaultClassificationTier is the tier of namespaces without a classification label
+	DefaultClassificationTier = "default"
+)
+
+type classificationAuthorizer struct {
+	authorizer     Authorizer
+	minRolePerTier map[string]Role
+}
+
+var _ Authorizer = (*classificationAuthorizer)(nil)
+
+// NewClassificationAuthorizer creates an authorizer that requires the caller to hold at least the role
+// minRolePerTier maps the classification tier of the target namespace to, among the roles granted in the
+// namespace, see Claims.GrantedRoles, before delegating to authorizer. Namespaces without a ClassificationLabel are of
+// DefaultClassificationTier. Calls to namespaces of tiers missing from minRolePerTier are denied.
+// The tier is read from CallTarget.NamespaceLabels, see WithNamespaceLabelsLookup.
+func NewClassificationAuthorizer(authorizer Authorizer, minRolePerTier map[string]Role) Authorizer {
+	copied := make(map[string]Role, len(minRolePerTier))
+	for tier, role := range minRolePerTier {
+		copied[tier] = role
+	}
+	return &classificationAuthorizer{
+		authorizer:     authorizer,
+		minRolePerTier: copied,
+	}
+}
+
+func (a *classificationAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if target.Namespace == "" {
+		return a.authorizer.Authorize(ctx, claims, target)
+	}
+	tier, ok := target.NamespaceLabels[ClassificationLabel]
+	if !ok {
+		tier = DefaultClassificationTier
+	}
+	minimumRole, ok := a.minRolePerTier[tier]
+	if !ok {
+		return Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil
+	}
+	if claims == nil {
+		return Result{Decision: DecisionDeny, Reason: ReasonNoClaims}, nil
+	}
+	if !hasMinimumRole(claims.GrantedRoles(target.Namespace), minimumRole) {
+		return Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil
+	}
+	return a.authorizer.Authorize(ctx, claims, target)
+}
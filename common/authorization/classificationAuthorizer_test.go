@@ -0,0 +1,120 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	classificationAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		mockAuthorizer *MockAuthorizer
+		authorizer     Authorizer
+	}
+)
+
+func TestClassificationAuthorizerSuite(t *testing.T) {
+	s := new(classificationAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *classificationAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(Result{Decision: DecisionAllow}, nil).AnyTimes()
+	s.authorizer = NewClassificationAuthorizer(s.mockAuthorizer, map[string]Role{
+		DefaultClassificationTier: RoleWorker,
+		"internal":                RoleReader,
+		"confidential":            RoleWriter,
+		"restricted":              RoleAdmin,
+	})
+}
+
+func (s *classificationAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *classificationAuthorizerSuite) TestTiersAndRoles() {
+	testCases := []struct {
+		tier     string
+		role     Role
+		expected Decision
+	}{
+		{"", RoleWorker, DecisionAllow},
+		{"internal", RoleWorker, DecisionDeny},
+		{"internal", RoleReader, DecisionAllow},
+		{"internal", RoleAdmin, DecisionAllow},
+		{"confidential", RoleReader, DecisionDeny},
+		{"confidential", RoleWriter, DecisionAllow},
+		{"restricted", RoleWriter, DecisionDeny},
+		{"restricted", RoleWriter | RoleReader, DecisionDeny},
+		{"restricted", RoleAdmin, DecisionAllow},
+		{"unknown", RoleAdmin, DecisionDeny},
+	}
+	for _, tc := range testCases {
+		s.Run(fmt.Sprintf("%s/%d", tc.tier, tc.role), func() {
+			target := &CallTarget{APIName: describeNamespaceTarget.APIName, Namespace: testNamespace}
+			if tc.tier != "" {
+				target.NamespaceLabels = map[string]string{ClassificationLabel: tc.tier}
+			}
+			claims := &Claims{Namespaces: map[string]Role{testNamespace: tc.role}}
+
+			result, err := s.authorizer.Authorize(ctx, claims, target)
+			s.NoError(err)
+			s.Equal(tc.expected, result.Decision)
+		})
+	}
+}
+
+func (s *classificationAuthorizerSuite) TestSystemRole() {
+	target := &CallTarget{Namespace: testNamespace, NamespaceLabels: map[string]string{ClassificationLabel: "restricted"}}
+	result, err := s.authorizer.Authorize(ctx, &Claims{System: RoleAdmin}, target)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *classificationAuthorizerSuite) TestNoClaims() {
+	result, err := s.authorizer.Authorize(ctx, nil, &CallTarget{Namespace: testNamespace})
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonNoClaims, result.Reason)
+}
+
+func (s *classificationAuthorizerSuite) TestNoNamespace() {
+	result, err := s.authorizer.Authorize(ctx, nil, &CallTarget{APIName: "/temporal.api.workflowservice.v1.WorkflowService/ListNamespaces"})
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
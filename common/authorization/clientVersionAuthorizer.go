@@ -0,0 +1,90 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blang/semver/v4"
+)
+
+type clientVersionAuthorizer struct {
+	authorizer         Authorizer
+	apis               map[string]struct{}
+	minVersions        map[string]semver.Version
+	denyUnknownClients bool
+}
+
+var _ Authorizer = (*clientVersionAuthorizer)(nil)
+
+// NewClientVersionAuthorizer creates an authorizer that denies calls to the given APIs from clients older than
+// the minimum version minVersions maps their client name to, e.g. "temporal-go" to "1.5.0", and calls from known
+// clients reporting a version that is not valid semver. Calls to the APIs from clients missing from minVersions,
+// including clients that don't report their name, are denied if denyUnknownClients is set. All other calls are
+// decided by authorizer. Fails if a minimum version is not valid semver.
+func NewClientVersionAuthorizer(
+	authorizer Authorizer,
+	apis []string,
+	minVersions map[string]string,
+	denyUnknownClients bool,
+) (Authorizer, error) {
+	a := &clientVersionAuthorizer{
+		authorizer:         authorizer,
+		apis:               make(map[string]struct{}, len(apis)),
+		minVersions:        make(map[string]semver.Version, len(minVersions)),
+		denyUnknownClients: denyUnknownClients,
+	}
+	for _, api := range apis {
+		a.apis[api] = struct{}{}
+	}
+	for clientName, version := range minVersions {
+		minVersion, err := semver.Parse(version)
+		if err != nil {
+			return nil, fmt.Errorf("invalid minimum version %q of client %q: %w", version, clientName, err)
+		}
+		a.minVersions[clientName] = minVersion
+	}
+	return a, nil
+}
+
+func (a *clientVersionAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if _, ok := a.apis[target.APIName]; ok && !a.isAllowed(target.ClientName, target.ClientVersion) {
+		return Result{Decision: DecisionDeny, Reason: ReasonClientVersion}, nil
+	}
+	return a.authorizer.Authorize(ctx, claims, target)
+}
+
+func (a *clientVersionAuthorizer) isAllowed(clientName string, clientVersion string) bool {
+	minVersion, ok := a.minVersions[clientName]
+	if !ok {
+		return !a.denyUnknownClients
+	}
+	version, err := semver.Parse(clientVersion)
+	if err != nil {
+		return false
+	}
+	return version.GTE(minVersion)
+}
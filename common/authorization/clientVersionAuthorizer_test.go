@@ -0,0 +1,125 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"go.temporal.io/server/common/headers"
+)
+
+type (
+	clientVersionAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		mockAuthorizer *MockAuthorizer
+		minVersions    map[string]string
+	}
+)
+
+func TestClientVersionAuthorizerSuite(t *testing.T) {
+	s := new(clientVersionAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *clientVersionAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(Result{Decision: DecisionAllow}, nil).AnyTimes()
+	s.minVersions = map[string]string{headers.ClientNameGoSDK: "1.5.0"}
+}
+
+func (s *clientVersionAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *clientVersionAuthorizerSuite) TestBelowMinimum() {
+	authorizer := s.newAuthorizer(false)
+	result := s.authorize(authorizer, headers.ClientNameGoSDK, "1.4.9")
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonClientVersion, result.Reason)
+}
+
+func (s *clientVersionAuthorizerSuite) TestAtMinimum() {
+	authorizer := s.newAuthorizer(false)
+	s.Equal(DecisionAllow, s.authorize(authorizer, headers.ClientNameGoSDK, "1.5.0").Decision)
+	s.Equal(DecisionAllow, s.authorize(authorizer, headers.ClientNameGoSDK, "1.10.0").Decision)
+}
+
+func (s *clientVersionAuthorizerSuite) TestInvalidVersion() {
+	authorizer := s.newAuthorizer(false)
+	s.Equal(DecisionDeny, s.authorize(authorizer, headers.ClientNameGoSDK, "").Decision)
+	s.Equal(DecisionDeny, s.authorize(authorizer, headers.ClientNameGoSDK, "latest").Decision)
+}
+
+func (s *clientVersionAuthorizerSuite) TestUnknownClientAllowed() {
+	authorizer := s.newAuthorizer(false)
+	s.Equal(DecisionAllow, s.authorize(authorizer, headers.ClientNameJavaSDK, "0.1.0").Decision)
+	s.Equal(DecisionAllow, s.authorize(authorizer, "", "").Decision)
+}
+
+func (s *clientVersionAuthorizerSuite) TestUnknownClientDenied() {
+	authorizer := s.newAuthorizer(true)
+	s.Equal(DecisionDeny, s.authorize(authorizer, headers.ClientNameJavaSDK, "0.1.0").Decision)
+	s.Equal(DecisionDeny, s.authorize(authorizer, "", "").Decision)
+	s.Equal(DecisionAllow, s.authorize(authorizer, headers.ClientNameGoSDK, "1.5.0").Decision)
+}
+
+func (s *clientVersionAuthorizerSuite) TestOtherAPIs() {
+	authorizer := s.newAuthorizer(true)
+	target := &CallTarget{APIName: describeNamespaceTarget.APIName, ClientName: headers.ClientNameGoSDK, ClientVersion: "1.4.9"}
+	result, err := authorizer.Authorize(ctx, nil, target)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *clientVersionAuthorizerSuite) TestInvalidMinimumVersion() {
+	authorizer, err := NewClientVersionAuthorizer(s.mockAuthorizer, []string{startWorkflowExecutionTarget.APIName},
+		map[string]string{headers.ClientNameGoSDK: "1.5"}, false)
+	s.Error(err)
+	s.Nil(authorizer)
+}
+
+func (s *clientVersionAuthorizerSuite) newAuthorizer(denyUnknownClients bool) Authorizer {
+	authorizer, err := NewClientVersionAuthorizer(s.mockAuthorizer, []string{startWorkflowExecutionTarget.APIName},
+		s.minVersions, denyUnknownClients)
+	s.NoError(err)
+	return authorizer
+}
+
+func (s *clientVersionAuthorizerSuite) authorize(authorizer Authorizer, clientName string, clientVersion string) Result {
+	target := &CallTarget{APIName: startWorkflowExecutionTarget.APIName, ClientName: clientName, ClientVersion: clientVersion}
+	result, err := authorizer.Authorize(ctx, nil, target)
+	s.NoError(err)
+	return result
+}
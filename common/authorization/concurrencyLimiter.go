@@ -0,0 +1,83 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"sync"
+)
+
+type (
+	// ConcurrencyLimiter limits the number of calls each subject may have in flight at the same time
+	ConcurrencyLimiter interface {
+		// Acquire reserves a slot for a call of subject. If the subject has no free slot it returns false,
+		// otherwise the returned func must be called exactly once when the call completes.
+		Acquire(subject string) (release func(), ok bool)
+	}
+
+	subjectConcurrencyLimiter struct {
+		limit int
+
+		sync.Mutex
+		inFlight map[string]int
+	}
+)
+
+var _ ConcurrencyLimiter = (*subjectConcurrencyLimiter)(nil)
+
+// NewSubjectConcurrencyLimiter creates a ConcurrencyLimiter that allows up to limit calls in flight per subject,
+// e.g. to keep a token shared across many clients from being used concurrently. Subjects are forgotten
+// as soon as they have no calls in flight, so memory use is bounded by the number of calls in flight.
+func NewSubjectConcurrencyLimiter(limit int) ConcurrencyLimiter {
+	return &subjectConcurrencyLimiter{
+		limit:    limit,
+		inFlight: make(map[string]int),
+	}
+}
+
+func (l *subjectConcurrencyLimiter) Acquire(subject string) (func(), bool) {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.inFlight[subject] >= l.limit {
+		return nil, false
+	}
+	l.inFlight[subject]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { l.release(subject) })
+	}, true
+}
+
+func (l *subjectConcurrencyLimiter) release(subject string) {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.inFlight[subject] <= 1 {
+		delete(l.inFlight, subject)
+		return
+	}
+	l.inFlight[subject]--
+}
@@ -0,0 +1,75 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubjectConcurrencyLimiter(t *testing.T) {
+	limiter := NewSubjectConcurrencyLimiter(2).(*subjectConcurrencyLimiter)
+
+	release1, ok := limiter.Acquire("alice")
+	require.True(t, ok)
+	release2, ok := limiter.Acquire("alice")
+	require.True(t, ok)
+
+	// at the limit
+	_, ok = limiter.Acquire("alice")
+	require.False(t, ok)
+
+	// other subjects are limited separately
+	releaseBob, ok := limiter.Acquire("bob")
+	require.True(t, ok)
+
+	release1()
+	release3, ok := limiter.Acquire("alice")
+	require.True(t, ok)
+
+	release2()
+	release3()
+	releaseBob()
+	require.Empty(t, limiter.inFlight)
+}
+
+func TestSubjectConcurrencyLimiterReleaseTwice(t *testing.T) {
+	limiter := NewSubjectConcurrencyLimiter(1).(*subjectConcurrencyLimiter)
+
+	release1, ok := limiter.Acquire("alice")
+	require.True(t, ok)
+	release1()
+	release2, ok := limiter.Acquire("alice")
+	require.True(t, ok)
+
+	// releasing again must not free the slot of another call
+	release1()
+	_, ok = limiter.Acquire("alice")
+	require.False(t, ok)
+
+	release2()
+	require.Empty(t, limiter.inFlight)
+}
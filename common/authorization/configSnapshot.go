@@ -0,0 +1,158 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+type (
+	// PolicySnapshotter is implemented by authorizers that can export a snapshot of their current policy
+	PolicySnapshotter interface {
+		// PolicySnapshot returns a copy of the policy in effect at the time of the call
+		PolicySnapshot() *PolicySnapshot
+	}
+
+	// PolicySnapshot is a point-in-time copy of the role-to-API policy of an authorizer. Its JSON encoding is
+	// stable: the same policy always encodes to the same bytes, so snapshots can be compared and attested.
+	PolicySnapshot struct {
+		// Roles maps role names, as used in the permissions of JWT tokens, to the sorted API names granted to
+		// the role. Policies of combined roles are named by joining the role names with "|", e.g. "read|write".
+		Roles map[string][]string `json:"roles"`
+	}
+
+	// ConfigSnapshot is a point-in-time snapshot of the authorization configuration active in an interceptor.
+	// Like PolicySnapshot, its JSON encoding is stable.
+	ConfigSnapshot struct {
+		// Policy of the authorizer, nil if the authorizer is not a PolicySnapshotter
+		Policy             *PolicySnapshot `json:"policy,omitempty"`
+		AnonymousAPIs      []string        `json:"anonymousAPIs,omitempty"`
+		WarnOnlyAPIs       []string        `json:"warnOnlyAPIs,omitempty"`
+		ReadOnly           bool            `json:"readOnly"`
+		BypassedNamespaces []string        `json:"bypassedNamespaces,omitempty"`
+	}
+
+	// ConfigInspector retrieves the active configuration of the authorization interceptor it is installed in
+	// with WithConfigInspector, e.g. to answer auditors asking which authorization rules are in effect
+	ConfigInspector struct {
+		interceptor atomic.Value // *interceptor
+	}
+)
+
+// NewConfigInspector creates a ConfigInspector, to be installed in an interceptor with WithConfigInspector
+func NewConfigInspector() *ConfigInspector {
+	return &ConfigInspector{}
+}
+
+// Snapshot returns the configuration active in the interceptor at the time of the call,
+// nil if the inspector is not installed in an interceptor
+func (i *ConfigInspector) Snapshot() *ConfigSnapshot {
+	a, ok := i.interceptor.Load().(*interceptor)
+	if !ok {
+		return nil
+	}
+	snapshot := &ConfigSnapshot{
+		AnonymousAPIs: sortedKeys(a.anonymousAPIs),
+		WarnOnlyAPIs:  sortedKeys(a.warnOnlyAPIs),
+	}
+	if snapshotter, ok := a.authorizer.(PolicySnapshotter); ok {
+		snapshot.Policy = snapshotter.PolicySnapshot()
+	}
+	if a.maintenanceMode != nil {
+		snapshot.ReadOnly = a.maintenanceMode.IsReadOnly()
+	}
+	if a.drBypass != nil {
+		if bypassed := a.drBypass.BypassedNamespaces(); len(bypassed) > 0 {
+			snapshot.BypassedNamespaces = bypassed
+		}
+	}
+	return snapshot
+}
+
+func newPolicySnapshot(policies map[Role][]string) *PolicySnapshot {
+	granted := make(map[string]map[string]struct{}, len(policies))
+	for role, apiNames := range policies {
+		name := roleName(role)
+		if granted[name] == nil {
+			granted[name] = make(map[string]struct{}, len(apiNames))
+		}
+		for _, apiName := range apiNames {
+			granted[name][apiName] = struct{}{}
+		}
+	}
+	snapshot := &PolicySnapshot{Roles: make(map[string][]string, len(granted))}
+	for name, apiNames := range granted {
+		snapshot.Roles[name] = sortedKeys(apiNames)
+	}
+	return snapshot
+}
+
+func (s *PolicySnapshot) copy() *PolicySnapshot {
+	copied := &PolicySnapshot{Roles: make(map[string][]string, len(s.Roles))}
+	for name, apiNames := range s.Roles {
+		copied.Roles[name] = append([]string(nil), apiNames...)
+	}
+	return copied
+}
+
+// roleName names the roles of the bitmask like the permissions of JWT tokens, joined with "|".
+// Roles without a permission name are named by their value.
+func roleName(role Role) string {
+	var names []string
+	for _, r := range []struct {
+		role Role
+		name string
+	}{
+		{RoleWorker, permissionWorker},
+		{RoleReader, permissionRead},
+		{RoleWriter, permissionWrite},
+		{RoleAdmin, permissionAdmin},
+		{RoleImpersonator, "impersonator"},
+	} {
+		if role&r.role != 0 {
+			names = append(names, r.name)
+			role &^= r.role
+		}
+	}
+	if role != RoleUndefined || len(names) == 0 {
+		names = append(names, strconv.Itoa(int(role)))
+	}
+	return strings.Join(names, "|")
+}
+
+func sortedKeys(set map[string]struct{}) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,96 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+
+	"go.temporal.io/server/common/log/loggerimpl"
+	"go.temporal.io/server/common/metrics"
+)
+
+func TestConfigInspector(t *testing.T) {
+	authorizer := NewStaticAuthorizer(map[Role][]string{RoleReader: {describeNamespaceTarget.APIName}})
+	maintenanceMode := NewMaintenanceMode()
+	bypass := NewDisasterRecoveryBypass()
+	inspector := NewConfigInspector()
+	require.Nil(t, inspector.Snapshot())
+
+	NewAuthorizationInterceptor(
+		nil,
+		authorizer,
+		metrics.NewClient(tally.NoopScope, metrics.Frontend),
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithAnonymousAPIs(startWorkflowExecutionTarget.APIName, describeNamespaceTarget.APIName),
+		WithMaintenanceMode(maintenanceMode),
+		WithUnsafeDisasterRecoveryBypass(bypass),
+		WithConfigInspector(inspector))
+
+	require.Equal(t, &ConfigSnapshot{
+		Policy:        &PolicySnapshot{Roles: map[string][]string{"read": {describeNamespaceTarget.APIName}}},
+		AnonymousAPIs: []string{describeNamespaceTarget.APIName, startWorkflowExecutionTarget.APIName},
+	}, inspector.Snapshot())
+
+	// the snapshot reflects runtime updates
+	authorizer.UpdatePolicies(map[Role][]string{RoleWriter: {startWorkflowExecutionTarget.APIName}})
+	maintenanceMode.SetReadOnly(true)
+	bypass.UnsafeBypassNamespace(testNamespace)
+	require.Equal(t, &ConfigSnapshot{
+		Policy:             &PolicySnapshot{Roles: map[string][]string{"write": {startWorkflowExecutionTarget.APIName}}},
+		AnonymousAPIs:      []string{describeNamespaceTarget.APIName, startWorkflowExecutionTarget.APIName},
+		ReadOnly:           true,
+		BypassedNamespaces: []string{testNamespace},
+	}, inspector.Snapshot())
+}
+
+func TestConfigSnapshotStableJSON(t *testing.T) {
+	policies := map[Role][]string{
+		RoleReader: {"b", "a", "c"},
+		RoleWriter: {"c", "a"},
+		RoleAdmin:  {"*"},
+	}
+	expected, err := json.Marshal(&ConfigSnapshot{Policy: newPolicySnapshot(policies)})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"policy":{"roles":{"read":["a","b","c"],"write":["a","c"],"admin":["*"]}},"readOnly":false}`, string(expected))
+
+	for i := 0; i < 10; i++ {
+		encoded, err := json.Marshal(&ConfigSnapshot{Policy: NewTrieAuthorizer(policies).PolicySnapshot()})
+		require.NoError(t, err)
+		require.Equal(t, string(expected), string(encoded))
+	}
+}
+
+func TestRoleName(t *testing.T) {
+	require.Equal(t, "read", roleName(RoleReader))
+	require.Equal(t, "worker|admin", roleName(RoleWorker|RoleAdmin))
+	require.Equal(t, "impersonator", roleName(RoleImpersonator))
+	require.Equal(t, "0", roleName(RoleUndefined))
+	require.Equal(t, "read|64", roleName(RoleReader|Role(64)))
+}
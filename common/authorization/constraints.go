@@ -0,0 +1,110 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"sort"
+
+	"go.temporal.io/api/workflowservice/v1"
+)
+
+// Constraints limit the results of an allowed call. Handlers of list and search APIs
+// retrieve them with ConstraintsFromContext and filter their results accordingly.
+type Constraints struct {
+	// Namespaces whose entities may be returned, nil means any namespace
+	Namespaces []string
+}
+
+// ConstraintsFromContext returns the constraints the authorizer attached to the call, or nil if there are none
+func ConstraintsFromContext(ctx context.Context) *Constraints {
+	constraints, _ := ctx.Value(ContextKeyConstraints).(*Constraints)
+	return constraints
+}
+
+// NamespaceConstraints returns the constraints that limit the results of a call to the namespaces
+// the caller holds a role in, or nil if the caller holds a system role and may see all namespaces.
+// Authorizers can attach them to calls without a target namespace, such as ListNamespaces.
+func NamespaceConstraints(claims *Claims) *Constraints {
+	if claims != nil && claims.System != RoleUndefined {
+		return nil
+	}
+	namespaces := []string{}
+	if claims != nil {
+		for namespace, role := range claims.Namespaces {
+			if role != RoleUndefined {
+				namespaces = append(namespaces, namespace)
+			}
+		}
+	}
+	sort.Strings(namespaces)
+	return &Constraints{Namespaces: namespaces}
+}
+
+// AllowsNamespace checks if results from the namespace may be returned
+func (c *Constraints) AllowsNamespace(namespace string) bool {
+	if c == nil || c.Namespaces == nil {
+		return true
+	}
+	for _, ns := range c.Namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// Equal reports whether both constraints permit the same namespaces in the same order
+func (c *Constraints) Equal(other *Constraints) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+	if (c.Namespaces == nil) != (other.Namespaces == nil) || len(c.Namespaces) != len(other.Namespaces) {
+		return false
+	}
+	for i := range c.Namespaces {
+		if c.Namespaces[i] != other.Namespaces[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterNamespaces returns the namespaces whose results may be returned, in their original order.
+// Handlers of ListNamespaces use it to return the namespaces visible to the caller instead of denying the call.
+func (c *Constraints) FilterNamespaces(
+	namespaces []*workflowservice.DescribeNamespaceResponse,
+) []*workflowservice.DescribeNamespaceResponse {
+	if c == nil || c.Namespaces == nil {
+		return namespaces
+	}
+	filtered := make([]*workflowservice.DescribeNamespaceResponse, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		if c.AllowsNamespace(namespace.GetNamespaceInfo().GetName()) {
+			filtered = append(filtered, namespace)
+		}
+	}
+	return filtered
+}
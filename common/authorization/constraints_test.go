@@ -0,0 +1,87 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	namespacepb "go.temporal.io/api/namespace/v1"
+	"go.temporal.io/api/workflowservice/v1"
+)
+
+func TestConstraintsAllowsNamespace(t *testing.T) {
+	var unconstrained *Constraints
+	require.True(t, unconstrained.AllowsNamespace(testNamespace))
+	require.True(t, (&Constraints{}).AllowsNamespace(testNamespace))
+
+	constraints := &Constraints{Namespaces: []string{"foo", testNamespace}}
+	require.True(t, constraints.AllowsNamespace(testNamespace))
+	require.False(t, constraints.AllowsNamespace("bar"))
+	require.False(t, (&Constraints{Namespaces: []string{}}).AllowsNamespace(testNamespace))
+}
+
+func TestConstraintsEqual(t *testing.T) {
+	var unconstrained *Constraints
+	require.True(t, unconstrained.Equal(nil))
+	require.False(t, unconstrained.Equal(&Constraints{}))
+	require.True(t, (&Constraints{Namespaces: []string{"foo"}}).Equal(&Constraints{Namespaces: []string{"foo"}}))
+	require.False(t, (&Constraints{Namespaces: []string{"foo"}}).Equal(&Constraints{Namespaces: []string{"bar"}}))
+	require.False(t, (&Constraints{}).Equal(&Constraints{Namespaces: []string{}}))
+}
+
+func TestConstraintsFromContext(t *testing.T) {
+	require.Nil(t, ConstraintsFromContext(context.Background()))
+
+	constraints := &Constraints{Namespaces: []string{testNamespace}}
+	ctx := context.WithValue(context.Background(), ContextKeyConstraints, constraints)
+	require.Equal(t, constraints, ConstraintsFromContext(ctx))
+}
+
+func TestConstraintsFilterNamespaces(t *testing.T) {
+	var namespaces []*workflowservice.DescribeNamespaceResponse
+	for _, name := range []string{"foo", testNamespace, "bar", "baz"} {
+		namespaces = append(namespaces, &workflowservice.DescribeNamespaceResponse{
+			NamespaceInfo: &namespacepb.NamespaceInfo{Name: name},
+		})
+	}
+
+	var unconstrained *Constraints
+	require.Equal(t, namespaces, unconstrained.FilterNamespaces(namespaces))
+
+	filtered := (&Constraints{Namespaces: []string{"baz", testNamespace, "other"}}).FilterNamespaces(namespaces)
+	require.Equal(t, []*workflowservice.DescribeNamespaceResponse{namespaces[1], namespaces[3]}, filtered)
+
+	require.Empty(t, (&Constraints{Namespaces: []string{}}).FilterNamespaces(namespaces))
+}
+
+func TestNamespaceConstraints(t *testing.T) {
+	require.Nil(t, NamespaceConstraints(&Claims{System: RoleReader}))
+	require.Equal(t, &Constraints{Namespaces: []string{}}, NamespaceConstraints(nil))
+	require.Equal(t, &Constraints{Namespaces: []string{"bar", testNamespace}}, NamespaceConstraints(&Claims{
+		Namespaces: map[string]Role{testNamespace: RoleWorker, "bar": RoleReader, "foo": RoleUndefined},
+	}))
+}
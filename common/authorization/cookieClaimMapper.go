@@ -0,0 +1,68 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"net/http"
+)
+
+type (
+	// CookieValidator parses and validates the value of a session cookie and returns the claims of its subject
+	CookieValidator func(value string) (*Claims, error)
+
+	cookieClaimMapper struct {
+		cookieName string
+		validator  CookieValidator
+	}
+)
+
+var _ ClaimMapper = (*cookieClaimMapper)(nil)
+
+// NewCookieClaimMapper creates a claim mapper for browser based access that maps the claims from the session cookie
+// with the given name, as forwarded by grpc-web in the cookie header. Callers without the cookie get empty claims,
+// calls with a cookie rejected by validator fail with its error.
+func NewCookieClaimMapper(cookieName string, validator CookieValidator) ClaimMapper {
+	return &cookieClaimMapper{
+		cookieName: cookieName,
+		validator:  validator,
+	}
+}
+
+func (m *cookieClaimMapper) GetClaims(_ context.Context, authInfo *AuthInfo) (*Claims, error) {
+	if authInfo.Cookie == "" {
+		return &Claims{}, nil
+	}
+	// reuse the cookie parsing of net/http, which skips malformed cookies
+	request := http.Request{Header: http.Header{"Cookie": []string{authInfo.Cookie}}}
+	cookie, err := request.Cookie(m.cookieName)
+	if err == http.ErrNoCookie {
+		return &Claims{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m.validator(cookie.Value)
+}
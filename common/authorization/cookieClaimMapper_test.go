@@ -0,0 +1,100 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.temporal.io/api/serviceerror"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	testCookieName  = "temporal-session"
+	testSessionID   = "valid-session"
+	testCookieValue = testCookieName + "=" + testSessionID
+)
+
+var errInvalidSession = serviceerror.NewPermissionDenied("invalid session")
+
+type (
+	cookieClaimMapperSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		claimMapper ClaimMapper
+	}
+)
+
+func TestCookieClaimMapperSuite(t *testing.T) {
+	s := new(cookieClaimMapperSuite)
+	suite.Run(t, s)
+}
+
+func (s *cookieClaimMapperSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.claimMapper = NewCookieClaimMapper(testCookieName, func(value string) (*Claims, error) {
+		if value != testSessionID {
+			return nil, errInvalidSession
+		}
+		return &Claims{Subject: testSubject, Namespaces: map[string]Role{testNamespace: RoleReader}}, nil
+	})
+}
+
+func (s *cookieClaimMapperSuite) TestCookiePresent() {
+	claims, err := s.claimMapper.GetClaims(ctx, &AuthInfo{Cookie: "theme=dark; " + testCookieValue})
+	s.NoError(err)
+	s.Equal(testSubject, claims.Subject)
+	s.Equal(RoleReader, claims.Namespaces[testNamespace])
+}
+
+func (s *cookieClaimMapperSuite) TestCookieFromMetadata() {
+	md := metadata.Pairs("cookie", "theme=dark", "cookie", testCookieValue)
+	authInfo := NewAuthInfoFromContext(metadata.NewIncomingContext(ctx, md))
+	s.NotNil(authInfo)
+
+	claims, err := s.claimMapper.GetClaims(ctx, authInfo)
+	s.NoError(err)
+	s.Equal(testSubject, claims.Subject)
+}
+
+func (s *cookieClaimMapperSuite) TestCookieAbsent() {
+	claims, err := s.claimMapper.GetClaims(ctx, &AuthInfo{Cookie: "theme=dark"})
+	s.NoError(err)
+	s.Equal(&Claims{}, claims)
+
+	claims, err = s.claimMapper.GetClaims(ctx, &AuthInfo{AuthToken: "Bearer token"})
+	s.NoError(err)
+	s.Equal(&Claims{}, claims)
+}
+
+func (s *cookieClaimMapperSuite) TestCookieInvalid() {
+	claims, err := s.claimMapper.GetClaims(ctx, &AuthInfo{Cookie: testCookieName + "=forged-session"})
+	s.True(errors.Is(err, errInvalidSession))
+	s.Nil(claims)
+}
@@ -0,0 +1,93 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+)
+
+type (
+	costBudgetAuthorizer struct {
+		authorizer Authorizer
+		budgets    map[Role]int
+	}
+
+	sizer interface {
+		Size() int
+	}
+)
+
+var _ Authorizer = (*costBudgetAuthorizer)(nil)
+
+// NewCostBudgetAuthorizer creates an authorizer that denies requests whose CallTarget.Cost exceeds the budget
+// of the caller's role in the target namespace, see Claims.GrantedRoles, and delegates all other requests to authorizer.
+// A caller holding several roles gets the largest of their budgets. Roles without a budget are not limited,
+// callers without any role get the budget of RoleUndefined.
+func NewCostBudgetAuthorizer(authorizer Authorizer, budgets map[Role]int) Authorizer {
+	return &costBudgetAuthorizer{
+		authorizer: authorizer,
+		budgets:    budgets,
+	}
+}
+
+func (a *costBudgetAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if budget, limited := roleBudget(a.budgets, claims, target.Namespace); limited && target.Cost > budget {
+		return Result{Decision: DecisionDeny, Reason: ReasonCostExceeded}, nil
+	}
+	return a.authorizer.Authorize(ctx, claims, target)
+}
+
+// roleBudget returns the largest of the budgets of the caller's roles in namespace,
+// or false if one of the roles is not limited
+func roleBudget(budgets map[Role]int, claims *Claims, namespace string) (int, bool) {
+	roles := claims.GrantedRoles(namespace)
+	if roles == RoleUndefined {
+		budget, limited := budgets[RoleUndefined]
+		return budget, limited
+	}
+
+	budget := 0
+	for role := Role(1); role != 0 && role <= roles; role <<= 1 {
+		if roles&role == 0 {
+			continue
+		}
+		roleBudget, limited := budgets[role]
+		if !limited {
+			return 0, false
+		}
+		if roleBudget > budget {
+			budget = roleBudget
+		}
+	}
+	return budget, true
+}
+
+// RequestSize is a RequestCostFunc that uses the serialized size of the request as its cost
+func RequestSize(_ string, req interface{}) int {
+	if r, ok := req.(sizer); ok {
+		return r.Size()
+	}
+	return 0
+}
@@ -0,0 +1,119 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.temporal.io/api/workflowservice/v1"
+)
+
+type (
+	costBudgetAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		mockAuthorizer *MockAuthorizer
+		authorizer     Authorizer
+	}
+)
+
+func TestCostBudgetAuthorizerSuite(t *testing.T) {
+	s := new(costBudgetAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *costBudgetAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.authorizer = NewCostBudgetAuthorizer(s.mockAuthorizer, map[Role]int{
+		RoleUndefined: 0,
+		RoleWorker:    100,
+		RoleWriter:    1000,
+	})
+}
+
+func (s *costBudgetAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *costBudgetAuthorizerSuite) TestUnderBudget() {
+	claims := &Claims{Namespaces: map[string]Role{testNamespace: RoleWorker}}
+	target := &CallTarget{Namespace: testNamespace, Cost: 100}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, target).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, target)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *costBudgetAuthorizerSuite) TestOverBudget() {
+	claims := &Claims{Namespaces: map[string]Role{testNamespace: RoleWorker}}
+	result, err := s.authorizer.Authorize(ctx, claims, &CallTarget{Namespace: testNamespace, Cost: 101})
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonCostExceeded, result.Reason)
+}
+
+func (s *costBudgetAuthorizerSuite) TestLargestBudgetOfHeldRoles() {
+	claims := &Claims{System: RoleWriter, Namespaces: map[string]Role{testNamespace: RoleWorker}}
+	target := &CallTarget{Namespace: testNamespace, Cost: 1000}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, target).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, target)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+
+	result, err = s.authorizer.Authorize(ctx, claims, &CallTarget{Namespace: testNamespace, Cost: 1001})
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+}
+
+func (s *costBudgetAuthorizerSuite) TestUnlimitedRole() {
+	claims := &Claims{Namespaces: map[string]Role{testNamespace: RoleWorker | RoleAdmin}}
+	target := &CallTarget{Namespace: testNamespace, Cost: 1 << 20}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, target).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, target)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *costBudgetAuthorizerSuite) TestNoRole() {
+	result, err := s.authorizer.Authorize(ctx, nil, &CallTarget{Namespace: testNamespace, Cost: 1})
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+}
+
+func (s *costBudgetAuthorizerSuite) TestRequestSize() {
+	request := &workflowservice.StartWorkflowExecutionRequest{Namespace: testNamespace}
+	s.Equal(request.Size(), RequestSize("", request))
+	s.Equal(0, RequestSize("", "not a proto"))
+}
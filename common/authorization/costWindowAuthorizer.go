@@ -0,0 +1,167 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/metrics"
+)
+
+type (
+	// CostWindowConfig configures the authorizer created by NewCostWindowAuthorizer
+	CostWindowConfig struct {
+		// Budgets is the total cost each role may spend in a namespace within Window
+		Budgets map[Role]int
+		// Window is the period after which a subject's spent budget is replenished
+		Window time.Duration
+		// MaxSubjects bounds the number of tracked subjects, the least recently active are evicted first.
+		// Zero disables tracking.
+		MaxSubjects int
+		// Cost computes the cost of a call, defaults to CallTarget.Cost
+		Cost func(target *CallTarget) int
+	}
+
+	costWindowAuthorizer struct {
+		authorizer    Authorizer
+		config        CostWindowConfig
+		metricsClient metrics.Client
+		timeSource    clock.TimeSource
+
+		sync.Mutex
+		usage    map[costWindowKey]*list.Element // values are *costWindowUsage
+		byAccess *list.List                      // most recently active subject first
+	}
+
+	costWindowKey struct {
+		subject   string
+		namespace string
+	}
+
+	// costWindowUsage tracks the cost a subject spent in a namespace within the current window
+	costWindowUsage struct {
+		key         costWindowKey
+		windowStart time.Time
+		lastAccess  time.Time
+		spent       int
+	}
+)
+
+var _ Authorizer = (*costWindowAuthorizer)(nil)
+
+// NewCostWindowAuthorizer creates an authorizer that accumulates the cost of the calls each subject makes
+// to a namespace, and denies calls once the budget of the caller's role for the current window is spent.
+// Delegated calls are charged to the end user.
+// Budgets are selected as by NewCostBudgetAuthorizer, callers without credentials share a single budget.
+// All other calls are decided by authorizer. Denied calls fail with a ResourceExhausted error.
+// Subjects idle for longer than the window are forgotten, so memory use is bounded by MaxSubjects.
+func NewCostWindowAuthorizer(
+	authorizer Authorizer,
+	config CostWindowConfig,
+	metricsClient metrics.Client,
+	timeSource clock.TimeSource,
+) Authorizer {
+	if config.Cost == nil {
+		config.Cost = func(target *CallTarget) int { return target.Cost }
+	}
+	return &costWindowAuthorizer{
+		authorizer:    authorizer,
+		config:        config,
+		metricsClient: metricsClient,
+		timeSource:    timeSource,
+		usage:         make(map[costWindowKey]*list.Element),
+		byAccess:      list.New(),
+	}
+}
+
+func (a *costWindowAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	budget, limited := roleBudget(a.config.Budgets, claims, target.Namespace)
+	if limited && a.config.MaxSubjects > 0 {
+		var subject string
+		if claims != nil {
+			subject = claims.EffectiveClaims().Subject
+		}
+		spent, ok := a.spend(costWindowKey{subject: subject, namespace: target.Namespace}, a.config.Cost(target), budget)
+		scope := a.metricsClient.Scope(metrics.AuthorizationScope, metrics.NamespaceTag(target.Namespace))
+		if budget > 0 {
+			scope.UpdateGauge(metrics.ServiceAuthorizationCostBudgetUsageGauge, float64(spent)/float64(budget))
+		}
+		if !ok {
+			scope.IncCounter(metrics.ServiceAuthorizationCostBudgetExhaustedCounter)
+			return Result{Decision: DecisionDeny, Reason: ReasonBudgetExhausted}, nil
+		}
+	}
+	return a.authorizer.Authorize(ctx, claims, target)
+}
+
+// spend adds cost to the budget spent under key in the current window and returns the total spent.
+// It returns false if the total would exceed budget, in which case the cost is not added.
+func (a *costWindowAuthorizer) spend(key costWindowKey, cost int, budget int) (int, bool) {
+	now := a.timeSource.Now()
+
+	a.Lock()
+	defer a.Unlock()
+
+	a.expireIdleSubjects(now.Add(-a.config.Window))
+	element, ok := a.usage[key]
+	if ok {
+		a.byAccess.MoveToFront(element)
+	} else {
+		if len(a.usage) >= a.config.MaxSubjects {
+			oldest := a.byAccess.Remove(a.byAccess.Back()).(*costWindowUsage)
+			delete(a.usage, oldest.key)
+		}
+		element = a.byAccess.PushFront(&costWindowUsage{key: key, windowStart: now})
+		a.usage[key] = element
+	}
+
+	usage := element.Value.(*costWindowUsage)
+	usage.lastAccess = now
+	if !now.Before(usage.windowStart.Add(a.config.Window)) {
+		usage.windowStart = now
+		usage.spent = 0
+	}
+	if usage.spent+cost > budget {
+		return usage.spent, false
+	}
+	usage.spent += cost
+	return usage.spent, true
+}
+
+// expireIdleSubjects forgets the subjects that have not made any call since windowStart
+func (a *costWindowAuthorizer) expireIdleSubjects(windowStart time.Time) {
+	for element := a.byAccess.Back(); element != nil; element = a.byAccess.Back() {
+		usage := element.Value.(*costWindowUsage)
+		if !usage.lastAccess.Before(windowStart) {
+			return
+		}
+		a.byAccess.Remove(element)
+		delete(a.usage, usage.key)
+	}
+}
@@ -0,0 +1,198 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/metrics"
+)
+
+type (
+	costWindowAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller        *gomock.Controller
+		mockAuthorizer    *MockAuthorizer
+		mockMetricsClient *metrics.MockClient
+		mockMetricsScope  *metrics.MockScope
+		timeSource        *clock.EventTimeSource
+		config            CostWindowConfig
+	}
+)
+
+func TestCostWindowAuthorizerSuite(t *testing.T) {
+	s := new(costWindowAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *costWindowAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.mockMetricsClient = metrics.NewMockClient(s.controller)
+	s.mockMetricsScope = metrics.NewMockScope(s.controller)
+	s.timeSource = clock.NewEventTimeSource().Update(time.Unix(0, 0))
+	s.config = CostWindowConfig{
+		Budgets:     map[Role]int{RoleUndefined: 10, RoleReader: 100},
+		Window:      time.Minute,
+		MaxSubjects: 10,
+	}
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(Result{Decision: DecisionAllow}, nil).AnyTimes()
+	s.mockMetricsClient.EXPECT().Scope(metrics.AuthorizationScope, gomock.Any()).Return(s.mockMetricsScope).AnyTimes()
+	s.mockMetricsScope.EXPECT().UpdateGauge(metrics.ServiceAuthorizationCostBudgetUsageGauge, gomock.Any()).AnyTimes()
+}
+
+func (s *costWindowAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *costWindowAuthorizerSuite) TestExhaustAndReplenish() {
+	authorizer := NewCostWindowAuthorizer(s.mockAuthorizer, s.config, s.mockMetricsClient, s.timeSource)
+	for i := 0; i < 4; i++ {
+		s.assertDecision(authorizer, "alice", testNamespace, 25, DecisionAllow)
+	}
+
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceAuthorizationCostBudgetExhaustedCounter).Times(2)
+	result := s.assertDecision(authorizer, "alice", testNamespace, 1, DecisionDeny)
+	s.Equal(ReasonBudgetExhausted, result.Reason)
+
+	// other subjects and namespaces have their own budget
+	s.assertDecision(authorizer, "bob", testNamespace, 100, DecisionAllow)
+	s.assertDecision(authorizer, "alice", "other-namespace", 10, DecisionAllow)
+
+	s.timeSource.Update(time.Unix(59, 0))
+	s.assertDecision(authorizer, "alice", testNamespace, 1, DecisionDeny)
+
+	s.timeSource.Update(time.Unix(60, 0))
+	s.assertDecision(authorizer, "alice", testNamespace, 100, DecisionAllow)
+}
+
+func (s *costWindowAuthorizerSuite) TestDeniedCostIsNotSpent() {
+	authorizer := NewCostWindowAuthorizer(s.mockAuthorizer, s.config, s.mockMetricsClient, s.timeSource)
+	s.assertDecision(authorizer, "alice", testNamespace, 90, DecisionAllow)
+
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceAuthorizationCostBudgetExhaustedCounter)
+	s.assertDecision(authorizer, "alice", testNamespace, 20, DecisionDeny)
+	s.assertDecision(authorizer, "alice", testNamespace, 10, DecisionAllow)
+}
+
+func (s *costWindowAuthorizerSuite) TestUsageGauge() {
+	s.mockMetricsClient = metrics.NewMockClient(s.controller)
+	s.mockMetricsScope = metrics.NewMockScope(s.controller)
+	s.mockMetricsClient.EXPECT().Scope(metrics.AuthorizationScope, metrics.NamespaceTag(testNamespace)).Return(s.mockMetricsScope).Times(2)
+	authorizer := NewCostWindowAuthorizer(s.mockAuthorizer, s.config, s.mockMetricsClient, s.timeSource)
+	gomock.InOrder(
+		s.mockMetricsScope.EXPECT().UpdateGauge(metrics.ServiceAuthorizationCostBudgetUsageGauge, 0.25),
+		s.mockMetricsScope.EXPECT().UpdateGauge(metrics.ServiceAuthorizationCostBudgetUsageGauge, 0.75),
+	)
+	s.assertDecision(authorizer, "alice", testNamespace, 25, DecisionAllow)
+	s.assertDecision(authorizer, "alice", testNamespace, 50, DecisionAllow)
+}
+
+func (s *costWindowAuthorizerSuite) TestCostFunc() {
+	s.config.Cost = func(target *CallTarget) int {
+		if target.APIName == startWorkflowExecutionTarget.APIName {
+			return 60
+		}
+		return 1
+	}
+	authorizer := NewCostWindowAuthorizer(s.mockAuthorizer, s.config, s.mockMetricsClient, s.timeSource)
+	claims := &Claims{Subject: "alice", Namespaces: map[string]Role{testNamespace: RoleReader}}
+
+	result, err := authorizer.Authorize(ctx, claims, startWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceAuthorizationCostBudgetExhaustedCounter)
+	result, err = authorizer.Authorize(ctx, claims, startWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+
+	result, err = authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *costWindowAuthorizerSuite) TestRoleBudgets() {
+	authorizer := NewCostWindowAuthorizer(s.mockAuthorizer, s.config, s.mockMetricsClient, s.timeSource)
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceAuthorizationCostBudgetExhaustedCounter)
+	result, err := authorizer.Authorize(ctx, &Claims{Subject: "alice"}, &CallTarget{Namespace: testNamespace, Cost: 11})
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+
+	// writers are not limited
+	claims := &Claims{Subject: "bob", Namespaces: map[string]Role{testNamespace: RoleReader | RoleWriter}}
+	for i := 0; i < 3; i++ {
+		result, err = authorizer.Authorize(ctx, claims, &CallTarget{Namespace: testNamespace, Cost: 1000})
+		s.NoError(err)
+		s.Equal(DecisionAllow, result.Decision)
+	}
+}
+
+func (s *costWindowAuthorizerSuite) TestIdleSubjectsExpire() {
+	authorizer := NewCostWindowAuthorizer(s.mockAuthorizer, s.config, s.mockMetricsClient, s.timeSource).(*costWindowAuthorizer)
+	s.assertDecision(authorizer, "alice", testNamespace, 1, DecisionAllow)
+	s.assertDecision(authorizer, "bob", testNamespace, 1, DecisionAllow)
+	s.Len(authorizer.usage, 2)
+
+	s.timeSource.Update(time.Unix(61, 0))
+	s.assertDecision(authorizer, "carol", testNamespace, 1, DecisionAllow)
+	s.Len(authorizer.usage, 1)
+	s.Equal(1, authorizer.byAccess.Len())
+}
+
+func (s *costWindowAuthorizerSuite) TestMaxSubjects() {
+	s.config.MaxSubjects = 2
+	authorizer := NewCostWindowAuthorizer(s.mockAuthorizer, s.config, s.mockMetricsClient, s.timeSource).(*costWindowAuthorizer)
+	for i := 0; i < 5; i++ {
+		s.assertDecision(authorizer, fmt.Sprintf("subject-%d", i), testNamespace, 1, DecisionAllow)
+	}
+	s.Len(authorizer.usage, 2)
+	s.Equal(2, authorizer.byAccess.Len())
+}
+
+func (s *costWindowAuthorizerSuite) assertDecision(
+	authorizer Authorizer,
+	subject string,
+	namespace string,
+	cost int,
+	decision Decision,
+) Result {
+	claims := &Claims{Subject: subject, Namespaces: map[string]Role{namespace: RoleReader}}
+	result, err := authorizer.Authorize(ctx, claims, &CallTarget{Namespace: namespace, Cost: cost})
+	s.NoError(err)
+	s.Equal(decision, result.Decision)
+	return result
+}
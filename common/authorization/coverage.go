@@ -0,0 +1,55 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+)
+
+// coverageSubject is the subject ValidateCoverage authorizes APIs for
+const coverageSubject = "temporal-coverage-validation"
+
+// ValidateCoverage returns the full names of the APIs among apiNames that authorizer leaves unclassified,
+// i.e. for which it decides DecisionUnknown or fails, in the order of apiNames. If apiNames is nil all APIs
+// of the workflow and admin services are validated. It is meant to run at startup or in CI to verify that
+// a policy, such as that of NewStaticAuthorizer, explicitly covers every API. Each API is authorized for
+// a caller without roles and without a target namespace, so decorators that decide on the caller alone,
+// such as its audience, should not wrap authorizer.
+func ValidateCoverage(authorizer Authorizer, apiNames []string) []string {
+	if apiNames == nil {
+		apiNames = make([]string, 0, len(WorkflowServiceAPIs)+len(AdminServiceAPIs))
+		apiNames = append(apiNames, WorkflowServiceAPIs...)
+		apiNames = append(apiNames, AdminServiceAPIs...)
+	}
+	var unclassified []string
+	for _, apiName := range apiNames {
+		target := &CallTarget{APIName: apiName, APIGroup: GetAPIGroup(apiName)}
+		result, err := authorizer.Authorize(context.Background(), &Claims{Subject: coverageSubject}, target)
+		if err != nil || result.Decision == DecisionUnknown {
+			unclassified = append(unclassified, apiName)
+		}
+	}
+	return unclassified
+}
@@ -0,0 +1,131 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	coverageSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		mockAuthorizer *MockAuthorizer
+	}
+)
+
+func TestCoverageSuite(t *testing.T) {
+	s := new(coverageSuite)
+	suite.Run(t, s)
+}
+
+func (s *coverageSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+}
+
+func (s *coverageSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *coverageSuite) TestUnclassifiedAPIs() {
+	// the policy only classifies reads
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), gomock.Not(gomock.Nil()), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *Claims, target *CallTarget) (Result, error) {
+			if target.APIGroup == APIGroupRead {
+				return Result{Decision: DecisionAllow}, nil
+			}
+			return Result{}, nil
+		}).Times(3)
+
+	unclassified := ValidateCoverage(s.mockAuthorizer, []string{
+		startWorkflowExecutionTarget.APIName,
+		describeNamespaceTarget.APIName,
+		workflowServicePrefix + "RegisterNamespace",
+	})
+	s.Equal([]string{startWorkflowExecutionTarget.APIName, workflowServicePrefix + "RegisterNamespace"}, unclassified)
+}
+
+func (s *coverageSuite) TestDeniedAPIsAreClassified() {
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), gomock.Not(gomock.Nil()), gomock.Any()).
+		Return(Result{Decision: DecisionDeny}, nil).Times(2)
+
+	s.Empty(ValidateCoverage(s.mockAuthorizer, []string{startWorkflowExecutionTarget.APIName, describeNamespaceTarget.APIName}))
+}
+
+func (s *coverageSuite) TestFailingAPIsAreUnclassified() {
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), gomock.Not(gomock.Nil()), gomock.Any()).
+		Return(Result{Decision: DecisionAllow}, errors.New("policy error"))
+
+	s.Equal([]string{describeNamespaceTarget.APIName}, ValidateCoverage(s.mockAuthorizer, []string{describeNamespaceTarget.APIName}))
+}
+
+func (s *coverageSuite) TestAllAPIs() {
+	var validated []string
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), gomock.Not(gomock.Nil()), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *Claims, target *CallTarget) (Result, error) {
+			validated = append(validated, target.APIName)
+			if target.APIName == describeNamespaceTarget.APIName {
+				return Result{}, nil
+			}
+			return Result{Decision: DecisionAllow}, nil
+		}).AnyTimes()
+
+	s.Equal([]string{describeNamespaceTarget.APIName}, ValidateCoverage(s.mockAuthorizer, nil))
+	s.Equal(append(append([]string{}, WorkflowServiceAPIs...), AdminServiceAPIs...), validated)
+}
+
+func (s *coverageSuite) TestEmptyPolicy() {
+	s.Equal(append(append([]string{}, WorkflowServiceAPIs...), AdminServiceAPIs...),
+		ValidateCoverage(NewStaticAuthorizer(map[Role][]string{}), nil))
+}
+
+func (s *coverageSuite) TestPartialPolicy() {
+	policies := map[Role][]string{
+		RoleReader: {apiGroupPrefix + string(APIGroupRead)},
+		RoleWriter: {apiGroupPrefix + string(APIGroupWrite)},
+		RoleAdmin:  {adminServicePrefix + "*"},
+	}
+	expected := []string{
+		workflowServicePrefix + "DeprecateNamespace",
+		workflowServicePrefix + "RegisterNamespace",
+		workflowServicePrefix + "UpdateNamespace",
+	}
+	for _, authorizer := range []Authorizer{NewStaticAuthorizer(policies), NewTrieAuthorizer(policies)} {
+		s.Equal(expected, ValidateCoverage(authorizer, nil))
+	}
+
+	policies[RoleAdmin] = append(policies[RoleAdmin], apiGroupPrefix+string(APIGroupAdmin))
+	s.Empty(ValidateCoverage(NewStaticAuthorizer(policies), nil))
+}
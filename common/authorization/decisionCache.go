@@ -0,0 +1,186 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.temporal.io/server/common/clock"
+)
+
+type (
+	// DecisionCacheKey identifies the calls an authorization decision can be reused for.
+	// It is a comparable composite of the fields rather than a hash of them, so distinct calls never share a key
+	// of the in-memory cache. Shared stores use String, or Hash where the length of keys is limited.
+	// Subject, Issuer and TenantID are those of the end user, Actor is the subject of the caller of delegated calls.
+	DecisionCacheKey struct {
+		Subject    string
+		Issuer     string
+		TenantID   string
+		Actor      string
+		APIName    string
+		Namespace  string
+		WorkflowID string
+		RunID      string
+		ActivityID string
+	}
+
+	// DecisionCache stores authorization decisions for the authorizer created by NewCachingAuthorizer.
+	// Implementations backed by a shared store, such as Redis, let frontend replicas reuse each other's decisions.
+	DecisionCache interface {
+		// Get returns the decision stored for key, and false if there is none or it expired
+		Get(ctx context.Context, key DecisionCacheKey) (Result, bool, error)
+		// Set stores the decision for key for the duration of ttl
+		Set(ctx context.Context, key DecisionCacheKey, result Result, ttl time.Duration) error
+		// Invalidate removes all decisions stored for subject, as end user or actor, e.g. after the subject's roles changed
+		Invalidate(ctx context.Context, subject string) error
+	}
+
+	lruDecisionCache struct {
+		maxSize    int
+		timeSource clock.TimeSource
+
+		sync.Mutex
+		entries  map[DecisionCacheKey]*list.Element // values are *decisionCacheEntry
+		byAccess *list.List                         // most recently used entry first
+	}
+
+	decisionCacheEntry struct {
+		key       DecisionCacheKey
+		result    Result
+		expiresAt time.Time
+	}
+)
+
+var _ DecisionCache = (*lruDecisionCache)(nil)
+
+// newDecisionCacheKey creates the cache key of a call
+func newDecisionCacheKey(claims *Claims, target *CallTarget) DecisionCacheKey {
+	key := DecisionCacheKey{
+		APIName:    target.APIName,
+		Namespace:  target.Namespace,
+		WorkflowID: target.WorkflowID,
+		RunID:      target.RunID,
+		ActivityID: target.ActivityID,
+	}
+	if claims != nil {
+		effective := claims.EffectiveClaims()
+		key.Subject = effective.Subject
+		key.Issuer = effective.Issuer
+		key.TenantID = effective.TenantID
+		if claims.OnBehalfOf != nil {
+			key.Actor = claims.Subject
+		}
+	}
+	return key
+}
+
+// String encodes the key unambiguously, e.g. for use as the key of an external store
+func (k DecisionCacheKey) String() string {
+	fields := []string{k.Subject, k.Issuer, k.TenantID, k.Actor, k.APIName, k.Namespace, k.WorkflowID, k.RunID, k.ActivityID}
+	for i, field := range fields {
+		fields[i] = strconv.Quote(field)
+	}
+	return strings.Join(fields, "/")
+}
+
+// Hash returns the hex encoded SHA-256 of String, a fixed length key for stores that limit the length of keys.
+// Collisions are as unlikely as for SHA-256 since String encodes the fields unambiguously.
+func (k DecisionCacheKey) Hash() string {
+	sum := sha256.Sum256([]byte(k.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewLRUDecisionCache creates an in-memory DecisionCache holding up to maxSize decisions,
+// evicting the least recently used first
+func NewLRUDecisionCache(maxSize int, timeSource clock.TimeSource) DecisionCache {
+	return &lruDecisionCache{
+		maxSize:    maxSize,
+		timeSource: timeSource,
+		entries:    make(map[DecisionCacheKey]*list.Element),
+		byAccess:   list.New(),
+	}
+}
+
+func (c *lruDecisionCache) Get(_ context.Context, key DecisionCacheKey) (Result, bool, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return Result{}, false, nil
+	}
+	entry := element.Value.(*decisionCacheEntry)
+	if !c.timeSource.Now().Before(entry.expiresAt) {
+		c.byAccess.Remove(element)
+		delete(c.entries, key)
+		return Result{}, false, nil
+	}
+	c.byAccess.MoveToFront(element)
+	return entry.result, true, nil
+}
+
+func (c *lruDecisionCache) Set(_ context.Context, key DecisionCacheKey, result Result, ttl time.Duration) error {
+	if c.maxSize <= 0 {
+		return nil
+	}
+	expiresAt := c.timeSource.Now().Add(ttl)
+
+	c.Lock()
+	defer c.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*decisionCacheEntry)
+		entry.result, entry.expiresAt = result, expiresAt
+		c.byAccess.MoveToFront(element)
+		return nil
+	}
+	if len(c.entries) >= c.maxSize {
+		oldest := c.byAccess.Remove(c.byAccess.Back()).(*decisionCacheEntry)
+		delete(c.entries, oldest.key)
+	}
+	c.entries[key] = c.byAccess.PushFront(&decisionCacheEntry{key: key, result: result, expiresAt: expiresAt})
+	return nil
+}
+
+func (c *lruDecisionCache) Invalidate(_ context.Context, subject string) error {
+	c.Lock()
+	defer c.Unlock()
+
+	for key, element := range c.entries {
+		if key.Subject == subject || key.Actor == subject {
+			c.byAccess.Remove(element)
+			delete(c.entries, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"go.temporal.io/server/common/clock"
+)
+
+type (
+	decisionCacheSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		timeSource *clock.EventTimeSource
+	}
+)
+
+func TestDecisionCacheSuite(t *testing.T) {
+	s := new(decisionCacheSuite)
+	suite.Run(t, s)
+}
+
+func (s *decisionCacheSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.timeSource = clock.NewEventTimeSource().Update(time.Unix(0, 0))
+}
+
+func (s *decisionCacheSuite) TestGetSet() {
+	cache := NewLRUDecisionCache(10, s.timeSource)
+	key := DecisionCacheKey{Subject: testSubject, APIName: describeNamespaceTarget.APIName}
+
+	_, found, err := cache.Get(ctx, key)
+	s.NoError(err)
+	s.False(found)
+
+	s.NoError(cache.Set(ctx, key, Result{Decision: DecisionAllow}, time.Minute))
+	result, found, err := cache.Get(ctx, key)
+	s.NoError(err)
+	s.True(found)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *decisionCacheSuite) TestExpiry() {
+	cache := NewLRUDecisionCache(10, s.timeSource)
+	key := DecisionCacheKey{Subject: testSubject}
+	s.NoError(cache.Set(ctx, key, Result{Decision: DecisionAllow}, time.Minute))
+
+	s.timeSource.Update(time.Unix(59, 0))
+	_, found, _ := cache.Get(ctx, key)
+	s.True(found)
+
+	s.timeSource.Update(time.Unix(60, 0))
+	_, found, _ = cache.Get(ctx, key)
+	s.False(found)
+}
+
+func (s *decisionCacheSuite) TestEviction() {
+	cache := NewLRUDecisionCache(2, s.timeSource)
+	first, second, third := DecisionCacheKey{Subject: "first"}, DecisionCacheKey{Subject: "second"}, DecisionCacheKey{Subject: "third"}
+	s.NoError(cache.Set(ctx, first, Result{Decision: DecisionAllow}, time.Minute))
+	s.NoError(cache.Set(ctx, second, Result{Decision: DecisionAllow}, time.Minute))
+	// use first, so that second is the least recently used
+	_, found, _ := cache.Get(ctx, first)
+	s.True(found)
+	s.NoError(cache.Set(ctx, third, Result{Decision: DecisionAllow}, time.Minute))
+
+	_, found, _ = cache.Get(ctx, second)
+	s.False(found)
+	_, found, _ = cache.Get(ctx, first)
+	s.True(found)
+	_, found, _ = cache.Get(ctx, third)
+	s.True(found)
+}
+
+func (s *decisionCacheSuite) TestInvalidate() {
+	cache := NewLRUDecisionCache(10, s.timeSource)
+	describe := DecisionCacheKey{Subject: testSubject, APIName: describeNamespaceTarget.APIName}
+	start := DecisionCacheKey{Subject: testSubject, APIName: startWorkflowExecutionTarget.APIName}
+	other := DecisionCacheKey{Subject: "other", APIName: describeNamespaceTarget.APIName}
+	delegated := DecisionCacheKey{Subject: "other", Actor: testSubject, APIName: describeNamespaceTarget.APIName}
+	for _, key := range []DecisionCacheKey{describe, start, other, delegated} {
+		s.NoError(cache.Set(ctx, key, Result{Decision: DecisionAllow}, time.Minute))
+	}
+
+	s.NoError(cache.Invalidate(ctx, testSubject))
+	_, found, _ := cache.Get(ctx, describe)
+	s.False(found)
+	_, found, _ = cache.Get(ctx, start)
+	s.False(found)
+	_, found, _ = cache.Get(ctx, delegated)
+	s.False(found)
+	_, found, _ = cache.Get(ctx, other)
+	s.True(found)
+}
+
+func (s *decisionCacheSuite) TestKeyString() {
+	s.NotEqual(
+		DecisionCacheKey{Subject: "a/b", APIName: "c"}.String(),
+		DecisionCacheKey{Subject: "a", APIName: "b/c"}.String(),
+	)
+}
+
+func (s *decisionCacheSuite) TestCraftedCollisions() {
+	// pairs of keys that would collide if the fields were joined with a separator or quoted naively
+	collisions := [][2]DecisionCacheKey{
+		{{Subject: "a/b", APIName: "c"}, {Subject: "a", APIName: "b/c"}},
+		{{Subject: `a"/"b`}, {Subject: "a", APIName: "b"}},
+		{{Subject: "ab"}, {Subject: "a", APIName: "b"}},
+		{{Subject: "a", Issuer: "b"}, {Subject: "a", TenantID: "b"}},
+		{{Subject: "a", Actor: "b"}, {Subject: "a", TenantID: "b"}},
+		{{Namespace: "ns", WorkflowID: ""}, {Namespace: "", WorkflowID: "ns"}},
+		{{WorkflowID: `w\"`, RunID: "r"}, {WorkflowID: "w", RunID: `"r`}},
+	}
+	for _, keys := range collisions {
+		cache := NewLRUDecisionCache(10, s.timeSource)
+		s.NoError(cache.Set(ctx, keys[0], Result{Decision: DecisionAllow}, time.Minute))
+		s.NoError(cache.Set(ctx, keys[1], Result{Decision: DecisionDeny}, time.Minute))
+
+		first, found, _ := cache.Get(ctx, keys[0])
+		s.True(found)
+		s.Equal(DecisionAllow, first.Decision)
+		second, found, _ := cache.Get(ctx, keys[1])
+		s.True(found)
+		s.Equal(DecisionDeny, second.Decision)
+
+		s.NotEqual(keys[0].String(), keys[1].String())
+		s.NotEqual(keys[0].Hash(), keys[1].Hash())
+	}
+}
+
+func (s *decisionCacheSuite) TestKeyHash() {
+	key := DecisionCacheKey{Subject: testSubject, APIName: describeNamespaceTarget.APIName}
+	s.Len(key.Hash(), 64)
+	s.Equal(key.Hash(), DecisionCacheKey{Subject: testSubject, APIName: describeNamespaceTarget.APIName}.Hash())
+}
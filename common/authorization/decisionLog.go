@@ -0,0 +1,234 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+)
+
+const (
+	// DecisionLogFormat identifies decision logs in their header
+	DecisionLogFormat = "temporal-authorization-decision-log"
+	// DecisionLogVersion is the version of the decision logs written by NewDecisionLogWriter.
+	// ReadDecisionLog reads all versions up to it.
+	DecisionLogVersion = 2
+
+	maxDecisionLogLineSize = 1024 * 1024
+)
+
+// ErrUnsupportedDecisionLogVersion is returned by ReadDecisionLog for logs of versions it can't read,
+// e.g. logs written by a later release
+var ErrUnsupportedDecisionLogVersion = errors.New("unsupported decision log version")
+
+// decisionLogSchemas are the fields of the records of each version of the decision log, as listed in the header.
+//
+// Version 1 records are flat, with the fields of an AuthorizationAuditRecord. Version 2 records group the call
+// into a target and add the roles of the caller, so that decisions recorded before a role change don't
+// replay for the changed roles.
+var decisionLogSchemas = map[int][]string{
+	1: {"time", "subject", "apiName", "namespace", "workflowId", "runId", "decision", "reason"},
+	2: {"time", "subject", "issuer", "roles.system", "roles.namespace",
+		"target.apiName", "target.namespace", "target.workflowId", "target.runId", "decision", "reason"},
+}
+
+type (
+	// DecisionLogHeader is the first line of a decision log, describing the version and the fields of its records
+	DecisionLogHeader struct {
+		Format  string   `json:"format"`
+		Version int      `json:"version"`
+		Schema  []string `json:"schema"`
+	}
+
+	// RecordedDecision is an authorization decision as recorded in a decision log, in the layout of
+	// the current DecisionLogVersion. Records of earlier versions are converted to it when read.
+	RecordedDecision struct {
+		Time    time.Time `json:"time"`
+		Subject string    `json:"subject,omitempty"`
+		Issuer  string    `json:"issuer,omitempty"`
+		// Roles of the caller, nil for records of version 1, which didn't record them
+		Roles    *RecordedRoles `json:"roles,omitempty"`
+		Target   RecordedTarget `json:"target"`
+		Decision string         `json:"decision"`
+		Reason   string         `json:"reason,omitempty"`
+	}
+
+	// RecordedRoles are the system role of the caller and its role in the target namespace
+	RecordedRoles struct {
+		System    Role `json:"system"`
+		Namespace Role `json:"namespace"`
+	}
+
+	// RecordedTarget is the target of a recorded call
+	RecordedTarget struct {
+		APIName    string `json:"apiName"`
+		Namespace  string `json:"namespace,omitempty"`
+		WorkflowID string `json:"workflowId,omitempty"`
+		RunID      string `json:"runId,omitempty"`
+	}
+
+	// recordedDecisionV1 is the layout of the records of version 1
+	recordedDecisionV1 struct {
+		Time       time.Time `json:"time"`
+		Subject    string    `json:"subject,omitempty"`
+		APIName    string    `json:"apiName"`
+		Namespace  string    `json:"namespace,omitempty"`
+		WorkflowID string    `json:"workflowId,omitempty"`
+		RunID      string    `json:"runId,omitempty"`
+		Decision   string    `json:"decision"`
+		Reason     string    `json:"reason,omitempty"`
+	}
+)
+
+// NewDecisionLogWriter writes the header of a decision log of the current DecisionLogVersion to w and creates
+// a DecisionObserver appending each decision to it as a line of JSON. Failures to write records are logged.
+func NewDecisionLogWriter(w io.Writer, timeSource clock.TimeSource, logger log.Logger) (DecisionObserver, error) {
+	header, err := json.Marshal(&DecisionLogHeader{
+		Format:  DecisionLogFormat,
+		Version: DecisionLogVersion,
+		Schema:  decisionLogSchemas[DecisionLogVersion],
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(append(header, '\n')); err != nil {
+		return nil, err
+	}
+
+	var lock sync.Mutex // observers run concurrently
+	return func(ctx context.Context, claims *Claims, target *CallTarget, result Result) {
+		data, err := json.Marshal(newRecordedDecision(timeSource.Now(), claims, target, result))
+		if err != nil {
+			logger.Error("unable to serialize recorded authorization decision", tag.Error(err))
+			return
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		if _, err := w.Write(append(data, '\n')); err != nil {
+			logger.Warn("unable to write recorded authorization decision", tag.Error(err))
+		}
+	}, nil
+}
+
+func newRecordedDecision(now time.Time, claims *Claims, target *CallTarget, result Result) *RecordedDecision {
+	record := &RecordedDecision{
+		Time:  now.UTC(),
+		Roles: &RecordedRoles{},
+		Target: RecordedTarget{
+			APIName:    target.APIName,
+			Namespace:  target.Namespace,
+			WorkflowID: target.WorkflowID,
+			RunID:      target.RunID,
+		},
+		Decision: decisionName(result.Decision),
+		Reason:   string(result.Reason),
+	}
+	if claims != nil {
+		effective := claims.EffectiveClaims()
+		record.Subject = effective.Subject
+		record.Issuer = effective.Issuer
+		record.Roles.System = effective.System
+		record.Roles.Namespace = effective.Namespaces[target.Namespace]
+	}
+	return record
+}
+
+// ReadDecisionLog reads the records of the decision log in r, converting records of earlier versions to
+// the current layout. It fails with ErrUnsupportedDecisionLogVersion for logs of unknown versions, and with
+// an error for data that is not a decision log or whose schema doesn't match its version.
+func ReadDecisionLog(r io.Reader) ([]*RecordedDecision, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxDecisionLogLineSize)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("decision log has no header")
+	}
+	var header DecisionLogHeader
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil || header.Format != DecisionLogFormat {
+		return nil, errors.New("data is not a decision log")
+	}
+	schema, ok := decisionLogSchemas[header.Version]
+	if !ok {
+		return nil, fmt.Errorf("%w %d, versions up to %d are supported", ErrUnsupportedDecisionLogVersion, header.Version, DecisionLogVersion)
+	}
+	if !reflect.DeepEqual(header.Schema, schema) {
+		return nil, fmt.Errorf("schema of decision log doesn't match version %d", header.Version)
+	}
+
+	var records []*RecordedDecision
+	for line := 2; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		record, err := unmarshalRecordedDecision(header.Version, scanner.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("invalid record on line %d of decision log: %w", line, err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func unmarshalRecordedDecision(version int, data []byte) (*RecordedDecision, error) {
+	if version == 1 {
+		var v1 recordedDecisionV1
+		if err := json.Unmarshal(data, &v1); err != nil {
+			return nil, err
+		}
+		return &RecordedDecision{
+			Time:    v1.Time,
+			Subject: v1.Subject,
+			Target: RecordedTarget{
+				APIName:    v1.APIName,
+				Namespace:  v1.Namespace,
+				WorkflowID: v1.WorkflowID,
+				RunID:      v1.RunID,
+			},
+			Decision: v1.Decision,
+			Reason:   v1.Reason,
+		}, nil
+	}
+	var record RecordedDecision
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
@@ -0,0 +1,156 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/log"
+)
+
+type (
+	decisionLogSuite struct {
+		suite.Suite
+		*require.Assertions
+	}
+)
+
+// decisionLogV1 is a recording in the layout of version 1 of the decision log
+const decisionLogV1 = `{"format":"temporal-authorization-decision-log","version":1,"schema":["time","subject","apiName","namespace","workflowId","runId","decision","reason"]}
+{"time":"2020-09-13T12:26:40Z","subject":"test-user","apiName":"/temporal.api.workflowservice.v1.WorkflowService/StartWorkflowExecution","namespace":"test-namespace","workflowId":"wid","decision":"allow"}
+{"time":"2020-09-13T12:26:41Z","subject":"test-user","apiName":"/temporal.api.workflowservice.v1.WorkflowService/DescribeNamespace","namespace":"test-namespace","decision":"deny","reason":"insufficient_role"}
+`
+
+func TestDecisionLogSuite(t *testing.T) {
+	s := new(decisionLogSuite)
+	suite.Run(t, s)
+}
+
+func (s *decisionLogSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *decisionLogSuite) TestReadVersion1() {
+	records, err := ReadDecisionLog(strings.NewReader(decisionLogV1))
+	s.NoError(err)
+	s.Equal([]*RecordedDecision{
+		{
+			Time:    time.Unix(1600000000, 0).UTC(),
+			Subject: testSubject,
+			Target: RecordedTarget{
+				APIName:    startWorkflowExecutionTarget.APIName,
+				Namespace:  testNamespace,
+				WorkflowID: "wid",
+			},
+			Decision: decisionNameAllow,
+		},
+		{
+			Time:    time.Unix(1600000001, 0).UTC(),
+			Subject: testSubject,
+			Target: RecordedTarget{
+				APIName:   describeNamespaceTarget.APIName,
+				Namespace: testNamespace,
+			},
+			Decision: decisionNameDeny,
+			Reason:   string(ReasonInsufficientRole),
+		},
+	}, records)
+}
+
+func (s *decisionLogSuite) TestWriteAndRead() {
+	var buf bytes.Buffer
+	timeSource := clock.NewEventTimeSource().Update(time.Unix(1600000000, 0))
+	writer, err := NewDecisionLogWriter(&buf, timeSource, log.NewNoop())
+	s.NoError(err)
+	claims := &Claims{Subject: testSubject, Issuer: "issuer", Namespaces: map[string]Role{testNamespace: RoleWriter}}
+	target := &CallTarget{APIName: startWorkflowExecutionTarget.APIName, Namespace: testNamespace, WorkflowID: "wid", RunID: "rid"}
+	writer(ctx, claims, target, Result{Decision: DecisionDeny, Reason: ReasonNotOwner})
+	writer(ctx, nil, describeNamespaceTarget, Result{Decision: DecisionAllow})
+
+	records, err := ReadDecisionLog(&buf)
+	s.NoError(err)
+	s.Equal([]*RecordedDecision{
+		{
+			Time:    time.Unix(1600000000, 0).UTC(),
+			Subject: testSubject,
+			Issuer:  "issuer",
+			Roles:   &RecordedRoles{Namespace: RoleWriter},
+			Target: RecordedTarget{
+				APIName:    startWorkflowExecutionTarget.APIName,
+				Namespace:  testNamespace,
+				WorkflowID: "wid",
+				RunID:      "rid",
+			},
+			Decision: decisionNameDeny,
+			Reason:   string(ReasonNotOwner),
+		},
+		{
+			Time:     time.Unix(1600000000, 0).UTC(),
+			Roles:    &RecordedRoles{},
+			Target:   RecordedTarget{APIName: describeNamespaceTarget.APIName, Namespace: describeNamespaceTarget.Namespace},
+			Decision: decisionNameAllow,
+		},
+	}, records)
+}
+
+func (s *decisionLogSuite) TestUnsupportedVersion() {
+	data := `{"format":"temporal-authorization-decision-log","version":3,"schema":["time","decision"]}
+{"time":"2020-09-13T12:26:40Z","decision":"allow"}
+`
+	_, err := ReadDecisionLog(strings.NewReader(data))
+	s.Error(err)
+	s.True(errors.Is(err, ErrUnsupportedDecisionLogVersion))
+	s.Contains(err.Error(), "3")
+}
+
+func (s *decisionLogSuite) TestSchemaMismatch() {
+	data := `{"format":"temporal-authorization-decision-log","version":1,"schema":["time","decision"]}
+`
+	_, err := ReadDecisionLog(strings.NewReader(data))
+	s.Error(err)
+	s.False(errors.Is(err, ErrUnsupportedDecisionLogVersion))
+}
+
+func (s *decisionLogSuite) TestNotADecisionLog() {
+	_, err := ReadDecisionLog(strings.NewReader(`{"time":"2020-09-13T12:26:40Z","decision":"allow"}`))
+	s.Error(err)
+	_, err = ReadDecisionLog(strings.NewReader(""))
+	s.Error(err)
+}
+
+func (s *decisionLogSuite) TestInvalidRecord() {
+	data := strings.SplitN(decisionLogV1, "\n", 2)[0] + "\n{\"time\":\n"
+	_, err := ReadDecisionLog(strings.NewReader(data))
+	s.Error(err)
+	s.Contains(err.Error(), "line 2")
+}
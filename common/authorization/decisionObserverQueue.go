@@ -0,0 +1,101 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/metrics"
+)
+
+const (
+	defaultDecisionObserverQueueSize = 1000
+	defaultDecisionObserverWorkers   = 4
+)
+
+type (
+	decisionObservation struct {
+		ctx    context.Context
+		claims *Claims
+		target *CallTarget
+		result Result
+	}
+
+	// decisionObserverQueue notifies a decision observer from a fixed number of background goroutines,
+	// so that slow observers neither delay calls nor pile up goroutines
+	decisionObserverQueue struct {
+		observer      DecisionObserver
+		metricsClient metrics.Client
+		logger        log.Logger
+		observations  chan decisionObservation
+	}
+)
+
+// newDecisionObserverQueue creates a queue of up to queueSize decisions notified to observer by workers
+// goroutines. Decisions enqueued while the queue is full are dropped and counted.
+func newDecisionObserverQueue(
+	observer DecisionObserver,
+	queueSize int,
+	workers int,
+	metricsClient metrics.Client,
+	logger log.Logger,
+) *decisionObserverQueue {
+	q := &decisionObserverQueue{
+		observer:      observer,
+		metricsClient: metricsClient,
+		logger:        logger,
+		observations:  make(chan decisionObservation, queueSize),
+	}
+	for i := 0; i < workers; i++ {
+		go q.observeLoop()
+	}
+	return q
+}
+
+func (q *decisionObserverQueue) enqueue(ctx context.Context, claims *Claims, target *CallTarget, result Result) {
+	select {
+	case q.observations <- decisionObservation{ctx: ctx, claims: claims, target: target, result: result}:
+	default:
+		q.metricsClient.IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationDecisionObserverDroppedCounter)
+	}
+}
+
+func (q *decisionObserverQueue) observeLoop() {
+	for observation := range q.observations {
+		q.observe(observation)
+	}
+}
+
+// observe notifies the decision observer, recovering from its panics
+func (q *decisionObserverQueue) observe(observation decisionObservation) {
+	defer func() {
+		if p := recover(); p != nil {
+			q.logger.Error("authorization decision observer panicked", tag.Value(p))
+		}
+	}()
+	q.observer(observation.ctx, observation.claims, observation.target, observation.result)
+}
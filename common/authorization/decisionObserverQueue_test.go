@@ -0,0 +1,117 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"go.temporal.io/server/common/log/loggerimpl"
+	"go.temporal.io/server/common/metrics"
+)
+
+type (
+	decisionObserverQueueSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller        *gomock.Controller
+		mockMetricsClient *metrics.MockClient
+	}
+)
+
+func TestDecisionObserverQueueSuite(t *testing.T) {
+	s := new(decisionObserverQueueSuite)
+	suite.Run(t, s)
+}
+
+func (s *decisionObserverQueueSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockMetricsClient = metrics.NewMockClient(s.controller)
+}
+
+func (s *decisionObserverQueueSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *decisionObserverQueueSuite) TestObserved() {
+	observed := make(chan Result, 1)
+	queue := s.newQueue(1, func(_ context.Context, _ *Claims, target *CallTarget, result Result) {
+		s.Equal(describeNamespaceTarget, target)
+		observed <- result
+	})
+
+	queue.enqueue(ctx, nil, describeNamespaceTarget, Result{Decision: DecisionAllow})
+	s.Equal(DecisionAllow, (<-observed).Decision)
+}
+
+func (s *decisionObserverQueueSuite) TestDroppedWhenFull() {
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationDecisionObserverDroppedCounter).Times(1)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	observed := make(chan *CallTarget, 2)
+	queue := s.newQueue(1, func(_ context.Context, _ *Claims, target *CallTarget, _ Result) {
+		if target == describeNamespaceTarget {
+			close(started)
+			<-release
+		}
+		observed <- target
+	})
+
+	queue.enqueue(ctx, nil, describeNamespaceTarget, Result{Decision: DecisionAllow})
+	<-started
+	// the worker is busy, the queue holds one decision and drops the next
+	queue.enqueue(ctx, nil, startWorkflowExecutionTarget, Result{Decision: DecisionAllow})
+	queue.enqueue(ctx, nil, startWorkflowExecutionTarget, Result{Decision: DecisionDeny})
+	close(release)
+
+	s.Equal(describeNamespaceTarget, <-observed)
+	s.Equal(startWorkflowExecutionTarget, <-observed)
+}
+
+func (s *decisionObserverQueueSuite) TestObserverPanic() {
+	observed := make(chan struct{})
+	queue := s.newQueue(2, func(_ context.Context, _ *Claims, target *CallTarget, _ Result) {
+		if target == describeNamespaceTarget {
+			panic("observer failure")
+		}
+		close(observed)
+	})
+
+	// the worker survives the panic and observes the next decision
+	queue.enqueue(ctx, nil, describeNamespaceTarget, Result{Decision: DecisionAllow})
+	queue.enqueue(ctx, nil, startWorkflowExecutionTarget, Result{Decision: DecisionAllow})
+	<-observed
+}
+
+func (s *decisionObserverQueueSuite) newQueue(queueSize int, observer DecisionObserver) *decisionObserverQueue {
+	return newDecisionObserverQueue(observer, queueSize, 1, s.mockMetricsClient, loggerimpl.NewLogger(zap.NewNop()))
+}
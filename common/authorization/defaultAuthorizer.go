@@ -44,15 +44,10 @@ func (a *defaultAuthorizer) Authorize(_ context.Context, claims *Claims, target
 		return Result{Decision: DecisionAllow}, nil
 	}
 	if claims == nil {
-		return Result{Decision: DecisionDeny}, nil
+		return Result{Decision: DecisionDeny, Reason: ReasonNoClaims}, nil
 	}
-	// Check system level permissions
-	if claims.System == RoleAdmin || claims.System == RoleWriter {
-		return Result{Decision: DecisionAllow}, nil
-	}
-	roles, found := claims.Namespaces[target.Namespace]
-	if !found || roles == RoleUndefined {
-		return Result{Decision: DecisionDeny}, nil
+	if role, _ := claims.EffectiveClaims().EffectiveRole(target.Namespace); role == RoleUndefined {
+		return Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil
 	}
 	return Result{Decision: DecisionAllow}, nil
 }
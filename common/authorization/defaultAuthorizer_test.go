@@ -103,6 +103,7 @@ func (s *defaultAuthorizerSuite) TestSystemReaderAuthZ() {
 	result, err := s.authorizer.Authorize(nil, &claimsSystemReader, &targetFooBar)
 	s.NoError(err)
 	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonInsufficientRole, result.Reason)
 }
 func (s *defaultAuthorizerSuite) TestSystemReaderBarUndefinedAuthZ() {
 	result, err := s.authorizer.Authorize(nil, &claimsSystemReaderNamespaceUndefined, &targetFooBar)
@@ -114,6 +115,12 @@ func (s *defaultAuthorizerSuite) TestSystemUndefinedNamespaceReaderAuthZ() {
 	s.NoError(err)
 	s.Equal(DecisionDeny, result.Decision)
 }
+func (s *defaultAuthorizerSuite) TestNoClaimsAuthZ() {
+	result, err := s.authorizer.Authorize(nil, nil, &targetFooBar)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonNoClaims, result.Reason)
+}
 func (s *defaultAuthorizerSuite) TestGetAuthorizerFromConfigNoop() {
 	s.testGetAuthorizerFromConfig("", true, reflect.TypeOf(&noopAuthorizer{}))
 }
@@ -25,14 +25,21 @@
 package authorization
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"math"
 	"strings"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/uber-go/tally"
 	"go.temporal.io/api/serviceerror"
 
+	"go.temporal.io/server/common/clock"
 	"go.temporal.io/server/common/log"
 	"go.temporal.io/server/common/log/loggerimpl"
+	"go.temporal.io/server/common/metrics"
 	"go.temporal.io/server/common/service/config"
 )
 
@@ -40,11 +47,23 @@ const (
 	defaultPermissionsClaimName = "permissions"
 	authorizationBearer         = "bearer"
 	headerSubject               = "sub"
+	headerIssuer                = "iss"
+	headerAudience              = "aud"
+	headerAuthMethods           = "amr"
+	headerAuthLevel             = "loa"
+	headerGroups                = "groups"
+	headerActor                 = "act"
+	headerTenantID              = "tenant_id"
+	headerTokenID               = "jti"
+	headerExpiresAt             = "exp"
+	headerIssuedAt              = "iat"
+	headerNotBefore             = "nbf"
 	permissionScopeSystem       = "system"
 	permissionRead              = "read"
 	permissionWrite             = "write"
 	permissionWorker            = "worker"
 	permissionAdmin             = "admin"
+	defaultClockSkew            = 60 * time.Second
 )
 
 // Default claim mapper that gives system level admin permission to everybody
@@ -52,20 +71,40 @@ type defaultJWTClaimMapper struct {
 	keyProvider          TokenKeyProvider
 	logger               log.Logger
 	permissionsClaimName string
+	clockSkew            time.Duration
+	metricsClient        metrics.Client
+	timeSource           clock.TimeSource
 }
 
 func NewDefaultJWTClaimMapper(provider TokenKeyProvider, cfg *config.Config) ClaimMapper {
+	return NewDefaultJWTClaimMapperWithMetrics(provider, cfg, metrics.NewClient(tally.NoopScope, metrics.Frontend))
+}
+
+// NewDefaultJWTClaimMapperWithMetrics creates a default JWT claim mapper that counts the tokens
+// accepted only because of the configured clock skew tolerance
+func NewDefaultJWTClaimMapperWithMetrics(provider TokenKeyProvider, cfg *config.Config, metricsClient metrics.Client) ClaimMapper {
 	claimName := cfg.Global.Authorization.PermissionsClaimName
 	if claimName == "" {
 		claimName = defaultPermissionsClaimName
 	}
+	clockSkew := defaultClockSkew
+	if cfg.Global.Authorization.ClockSkew != nil {
+		clockSkew = *cfg.Global.Authorization.ClockSkew
+	}
 	logger := loggerimpl.NewLogger(cfg.Log.NewZapLogger())
-	return &defaultJWTClaimMapper{keyProvider: provider, logger: logger, permissionsClaimName: claimName}
+	return &defaultJWTClaimMapper{
+		keyProvider:          provider,
+		logger:               logger,
+		permissionsClaimName: claimName,
+		clockSkew:            clockSkew,
+		metricsClient:        metricsClient,
+		timeSource:           clock.NewRealTimeSource(),
+	}
 }
 
 var _ ClaimMapper = (*defaultJWTClaimMapper)(nil)
 
-func (a *defaultJWTClaimMapper) GetClaims(authInfo *AuthInfo) (*Claims, error) {
+func (a *defaultJWTClaimMapper) GetClaims(_ context.Context, authInfo *AuthInfo) (*Claims, error) {
 
 	claims := Claims{}
 
@@ -84,11 +123,38 @@ func (a *defaultJWTClaimMapper) GetClaims(authInfo *AuthInfo) (*Claims, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := a.validateTimes(jwtClaims); err != nil {
+		return nil, err
+	}
 	subject, ok := jwtClaims[headerSubject].(string)
 	if !ok {
 		return nil, serviceerror.NewPermissionDenied("unexpected value type of \"sub\" claim")
 	}
 	claims.Subject = subject
+	if issuer, ok := jwtClaims[headerIssuer].(string); ok {
+		claims.Issuer = issuer
+	}
+	if tenantID, ok := jwtClaims[headerTenantID].(string); ok {
+		claims.TenantID = tenantID
+	}
+	if tokenID, ok := jwtClaims[headerTokenID].(string); ok {
+		claims.TokenID = tokenID
+	}
+	// validated by validateTimes
+	if expiresAt, ok, _ := timeClaim(jwtClaims, headerExpiresAt); ok {
+		claims.ExpiresAt = time.Unix(expiresAt, 0)
+	}
+	a.extractAudience(jwtClaims[headerAudience], &claims)
+	if authMethods, ok := jwtClaims[headerAuthMethods].([]interface{}); ok {
+		a.extractAuthMethods(authMethods, &claims)
+	}
+	if authLevel, ok := jwtClaims[headerAuthLevel]; ok {
+		a.extractAuthLevel(authLevel, &claims)
+	}
+	if groups, ok := jwtClaims[headerGroups].([]interface{}); ok {
+		a.extractGroups(groups, &claims)
+	}
+	extractAttributes(jwtClaims, &claims)
 	permissions, ok := jwtClaims[a.permissionsClaimName].([]interface{})
 	if ok {
 		err := a.extractPermissions(permissions, &claims)
@@ -96,9 +162,34 @@ func (a *defaultJWTClaimMapper) GetClaims(authInfo *AuthInfo) (*Claims, error) {
 			return nil, err
 		}
 	}
+	if actor, ok := jwtClaims[headerActor]; ok {
+		return delegatedClaims(actor, &claims)
+	}
 	return &claims, nil
 }
 
+// delegatedClaims returns the claims of the actor of a delegated token, as named by its "act" claim,
+// acting on behalf of the subject of the token. Roles are only granted to the subject, not to the actor.
+func delegatedClaims(actor interface{}, subjectClaims *Claims) (*Claims, error) {
+	actorClaims, ok := actor.(map[string]interface{})
+	if !ok {
+		return nil, serviceerror.NewPermissionDenied("unexpected value type of \"act\" claim")
+	}
+	actorSubject, ok := actorClaims[headerSubject].(string)
+	if !ok || actorSubject == "" {
+		return nil, serviceerror.NewPermissionDenied("unexpected value type of \"sub\" claim of \"act\" claim")
+	}
+	return &Claims{
+		Subject:    actorSubject,
+		Issuer:     subjectClaims.Issuer,
+		TenantID:   subjectClaims.TenantID,
+		TokenID:    subjectClaims.TokenID,
+		ExpiresAt:  subjectClaims.ExpiresAt,
+		Audience:   subjectClaims.Audience,
+		OnBehalfOf: subjectClaims,
+	}, nil
+}
+
 func (a *defaultJWTClaimMapper) extractPermissions(permissions []interface{}, claims *Claims) error {
 	for _, permission := range permissions {
 		p, ok := permission.(string)
@@ -126,8 +217,153 @@ func (a *defaultJWTClaimMapper) extractPermissions(permissions []interface{}, cl
 	return nil
 }
 
+// extractAudience extracts the "aud" claim, which holds either a single audience or a list of them
+func (a *defaultJWTClaimMapper) extractAudience(audience interface{}, claims *Claims) {
+	switch value := audience.(type) {
+	case nil:
+	case string:
+		claims.Audience = []string{value}
+	case []interface{}:
+		for _, aud := range value {
+			s, ok := aud.(string)
+			if !ok {
+				a.logger.Warn(fmt.Sprintf("ignoring audience that is not a string: %v", aud))
+				continue
+			}
+			claims.Audience = append(claims.Audience, s)
+		}
+	default:
+		a.logger.Warn(fmt.Sprintf("ignoring audience claim that is neither a string nor a list: %v", audience))
+	}
+}
+
+func (a *defaultJWTClaimMapper) extractAuthMethods(authMethods []interface{}, claims *Claims) {
+	for _, authMethod := range authMethods {
+		m, ok := authMethod.(string)
+		if !ok {
+			a.logger.Warn(fmt.Sprintf("ignoring authentication method that is not a string: %v", authMethod))
+			continue
+		}
+		claims.AuthMethods = append(claims.AuthMethods, m)
+	}
+}
+
+// extractAuthLevel extracts the "loa" claim, which must be a non-negative integer
+func (a *defaultJWTClaimMapper) extractAuthLevel(authLevel interface{}, claims *Claims) {
+	var level float64
+	switch value := authLevel.(type) {
+	case float64:
+		level = value
+	case json.Number:
+		level, _ = value.Float64()
+	default:
+		level = -1
+	}
+	if level < 0 || level != math.Trunc(level) {
+		a.logger.Warn(fmt.Sprintf("ignoring level of assurance that is not a non-negative integer: %v", authLevel))
+		return
+	}
+	claims.AuthLevel = int(level)
+}
+
+func (a *defaultJWTClaimMapper) extractGroups(groups []interface{}, claims *Claims) {
+	for _, group := range groups {
+		g, ok := group.(string)
+		if !ok {
+			a.logger.Warn(fmt.Sprintf("ignoring group that is not a string: %v", group))
+			continue
+		}
+		claims.Groups = append(claims.Groups, g)
+	}
+}
+
+// extractAttributes sets the claims of the token whose values are strings or lists of strings as attributes.
+// Lists with values that are not strings are ignored.
+func extractAttributes(jwtClaims jwt.MapClaims, claims *Claims) {
+	for name, value := range jwtClaims {
+		var values []string
+		switch v := value.(type) {
+		case string:
+			values = []string{v}
+		case []interface{}:
+			values = make([]string, 0, len(v))
+			for _, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					values = nil
+					break
+				}
+				values = append(values, s)
+			}
+		}
+		if values == nil {
+			continue
+		}
+		if claims.Attributes == nil {
+			claims.Attributes = make(map[string][]string)
+		}
+		claims.Attributes[name] = values
+	}
+}
+
+// validateTimes checks the expiry, issue and not-before times of the token, tolerating the configured clock skew
+func (a *defaultJWTClaimMapper) validateTimes(jwtClaims jwt.MapClaims) error {
+	now := a.timeSource.Now().Unix()
+	skew := int64(a.clockSkew / time.Second)
+	skewTolerated := false
+
+	expiresAt, ok, err := timeClaim(jwtClaims, headerExpiresAt)
+	if err != nil {
+		return err
+	}
+	if ok {
+		if now > expiresAt+skew {
+			return serviceerror.NewPermissionDenied("token is expired")
+		}
+		skewTolerated = skewTolerated || now > expiresAt
+	}
+	for _, name := range []string{headerIssuedAt, headerNotBefore} {
+		notBefore, ok, err := timeClaim(jwtClaims, name)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if now+skew < notBefore {
+			return serviceerror.NewPermissionDenied("token is not valid yet")
+		}
+		skewTolerated = skewTolerated || now < notBefore
+	}
+
+	if skewTolerated {
+		a.metricsClient.IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationClockSkewToleratedCounter)
+	}
+	return nil
+}
+
+// timeClaim returns the value of a claim holding seconds since the epoch and whether the token has the claim
+func timeClaim(jwtClaims jwt.MapClaims, name string) (int64, bool, error) {
+	switch value := jwtClaims[name].(type) {
+	case nil:
+		return 0, false, nil
+	case float64:
+		return int64(value), true, nil
+	case json.Number:
+		seconds, err := value.Int64()
+		if err != nil {
+			return 0, false, serviceerror.NewPermissionDenied(fmt.Sprintf("unexpected value of %q claim", name))
+		}
+		return seconds, true, nil
+	}
+	return 0, false, serviceerror.NewPermissionDenied(fmt.Sprintf("unexpected value type of %q claim", name))
+}
+
+// parseJWT parses the token and verifies its signature. Expiry is checked by the claim mapper,
+// which tolerates clock skew between the token issuer and this host.
 func parseJWT(tokenString string, keyProvider TokenKeyProvider) (jwt.MapClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+	parser := &jwt.Parser{SkipClaimsValidation: true}
+	token, err := parser.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 
 		kid, ok := token.Header["kid"].(string)
 		if !ok {
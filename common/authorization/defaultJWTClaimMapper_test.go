@@ -37,7 +37,11 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
+	"go.temporal.io/api/serviceerror"
 
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/metrics"
 	"go.temporal.io/server/common/service/config"
 )
 
@@ -55,9 +59,15 @@ const (
 	defaultNamespace = "default"
 )
 
+var (
+	testTokenTime = time.Unix(1600000000, 0)
+)
+
 var (
 	permissionsAdmin              = []string{"system:admin", "default:read"}
 	permissionsReaderWriterWorker = []string{"default:read", "default:write", "default:worker"}
+	testAuthMethods               = []string{"pwd", "otp"}
+	testGroups                    = []string{"developers", "operators"}
 )
 
 type (
@@ -65,10 +75,11 @@ type (
 		suite.Suite
 		*require.Assertions
 
-		controller     *gomock.Controller
-		tokenGenerator *tokenGenerator
-		claimMapper    ClaimMapper
-		config         *config.Config
+		controller        *gomock.Controller
+		tokenGenerator    *tokenGenerator
+		claimMapper       ClaimMapper
+		config            *config.Config
+		mockMetricsClient *metrics.MockClient
 	}
 )
 
@@ -123,30 +134,152 @@ func (s *defaultClaimMapperSuite) TestTokenWithAdminPermissions() {
 		testSubject, permissionsAdmin, errorTestOptionNoError)
 	s.NoError(err)
 	authInfo := &AuthInfo{
-		AddBearer(tokenString),
-		nil,
-		nil,
-		"",
+		AuthToken: AddBearer(tokenString),
 	}
-	claims, err := s.claimMapper.GetClaims(authInfo)
+	claims, err := s.claimMapper.GetClaims(ctx, authInfo)
 	s.NoError(err)
 	s.Equal(testSubject, claims.Subject)
+	s.Equal("test", claims.Issuer)
+	s.Equal(testAuthMethods, claims.AuthMethods)
+	s.Equal(testGroups, claims.Groups)
+	s.Equal(testGroups, claims.Attributes["groups"])
+	s.Equal([]string{testSubject}, claims.Attributes["sub"])
 	s.Equal(RoleAdmin, claims.System)
 	s.Equal(1, len(claims.Namespaces))
 	defaultRole := claims.Namespaces[defaultNamespace]
 	s.Equal(RoleReader, defaultRole)
+	s.Nil(claims.OnBehalfOf)
+}
+func (s *defaultClaimMapperSuite) TestTokenAttributes() {
+	tokenString, err := s.tokenGenerator.generateTokenWithClaims(CustomClaims{
+		Regions:        []interface{}{"eu", "us"},
+		Tenant:         "acme",
+		Audience:       []interface{}{"cluster", 1},
+		StandardClaims: jwt.StandardClaims{Subject: testSubject, ExpiresAt: time.Now().Add(time.Hour).Unix()},
+	})
+	s.NoError(err)
+	claims, err := s.claimMapper.GetClaims(ctx, &AuthInfo{AuthToken: AddBearer(tokenString)})
+	s.NoError(err)
+	s.Equal([]string{"eu", "us"}, claims.Attributes["regions"])
+	s.Equal([]string{"acme"}, claims.Attributes["tenant"])
+	s.NotContains(claims.Attributes, "aud")
+	s.NotContains(claims.Attributes, "exp")
+}
+
+func (s *defaultClaimMapperSuite) TestTokenTenantID() {
+	tokenString, err := s.tokenGenerator.generateTokenWithClaims(CustomClaims{
+		TenantID:       "acme",
+		StandardClaims: jwt.StandardClaims{Subject: testSubject, ExpiresAt: time.Now().Add(time.Hour).Unix()},
+	})
+	s.NoError(err)
+	claims, err := s.claimMapper.GetClaims(ctx, &AuthInfo{AuthToken: AddBearer(tokenString)})
+	s.NoError(err)
+	s.Equal("acme", claims.TenantID)
+}
+
+func (s *defaultClaimMapperSuite) TestTokenID() {
+	tokenString, err := s.tokenGenerator.generateTokenWithClaims(CustomClaims{
+		StandardClaims: jwt.StandardClaims{Subject: testSubject, Id: "token-1", ExpiresAt: time.Now().Add(time.Hour).Unix()},
+	})
+	s.NoError(err)
+	claims, err := s.claimMapper.GetClaims(ctx, &AuthInfo{AuthToken: AddBearer(tokenString)})
+	s.NoError(err)
+	s.Equal("token-1", claims.TokenID)
+}
+
+func (s *defaultClaimMapperSuite) TestTokenExpiresAt() {
+	expiresAt := time.Unix(time.Now().Add(time.Hour).Unix(), 0)
+	tokenString, err := s.tokenGenerator.generateTokenWithClaims(CustomClaims{
+		StandardClaims: jwt.StandardClaims{Subject: testSubject, ExpiresAt: expiresAt.Unix()},
+	})
+	s.NoError(err)
+	claims, err := s.claimMapper.GetClaims(ctx, &AuthInfo{AuthToken: AddBearer(tokenString)})
+	s.NoError(err)
+	s.True(expiresAt.Equal(claims.ExpiresAt))
+}
+
+func (s *defaultClaimMapperSuite) TestTokenAuthLevel() {
+	for _, tc := range []struct {
+		authLevel interface{}
+		expected  int
+	}{
+		{authLevel: nil, expected: 0},
+		{authLevel: 2, expected: 2},
+		{authLevel: 2.5, expected: 0},
+		{authLevel: -1, expected: 0},
+		{authLevel: "2", expected: 0},
+	} {
+		tokenString, err := s.tokenGenerator.generateTokenWithClaims(CustomClaims{
+			AuthLevel:      tc.authLevel,
+			StandardClaims: jwt.StandardClaims{Subject: testSubject, ExpiresAt: time.Now().Add(time.Hour).Unix()},
+		})
+		s.NoError(err)
+		claims, err := s.claimMapper.GetClaims(ctx, &AuthInfo{AuthToken: AddBearer(tokenString)})
+		s.NoError(err)
+		s.Equal(tc.expected, claims.AuthLevel, "loa claim %v", tc.authLevel)
+	}
+}
+
+func (s *defaultClaimMapperSuite) TestTokenOnBehalfOf() {
+	tokenString, err := s.tokenGenerator.generateTokenWithClaims(CustomClaims{
+		Permissions:    permissionsReaderWriterWorker,
+		Actor:          map[string]interface{}{"sub": "gateway"},
+		TenantID:       "acme",
+		StandardClaims: jwt.StandardClaims{Subject: testSubject, Issuer: "test"},
+	})
+	s.NoError(err)
+	claims, err := s.claimMapper.GetClaims(ctx, &AuthInfo{AuthToken: AddBearer(tokenString)})
+	s.NoError(err)
+	s.Equal("gateway", claims.Subject)
+	s.Equal("test", claims.Issuer)
+	s.Equal("acme", claims.TenantID)
+	s.Equal(RoleUndefined, claims.System)
+	s.Empty(claims.Namespaces)
+	s.NotNil(claims.OnBehalfOf)
+	s.Equal(testSubject, claims.OnBehalfOf.Subject)
+	s.Equal(RoleReader|RoleWriter|RoleWorker, claims.OnBehalfOf.Namespaces[defaultNamespace])
+}
+func (s *defaultClaimMapperSuite) TestTokenWithAudience() {
+	tokenString, err := s.tokenGenerator.generateTokenWithClaims(CustomClaims{
+		Audience:       testNamespace,
+		StandardClaims: jwt.StandardClaims{Subject: testSubject},
+	})
+	s.NoError(err)
+	claims, err := s.claimMapper.GetClaims(ctx, &AuthInfo{AuthToken: AddBearer(tokenString)})
+	s.NoError(err)
+	s.Equal([]string{testNamespace}, claims.Audience)
+}
+func (s *defaultClaimMapperSuite) TestTokenWithMultipleAudiences() {
+	tokenString, err := s.tokenGenerator.generateTokenWithClaims(CustomClaims{
+		Audience:       []string{testNamespace, "temporal-cluster"},
+		Actor:          map[string]interface{}{"sub": "gateway"},
+		StandardClaims: jwt.StandardClaims{Subject: testSubject},
+	})
+	s.NoError(err)
+	claims, err := s.claimMapper.GetClaims(ctx, &AuthInfo{AuthToken: AddBearer(tokenString)})
+	s.NoError(err)
+	s.Equal([]string{testNamespace, "temporal-cluster"}, claims.Audience)
+	s.Equal(claims.Audience, claims.OnBehalfOf.Audience)
+}
+func (s *defaultClaimMapperSuite) TestTokenOnBehalfOfInvalidActor() {
+	for _, actor := range []interface{}{"gateway", map[string]interface{}{"client_id": "gateway"}} {
+		tokenString, err := s.tokenGenerator.generateTokenWithClaims(CustomClaims{
+			Actor:          actor,
+			StandardClaims: jwt.StandardClaims{Subject: testSubject},
+		})
+		s.NoError(err)
+		_, err = s.claimMapper.GetClaims(ctx, &AuthInfo{AuthToken: AddBearer(tokenString)})
+		s.IsType(&serviceerror.PermissionDenied{}, err)
+	}
 }
 func (s *defaultClaimMapperSuite) TestTokenWithReaderWriterWorkerPermissions() {
 	tokenString, err := s.tokenGenerator.generateToken(
 		testSubject, permissionsReaderWriterWorker, errorTestOptionNoError)
 	s.NoError(err)
 	authInfo := &AuthInfo{
-		AddBearer(tokenString),
-		nil,
-		nil,
-		"",
+		AuthToken: AddBearer(tokenString),
 	}
-	claims, err := s.claimMapper.GetClaims(authInfo)
+	claims, err := s.claimMapper.GetClaims(ctx, authInfo)
 	s.NoError(err)
 	s.Equal(testSubject, claims.Subject)
 	s.Equal(RoleUndefined, claims.System)
@@ -154,6 +287,67 @@ func (s *defaultClaimMapperSuite) TestTokenWithReaderWriterWorkerPermissions() {
 	defaultRole := claims.Namespaces[defaultNamespace]
 	s.Equal(RoleReader|RoleWriter|RoleWorker, defaultRole)
 }
+func (s *defaultClaimMapperSuite) TestTokenNotExpired() {
+	claimMapper := s.newClockSkewTestClaimMapper(nil, testTokenTime)
+	_, err := claimMapper.GetClaims(ctx, s.tokenWithTimes(jwt.StandardClaims{ExpiresAt: testTokenTime.Unix()}))
+	s.NoError(err)
+}
+func (s *defaultClaimMapperSuite) TestTokenExpiredWithinClockSkew() {
+	claimMapper := s.newClockSkewTestClaimMapper(nil, testTokenTime.Add(defaultClockSkew))
+	s.expectClockSkewTolerated()
+	claims, err := claimMapper.GetClaims(ctx, s.tokenWithTimes(jwt.StandardClaims{ExpiresAt: testTokenTime.Unix()}))
+	s.NoError(err)
+	s.Equal(testSubject, claims.Subject)
+}
+func (s *defaultClaimMapperSuite) TestTokenExpiredBeyondClockSkew() {
+	claimMapper := s.newClockSkewTestClaimMapper(nil, testTokenTime.Add(defaultClockSkew+time.Second))
+	_, err := claimMapper.GetClaims(ctx, s.tokenWithTimes(jwt.StandardClaims{ExpiresAt: testTokenTime.Unix()}))
+	s.IsType(&serviceerror.PermissionDenied{}, err)
+}
+func (s *defaultClaimMapperSuite) TestTokenIssuedInFutureWithinClockSkew() {
+	claimMapper := s.newClockSkewTestClaimMapper(nil, testTokenTime.Add(-defaultClockSkew))
+	s.expectClockSkewTolerated()
+	_, err := claimMapper.GetClaims(ctx, s.tokenWithTimes(jwt.StandardClaims{
+		ExpiresAt: testTokenTime.Add(time.Hour).Unix(),
+		IssuedAt:  testTokenTime.Unix(),
+		NotBefore: testTokenTime.Unix(),
+	}))
+	s.NoError(err)
+}
+func (s *defaultClaimMapperSuite) TestTokenIssuedInFutureBeyondClockSkew() {
+	claimMapper := s.newClockSkewTestClaimMapper(nil, testTokenTime.Add(-defaultClockSkew-time.Second))
+	_, err := claimMapper.GetClaims(ctx, s.tokenWithTimes(jwt.StandardClaims{IssuedAt: testTokenTime.Unix()}))
+	s.IsType(&serviceerror.PermissionDenied{}, err)
+}
+func (s *defaultClaimMapperSuite) TestTokenNotBeforeBeyondClockSkew() {
+	claimMapper := s.newClockSkewTestClaimMapper(nil, testTokenTime.Add(-defaultClockSkew-time.Second))
+	_, err := claimMapper.GetClaims(ctx, s.tokenWithTimes(jwt.StandardClaims{NotBefore: testTokenTime.Unix()}))
+	s.IsType(&serviceerror.PermissionDenied{}, err)
+}
+func (s *defaultClaimMapperSuite) TestConfiguredClockSkew() {
+	clockSkew := 10 * time.Second
+	claimMapper := s.newClockSkewTestClaimMapper(&clockSkew, testTokenTime.Add(10*time.Second))
+	s.expectClockSkewTolerated()
+	authInfo := s.tokenWithTimes(jwt.StandardClaims{ExpiresAt: testTokenTime.Unix()})
+	_, err := claimMapper.GetClaims(ctx, authInfo)
+	s.NoError(err)
+
+	claimMapper.(*defaultJWTClaimMapper).timeSource = clock.NewEventTimeSource().Update(testTokenTime.Add(11 * time.Second))
+	_, err = claimMapper.GetClaims(ctx, authInfo)
+	s.IsType(&serviceerror.PermissionDenied{}, err)
+}
+func (s *defaultClaimMapperSuite) TestNoClockSkew() {
+	var clockSkew time.Duration
+	claimMapper := s.newClockSkewTestClaimMapper(&clockSkew, testTokenTime)
+	_, err := claimMapper.GetClaims(ctx, s.tokenWithTimes(jwt.StandardClaims{ExpiresAt: testTokenTime.Unix()}))
+	s.NoError(err)
+
+	claimMapper.(*defaultJWTClaimMapper).timeSource = clock.NewEventTimeSource().Update(testTokenTime.Add(time.Second))
+	_, err = claimMapper.GetClaims(ctx, s.tokenWithTimes(jwt.StandardClaims{ExpiresAt: testTokenTime.Unix()}))
+	s.IsType(&serviceerror.PermissionDenied{}, err)
+	_, err = claimMapper.GetClaims(ctx, s.tokenWithTimes(jwt.StandardClaims{NotBefore: testTokenTime.Add(2 * time.Second).Unix()}))
+	s.IsType(&serviceerror.PermissionDenied{}, err)
+}
 func (s *defaultClaimMapperSuite) TestGetClaimMapperFromConfigNoop() {
 	s.testGetClaimMapperFromConfig("", true, reflect.TypeOf(&noopClaimMapper{}))
 }
@@ -169,18 +363,41 @@ func (s *defaultClaimMapperSuite) testGetClaimMapperFromConfig(name string, vali
 
 	cfg := config.Config{}
 	cfg.Global.Authorization.ClaimMapper = name
-	cm, err := GetClaimMapperFromConfig(&cfg)
+	metricsClient := metrics.NewClient(tally.NoopScope, metrics.Frontend)
+	cm, err := GetClaimMapperFromConfig(&cfg, metricsClient)
 	if valid {
 		s.NoError(err)
 		s.NotNil(cm)
 		t := reflect.TypeOf(cm)
 		s.True(t == cmType)
+		if jwtClaimMapper, ok := cm.(*defaultJWTClaimMapper); ok {
+			s.Equal(metricsClient, jwtClaimMapper.metricsClient)
+		}
 	} else {
 		s.Error(err)
 		s.Nil(cm)
 	}
 }
 
+func (s *defaultClaimMapperSuite) newClockSkewTestClaimMapper(clockSkew *time.Duration, now time.Time) ClaimMapper {
+	s.config.Global.Authorization.ClockSkew = clockSkew
+	s.mockMetricsClient = metrics.NewMockClient(s.controller)
+	claimMapper := NewDefaultJWTClaimMapperWithMetrics(s.tokenGenerator, s.config, s.mockMetricsClient)
+	claimMapper.(*defaultJWTClaimMapper).timeSource = clock.NewEventTimeSource().Update(now)
+	return claimMapper
+}
+
+func (s *defaultClaimMapperSuite) expectClockSkewTolerated() {
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationClockSkewToleratedCounter)
+}
+
+func (s *defaultClaimMapperSuite) tokenWithTimes(times jwt.StandardClaims) *AuthInfo {
+	times.Subject = testSubject
+	tokenString, err := s.tokenGenerator.generateTokenWithClaims(CustomClaims{StandardClaims: times})
+	s.NoError(err)
+	return &AuthInfo{AuthToken: AddBearer(tokenString)}
+}
+
 func AddBearer(token string) string {
 	return "Bearer " + token
 }
@@ -204,7 +421,15 @@ func newTokenGenerator() *tokenGenerator {
 
 type (
 	CustomClaims struct {
-		Permissions []string `json:"permissions"`
+		Permissions []string    `json:"permissions"`
+		AuthMethods []string    `json:"amr,omitempty"`
+		AuthLevel   interface{} `json:"loa,omitempty"`
+		Groups      []string    `json:"groups,omitempty"`
+		Actor       interface{} `json:"act,omitempty"`
+		Audience    interface{} `json:"aud,omitempty"`
+		Regions     interface{} `json:"regions,omitempty"`
+		Tenant      string      `json:"tenant,omitempty"`
+		TenantID    string      `json:"tenant_id,omitempty"`
 		jwt.StandardClaims
 	}
 )
@@ -215,8 +440,10 @@ func (CustomClaims) Valid() error {
 
 func (tg *tokenGenerator) generateToken(subject string, permissions []string, options errorTestOptions) (string, error) {
 	claims := CustomClaims{
-		permissions,
-		jwt.StandardClaims{
+		Permissions: permissions,
+		AuthMethods: testAuthMethods,
+		Groups:      testGroups,
+		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: time.Now().Add(time.Hour).Unix(),
 			Issuer:    "test",
 		},
@@ -225,6 +452,14 @@ func (tg *tokenGenerator) generateToken(subject string, permissions []string, op
 		claims.Subject = subject
 	}
 
+	return tg.generateTokenWithOptions(claims, options)
+}
+
+func (tg *tokenGenerator) generateTokenWithClaims(claims CustomClaims) (string, error) {
+	return tg.generateTokenWithOptions(claims, errorTestOptionNoError)
+}
+
+func (tg *tokenGenerator) generateTokenWithOptions(claims CustomClaims, options errorTestOptions) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
 	if options&errorTestOptionNoKID == 0 {
 		token.Header["kid"] = "test-key"
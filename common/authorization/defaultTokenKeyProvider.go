@@ -30,34 +30,65 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/uber-go/tally"
 	"gopkg.in/square/go-jose.v2"
 
 	"go.temporal.io/server/common/log"
 	"go.temporal.io/server/common/log/loggerimpl"
 	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/metrics"
 	"go.temporal.io/server/common/service/config"
 )
 
+const (
+	// minimum time between two refreshes triggered by tokens with unknown key IDs
+	defaultMissRefreshInterval = 10 * time.Second
+	// time after which a failed background refresh is retried
+	refreshRetryInterval = 10 * time.Second
+	// keys that expire are refreshed in the background once this fraction of their lifetime passed
+	refreshAheadRatio = 0.8
+)
+
 // Default token key provider
 type defaultTokenKeyProvider struct {
-	config   config.JWTKeyProvider
-	rsaKeys  map[string]*rsa.PublicKey
-	ecKeys   map[string]*ecdsa.PublicKey
-	keysLock sync.RWMutex
-	ticker   *time.Ticker
-	logger   log.Logger
-	stop     chan bool
+	config        config.JWTKeyProvider
+	rsaKeys       map[string]*rsa.PublicKey
+	ecKeys        map[string]*ecdsa.PublicKey
+	keysExpireAt  time.Time // zero if the keys don't expire
+	keysLock      sync.RWMutex
+	logger        log.Logger
+	metricsClient metrics.Client
+
+	// refreshLock serializes refreshes, so that concurrent key misses share a single fetch
+	refreshLock         sync.Mutex
+	lastRefreshAttempt  time.Time
+	missRefreshInterval time.Duration
+	refreshTimer        *time.Timer
+	closed              bool
 }
 
 var _ TokenKeyProvider = (*defaultTokenKeyProvider)(nil)
 
 func NewDefaultTokenKeyProvider(cfg *config.Config) *defaultTokenKeyProvider {
+	return NewDefaultTokenKeyProviderWithMetrics(cfg, metrics.NewClient(tally.NoopScope, metrics.Frontend))
+}
+
+// NewDefaultTokenKeyProviderWithMetrics creates a token key provider that reports key refreshes to metricsClient.
+// Keys are refreshed in the background every RefreshInterval, and ahead of their expiry if the key sources
+// limit their lifetime with the max-age of Cache-Control, so lookups don't wait for a refresh while keys are valid.
+func NewDefaultTokenKeyProviderWithMetrics(cfg *config.Config, metricsClient metrics.Client) *defaultTokenKeyProvider {
 	logger := loggerimpl.NewLogger(cfg.Log.NewZapLogger())
-	provider := defaultTokenKeyProvider{config: cfg.Global.Authorization.JWTKeyProvider, logger: logger}
+	provider := defaultTokenKeyProvider{
+		config:              cfg.Global.Authorization.JWTKeyProvider,
+		logger:              logger,
+		metricsClient:       metricsClient,
+		missRefreshInterval: defaultMissRefreshInterval,
+	}
 	provider.init()
 	return &provider
 }
@@ -66,22 +97,20 @@ func (a *defaultTokenKeyProvider) init() {
 	a.rsaKeys = make(map[string]*rsa.PublicKey)
 	a.ecKeys = make(map[string]*ecdsa.PublicKey)
 	if len(a.config.KeySourceURIs) > 0 {
-		err := a.updateKeys()
+		err := a.refreshKeys()
 		if err != nil {
 			a.logger.Error("error during initial retrieval of token keys: ", tag.Error(err))
 		}
 	}
-	if a.config.RefreshInterval > 0 {
-		a.stop = make(chan bool)
-		a.ticker = time.NewTicker(a.config.RefreshInterval)
-		go a.timerCallback()
-	}
 }
 
 func (a *defaultTokenKeyProvider) Close() {
-	a.ticker.Stop()
-	a.stop <- true
-	close(a.stop)
+	a.refreshLock.Lock()
+	defer a.refreshLock.Unlock()
+	a.closed = true
+	if a.refreshTimer != nil {
+		a.refreshTimer.Stop()
+	}
 }
 
 func (a *defaultTokenKeyProvider) RsaKey(alg string, kid string) (*rsa.PublicKey, error) {
@@ -89,83 +118,188 @@ func (a *defaultTokenKeyProvider) RsaKey(alg string, kid string) (*rsa.PublicKey
 		return nil, fmt.Errorf("unexpected signing algorithm: %s", alg)
 	}
 
-	a.keysLock.RLock()
-	key, found := a.rsaKeys[kid]
-	a.keysLock.RUnlock()
+	key, found, expired := a.rsaKey(kid)
+	if (!found || expired) && a.refreshOnMiss() {
+		key, found, _ = a.rsaKey(kid)
+	}
 	if !found {
 		return nil, fmt.Errorf("RSA key not found for key ID: %s", kid)
 	}
 	return key, nil
 }
 
+func (a *defaultTokenKeyProvider) rsaKey(kid string) (*rsa.PublicKey, bool, bool) {
+	a.keysLock.RLock()
+	defer a.keysLock.RUnlock()
+	key, found := a.rsaKeys[kid]
+	return key, found, a.keysExpiredLocked()
+}
+
 func (a *defaultTokenKeyProvider) EcdsaKey(alg string, kid string) (*ecdsa.PublicKey, error) {
 	if !strings.EqualFold(alg, "ec256") {
 		return nil, fmt.Errorf("unexpected signing algorithm: %s", alg)
 	}
 
-	a.keysLock.RLock()
-	key, found := a.ecKeys[kid]
-	a.keysLock.RUnlock()
+	key, found, expired := a.ecdsaKey(kid)
+	if (!found || expired) && a.refreshOnMiss() {
+		key, found, _ = a.ecdsaKey(kid)
+	}
 	if !found {
 		return nil, fmt.Errorf("ECDSA key not found for key ID: %s", kid)
 	}
 	return key, nil
 }
 
-func (a *defaultTokenKeyProvider) timerCallback() {
-	for {
-		select {
-		case <-a.stop:
-			break
-		case <-a.ticker.C:
-		}
-		if len(a.config.KeySourceURIs) > 0 {
-			err := a.updateKeys()
-			if err != nil {
-				a.logger.Error("error while refreshing token keys: ", tag.Error(err))
-			}
+func (a *defaultTokenKeyProvider) ecdsaKey(kid string) (*ecdsa.PublicKey, bool, bool) {
+	a.keysLock.RLock()
+	defer a.keysLock.RUnlock()
+	key, found := a.ecKeys[kid]
+	return key, found, a.keysExpiredLocked()
+}
+
+// keysExpiredLocked checks if the keys outlived the lifetime the key sources gave them, it must be called
+// while holding keysLock
+func (a *defaultTokenKeyProvider) keysExpiredLocked() bool {
+	return !a.keysExpireAt.IsZero() && !time.Now().Before(a.keysExpireAt)
+}
+
+// backgroundRefresh refreshes keys ahead of their expiry or periodically, see scheduleRefreshLocked
+func (a *defaultTokenKeyProvider) backgroundRefresh() {
+	a.refreshLock.Lock()
+	defer a.refreshLock.Unlock()
+	if a.closed {
+		return
+	}
+	if err := a.refreshKeysLocked(); err != nil {
+		a.logger.Error("error while refreshing token keys: ", tag.Error(err))
+	}
+}
+
+// scheduleRefreshLocked schedules the next background refresh after delay, replacing the scheduled one.
+// Nothing is scheduled for a delay that is not positive. It must be called while holding refreshLock.
+func (a *defaultTokenKeyProvider) scheduleRefreshLocked(delay time.Duration) {
+	if a.closed || delay <= 0 {
+		return
+	}
+	if a.refreshTimer != nil {
+		a.refreshTimer.Stop()
+	}
+	a.refreshTimer = time.AfterFunc(delay, a.backgroundRefresh)
+}
+
+// refreshOnMiss synchronously refreshes keys after a lookup of an unknown key ID, e.g. right after the keys
+// were rotated by the issuer, or of keys that expired since no background refresh succeeded in time.
+// Lookups of known, unexpired key IDs never wait for a refresh, since the new keys replace the old ones
+// only once they are fully retrieved. Keys that expired are still served if the refresh fails.
+// Returns true if the lookup is worth retrying.
+func (a *defaultTokenKeyProvider) refreshOnMiss() bool {
+	if len(a.config.KeySourceURIs) == 0 {
+		return false
+	}
+
+	a.refreshLock.Lock()
+	defer a.refreshLock.Unlock()
+	// Keys were refreshed recently, possibly while this call waited for the lock.
+	// Don't let tokens with bogus key IDs hammer the key sources.
+	if time.Since(a.lastRefreshAttempt) < a.missRefreshInterval {
+		return true
+	}
+	err := a.refreshKeysLocked()
+	if err != nil {
+		a.logger.Error("error while refreshing token keys after key miss: ", tag.Error(err))
+		return false
+	}
+	return true
+}
+
+func (a *defaultTokenKeyProvider) refreshKeys() error {
+	a.refreshLock.Lock()
+	defer a.refreshLock.Unlock()
+	return a.refreshKeysLocked()
+}
+
+func (a *defaultTokenKeyProvider) refreshKeysLocked() error {
+	a.lastRefreshAttempt = time.Now()
+	lifetime, err := a.updateKeys()
+	if err != nil {
+		a.metricsClient.IncCounter(metrics.AuthorizationScope, metrics.ServiceErrAuthorizationKeyRefreshFailedCounter)
+		if a.config.RefreshInterval > 0 || a.keysExpire() {
+			a.scheduleRefreshLocked(refreshRetryInterval)
 		}
+		return err
 	}
+	a.metricsClient.IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationKeyRefreshCounter)
+	a.scheduleRefreshLocked(a.refreshDelay(lifetime))
+	return nil
 }
 
-func (a *defaultTokenKeyProvider) updateKeys() error {
+// refreshDelay returns the time until the next background refresh of keys that expire after lifetime,
+// zero if the keys neither expire nor are refreshed periodically
+func (a *defaultTokenKeyProvider) refreshDelay(lifetime time.Duration) time.Duration {
+	delay := a.config.RefreshInterval
+	if ahead := time.Duration(float64(lifetime) * refreshAheadRatio); lifetime > 0 && (delay <= 0 || ahead < delay) {
+		delay = ahead
+	}
+	return delay
+}
+
+func (a *defaultTokenKeyProvider) keysExpire() bool {
+	a.keysLock.RLock()
+	defer a.keysLock.RUnlock()
+	return !a.keysExpireAt.IsZero()
+}
+
+// updateKeys retrieves the keys from all key sources and returns their lifetime, the shortest max-age of the
+// responses, or zero if no key source limited it
+func (a *defaultTokenKeyProvider) updateKeys() (time.Duration, error) {
 	if len(a.config.KeySourceURIs) == 0 {
-		return fmt.Errorf("no URIs configured for retrieving token keys")
+		return 0, fmt.Errorf("no URIs configured for retrieving token keys")
 	}
 
 	rsaKeys := make(map[string]*rsa.PublicKey)
 	ecKeys := make(map[string]*ecdsa.PublicKey)
 
+	var lifetime time.Duration
 	for _, uri := range a.config.KeySourceURIs {
-		err := a.updateKeysFromURI(uri, rsaKeys, ecKeys)
+		maxAge, err := a.updateKeysFromURI(uri, rsaKeys, ecKeys)
 		if err != nil {
-			return err
+			return 0, err
 		}
+		if maxAge > 0 && (lifetime == 0 || maxAge < lifetime) {
+			lifetime = maxAge
+		}
+	}
+	var expireAt time.Time
+	if lifetime > 0 {
+		expireAt = time.Now().Add(lifetime)
 	}
 	// swap old keys with the new ones
 	a.keysLock.Lock()
 	a.rsaKeys = rsaKeys
 	a.ecKeys = ecKeys
+	a.keysExpireAt = expireAt
 	a.keysLock.Unlock()
-	return nil
+	return lifetime, nil
 }
 
+// updateKeysFromURI adds the keys of the key source at uri to rsaKeys and ecKeys,
+// and returns the max-age of the response, zero if it has none
 func (a *defaultTokenKeyProvider) updateKeysFromURI(
 	uri string,
 	rsaKeys map[string]*rsa.PublicKey,
 	ecKeys map[string]*ecdsa.PublicKey,
-) error {
+) (time.Duration, error) {
 
 	resp, err := http.Get(uri)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer resp.Body.Close()
 
 	jwks := jose.JSONWebKeySet{}
 	err = json.NewDecoder(resp.Body).Decode(&jwks)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	for _, k := range jwks.Keys {
@@ -178,7 +312,23 @@ func (a *defaultTokenKeyProvider) updateKeysFromURI(
 			a.logger.Warn(fmt.Sprintf("unexpected type of JWKS public key %s", k.Algorithm))
 		}
 	}
-	return nil
+	return maxAge(resp.Header.Get("Cache-Control")), nil
+}
+
+// maxAge returns the max-age directive of a Cache-Control header value, zero if it has none
+func maxAge(cacheControl string) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.TrimSpace(directive)
+		if !strings.HasPrefix(strings.ToLower(directive), "max-age=") {
+			continue
+		}
+		seconds, err := strconv.Atoi(directive[len("max-age="):])
+		if err != nil || seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	return 0
 }
 
 func (a *defaultTokenKeyProvider) HmacKey(alg string, kid string) ([]byte, error) {
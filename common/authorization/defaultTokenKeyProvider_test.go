@@ -0,0 +1,260 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/square/go-jose.v2"
+
+	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/service/config"
+)
+
+type (
+	tokenKeyProviderSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller        *gomock.Controller
+		mockMetricsClient *metrics.MockClient
+		keySource         *testKeySource
+		server            *httptest.Server
+	}
+
+	testKeySource struct {
+		sync.Mutex
+		keys    map[string]*rsa.PublicKey
+		maxAge  int
+		blockCh chan struct{}
+		fetchCh chan struct{}
+	}
+)
+
+func TestTokenKeyProviderSuite(t *testing.T) {
+	s := new(tokenKeyProviderSuite)
+	suite.Run(t, s)
+}
+
+func (s *tokenKeyProviderSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockMetricsClient = metrics.NewMockClient(s.controller)
+	s.keySource = &testKeySource{keys: map[string]*rsa.PublicKey{"key1": s.newKey()}}
+	s.server = httptest.NewServer(s.keySource)
+}
+
+func (s *tokenKeyProviderSuite) TearDownTest() {
+	s.server.Close()
+	s.controller.Finish()
+}
+
+func (s *tokenKeyProviderSuite) TestKeyRotation() {
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationKeyRefreshCounter).Times(3)
+	provider := s.newProvider()
+	provider.missRefreshInterval = 0
+
+	_, err := provider.RsaKey("RS256", "key1")
+	s.NoError(err)
+
+	s.keySource.setKeys(map[string]*rsa.PublicKey{"key2": s.newKey()})
+	key, err := provider.RsaKey("RS256", "key2")
+	s.NoError(err)
+	s.NotNil(key)
+	_, err = provider.RsaKey("RS256", "key1")
+	s.Error(err)
+}
+
+func (s *tokenKeyProviderSuite) TestMissRefreshRateLimited() {
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationKeyRefreshCounter).Times(1)
+	provider := s.newProvider()
+
+	s.keySource.setKeys(map[string]*rsa.PublicKey{"key2": s.newKey()})
+	_, err := provider.RsaKey("RS256", "key2")
+	s.Error(err)
+}
+
+func (s *tokenKeyProviderSuite) TestRefreshFailure() {
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationKeyRefreshCounter).Times(1)
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceErrAuthorizationKeyRefreshFailedCounter).Times(1)
+	provider := s.newProvider()
+	provider.missRefreshInterval = 0
+	s.server.Close()
+
+	_, err := provider.RsaKey("RS256", "key2")
+	s.Error(err)
+	// stale keys are still served
+	_, err = provider.RsaKey("RS256", "key1")
+	s.NoError(err)
+}
+
+func (s *tokenKeyProviderSuite) TestNoBlockingDuringBackgroundRefresh() {
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationKeyRefreshCounter).Times(2)
+	provider := s.newProvider()
+
+	s.keySource.block()
+	refreshDone := make(chan error)
+	go func() {
+		refreshDone <- provider.refreshKeys()
+	}()
+	<-s.keySource.fetchCh
+
+	lookupDone := make(chan error)
+	go func() {
+		_, err := provider.RsaKey("RS256", "key1")
+		lookupDone <- err
+	}()
+	select {
+	case err := <-lookupDone:
+		s.NoError(err)
+	case <-time.After(5 * time.Second):
+		s.Fail("key lookup blocked on background refresh")
+	}
+
+	s.keySource.unblock()
+	s.NoError(<-refreshDone)
+}
+
+func (s *tokenKeyProviderSuite) TestPeriodicBackgroundRefresh() {
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationKeyRefreshCounter).MinTimes(2)
+	provider := s.newProviderWithRefreshInterval(50 * time.Millisecond)
+	defer provider.Close()
+
+	s.keySource.setKeys(map[string]*rsa.PublicKey{"key2": s.newKey()})
+	s.Eventually(func() bool {
+		_, found, _ := provider.rsaKey("key2")
+		return found
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func (s *tokenKeyProviderSuite) TestBackgroundRefreshAheadOfExpiry() {
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationKeyRefreshCounter).MinTimes(2)
+	s.keySource.setMaxAge(1)
+	provider := s.newProvider()
+	defer provider.Close()
+
+	s.keySource.setKeys(map[string]*rsa.PublicKey{"key2": s.newKey()})
+	// the keys expire after a second, they are refreshed before
+	s.Eventually(func() bool {
+		_, found, expired := provider.rsaKey("key2")
+		return found && !expired
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func (s *tokenKeyProviderSuite) TestExpiredKeysRefreshedOnLookup() {
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationKeyRefreshCounter).Times(2)
+	provider := s.newProvider()
+	provider.missRefreshInterval = 0
+	provider.keysLock.Lock()
+	provider.keysExpireAt = time.Now().Add(-time.Second)
+	provider.keysLock.Unlock()
+
+	rotated := s.newKey()
+	s.keySource.setKeys(map[string]*rsa.PublicKey{"key1": rotated})
+	key, err := provider.RsaKey("RS256", "key1")
+	s.NoError(err)
+	s.Equal(rotated, key)
+}
+
+func (s *tokenKeyProviderSuite) TestMaxAge() {
+	s.Equal(time.Duration(0), maxAge(""))
+	s.Equal(time.Duration(0), maxAge("no-cache"))
+	s.Equal(time.Duration(0), maxAge("max-age=invalid"))
+	s.Equal(time.Duration(0), maxAge("max-age=0"))
+	s.Equal(300*time.Second, maxAge("max-age=300"))
+	s.Equal(300*time.Second, maxAge("public, Max-Age=300, must-revalidate"))
+}
+
+func (s *tokenKeyProviderSuite) newProvider() *defaultTokenKeyProvider {
+	return s.newProviderWithRefreshInterval(0)
+}
+
+func (s *tokenKeyProviderSuite) newProviderWithRefreshInterval(refreshInterval time.Duration) *defaultTokenKeyProvider {
+	cfg := &config.Config{}
+	cfg.Global.Authorization.JWTKeyProvider.KeySourceURIs = []string{s.server.URL}
+	cfg.Global.Authorization.JWTKeyProvider.RefreshInterval = refreshInterval
+	return NewDefaultTokenKeyProviderWithMetrics(cfg, s.mockMetricsClient)
+}
+
+func (s *tokenKeyProviderSuite) newKey() *rsa.PublicKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.NoError(err)
+	return &key.PublicKey
+}
+
+func (ks *testKeySource) setKeys(keys map[string]*rsa.PublicKey) {
+	ks.Lock()
+	defer ks.Unlock()
+	ks.keys = keys
+}
+
+func (ks *testKeySource) setMaxAge(seconds int) {
+	ks.Lock()
+	defer ks.Unlock()
+	ks.maxAge = seconds
+}
+
+func (ks *testKeySource) block() {
+	ks.Lock()
+	defer ks.Unlock()
+	ks.blockCh = make(chan struct{})
+	ks.fetchCh = make(chan struct{})
+}
+
+func (ks *testKeySource) unblock() {
+	close(ks.blockCh)
+}
+
+func (ks *testKeySource) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	ks.Lock()
+	blockCh, fetchCh := ks.blockCh, ks.fetchCh
+	ks.Unlock()
+	if blockCh != nil {
+		close(fetchCh)
+		<-blockCh
+	}
+
+	ks.Lock()
+	defer ks.Unlock()
+	if ks.maxAge > 0 {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", ks.maxAge))
+	}
+	jwks := jose.JSONWebKeySet{}
+	for kid, key := range ks.keys {
+		jwks.Keys = append(jwks.Keys, jose.JSONWebKey{Key: key, KeyID: kid, Algorithm: "RS256", Use: "sig"})
+	}
+	_ = json.NewEncoder(w).Encode(jwks)
+}
@@ -0,0 +1,186 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/metrics"
+)
+
+type (
+	// DenyCooldownConfig configures the authorizer created by NewDenyCooldownAuthorizer
+	DenyCooldownConfig struct {
+		// MaxDenials is the number of denied calls within Window that puts a subject into cooldown
+		MaxDenials int
+		// Window is the period denied calls are counted over
+		Window time.Duration
+		// Cooldown is the period during which all calls of a subject are denied once it reached MaxDenials
+		Cooldown time.Duration
+		// MaxSubjects bounds the number of tracked subjects, the least recently denied are evicted first.
+		// Zero disables tracking.
+		MaxSubjects int
+	}
+
+	denyCooldownAuthorizer struct {
+		authorizer    Authorizer
+		config        DenyCooldownConfig
+		metricsClient metrics.Client
+		timeSource    clock.TimeSource
+
+		sync.Mutex
+		subjects map[string]*list.Element // values are *subjectDenials
+		byDenial *list.List               // most recently denied subject first
+	}
+
+	// subjectDenials tracks the calls of a subject denied within the current window
+	subjectDenials struct {
+		subject       string
+		windowStart   time.Time
+		lastDenial    time.Time
+		denials       int
+		cooldownUntil time.Time
+	}
+)
+
+var _ Authorizer = (*denyCooldownAuthorizer)(nil)
+
+// NewDenyCooldownAuthorizer creates an authorizer that counts the calls of each subject denied by authorizer,
+// and puts subjects denied MaxDenials times within a window into a cooldown, such as a stolen token probing
+// for permissions. During the cooldown all calls of the subject are denied without consulting authorizer,
+// and fail with a ResourceExhausted error telling when to retry. Calls without a subject are not tracked,
+// delegated calls are tracked for the end user.
+// Subjects neither denied within the window nor in cooldown are forgotten, so memory use is bounded
+// by MaxSubjects.
+func NewDenyCooldownAuthorizer(
+	authorizer Authorizer,
+	config DenyCooldownConfig,
+	metricsClient metrics.Client,
+	timeSource clock.TimeSource,
+) Authorizer {
+	return &denyCooldownAuthorizer{
+		authorizer:    authorizer,
+		config:        config,
+		metricsClient: metricsClient,
+		timeSource:    timeSource,
+		subjects:      make(map[string]*list.Element),
+		byDenial:      list.New(),
+	}
+}
+
+func (a *denyCooldownAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if claims == nil || claims.EffectiveClaims().Subject == "" || a.config.MaxSubjects <= 0 {
+		return a.authorizer.Authorize(ctx, claims, target)
+	}
+	subject := claims.EffectiveClaims().Subject
+	if remaining := a.cooldownRemaining(subject); remaining > 0 {
+		a.metricsClient.IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationDenyCooldownCounter)
+		return NewDenyResult(NewDenyReason(ReasonDenyCooldown,
+			fmt.Sprintf("Too many denied requests, retry after %v.", remaining))), nil
+	}
+	result, err := a.authorizer.Authorize(ctx, claims, target)
+	if err == nil && result.Decision != DecisionAllow {
+		a.recordDenial(subject)
+	}
+	return result, err
+}
+
+// cooldownRemaining returns the time until the cooldown of subject ends, rounded up to a second,
+// zero if the subject is not in cooldown
+func (a *denyCooldownAuthorizer) cooldownRemaining(subject string) time.Duration {
+	now := a.timeSource.Now()
+
+	a.Lock()
+	defer a.Unlock()
+
+	element, ok := a.subjects[subject]
+	if !ok {
+		return 0
+	}
+	remaining := element.Value.(*subjectDenials).cooldownUntil.Sub(now)
+	if remaining <= 0 {
+		return 0
+	}
+	if rounded := remaining.Truncate(time.Second); rounded != remaining {
+		return rounded + time.Second
+	}
+	return remaining
+}
+
+// recordDenial counts a denied call of subject, and starts the cooldown of the subject once it reaches MaxDenials
+func (a *denyCooldownAuthorizer) recordDenial(subject string) {
+	now := a.timeSource.Now()
+
+	a.Lock()
+	defer a.Unlock()
+
+	a.expireSubjects(now)
+	element, ok := a.subjects[subject]
+	if ok {
+		a.byDenial.MoveToFront(element)
+	} else {
+		if len(a.subjects) >= a.config.MaxSubjects {
+			oldest := a.byDenial.Remove(a.byDenial.Back()).(*subjectDenials)
+			delete(a.subjects, oldest.subject)
+		}
+		element = a.byDenial.PushFront(&subjectDenials{subject: subject, windowStart: now})
+		a.subjects[subject] = element
+	}
+
+	denials := element.Value.(*subjectDenials)
+	denials.lastDenial = now
+	if !now.Before(denials.windowStart.Add(a.config.Window)) {
+		denials.windowStart = now
+		denials.denials = 0
+	}
+	denials.denials++
+	if denials.denials >= a.config.MaxDenials {
+		denials.cooldownUntil = now.Add(a.config.Cooldown)
+		denials.windowStart = now
+		denials.denials = 0
+	}
+}
+
+// expireSubjects forgets the subjects whose last denial is older than the window and whose cooldown has ended.
+// Both are measured from the last denial, so the least recently denied subjects expire first.
+func (a *denyCooldownAuthorizer) expireSubjects(now time.Time) {
+	retention := a.config.Window
+	if a.config.Cooldown > retention {
+		retention = a.config.Cooldown
+	}
+	for element := a.byDenial.Back(); element != nil; element = a.byDenial.Back() {
+		denials := element.Value.(*subjectDenials)
+		if !denials.lastDenial.Before(now.Add(-retention)) {
+			return
+		}
+		a.byDenial.Remove(element)
+		delete(a.subjects, denials.subject)
+	}
+}
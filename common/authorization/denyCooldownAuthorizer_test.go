@@ -0,0 +1,185 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/metrics"
+)
+
+type (
+	denyCooldownAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller        *gomock.Controller
+		mockMetricsClient *metrics.MockClient
+		timeSource        *clock.EventTimeSource
+		config            DenyCooldownConfig
+		authorizer        Authorizer
+	}
+)
+
+func TestDenyCooldownAuthorizerSuite(t *testing.T) {
+	s := new(denyCooldownAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *denyCooldownAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockMetricsClient = metrics.NewMockClient(s.controller)
+	s.timeSource = clock.NewEventTimeSource().Update(time.Unix(0, 0))
+	s.config = DenyCooldownConfig{MaxDenials: 3, Window: time.Minute, Cooldown: 5 * time.Minute, MaxSubjects: 10}
+	// readers may describe namespaces but not start workflows
+	s.authorizer = NewStaticAuthorizer(map[Role][]string{
+		RoleReader: {describeNamespaceTarget.APIName},
+		RoleWriter: {startWorkflowExecutionTarget.APIName},
+	})
+}
+
+func (s *denyCooldownAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *denyCooldownAuthorizerSuite) TestEnterCooldown() {
+	authorizer := NewDenyCooldownAuthorizer(s.authorizer, s.config, s.mockMetricsClient, s.timeSource)
+	for i := 0; i < 3; i++ {
+		s.Equal(ReasonInsufficientRole, s.assertDecision(authorizer, "alice", startWorkflowExecutionTarget, DecisionDeny).Reason)
+	}
+
+	// calls the inner authorizer would allow are denied during the cooldown too
+	s.timeSource.Update(time.Unix(30, 0))
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationDenyCooldownCounter)
+	result := s.assertDecision(authorizer, "alice", describeNamespaceTarget, DecisionDeny)
+	s.Equal(NewDenyResult(NewDenyReason(ReasonDenyCooldown, "Too many denied requests, retry after 4m30s.")), result)
+
+	// other subjects are counted separately
+	s.assertDecision(authorizer, "bob", describeNamespaceTarget, DecisionAllow)
+}
+
+func (s *denyCooldownAuthorizerSuite) TestExitCooldown() {
+	authorizer := NewDenyCooldownAuthorizer(s.authorizer, s.config, s.mockMetricsClient, s.timeSource)
+	for i := 0; i < 3; i++ {
+		s.assertDecision(authorizer, "alice", startWorkflowExecutionTarget, DecisionDeny)
+	}
+	s.timeSource.Update(time.Unix(299, 500000000))
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationDenyCooldownCounter)
+	result := s.assertDecision(authorizer, "alice", describeNamespaceTarget, DecisionDeny)
+	s.Equal("Too many denied requests, retry after 1s.", result.DenyReason.Message)
+
+	s.timeSource.Update(time.Unix(300, 0))
+	s.assertDecision(authorizer, "alice", describeNamespaceTarget, DecisionAllow)
+	// the denials before the cooldown don't count again
+	for i := 0; i < 2; i++ {
+		s.assertDecision(authorizer, "alice", startWorkflowExecutionTarget, DecisionDeny)
+	}
+	s.assertDecision(authorizer, "alice", describeNamespaceTarget, DecisionAllow)
+}
+
+func (s *denyCooldownAuthorizerSuite) TestDenialsOutsideWindow() {
+	authorizer := NewDenyCooldownAuthorizer(s.authorizer, s.config, s.mockMetricsClient, s.timeSource)
+	for i := 0; i < 2; i++ {
+		s.assertDecision(authorizer, "alice", startWorkflowExecutionTarget, DecisionDeny)
+	}
+	s.timeSource.Update(time.Unix(60, 0))
+	for i := 0; i < 2; i++ {
+		s.assertDecision(authorizer, "alice", startWorkflowExecutionTarget, DecisionDeny)
+	}
+	s.assertDecision(authorizer, "alice", describeNamespaceTarget, DecisionAllow)
+}
+
+func (s *denyCooldownAuthorizerSuite) TestCallsWithoutSubject() {
+	authorizer := NewDenyCooldownAuthorizer(s.authorizer, s.config, s.mockMetricsClient, s.timeSource).(*denyCooldownAuthorizer)
+	for i := 0; i < 5; i++ {
+		_, err := authorizer.Authorize(ctx, nil, startWorkflowExecutionTarget)
+		s.NoError(err)
+	}
+	s.Empty(authorizer.subjects)
+}
+
+func (s *denyCooldownAuthorizerSuite) TestSubjectsExpire() {
+	authorizer := NewDenyCooldownAuthorizer(s.authorizer, s.config, s.mockMetricsClient, s.timeSource).(*denyCooldownAuthorizer)
+	for i := 0; i < 3; i++ {
+		s.assertDecision(authorizer, "alice", startWorkflowExecutionTarget, DecisionDeny)
+	}
+	s.timeSource.Update(time.Unix(60, 0))
+	s.assertDecision(authorizer, "bob", startWorkflowExecutionTarget, DecisionDeny)
+	s.Len(authorizer.subjects, 2)
+
+	// subjects are kept until the end of a cooldown starting with their last denial
+	s.timeSource.Update(time.Unix(301, 0))
+	s.assertDecision(authorizer, "carol", startWorkflowExecutionTarget, DecisionDeny)
+	s.Len(authorizer.subjects, 2)
+	s.NotContains(authorizer.subjects, "alice")
+}
+
+func (s *denyCooldownAuthorizerSuite) TestMaxSubjects() {
+	s.config.MaxSubjects = 2
+	authorizer := NewDenyCooldownAuthorizer(s.authorizer, s.config, s.mockMetricsClient, s.timeSource).(*denyCooldownAuthorizer)
+	for i := 0; i < 3; i++ {
+		s.assertDecision(authorizer, fmt.Sprintf("subject-%d", i), startWorkflowExecutionTarget, DecisionDeny)
+	}
+	s.Len(authorizer.subjects, 2)
+	s.NotContains(authorizer.subjects, "subject-0")
+}
+
+func (s *denyCooldownAuthorizerSuite) TestDelegatedCalls() {
+	authorizer := NewDenyCooldownAuthorizer(NewOnBehalfOfAuthorizer(s.authorizer, []string{"gateway"}),
+		s.config, s.mockMetricsClient, s.timeSource)
+	onBehalfOf := func(subject string) *Claims {
+		return &Claims{Subject: "gateway", OnBehalfOf: &Claims{Subject: subject, Namespaces: map[string]Role{testNamespace: RoleReader}}}
+	}
+	for i := 0; i < 3; i++ {
+		result, err := authorizer.Authorize(ctx, onBehalfOf("alice"), startWorkflowExecutionTarget)
+		s.NoError(err)
+		s.Equal(ReasonInsufficientRole, result.Reason)
+	}
+
+	// the end user is put into cooldown, not the actor making calls for all end users
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationDenyCooldownCounter)
+	result, err := authorizer.Authorize(ctx, onBehalfOf("alice"), describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(ReasonDenyCooldown, result.Reason)
+	result, err = authorizer.Authorize(ctx, onBehalfOf("bob"), describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *denyCooldownAuthorizerSuite) assertDecision(authorizer Authorizer, subject string, target *CallTarget, decision Decision) Result {
+	claims := &Claims{Subject: subject, Namespaces: map[string]Role{testNamespace: RoleReader}}
+	result, err := authorizer.Authorize(ctx, claims, target)
+	s.NoError(err)
+	s.Equal(decision, result.Decision)
+	return result
+}
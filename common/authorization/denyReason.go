@@ -0,0 +1,84 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+// DenyReason explains a deny decision in detail. It is an error that wraps the DenyReason of the decision it was
+// derived from, so that authorizers composing other authorizers can attach their own context to the reason of the
+// inner authorizer instead of replacing it, e.g. "blocklist: subject revoked" wrapping "base: insufficient role".
+// The chain can be walked with errors.Unwrap and errors.As.
+type DenyReason struct {
+	// Code of the reason, the ReasonCode of the Result the reason explains
+	Code ReasonCode
+	// Message is a human-readable explanation, without the messages of the reasons it wraps
+	Message string
+	cause   *DenyReason
+}
+
+// NewDenyReason creates a DenyReason that wraps no other reason
+func NewDenyReason(code ReasonCode, message string) *DenyReason {
+	return &DenyReason{Code: code, Message: message}
+}
+
+// WrapDenyReason creates a DenyReason that wraps cause, typically the DenyReason of the result of an inner
+// authorizer. A nil cause is not wrapped.
+func WrapDenyReason(cause *DenyReason, code ReasonCode, message string) *DenyReason {
+	return &DenyReason{Code: code, Message: message, cause: cause}
+}
+
+// NewDenyResult creates a deny Result explained by reason
+func NewDenyResult(reason *DenyReason) Result {
+	return Result{Decision: DecisionDeny, Reason: reason.Code, DenyReason: reason}
+}
+
+// Error returns the messages of the chain of reasons, outermost first, separated by ": "
+func (r *DenyReason) Error() string {
+	message := r.Message
+	if message == "" {
+		message = string(r.Code)
+	}
+	if r.cause == nil {
+		return message
+	}
+	return message + ": " + r.cause.Error()
+}
+
+// Equal reports whether both reasons have the same code and message, and wrap equal reasons.
+// Nil reasons only equal nil reasons.
+func (r *DenyReason) Equal(other *DenyReason) bool {
+	for ; r != nil && other != nil; r, other = r.cause, other.cause {
+		if r.Code != other.Code || r.Message != other.Message {
+			return false
+		}
+	}
+	return r == nil && other == nil
+}
+
+// Unwrap returns the reason r wraps, nil if it wraps none
+func (r *DenyReason) Unwrap() error {
+	if r.cause == nil {
+		return nil
+	}
+	return r.cause
+}
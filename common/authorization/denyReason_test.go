@@ -0,0 +1,82 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const reasonSubjectRevoked ReasonCode = "subject_revoked"
+
+func TestDenyReasonWrapping(t *testing.T) {
+	base := NewDenyReason(ReasonInsufficientRole, "base: insufficient role")
+	blocklist := WrapDenyReason(base, reasonSubjectRevoked, "blocklist: subject revoked")
+
+	require.Equal(t, "blocklist: subject revoked: base: insufficient role", blocklist.Error())
+	require.Equal(t, "base: insufficient role", base.Error())
+	require.Equal(t, base, errors.Unwrap(blocklist))
+	require.Nil(t, errors.Unwrap(base))
+	require.True(t, errors.Is(blocklist, base))
+	require.False(t, errors.Is(base, blocklist))
+}
+
+func TestDenyReasonUnwrapping(t *testing.T) {
+	base := NewDenyReason(ReasonInsufficientRole, "")
+	outer := WrapDenyReason(WrapDenyReason(base, reasonSubjectRevoked, "blocklist: subject revoked"), ReasonUnspecified, "audit")
+	require.Equal(t, "audit: blocklist: subject revoked: insufficient_role", outer.Error())
+
+	var reasons []ReasonCode
+	for err := error(outer); err != nil; err = errors.Unwrap(err) {
+		var reason *DenyReason
+		require.True(t, errors.As(err, &reason))
+		reasons = append(reasons, reason.Code)
+	}
+	require.Equal(t, []ReasonCode{ReasonUnspecified, reasonSubjectRevoked, ReasonInsufficientRole}, reasons)
+}
+
+func TestWrapNilDenyReason(t *testing.T) {
+	reason := WrapDenyReason(nil, reasonSubjectRevoked, "blocklist: subject revoked")
+	require.Equal(t, "blocklist: subject revoked", reason.Error())
+	require.Nil(t, errors.Unwrap(reason))
+}
+
+func TestDenyReasonEqual(t *testing.T) {
+	var none *DenyReason
+	reason := WrapDenyReason(NewDenyReason(ReasonInsufficientRole, "base"), reasonSubjectRevoked, "blocklist")
+	require.True(t, none.Equal(nil))
+	require.False(t, none.Equal(reason))
+	require.False(t, reason.Equal(nil))
+	require.True(t, reason.Equal(WrapDenyReason(NewDenyReason(ReasonInsufficientRole, "base"), reasonSubjectRevoked, "blocklist")))
+	require.False(t, reason.Equal(NewDenyReason(reasonSubjectRevoked, "blocklist")))
+	require.False(t, reason.Equal(WrapDenyReason(NewDenyReason(ReasonInsufficientRole, "base"), reasonSubjectRevoked, "other")))
+}
+
+func TestNewDenyResult(t *testing.T) {
+	reason := NewDenyReason(reasonSubjectRevoked, "blocklist: subject revoked")
+	require.Equal(t, Result{Decision: DecisionDeny, Reason: reasonSubjectRevoked, DenyReason: reason}, NewDenyResult(reason))
+}
@@ -0,0 +1,96 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// DisasterRecoveryBypass is the set of namespaces whose calls the authorization interceptor allows without
+// authorizing them, to restore operations during disaster recovery when authorization itself is broken.
+// It disables all access control for the listed namespaces: it must only be used temporarily and
+// namespaces must be removed as soon as authorization works again. It is safe for concurrent use.
+type DisasterRecoveryBypass struct {
+	updateLock sync.Mutex
+	namespaces atomic.Value // map[string]struct{}, replaced on every update
+}
+
+// NewDisasterRecoveryBypass creates a disaster recovery bypass that initially bypasses no namespace
+func NewDisasterRecoveryBypass() *DisasterRecoveryBypass {
+	b := &DisasterRecoveryBypass{}
+	b.namespaces.Store(map[string]struct{}{})
+	return b
+}
+
+// UnsafeBypassNamespace turns off authorization for all calls to namespace
+func (b *DisasterRecoveryBypass) UnsafeBypassNamespace(namespace string) {
+	b.update(func(namespaces map[string]struct{}) {
+		namespaces[namespace] = struct{}{}
+	})
+}
+
+// EnforceNamespace turns authorization for namespace back on
+func (b *DisasterRecoveryBypass) EnforceNamespace(namespace string) {
+	b.update(func(namespaces map[string]struct{}) {
+		delete(namespaces, namespace)
+	})
+}
+
+// IsBypassed checks if authorization is turned off for namespace
+func (b *DisasterRecoveryBypass) IsBypassed(namespace string) bool {
+	_, ok := b.load()[namespace]
+	return ok
+}
+
+// BypassedNamespaces lists the namespaces authorization is turned off for, in alphabetical order
+func (b *DisasterRecoveryBypass) BypassedNamespaces() []string {
+	namespaces := b.load()
+	bypassed := make([]string, 0, len(namespaces))
+	for namespace := range namespaces {
+		bypassed = append(bypassed, namespace)
+	}
+	sort.Strings(bypassed)
+	return bypassed
+}
+
+func (b *DisasterRecoveryBypass) load() map[string]struct{} {
+	return b.namespaces.Load().(map[string]struct{})
+}
+
+// update atomically replaces the set with a copy changed by change, so that readers never need a lock
+func (b *DisasterRecoveryBypass) update(change func(namespaces map[string]struct{})) {
+	b.updateLock.Lock()
+	defer b.updateLock.Unlock()
+
+	current := b.load()
+	updated := make(map[string]struct{}, len(current)+1)
+	for namespace := range current {
+		updated[namespace] = struct{}{}
+	}
+	change(updated)
+	b.namespaces.Store(updated)
+}
@@ -0,0 +1,66 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisasterRecoveryBypass(t *testing.T) {
+	bypass := NewDisasterRecoveryBypass()
+	require.False(t, bypass.IsBypassed(testNamespace))
+	require.Empty(t, bypass.BypassedNamespaces())
+
+	bypass.UnsafeBypassNamespace(testNamespace)
+	bypass.UnsafeBypassNamespace("another-namespace")
+	require.True(t, bypass.IsBypassed(testNamespace))
+	require.False(t, bypass.IsBypassed("other-namespace"))
+	require.Equal(t, []string{"another-namespace", testNamespace}, bypass.BypassedNamespaces())
+
+	bypass.EnforceNamespace(testNamespace)
+	require.False(t, bypass.IsBypassed(testNamespace))
+	require.True(t, bypass.IsBypassed("another-namespace"))
+}
+
+func TestDisasterRecoveryBypassConcurrentUpdates(t *testing.T) {
+	bypass := NewDisasterRecoveryBypass()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				bypass.UnsafeBypassNamespace(testNamespace)
+				_ = bypass.IsBypassed(testNamespace)
+				bypass.EnforceNamespace(testNamespace)
+			}
+		}()
+	}
+	wg.Wait()
+	require.False(t, bypass.IsBypassed(testNamespace))
+}
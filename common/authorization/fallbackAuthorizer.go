@@ -0,0 +1,65 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+
+	"github.com/uber-go/tally"
+
+	"go.temporal.io/server/common/metrics"
+)
+
+type fallbackAuthorizer struct {
+	primary       Authorizer
+	fallback      Authorizer
+	metricsClient metrics.Client
+}
+
+var _ Authorizer = (*fallbackAuthorizer)(nil)
+
+// NewFallbackAuthorizer creates an authorizer that decides calls with primary, and with fallback only
+// if primary fails with an error. Decisions made by primary, including denies, are final.
+func NewFallbackAuthorizer(primary Authorizer, fallback Authorizer) Authorizer {
+	return NewFallbackAuthorizerWithMetrics(primary, fallback, metrics.NewClient(tally.NoopScope, metrics.Frontend))
+}
+
+// NewFallbackAuthorizerWithMetrics creates a fallback authorizer that counts the calls decided by fallback
+func NewFallbackAuthorizerWithMetrics(primary Authorizer, fallback Authorizer, metricsClient metrics.Client) Authorizer {
+	return &fallbackAuthorizer{
+		primary:       primary,
+		fallback:      fallback,
+		metricsClient: metricsClient,
+	}
+}
+
+func (a *fallbackAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	result, err := a.primary.Authorize(ctx, claims, target)
+	if err == nil {
+		return result, nil
+	}
+	a.metricsClient.IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationFallbackCounter)
+	return a.fallback.Authorize(ctx, claims, target)
+}
@@ -0,0 +1,104 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"go.temporal.io/server/common/metrics"
+)
+
+type (
+	fallbackAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller        *gomock.Controller
+		mockPrimary       *MockAuthorizer
+		mockFallback      *MockAuthorizer
+		mockMetricsClient *metrics.MockClient
+		authorizer        Authorizer
+	}
+)
+
+func TestFallbackAuthorizerSuite(t *testing.T) {
+	s := new(fallbackAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *fallbackAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockPrimary = NewMockAuthorizer(s.controller)
+	s.mockFallback = NewMockAuthorizer(s.controller)
+	s.mockMetricsClient = metrics.NewMockClient(s.controller)
+	s.authorizer = NewFallbackAuthorizerWithMetrics(s.mockPrimary, s.mockFallback, s.mockMetricsClient)
+}
+
+func (s *fallbackAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *fallbackAuthorizerSuite) TestPrimaryAllow() {
+	s.mockPrimary.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, nil, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *fallbackAuthorizerSuite) TestPrimaryDeny() {
+	s.mockPrimary.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, nil, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonInsufficientRole, result.Reason)
+}
+
+func (s *fallbackAuthorizerSuite) TestPrimaryError() {
+	s.mockPrimary.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).Return(Result{}, errors.New("unavailable"))
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationFallbackCounter)
+	s.mockFallback.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, nil, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *fallbackAuthorizerSuite) TestPrimaryAndFallbackError() {
+	s.mockPrimary.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).Return(Result{}, errors.New("unavailable"))
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationFallbackCounter)
+	s.mockFallback.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).Return(Result{}, errors.New("also unavailable"))
+
+	_, err := s.authorizer.Authorize(ctx, nil, describeNamespaceTarget)
+	s.Error(err)
+}
@@ -0,0 +1,129 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"time"
+
+	"go.temporal.io/server/common/clock"
+)
+
+type (
+	// RoleDowngrade is the most recent downgrade of the role of a subject
+	RoleDowngrade struct {
+		// PreviousRole is the role the subject held before the downgrade
+		PreviousRole Role
+		// DowngradedAt is when the role was downgraded
+		DowngradedAt time.Time
+	}
+
+	// RoleHistoryProvider resolves the history of the roles granted to subjects, e.g. from the audit log
+	// of the identity provider
+	RoleHistoryProvider interface {
+		// LastDowngrade returns the most recent downgrade of the role of subject within namespace,
+		// or of its system role if namespace is empty, nil if the role was never downgraded
+		LastDowngrade(ctx context.Context, subject string, namespace string) (*RoleDowngrade, error)
+	}
+
+	gracePeriodAuthorizer struct {
+		authorizer      Authorizer
+		history         RoleHistoryProvider
+		gracePeriod     time.Duration
+		nonCriticalAPIs map[string]struct{}
+		timeSource      clock.TimeSource
+	}
+)
+
+var _ Authorizer = (*gracePeriodAuthorizer)(nil)
+
+// NewGracePeriodAuthorizer creates an authorizer that keeps allowing subjects whose role was downgraded less than
+// gracePeriod ago the calls to nonCriticalAPIs their previous role was allowed, so that sessions in flight are not
+// cut off abruptly. Calls denied by authorizer are authorized again with the previous role of the subject in the
+// target namespace, as resolved by history; calls to all other APIs are denied right after the downgrade.
+// For delegated calls the role of the end user applies.
+func NewGracePeriodAuthorizer(
+	authorizer Authorizer,
+	history RoleHistoryProvider,
+	gracePeriod time.Duration,
+	nonCriticalAPIs []string,
+	timeSource clock.TimeSource,
+) Authorizer {
+	a := &gracePeriodAuthorizer{
+		authorizer:      authorizer,
+		history:         history,
+		gracePeriod:     gracePeriod,
+		nonCriticalAPIs: make(map[string]struct{}, len(nonCriticalAPIs)),
+		timeSource:      timeSource,
+	}
+	for _, api := range nonCriticalAPIs {
+		a.nonCriticalAPIs[api] = struct{}{}
+	}
+	return a
+}
+
+func (a *gracePeriodAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	result, err := a.authorizer.Authorize(ctx, claims, target)
+	if err != nil || result.Decision == DecisionAllow || claims == nil {
+		return result, err
+	}
+	if _, ok := a.nonCriticalAPIs[target.APIName]; !ok {
+		return result, nil
+	}
+
+	downgrade, err := a.history.LastDowngrade(ctx, claims.EffectiveClaims().Subject, target.Namespace)
+	if err != nil {
+		return Result{}, err
+	}
+	if downgrade == nil || !a.timeSource.Now().Before(downgrade.DowngradedAt.Add(a.gracePeriod)) {
+		return result, nil
+	}
+	graceResult, err := a.authorizer.Authorize(ctx, withPreviousRole(claims, target.Namespace, downgrade.PreviousRole), target)
+	if err != nil || graceResult.Decision != DecisionAllow {
+		return result, err
+	}
+	return graceResult, nil
+}
+
+// withPreviousRole returns a copy of claims in which the subject the call is made for holds role within namespace,
+// or at the system level if namespace is empty, instead of its current role
+func withPreviousRole(claims *Claims, namespace string, role Role) *Claims {
+	if claims.OnBehalfOf != nil {
+		actorClaims := *claims
+		actorClaims.OnBehalfOf = withPreviousRole(claims.OnBehalfOf, namespace, role)
+		return &actorClaims
+	}
+	previousClaims := *claims
+	if namespace == "" {
+		previousClaims.System = role
+		return &previousClaims
+	}
+	previousClaims.Namespaces = make(map[string]Role, len(claims.Namespaces)+1)
+	for ns, nsRole := range claims.Namespaces {
+		previousClaims.Namespaces[ns] = nsRole
+	}
+	previousClaims.Namespaces[namespace] = role
+	return &previousClaims
+}
@@ -0,0 +1,121 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"go.temporal.io/server/common/clock"
+)
+
+var testDowngradedAt = time.Unix(1600000000, 0)
+
+type (
+	gracePeriodAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		timeSource *clock.EventTimeSource
+		history    testRoleHistory
+		authorizer Authorizer
+		claims     *Claims
+		signal     *CallTarget
+		terminate  *CallTarget
+	}
+
+	testRoleHistory map[string]*RoleDowngrade // by subject and namespace
+)
+
+func (h testRoleHistory) LastDowngrade(_ context.Context, subject string, namespace string) (*RoleDowngrade, error) {
+	if subject == "unknown" {
+		return nil, errors.New("role history unavailable")
+	}
+	return h[subject+"/"+namespace], nil
+}
+
+func TestGracePeriodAuthorizerSuite(t *testing.T) {
+	s := new(gracePeriodAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *gracePeriodAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.timeSource = clock.NewEventTimeSource().Update(testDowngradedAt.Add(time.Minute))
+	s.history = testRoleHistory{
+		testSubject + "/" + testNamespace: {PreviousRole: RoleWriter, DowngradedAt: testDowngradedAt},
+	}
+	s.signal = &CallTarget{APIName: workflowServicePrefix + "SignalWorkflowExecution", APIGroup: APIGroupWrite, Namespace: testNamespace}
+	s.terminate = &CallTarget{APIName: workflowServicePrefix + "TerminateWorkflowExecution", APIGroup: APIGroupWrite, Namespace: testNamespace}
+	static := NewStaticAuthorizer(map[Role][]string{
+		RoleReader: {describeNamespaceTarget.APIName},
+		RoleWriter: {describeNamespaceTarget.APIName, s.signal.APIName, s.terminate.APIName},
+	})
+	s.authorizer = NewGracePeriodAuthorizer(NewOnBehalfOfAuthorizer(static, []string{"gateway"}), s.history, 5*time.Minute, []string{s.signal.APIName}, s.timeSource)
+	s.claims = &Claims{Subject: testSubject, Namespaces: map[string]Role{testNamespace: RoleReader}}
+}
+
+func (s *gracePeriodAuthorizerSuite) TestWithinGracePeriod() {
+	s.assertDecision(DecisionAllow, s.claims, s.signal)
+	s.assertDecision(DecisionDeny, s.claims, s.terminate) // critical
+	s.assertDecision(DecisionAllow, s.claims, describeNamespaceTarget)
+
+	// the claims of the caller are left as they are
+	s.Equal(RoleReader, s.claims.Namespaces[testNamespace])
+}
+
+func (s *gracePeriodAuthorizerSuite) TestAfterGracePeriod() {
+	s.timeSource.Update(testDowngradedAt.Add(5 * time.Minute))
+	s.assertDecision(DecisionDeny, s.claims, s.signal)
+	s.assertDecision(DecisionDeny, s.claims, s.terminate)
+	s.assertDecision(DecisionAllow, s.claims, describeNamespaceTarget)
+}
+
+func (s *gracePeriodAuthorizerSuite) TestNotDowngraded() {
+	s.assertDecision(DecisionDeny, &Claims{Subject: "other", Namespaces: map[string]Role{testNamespace: RoleReader}}, s.signal)
+	s.assertDecision(DecisionDeny, nil, s.signal)
+}
+
+func (s *gracePeriodAuthorizerSuite) TestDelegatedCall() {
+	claims := &Claims{Subject: "gateway", OnBehalfOf: s.claims}
+	s.assertDecision(DecisionAllow, claims, s.signal)
+	s.assertDecision(DecisionDeny, claims, s.terminate)
+}
+
+func (s *gracePeriodAuthorizerSuite) TestHistoryFailure() {
+	_, err := s.authorizer.Authorize(ctx, &Claims{Subject: "unknown"}, s.signal)
+	s.Error(err)
+}
+
+func (s *gracePeriodAuthorizerSuite) assertDecision(expected Decision, claims *Claims, target *CallTarget) {
+	result, err := s.authorizer.Authorize(ctx, claims, target)
+	s.NoError(err)
+	s.Equal(expected, result.Decision, target.APIName)
+}
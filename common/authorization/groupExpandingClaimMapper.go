@@ -0,0 +1,110 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"fmt"
+
+	"go.temporal.io/api/serviceerror"
+)
+
+type (
+	// NamespaceRole is a role granted within a namespace
+	NamespaceRole struct {
+		Namespace string
+		Role      Role
+	}
+
+	groupExpandingClaimMapper struct {
+		claimMapper         ClaimMapper
+		groupRoles          map[string][]NamespaceRole
+		rejectUnknownGroups bool
+	}
+)
+
+var _ ClaimMapper = (*groupExpandingClaimMapper)(nil)
+
+// NewGroupExpandingClaimMapper creates a claim mapper that grants the namespace roles mapped to each group
+// in the claims resolved by claimMapper, in addition to the roles asserted directly. The groups of the end user
+// of delegated claims are expanded the same way.
+// Groups missing from groupRoles are ignored, or rejected if rejectUnknownGroups is set.
+func NewGroupExpandingClaimMapper(
+	claimMapper ClaimMapper,
+	groupRoles map[string][]NamespaceRole,
+	rejectUnknownGroups bool,
+) ClaimMapper {
+	return &groupExpandingClaimMapper{
+		claimMapper:         claimMapper,
+		groupRoles:          groupRoles,
+		rejectUnknownGroups: rejectUnknownGroups,
+	}
+}
+
+func (a *groupExpandingClaimMapper) GetClaims(ctx context.Context, authInfo *AuthInfo) (*Claims, error) {
+	claims, err := a.claimMapper.GetClaims(ctx, authInfo)
+	if err != nil || claims == nil {
+		return claims, err
+	}
+	return a.expand(claims)
+}
+
+// expand grants the roles of the groups of claims, and of the end user of delegated claims
+func (a *groupExpandingClaimMapper) expand(claims *Claims) (*Claims, error) {
+	if len(claims.Groups) == 0 && claims.OnBehalfOf == nil {
+		return claims, nil
+	}
+	expanded := *claims
+	if claims.OnBehalfOf != nil {
+		onBehalfOf, err := a.expand(claims.OnBehalfOf)
+		if err != nil {
+			return nil, err
+		}
+		expanded.OnBehalfOf = onBehalfOf
+	}
+	if len(claims.Groups) == 0 {
+		return &expanded, nil
+	}
+
+	namespaces := make(map[string]Role, len(claims.Namespaces))
+	for namespace, role := range claims.Namespaces {
+		namespaces[namespace] = role
+	}
+	for _, group := range claims.Groups {
+		roles, ok := a.groupRoles[group]
+		if !ok {
+			if a.rejectUnknownGroups {
+				return nil, serviceerror.NewPermissionDenied(fmt.Sprintf("unknown group: %s", group))
+			}
+			continue
+		}
+		for _, role := range roles {
+			namespaces[role.Namespace] |= role.Role
+		}
+	}
+
+	expanded.Namespaces = namespaces
+	return &expanded, nil
+}
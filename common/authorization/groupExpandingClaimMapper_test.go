@@ -0,0 +1,157 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	groupExpandingClaimMapperSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller      *gomock.Controller
+		mockClaimMapper *MockClaimMapper
+		groupRoles      map[string][]NamespaceRole
+	}
+)
+
+func TestGroupExpandingClaimMapperSuite(t *testing.T) {
+	s := new(groupExpandingClaimMapperSuite)
+	suite.Run(t, s)
+}
+
+func (s *groupExpandingClaimMapperSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockClaimMapper = NewMockClaimMapper(s.controller)
+	s.groupRoles = map[string][]NamespaceRole{
+		"developers": {{Namespace: testNamespace, Role: RoleReader | RoleWriter}, {Namespace: "staging", Role: RoleAdmin}},
+		"workers":    {{Namespace: testNamespace, Role: RoleWorker}},
+	}
+}
+
+func (s *groupExpandingClaimMapperSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *groupExpandingClaimMapperSuite) TestGroupExpansion() {
+	authInfo := &AuthInfo{AuthToken: "token"}
+	s.mockClaimMapper.EXPECT().GetClaims(ctx, authInfo).Return(&Claims{
+		Subject:    testSubject,
+		Groups:     []string{"developers"},
+		Namespaces: map[string]Role{"production": RoleReader},
+	}, nil)
+
+	claims, err := NewGroupExpandingClaimMapper(s.mockClaimMapper, s.groupRoles, false).GetClaims(ctx, authInfo)
+	s.NoError(err)
+	s.Equal(testSubject, claims.Subject)
+	s.Equal(map[string]Role{
+		testNamespace: RoleReader | RoleWriter,
+		"staging":     RoleAdmin,
+		"production":  RoleReader,
+	}, claims.Namespaces)
+}
+
+func (s *groupExpandingClaimMapperSuite) TestOverlappingGroups() {
+	authInfo := &AuthInfo{AuthToken: "token"}
+	s.mockClaimMapper.EXPECT().GetClaims(ctx, authInfo).Return(&Claims{
+		Subject:    testSubject,
+		Groups:     []string{"developers", "workers"},
+		Namespaces: map[string]Role{testNamespace: RoleReader},
+	}, nil)
+
+	claims, err := NewGroupExpandingClaimMapper(s.mockClaimMapper, s.groupRoles, false).GetClaims(ctx, authInfo)
+	s.NoError(err)
+	s.Equal(RoleReader|RoleWriter|RoleWorker, claims.Namespaces[testNamespace])
+	s.Equal(RoleAdmin, claims.Namespaces["staging"])
+}
+
+func (s *groupExpandingClaimMapperSuite) TestUnknownGroupIgnored() {
+	authInfo := &AuthInfo{AuthToken: "token"}
+	s.mockClaimMapper.EXPECT().GetClaims(ctx, authInfo).Return(&Claims{
+		Subject: testSubject,
+		Groups:  []string{"unknown", "workers"},
+	}, nil)
+
+	claims, err := NewGroupExpandingClaimMapper(s.mockClaimMapper, s.groupRoles, false).GetClaims(ctx, authInfo)
+	s.NoError(err)
+	s.Equal(map[string]Role{testNamespace: RoleWorker}, claims.Namespaces)
+}
+
+func (s *groupExpandingClaimMapperSuite) TestUnknownGroupRejected() {
+	authInfo := &AuthInfo{AuthToken: "token"}
+	s.mockClaimMapper.EXPECT().GetClaims(ctx, authInfo).Return(&Claims{
+		Subject: testSubject,
+		Groups:  []string{"unknown", "workers"},
+	}, nil)
+
+	claims, err := NewGroupExpandingClaimMapper(s.mockClaimMapper, s.groupRoles, true).GetClaims(ctx, authInfo)
+	s.Error(err)
+	s.Nil(claims)
+}
+
+func (s *groupExpandingClaimMapperSuite) TestNoGroups() {
+	authInfo := &AuthInfo{AuthToken: "token"}
+	expected := &Claims{Subject: testSubject, System: RoleReader}
+	s.mockClaimMapper.EXPECT().GetClaims(ctx, authInfo).Return(expected, nil)
+
+	claims, err := NewGroupExpandingClaimMapper(s.mockClaimMapper, s.groupRoles, true).GetClaims(ctx, authInfo)
+	s.NoError(err)
+	s.Equal(expected, claims)
+}
+
+func (s *groupExpandingClaimMapperSuite) TestDelegatedClaims() {
+	authInfo := &AuthInfo{AuthToken: "token"}
+	s.mockClaimMapper.EXPECT().GetClaims(ctx, authInfo).Return(&Claims{
+		Subject:    "gateway",
+		OnBehalfOf: &Claims{Subject: testSubject, Groups: []string{"workers"}},
+	}, nil)
+
+	claims, err := NewGroupExpandingClaimMapper(s.mockClaimMapper, s.groupRoles, false).GetClaims(ctx, authInfo)
+	s.NoError(err)
+	s.Equal("gateway", claims.Subject)
+	s.Nil(claims.Namespaces)
+	s.Equal(map[string]Role{testNamespace: RoleWorker}, claims.OnBehalfOf.Namespaces)
+	role, _ := claims.EffectiveClaims().EffectiveRole(testNamespace)
+	s.Equal(RoleWorker, role)
+}
+
+func (s *groupExpandingClaimMapperSuite) TestDelegatedUnknownGroupRejected() {
+	authInfo := &AuthInfo{AuthToken: "token"}
+	s.mockClaimMapper.EXPECT().GetClaims(ctx, authInfo).Return(&Claims{
+		Subject:    "gateway",
+		OnBehalfOf: &Claims{Subject: testSubject, Groups: []string{"unknown"}},
+	}, nil)
+
+	claims, err := NewGroupExpandingClaimMapper(s.mockClaimMapper, s.groupRoles, true).GetClaims(ctx, authInfo)
+	s.Error(err)
+	s.Nil(claims)
+}
@@ -26,27 +26,66 @@ package authorization
 
 import (
 	"context"
-	"crypto/x509/pkix"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
+	"github.com/gogo/status"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 
+	enumspb "go.temporal.io/api/enums/v1"
 	"go.temporal.io/api/serviceerror"
 
+	"go.temporal.io/server/common/headers"
 	"go.temporal.io/server/common/log"
 	"go.temporal.io/server/common/log/tag"
 	"go.temporal.io/server/common/metrics"
 )
 
 var (
-	errUnauthorized = serviceerror.NewPermissionDenied("Request unauthorized.")
+	errUnauthorized       = serviceerror.NewPermissionDenied("Request unauthorized.")
+	errUnauthenticated    = status.Error(codes.Unauthenticated, "Request unauthenticated.")
+	errNamespaceNotActive = status.Error(codes.FailedPrecondition, "Namespace is deprecated or deleted, mutating requests are not allowed.")
+	errReadOnlyMode       = serviceerror.NewUnavailable("Cluster is in read-only mode for maintenance, mutating requests are not allowed.")
+	errBudgetExhausted    = serviceerror.NewResourceExhausted("Request cost budget exhausted.")
+	errConcurrencyLimit   = serviceerror.NewResourceExhausted("Too many concurrent requests.")
+	errDenyCooldown       = serviceerror.NewResourceExhausted("Too many denied requests.")
+	errRateClassExhausted = serviceerror.NewResourceExhausted("Namespace rate limit exceeded.")
+	errFeatureDisabled    = status.Error(codes.FailedPrecondition, "The feature of the API is not enabled.")
+	errDryRun             = status.Error(codes.Aborted, "Dry run, the request was not executed. The authorization decision is in the response metadata.")
+
+	errAuthorizationTimeout = errors.New("authorization timed out")
 )
 
 const (
 	ContextKeyMappedClaims = "auth-mappedClaims"
 	ContextAuthHeader      = "auth-header"
+	ContextKeyConstraints  = "auth-constraints"
+	ContextKeyAuthContext  = "auth-context"
+	// ContextKeyEffectiveRoles holds the *EffectiveRoles of the caller, see WithEffectiveRoles
+	ContextKeyEffectiveRoles = "auth-effective-roles"
+)
+
+const (
+	impersonateHeaderName = "x-temporal-impersonate"
+
+	// DryRunHeaderName is the header that requests a dry run of the authorization of a call, see WithDryRun
+	DryRunHeaderName = "x-temporal-dry-run"
+	// DryRunDecisionHeaderName is the response header with the decision of a dry run, "allow" or "deny"
+	DryRunDecisionHeaderName = "x-temporal-dry-run-decision"
+	// DryRunReasonHeaderName is the response header with the reason of the decision of a dry run, if any
+	DryRunReasonHeaderName = "x-temporal-dry-run-reason"
+
+	// DecisionTrailerName is the response trailer set to "deny" on denied calls
+	DecisionTrailerName = "x-temporal-authz-decision"
+	// ReasonTrailerName is the response trailer with the reason of the denial of a call, if any
+	ReasonTrailerName = "x-temporal-authz-reason"
 )
 
 func (a *interceptor) Interceptor(
@@ -56,84 +95,546 @@ func (a *interceptor) Interceptor(
 	handler grpc.UnaryHandler,
 ) (interface{}, error) {
 
-	var claims *Claims
+	if a.authorizer == nil {
+		return handler(ctx, req)
+	}
 
-	if a.claimMapper != nil && a.authorizer != nil {
-		var tlsSubject *pkix.Name
-		var authHeaders []string
-		var authExtraHeaders []string
-		var tlsConnection *credentials.TLSInfo
-
-		if md, ok := metadata.FromIncomingContext(ctx); ok {
-			authHeaders = md["authorization"]
-			authExtraHeaders = md["authorization-extras"]
-		}
-		if p, ok := peer.FromContext(ctx); ok {
-			if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
-				tlsConnection = &tlsInfo
-				if len(tlsInfo.State.VerifiedChains) > 0 && len(tlsInfo.State.VerifiedChains[0]) > 0 {
-					// The assumption here is that we only expect a single verified chain of certs (first[0]).
-					// It's unclear how we should handle a situation when more than one chain is presented,
-					// which subject to use. It's okay for us to limit ourselves to one chain.
-					// We can always extend this logic later.
-					// We tale the first element in the chain ([0]) because that's the client cert
-					// (at the beginning of the chain), not intermediary CAs or the root CA (at the end of the chain).
-					tlsSubject = &tlsInfo.State.VerifiedChains[0][0].Subject
-				}
+	if a.requestValidator != nil && a.validationOrder == ValidateBeforeAuthorization {
+		if err := a.requestValidator(info.FullMethod, req); err != nil {
+			return nil, err
+		}
+	}
+	ctx, err := a.authorizeCall(ctx, req, info)
+	if err != nil {
+		return nil, err
+	}
+	if a.requestValidator != nil && a.validationOrder == ValidateAfterAuthorization {
+		if err := a.requestValidator(info.FullMethod, req); err != nil {
+			return nil, err
+		}
+	}
+	if a.concurrencyLimiter != nil {
+		if authContext := AuthContextFromContext(ctx); authContext != nil && authContext.Subject != "" {
+			release, ok := a.concurrencyLimiter.Acquire(authContext.Subject)
+			if !ok {
+				a.metricsClient.IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationConcurrencyLimitCounter)
+				return nil, errConcurrencyLimit
 			}
+			// released even if the handler panics
+			defer release()
 		}
+	}
+	return handler(ctx, req)
+}
+
+// authorizeCall maps the caller's claims and authorizes the call, returning the context to pass to the handler
+func (a *interceptor) authorizeCall(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+) (context.Context, error) {
+
+	sampled := a.metricsSampler.sample()
+	if sampled {
+		totalSw := a.metricsClient.StartTimer(metrics.AuthorizationScope, metrics.ServiceAuthorizationInterceptorLatency)
+		defer totalSw.Stop()
+	}
+
+	var claims *Claims
+
+	if a.claimMapper != nil {
 		// Add auth info to context only if there's some auth info
-		if tlsSubject != nil || len(authHeaders) > 0 {
-			var authHeader string
-			var authExtraHeader string
-			if len(authHeaders) > 0 {
-				authHeader = authHeaders[0]
-			}
-			if len(authExtraHeaders) > 0 {
-				authExtraHeader = authExtraHeaders[0]
+		if authInfo := NewAuthInfoFromContext(ctx); authInfo != nil {
+			var mappedClaims *Claims
+			var err error
+			if withTimeout(ctx, a.claimMappingTimeout, func(ctx context.Context) {
+				mappedClaims, err = a.claimMapper.GetClaims(ctx, authInfo)
+			}) {
+				a.metricsClient.IncCounter(metrics.AuthorizationScope, metrics.ServiceErrClaimMappingTimeoutCounter)
+				a.logger.Error("authentication error", tag.Error(errors.New("claim mapping timed out")))
+				return nil, errUnauthenticated
 			}
-			authInfo := AuthInfo{
-				AuthToken:     authHeader,
-				TLSSubject:    tlsSubject,
-				TLSConnection: tlsConnection,
-				ExtraData:     authExtraHeader,
-			}
-			mappedClaims, err := a.claimMapper.GetClaims(&authInfo)
 			if err != nil {
-				return nil, a.logAuthError(err)
+				a.logger.Error("authentication error", tag.Error(err))
+				return nil, errUnauthenticated
 			}
 			claims = mappedClaims
 			ctx = context.WithValue(ctx, ContextKeyMappedClaims, mappedClaims)
-			if authHeader != "" {
-				ctx = context.WithValue(ctx, ContextAuthHeader, authHeader)
+			if authInfo.AuthToken != "" {
+				ctx = context.WithValue(ctx, ContextAuthHeader, authInfo.AuthToken)
 			}
 		}
 	}
 
-	if a.authorizer != nil {
-		var namespace string
-		requestWithNamespace, ok := req.(requestWithNamespace)
-		if ok {
-			namespace = requestWithNamespace.GetNamespace()
+	callerClaims := claims
+	if a.claimsLookup != nil {
+		impersonatedClaims, err := a.impersonate(ctx, claims)
+		if err != nil {
+			return nil, a.logAuthError(err)
 		}
+		if impersonatedClaims != claims {
+			claims = impersonatedClaims
+			ctx = context.WithValue(ctx, ContextKeyMappedClaims, impersonatedClaims)
+		}
+	}
 
-		apiName := info.FullMethod
+	apiName := info.FullMethod
+	target := newCallTarget(apiName, req, a.extractRequestContent)
+	if a.requestCost != nil {
+		target.Cost = a.requestCost(apiName, req)
+	}
+	target.SourceNamespace, target.SourceWorkflowID = sourceWorkflow(ctx)
+	target.ClientName, target.ClientVersion = clientVersion(ctx)
+	target.TLSState = tlsConnectionState(ctx)
+	if a.taskTokenDecoder != nil {
+		if err := setTaskTokenTarget(target, req, a.taskTokenDecoder); err != nil {
+			a.metricsClient.IncCounter(metrics.AuthorizationScope, metrics.ServiceErrUnauthorizedCounter)
+			return nil, a.logAuthError(err)
+		}
+	}
+	namespace := target.Namespace
 
-		scope := a.getMetricsScope(metrics.AuthorizationScope, namespace)
+	scope := a.getMetricsScope(metrics.AuthorizationScope, namespace)
+	if a.callerTypeTag {
+		scope = scope.Tagged(metrics.CallerTypeTag(callerType(claims)))
+	}
+	if sampled {
 		sw := scope.StartTimer(metrics.ServiceAuthorizationLatency)
 		defer sw.Stop()
+	}
 
-		result, err := a.authorizer.Authorize(ctx, claims, &CallTarget{Namespace: namespace, APIName: apiName})
+	dryRun := a.dryRunRole != RoleUndefined && isDryRun(ctx)
+	if dryRun && (callerClaims == nil || callerClaims.System&a.dryRunRole == 0) {
+		a.incUnauthorized(ctx, scope, namespace)
+		return nil, errUnauthorized
+	}
+
+	if a.globalNamespaceLookup != nil && namespace != "" {
+		isGlobal, err := a.globalNamespaceLookup.IsGlobalNamespace(namespace)
 		if err != nil {
 			scope.IncCounter(metrics.ServiceErrAuthorizeFailedCounter)
 			return nil, a.logAuthError(err)
 		}
-		if result.Decision != DecisionAllow {
-			scope.IncCounter(metrics.ServiceErrUnauthorizedCounter)
-			return nil, errUnauthorized
+		target.IsGlobalNamespace = isGlobal
+	}
+
+	if a.namespaceActivityLookup != nil && namespace != "" {
+		isActive, err := a.namespaceActivityLookup.IsNamespaceActive(namespace)
+		if err != nil {
+			scope.IncCounter(metrics.ServiceErrAuthorizeFailedCounter)
+			return nil, a.logAuthError(err)
 		}
+		target.IsNamespacePassive = !isActive
 	}
-	return handler(ctx, req)
+
+	if a.namespaceLabelsLookup != nil {
+		if err := a.resolveNamespaceLabels(target); err != nil {
+			scope.IncCounter(metrics.ServiceErrAuthorizeFailedCounter)
+			return nil, a.logAuthError(err)
+		}
+	}
+
+	if a.searchAttributePolicyLookup != nil {
+		if err := a.resolveSearchAttributePolicies(target); err != nil {
+			scope.IncCounter(metrics.ServiceErrAuthorizeFailedCounter)
+			return nil, a.logAuthError(err)
+		}
+	}
+
+	if a.maintenanceMode != nil && a.maintenanceMode.IsReadOnly() && IsMutatingAPI(apiName) {
+		scope.Tagged(metrics.ReasonTag(ReasonMaintenance.metricTagValue())).IncCounter(metrics.ServiceAuthorizationDenyReasonCounter)
+		setDenyTrailer(ctx, ReasonMaintenance)
+		return nil, errReadOnlyMode
+	}
+
+	if hint, ok := a.deprecatedAPIs[apiName]; ok {
+		scope.Tagged(metrics.ReasonTag(ReasonAPIDeprecated.metricTagValue())).IncCounter(metrics.ServiceAuthorizationDenyReasonCounter)
+		setDenyTrailer(ctx, ReasonAPIDeprecated)
+		return nil, deprecatedAPIError(apiName, hint)
+	}
+
+	if a.featureFlags != nil && !a.featureFlags.IsAPIEnabled(apiName, namespace) {
+		scope.Tagged(metrics.ReasonTag(ReasonFeatureDisabled.metricTagValue())).IncCounter(metrics.ServiceAuthorizationDenyReasonCounter)
+		setDenyTrailer(ctx, ReasonFeatureDisabled)
+		return nil, errFeatureDisabled
+	}
+
+	if a.drBypass != nil && namespace != "" && a.drBypass.IsBypassed(namespace) {
+		a.bypassed(scope, claims, target)
+		return ctx, nil
+	}
+
+	startTime := time.Now()
+	result, err := a.authorize(ctx, claims, target)
+	if err != nil {
+		if err == errAuthorizationTimeout {
+			scope.IncCounter(metrics.ServiceErrAuthorizeTimeoutCounter)
+		}
+		scope.IncCounter(metrics.ServiceErrAuthorizeFailedCounter)
+		return nil, a.logAuthError(err)
+	}
+	a.observeDecisionMetrics(target, result, time.Since(startTime))
+	if a.decisionObservations != nil {
+		a.decisionObservations.enqueue(ctx, claims, target, result)
+	}
+	if dryRun {
+		return nil, a.reportDryRun(ctx, result)
+	}
+	if result.Decision != DecisionAllow {
+		if !a.isWarnOnly(apiName) {
+			a.incUnauthorized(ctx, scope, namespace)
+			scope.Tagged(metrics.ReasonTag(result.Reason.metricTagValue())).IncCounter(metrics.ServiceAuthorizationDenyReasonCounter)
+			setDenyTrailer(ctx, result.Reason)
+			a.logDenyReason(claims, target, result)
+			return nil, a.denyError(claims, target, result)
+		}
+		a.warnDenied(scope, claims, target, result)
+	}
+	if result.Constraints != nil {
+		ctx = context.WithValue(ctx, ContextKeyConstraints, result.Constraints)
+	}
+	ctx = context.WithValue(ctx, ContextKeyAuthContext, newAuthContext(claims, target, result))
+	if a.effectiveRoles {
+		ctx = context.WithValue(ctx, ContextKeyEffectiveRoles, newEffectiveRoles(claims, namespace))
+	}
+	if a.namespaceStateLookup != nil && namespace != "" && IsMutatingAPI(apiName) {
+		if err := a.checkNamespaceState(namespace); err != nil {
+			return nil, err
+		}
+	}
+	return ctx, nil
+}
+
+// resolveNamespaceLabels sets the labels of the namespace of target and its sub-targets that target a namespace
+func (a *interceptor) resolveNamespaceLabels(target *CallTarget) error {
+	if target.Namespace != "" {
+		labels, err := a.namespaceLabelsLookup.GetNamespaceLabels(target.Namespace)
+		if err != nil {
+			return err
+		}
+		target.NamespaceLabels = labels
+		target.NamespaceRegion = labels[RegionLabel]
+		target.NamespaceTier = labels[TierLabel]
+		target.NamespaceLabelsResolved = true
+	}
+	for _, subTarget := range target.SubTargets {
+		if err := a.resolveNamespaceLabels(subTarget); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveSearchAttributePolicies sets the search attribute policy of target and its sub-targets that use search attributes
+func (a *interceptor) resolveSearchAttributePolicies(target *CallTarget) error {
+	if len(target.SearchAttributes) > 0 && target.Namespace != "" {
+		policy, err := a.searchAttributePolicyLookup.GetSearchAttributePolicy(target.Namespace)
+		if err != nil {
+			return err
+		}
+		target.SearchAttributePolicy = policy
+	}
+	for _, subTarget := range target.SubTargets {
+		if err := a.resolveSearchAttributePolicies(subTarget); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isDryRun checks if the caller requested a dry run of the call
+func isDryRun(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	values := md.Get(DryRunHeaderName)
+	return len(values) > 0 && strings.EqualFold(values[0], "true")
+}
+
+// reportDryRun sends the decision of a dry run to the caller in the response metadata
+func (a *interceptor) reportDryRun(ctx context.Context, result Result) error {
+	header := metadata.Pairs(DryRunDecisionHeaderName, decisionName(result.Decision))
+	if result.Reason != ReasonUnspecified {
+		header.Set(DryRunReasonHeaderName, string(result.Reason))
+	}
+	if err := grpc.SetHeader(ctx, header); err != nil {
+		return a.logAuthError(err)
+	}
+	return errDryRun
+}
+
+// setDenyTrailer reports the denial of the call and its reason in the response trailer, so that clients
+// can tell denials apart without parsing the error message
+func setDenyTrailer(ctx context.Context, reason ReasonCode) {
+	trailer := metadata.Pairs(DecisionTrailerName, decisionName(DecisionDeny))
+	if reason != ReasonUnspecified {
+		trailer.Set(ReasonTrailerName, string(reason))
+	}
+	// fails only for calls that are not made through a gRPC server, which have no trailer
+	_ = grpc.SetTrailer(ctx, trailer)
+}
+
+// tlsConnectionState returns the state of the TLS connection of the call, or nil if the connection is plaintext
+func tlsConnectionState(ctx context.Context) *tls.ConnectionState {
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			return &tlsInfo.State
+		}
+	}
+	return nil
+}
+
+// sourceWorkflow returns the namespace and ID of the workflow the call was made on behalf of, if any
+func sourceWorkflow(ctx context.Context) (string, string) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", ""
+	}
+	var namespace, workflowID string
+	if values := headerValues(md, SourceNamespaceHeaderName); len(values) > 0 {
+		namespace = values[0]
+	}
+	if values := headerValues(md, SourceWorkflowIDHeaderName); len(values) > 0 {
+		workflowID = values[0]
+	}
+	return namespace, workflowID
+}
+
+// clientVersion returns the name and version of the client that made the call, if reported
+func clientVersion(ctx context.Context) (string, string) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", ""
+	}
+	var name, version string
+	if values := headerValues(md, headers.ClientNameHeaderName); len(values) > 0 {
+		name = values[0]
+	}
+	if values := headerValues(md, headers.ClientVersionHeaderName); len(values) > 0 {
+		version = values[0]
+	}
+	return name, version
+}
+
+// authorize makes the authorization decision for the call, failing with errAuthorizationTimeout
+// if the authorizer exceeds the authorization timeout
+func (a *interceptor) authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if a.anonymousAPIs != nil && isAnonymous(claims) {
+		if _, ok := a.anonymousAPIs[target.APIName]; ok {
+			return Result{Decision: DecisionAllow}, nil
+		}
+		return Result{Decision: DecisionDeny, Reason: ReasonAnonymous}, nil
+	}
+	var result Result
+	var err error
+	if withTimeout(ctx, a.authorizationTimeout, func(ctx context.Context) {
+		result, err = a.authorizer.Authorize(ctx, claims, target)
+	}) {
+		return Result{}, errAuthorizationTimeout
+	}
+	return result, err
+}
+
+// withTimeout calls fn with a context derived from ctx that expires after timeout, zero means no timeout.
+// It reports whether the timeout expired before fn returned, in which case the results of fn must be discarded
+// even if fn ignored the expiry. Expiries of ctx itself are left to fn to report.
+func withTimeout(ctx context.Context, timeout time.Duration, fn func(ctx context.Context)) bool {
+	if timeout <= 0 {
+		fn(ctx)
+		return false
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	fn(timeoutCtx)
+	return timeoutCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil
+}
+
+// isWarnOnly checks if denied calls to the API are allowed through
+func (a *interceptor) isWarnOnly(apiName string) bool {
+	_, ok := a.warnOnlyAPIs[apiName]
+	return ok
+}
+
+// incUnauthorized counts an unauthorized call, also in the exemplar counter if one is set, linked to the trace of the call
+func (a *interceptor) incUnauthorized(ctx context.Context, scope metrics.Scope, namespace string) {
+	scope.IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	if a.unauthorizedExemplarCounter != nil {
+		a.unauthorizedExemplarCounter.IncUnauthorized(namespace, traceIDFromContext(ctx))
+	}
+}
+
+// warnDenied logs and counts a denied call to a warn-only API that is allowed through
+func (a *interceptor) warnDenied(scope metrics.Scope, claims *Claims, target *CallTarget, result Result) {
+	var subject string
+	if claims != nil {
+		subject = claims.Subject
+	}
+	a.logger.Warn("allowing call to warn-only API denied by authorizer",
+		tag.AuthSubject(subject),
+		tag.AuthAPIName(target.APIName),
+		tag.WorkflowNamespace(target.Namespace),
+		tag.AuthReason(string(result.Reason)))
+	scope.Tagged(metrics.ReasonTag(result.Reason.metricTagValue())).IncCounter(metrics.ServiceAuthorizationWarnOnlyCounter)
+}
+
+// bypassed logs and counts a call that is allowed without authorization by the disaster recovery bypass
+func (a *interceptor) bypassed(scope metrics.Scope, claims *Claims, target *CallTarget) {
+	var subject string
+	if claims != nil {
+		subject = claims.Subject
+	}
+	a.logger.Warn("AUTHORIZATION BYPASSED: allowing call to namespace listed in the disaster recovery bypass without authorization",
+		tag.AuthSubject(subject),
+		tag.AuthAPIName(target.APIName),
+		tag.WorkflowNamespace(target.Namespace))
+	scope.IncCounter(metrics.ServiceAuthorizationDRBypassCounter)
+}
+
+// denyError returns the error for a denied call, with the configured deny message if there is one, or else the
+// message of the outermost deny reason of the result. Calls denied because the caller presented no identity
+// fail with Unauthenticated, calls denied for exhausting a budget or rate limit or during a deny cooldown with
+// ResourceExhausted, all other denials of identified callers with PermissionDenied.
+func (a *interceptor) denyError(claims *Claims, target *CallTarget, result Result) error {
+	reason := result.Reason
+	message, ok := a.denyMessage(claims, target, reason)
+	if !ok && result.DenyReason != nil && result.DenyReason.Message != "" {
+		message, ok = result.DenyReason.Message, true
+	}
+	switch {
+	case reason == ReasonBudgetExhausted:
+		if !ok {
+			return errBudgetExhausted
+		}
+		return serviceerror.NewResourceExhausted(message)
+	case reason == ReasonDenyCooldown:
+		if !ok {
+			return errDenyCooldown
+		}
+		return serviceerror.NewResourceExhausted(message)
+	case reason == ReasonRateClassExhausted:
+		if !ok {
+			return errRateClassExhausted
+		}
+		return serviceerror.NewResourceExhausted(message)
+	case reason == ReasonNoClaims || reason == ReasonAnonymous:
+		if !ok {
+			return errUnauthenticated
+		}
+		return status.Error(codes.Unauthenticated, message)
+	case !ok:
+		return errUnauthorized
+	default:
+		return serviceerror.NewPermissionDenied(message)
+	}
+}
+
+// deprecatedAPIError returns the error for a call to a deprecated API, with the hint on migrating off the API
+func deprecatedAPIError(apiName string, hint string) error {
+	message := fmt.Sprintf("API %s is deprecated.", apiName)
+	if hint != "" {
+		message += " " + hint
+	}
+	return status.Error(codes.FailedPrecondition, message)
+}
+
+// logDenyReason logs the full chain of deny reasons of a denied call, if the authorizer explained the deny
+func (a *interceptor) logDenyReason(claims *Claims, target *CallTarget, result Result) {
+	if result.DenyReason == nil {
+		return
+	}
+	var subject string
+	if claims != nil {
+		subject = claims.Subject
+	}
+	a.logger.Info("authorization denied",
+		tag.AuthSubject(subject),
+		tag.AuthAPIName(target.APIName),
+		tag.WorkflowNamespace(target.Namespace),
+		tag.AuthReason(result.DenyReason.Error()))
+}
+
+// denyMessage renders the configured deny message for the API or the deny reason, if there is one
+func (a *interceptor) denyMessage(claims *Claims, target *CallTarget, reason ReasonCode) (string, bool) {
+	if a.denyMessages == nil {
+		return "", false
+	}
+	message, ok := a.denyMessages.ByAPI[target.APIName]
+	if !ok {
+		message, ok = a.denyMessages.ByReason[reason]
+	}
+	if !ok {
+		return "", false
+	}
+	var subject string
+	if claims != nil {
+		subject = claims.Subject
+	}
+	return strings.NewReplacer(
+		"{namespace}", target.Namespace,
+		"{subject}", subject,
+	).Replace(message), true
+}
+
+// observeDecisionMetrics reports the decision to the decision metrics observer, if any
+func (a *interceptor) observeDecisionMetrics(target *CallTarget, result Result, latency time.Duration) {
+	if a.decisionMetricsObserver != nil {
+		a.decisionMetricsObserver.ObserveDecision(target.Namespace, target.APIName, result.Decision, latency)
+	}
+}
+
+// isAnonymous checks if the caller presented no credentials or credentials that carry no identity and no roles
+func isAnonymous(claims *Claims) bool {
+	return claims == nil ||
+		(claims.Subject == "" && claims.System == RoleUndefined && len(claims.Namespaces) == 0)
+}
+
+// checkNamespaceState returns an error if the namespace no longer accepts mutating requests
+func (a *interceptor) checkNamespaceState(namespace string) error {
+	state, err := a.namespaceStateLookup.GetNamespaceState(namespace)
+	if err != nil {
+		return a.logAuthError(err)
+	}
+	switch state {
+	case enumspb.NAMESPACE_STATE_DEPRECATED, enumspb.NAMESPACE_STATE_DELETED:
+		return errNamespaceNotActive
+	}
+	return nil
+}
+
+// impersonate returns the claims of the subject named in the impersonation header if the caller
+// is allowed to impersonate, or the caller's own claims otherwise.
+func (a *interceptor) impersonate(ctx context.Context, claims *Claims) (*Claims, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return claims, nil
+	}
+	subjects := headerValues(md, impersonateHeaderName)
+	if len(subjects) == 0 || subjects[0] == "" {
+		return claims, nil
+	}
+	subject := subjects[0]
+
+	if claims == nil || claims.System&RoleImpersonator == 0 {
+		var callerSubject string
+		if claims != nil {
+			callerSubject = claims.Subject
+		}
+		a.logger.Warn("ignoring impersonation header from caller without impersonation role",
+			tag.AuthSubject(callerSubject), tag.AuthImpersonatedSubject(subject))
+		return claims, nil
+	}
+
+	impersonatedClaims, err := a.claimsLookup.LookupClaims(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	// impersonation must not escalate the caller to system roles it doesn't hold itself, e.g. to a system admin
+	if impersonatedClaims != nil && impersonatedClaims.System&^claims.System != RoleUndefined {
+		return nil, fmt.Errorf("subject %s can't impersonate subject %s holding system roles it lacks", claims.Subject, subject)
+	}
+	a.logger.Info("impersonating subject", tag.AuthSubject(claims.Subject), tag.AuthImpersonatedSubject(subject))
+	return impersonatedClaims, nil
 }
 
 func (a *interceptor) logAuthError(err error) error {
@@ -146,6 +647,37 @@ type interceptor struct {
 	claimMapper   ClaimMapper
 	metricsClient metrics.Client
 	logger        log.Logger
+	claimsLookup  ClaimsLookup
+
+	namespaceStateLookup  NamespaceStateLookup
+	globalNamespaceLookup GlobalNamespaceLookup
+	namespaceLabelsLookup NamespaceLabelsLookup
+	requestCost           RequestCostFunc
+	anonymousAPIs         map[string]struct{}
+	warnOnlyAPIs          map[string]struct{}
+	maintenanceMode       *MaintenanceMode
+	metricsSampler        *metricsSampler
+	decisionObservations  *decisionObserverQueue
+	denyMessages          *DenyMessages
+	concurrencyLimiter    ConcurrencyLimiter
+	dryRunRole            Role
+	effectiveRoles        bool
+	featureFlags          FeatureFlagResolver
+	callerTypeTag         bool
+	drBypass              *DisasterRecoveryBypass
+	streamMessageTargets  map[string]StreamMessageTargetExtractor
+	claimMappingTimeout   time.Duration
+	authorizationTimeout  time.Duration
+
+	searchAttributePolicyLookup NamespaceSearchAttributePolicyLookup
+	decisionMetricsObserver     DecisionMetricsObserver
+	namespaceActivityLookup     NamespaceActivityLookup
+	requestValidator            RequestValidator
+	validationOrder             ValidationOrder
+	deprecatedAPIs              map[string]string
+	taskTokenDecoder            TaskTokenDecoder
+	unauthorizedExemplarCounter UnauthorizedExemplarCounter
+	extractRequestContent       bool
 }
 
 // GetAuthorizationInterceptor creates an authorization interceptor and return a func that points to its Interceptor method
@@ -154,13 +686,33 @@ func NewAuthorizationInterceptor(
 	authorizer Authorizer,
 	metrics metrics.Client,
 	logger log.Logger,
+	opts ...InterceptorOption,
 ) grpc.UnaryServerInterceptor {
-	return (&interceptor{
+	a := &interceptor{
 		claimMapper:   claimMapper,
 		authorizer:    authorizer,
 		metricsClient: metrics,
 		logger:        logger,
-	}).Interceptor
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a.Interceptor
+}
+
+// callerType classifies the caller for the caller type metric tag: callers holding a system role are system
+// callers, callers without claims or a subject are anonymous
+func callerType(claims *Claims) string {
+	switch {
+	case claims == nil:
+		return metrics.AnonymousCallerTypeTagValue
+	case claims.System != RoleUndefined:
+		return metrics.SystemCallerTypeTagValue
+	case claims.Subject == "":
+		return metrics.AnonymousCallerTypeTagValue
+	default:
+		return metrics.UserCallerTypeTagValue
+	}
 }
 
 // getMetricsScopeWithNamespace return metrics scope with namespace tag
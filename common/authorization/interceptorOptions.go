@@ -0,0 +1,388 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"time"
+
+	enumspb "go.temporal.io/api/enums/v1"
+)
+
+type (
+	// InterceptorOption customizes the behavior of the authorization interceptor
+	InterceptorOption func(*interceptor)
+
+	// ClaimsLookup resolves the claims of an arbitrary subject, e.g. from the identity provider's directory
+	ClaimsLookup interface {
+		LookupClaims(ctx context.Context, subject string) (*Claims, error)
+	}
+
+	// RequestCostFunc computes the cost of a request to the given API
+	RequestCostFunc func(apiName string, req interface{}) int
+
+	// GlobalNamespaceLookup resolves whether a namespace is global, i.e. replicated across clusters
+	GlobalNamespaceLookup interface {
+		IsGlobalNamespace(namespace string) (bool, error)
+	}
+
+	// NamespaceActivityLookup resolves whether a namespace is active in the current cluster, i.e. it is not global
+	// or the current cluster is its active cluster
+	NamespaceActivityLookup interface {
+		IsNamespaceActive(namespace string) (bool, error)
+	}
+
+	// NamespaceLabelsLookup resolves the labels of a namespace, such as its data classification
+	NamespaceLabelsLookup interface {
+		GetNamespaceLabels(namespace string) (map[string]string, error)
+	}
+
+	// NamespaceSearchAttributePolicyLookup resolves the policy of a namespace restricting the use of its
+	// search attributes, nil if the namespace has none
+	NamespaceSearchAttributePolicyLookup interface {
+		GetSearchAttributePolicy(namespace string) (*SearchAttributePolicy, error)
+	}
+
+	// NamespaceNameLookup resolves the name of a namespace by its ID
+	NamespaceNameLookup interface {
+		GetNamespaceName(id string) (string, error)
+	}
+
+	// TaskTokenDecoder decodes a task token to the target it was issued for, failing for malformed tokens
+	TaskTokenDecoder func(taskToken []byte) (*TaskTokenTarget, error)
+
+	// DecisionObserver is notified of an authorization decision
+	DecisionObserver func(ctx context.Context, claims *Claims, target *CallTarget, result Result)
+
+	// DecisionMetricsObserver records metrics of authorization decisions in a metrics system other than
+	// metrics.Client, e.g. OpenTelemetry. latency is the time the authorizer took to make the decision.
+	// It is called synchronously on the path of the call and must be fast and safe for concurrent use.
+	DecisionMetricsObserver interface {
+		ObserveDecision(namespace string, api string, decision Decision, latency time.Duration)
+	}
+
+	// UnauthorizedExemplarCounter counts unauthorized calls in a metrics system that supports exemplars, e.g. a
+	// Prometheus registry, linking the count to the trace of the denied call. traceID is the ID of the W3C
+	// trace of the call, empty when the call is not traced, in which case no exemplar should be attached.
+	// It is called synchronously on the path of the call and must be fast and safe for concurrent use.
+	UnauthorizedExemplarCounter interface {
+		IncUnauthorized(namespace string, traceID string)
+	}
+
+	// DenyMessages are user-facing messages returned to callers whose calls are denied, in place of the
+	// generic "Request unauthorized." Messages can contain {namespace} and {subject} placeholders
+	// that are replaced with the target namespace and the caller's subject.
+	DenyMessages struct {
+		// ByAPI maps full API names to messages, it takes precedence over ByReason
+		ByAPI map[string]string
+		// ByReason maps the reason codes of deny decisions to messages
+		ByReason map[ReasonCode]string
+	}
+
+	// NamespaceStateLookup resolves the lifecycle state of a namespace
+	NamespaceStateLookup interface {
+		GetNamespaceState(namespace string) (enumspb.NamespaceState, error)
+	}
+
+	// FeatureFlagResolver resolves whether the feature flag gating an API is on, e.g. for preview APIs
+	FeatureFlagResolver interface {
+		IsAPIEnabled(apiName string, namespace string) bool
+	}
+
+	// StreamMessageTargetExtractor extracts the target of a message received on a stream, or returns nil if
+	// the message needs no authorization beyond the authorization of the stream itself
+	StreamMessageTargetExtractor func(msg interface{}) *CallTarget
+)
+
+// WithImpersonation enables the impersonation header. A caller holding RoleImpersonator at the system level
+// can set the header to the name of another subject, whose claims, resolved via lookup, are then used
+// in place of the caller's own claims. The header is ignored for all other callers. Calls impersonating
+// a subject holding system roles that the caller doesn't hold itself, such as a system admin, are rejected.
+func WithImpersonation(lookup ClaimsLookup) InterceptorOption {
+	return func(a *interceptor) {
+		a.claimsLookup = lookup
+	}
+}
+
+// WithNamespaceStateLookup rejects mutating APIs targeting a namespace that is deprecated or deleted.
+// Read-only APIs are still allowed.
+func WithNamespaceStateLookup(lookup NamespaceStateLookup) InterceptorOption {
+	return func(a *interceptor) {
+		a.namespaceStateLookup = lookup
+	}
+}
+
+// WithGlobalNamespaceLookup makes the interceptor resolve whether the target namespace is global
+// and expose it as CallTarget.IsGlobalNamespace
+func WithGlobalNamespaceLookup(lookup GlobalNamespaceLookup) InterceptorOption {
+	return func(a *interceptor) {
+		a.globalNamespaceLookup = lookup
+	}
+}
+
+// WithNamespaceLabelsLookup makes the interceptor resolve the labels of the target namespace
+// and expose them as CallTarget.NamespaceLabels
+func WithNamespaceLabelsLookup(lookup NamespaceLabelsLookup) InterceptorOption {
+	return func(a *interceptor) {
+		a.namespaceLabelsLookup = lookup
+	}
+}
+
+// WithRequestCost makes the interceptor compute the cost of each request and expose it as CallTarget.Cost
+func WithRequestCost(costFunc RequestCostFunc) InterceptorOption {
+	return func(a *interceptor) {
+		a.requestCost = costFunc
+	}
+}
+
+// WithAnonymousAPIs allows anonymous callers, i.e. callers with empty claims, to call the given APIs
+// without consulting the authorizer, and denies anonymous calls to all other APIs.
+// Calls from callers with non-empty claims are authorized as usual.
+func WithAnonymousAPIs(apiNames ...string) InterceptorOption {
+	return func(a *interceptor) {
+		a.anonymousAPIs = make(map[string]struct{}, len(apiNames))
+		for _, apiName := range apiNames {
+			a.anonymousAPIs[apiName] = struct{}{}
+		}
+	}
+}
+
+// WithWarnOnlyAPIs makes the interceptor allow calls to the given APIs even if the authorizer denies them,
+// logging and counting the denies instead, to observe the impact of a stricter policy before enforcing it
+func WithWarnOnlyAPIs(apiNames ...string) InterceptorOption {
+	return func(a *interceptor) {
+		a.warnOnlyAPIs = make(map[string]struct{}, len(apiNames))
+		for _, apiName := range apiNames {
+			a.warnOnlyAPIs[apiName] = struct{}{}
+		}
+	}
+}
+
+// WithMaintenanceMode rejects mutating APIs with Unavailable while mode is read-only, without consulting
+// the authorizer. Read-only APIs are authorized as usual.
+func WithMaintenanceMode(mode *MaintenanceMode) InterceptorOption {
+	return func(a *interceptor) {
+		a.maintenanceMode = mode
+	}
+}
+
+// WithMetricsSampleRate makes the interceptor emit its latency timers for only a fraction of calls,
+// between 0 and 1, to reduce the overhead of metrics under high load. Sampling is deterministic:
+// with a rate of 1/N the first call and every N-th call after it are sampled.
+// Counters of unauthorized and failed calls are emitted for every call regardless of the rate.
+func WithMetricsSampleRate(rate float64) InterceptorOption {
+	return func(a *interceptor) {
+		a.metricsSampler = newMetricsSampler(rate)
+	}
+}
+
+// WithDecisionObserver notifies observer of every decision made by the authorizer. The observer runs
+// asynchronously so it never delays or affects the call, and panics inside it are recovered and logged.
+// The context passed to it may already be done by the time it runs. Decisions are queued for a few
+// background goroutines, those made while the queue is full are dropped and counted.
+func WithDecisionObserver(observer DecisionObserver) InterceptorOption {
+	return func(a *interceptor) {
+		a.decisionObservations = newDecisionObserverQueue(
+			observer,
+			defaultDecisionObserverQueueSize,
+			defaultDecisionObserverWorkers,
+			a.metricsClient,
+			a.logger,
+		)
+	}
+}
+
+// WithDenyMessages makes the interceptor return the configured message to callers whose calls are denied
+// by the authorizer. Calls with no configured message are rejected with the default message.
+func WithDenyMessages(messages DenyMessages) InterceptorOption {
+	return func(a *interceptor) {
+		a.denyMessages = &messages
+	}
+}
+
+// WithConcurrencyLimiter makes the interceptor reject authorized calls with a ResourceExhausted error
+// when their subject already has the maximum number of calls in flight allowed by limiter.
+// Calls of anonymous callers are not limited.
+func WithConcurrencyLimiter(limiter ConcurrencyLimiter) InterceptorOption {
+	return func(a *interceptor) {
+		a.concurrencyLimiter = limiter
+	}
+}
+
+// WithDryRun enables the dry-run header for diagnostic callers, i.e. callers holding any of diagnosticRoles
+// at the system level. Their calls that set DryRunHeaderName to "true" are authorized as usual, but instead of
+// executing the handler the interceptor returns the decision in the DryRunDecisionHeaderName and
+// DryRunReasonHeaderName response headers and fails the call with an Aborted status.
+// Dry runs requested by all other callers are rejected.
+func WithDryRun(diagnosticRoles Role) InterceptorOption {
+	return func(a *interceptor) {
+		a.dryRunRole = diagnosticRoles
+	}
+}
+
+// WithEffectiveRoles makes the interceptor resolve the effective roles of the caller in the target namespace
+// of authorized calls, so that handlers can retrieve them with EffectiveRolesFromContext instead of
+// resolving the claims themselves.
+func WithEffectiveRoles() InterceptorOption {
+	return func(a *interceptor) {
+		a.effectiveRoles = true
+	}
+}
+
+// WithFeatureFlags rejects calls to APIs whose feature flag is off according to resolver with FailedPrecondition,
+// regardless of the roles of the caller and without consulting the authorizer. APIs that are not gated
+// must be reported as enabled by resolver.
+func WithFeatureFlags(resolver FeatureFlagResolver) InterceptorOption {
+	return func(a *interceptor) {
+		a.featureFlags = resolver
+	}
+}
+
+// WithCallerTypeTag tags the authorization metrics of a call, except for the total latency of the interceptor,
+// with the type of its caller, to separate the traffic of system components, callers holding a system role,
+// from the traffic of users and anonymous callers
+func WithCallerTypeTag() InterceptorOption {
+	return func(a *interceptor) {
+		a.callerTypeTag = true
+	}
+}
+
+// WithUnsafeDisasterRecoveryBypass allows all calls to the namespaces bypass currently lists without consulting
+// the authorizer, i.e. it disables access control for them. Each bypassed call is logged and counted.
+// Only meant to restore operations during disaster recovery, see DisasterRecoveryBypass.
+func WithUnsafeDisasterRecoveryBypass(bypass *DisasterRecoveryBypass) InterceptorOption {
+	return func(a *interceptor) {
+		a.drBypass = bypass
+	}
+}
+
+// WithStreamMessageTargets makes the stream interceptor authorize each message received on streams of method,
+// in addition to the stream open, using the target extractor returns for the message. A denied message fails
+// the receive of the handler, which closes the stream by returning the error. A target without an API name
+// is authorized as a call to method.
+func WithStreamMessageTargets(method string, extractor StreamMessageTargetExtractor) InterceptorOption {
+	return func(a *interceptor) {
+		if a.streamMessageTargets == nil {
+			a.streamMessageTargets = make(map[string]StreamMessageTargetExtractor)
+		}
+		a.streamMessageTargets[method] = extractor
+	}
+}
+
+// WithConfigInspector installs inspector in the interceptor, so that the active authorization configuration
+// of the interceptor can be retrieved with ConfigInspector.Snapshot
+func WithConfigInspector(inspector *ConfigInspector) InterceptorOption {
+	return func(a *interceptor) {
+		inspector.interceptor.Store(a)
+	}
+}
+
+// WithTimeouts limits the time the claim mapper may take to map the claims of a call and the time the authorizer
+// may take to authorize it, each with its own deadline so that e.g. a slow fetch of token keys doesn't consume
+// the time left to authorize. Calls exceeding either timeout fail, as unauthenticated or unauthorized respectively,
+// and are counted. A zero timeout leaves the step limited only by the deadline of the call.
+func WithTimeouts(claimMappingTimeout time.Duration, authorizationTimeout time.Duration) InterceptorOption {
+	return func(a *interceptor) {
+		a.claimMappingTimeout = claimMappingTimeout
+		a.authorizationTimeout = authorizationTimeout
+	}
+}
+
+// WithSearchAttributePolicyLookup makes the interceptor resolve the search attribute policies of the namespaces
+// targeted by calls using search attributes, and of their sub-targets, and expose them as
+// CallTarget.SearchAttributePolicy, see NewSearchAttributeAuthorizer. It implies WithRequestContentExtraction,
+// since the search attributes of calls must be extracted to be checked against the policies.
+func WithSearchAttributePolicyLookup(lookup NamespaceSearchAttributePolicyLookup) InterceptorOption {
+	return func(a *interceptor) {
+		a.searchAttributePolicyLookup = lookup
+		a.extractRequestContent = true
+	}
+}
+
+// WithDecisionMetricsObserver reports every decision made by the authorizer to observer, in addition to
+// the metrics recorded with the metrics client. Calls failing to be authorized are not reported as decisions.
+// A nil observer is ignored.
+func WithDecisionMetricsObserver(observer DecisionMetricsObserver) InterceptorOption {
+	return func(a *interceptor) {
+		a.decisionMetricsObserver = observer
+	}
+}
+
+// WithNamespaceActivityLookup makes the interceptor resolve whether the target namespace is active in the current
+// cluster and expose it as CallTarget.IsNamespacePassive, see NewActiveClusterAuthorizer
+func WithNamespaceActivityLookup(lookup NamespaceActivityLookup) InterceptorOption {
+	return func(a *interceptor) {
+		a.namespaceActivityLookup = lookup
+	}
+}
+
+// WithRequestValidation makes the interceptor validate requests with validator, e.g. ValidateRequest, and reject
+// malformed requests with the error of the validator. With ValidateBeforeAuthorization malformed requests are
+// rejected before any effort is spent on authorizing them, with ValidateAfterAuthorization callers that are not
+// authorized learn nothing about the validity of their requests.
+func WithRequestValidation(validator RequestValidator, order ValidationOrder) InterceptorOption {
+	return func(a *interceptor) {
+		a.requestValidator = validator
+		a.validationOrder = order
+	}
+}
+
+// WithDeprecatedAPIs rejects calls to deprecated APIs with FailedPrecondition, regardless of the roles of the caller
+// and without consulting the authorizer. deprecated maps the full names of the APIs to hints on migrating off them,
+// e.g. the API replacing them, which are included in the error returned to callers.
+func WithDeprecatedAPIs(deprecated map[string]string) InterceptorOption {
+	return func(a *interceptor) {
+		a.deprecatedAPIs = deprecated
+	}
+}
+
+// WithTaskTokenDecoder makes the interceptor decode the task tokens of calls to TaskTokenAPIs with decoder, e.g. one
+// created by NewTaskTokenDecoder, and set the namespace, workflow execution and activity of their CallTarget
+// to those of the token. Calls with a missing or malformed task token, or naming another namespace than
+// their token, fail as unauthorized.
+func WithTaskTokenDecoder(decoder TaskTokenDecoder) InterceptorOption {
+	return func(a *interceptor) {
+		a.taskTokenDecoder = decoder
+	}
+}
+
+// WithUnauthorizedExemplarCounter makes the interceptor count unauthorized calls also in counter, with the trace ID
+// of the call read from its traceparent header, so that dashboards can link spikes in denials to example traces.
+func WithUnauthorizedExemplarCounter(counter UnauthorizedExemplarCounter) InterceptorOption {
+	return func(a *interceptor) {
+		a.unauthorizedExemplarCounter = counter
+	}
+}
+
+// WithRequestContentExtraction makes the interceptor extract the search attributes and the memo set by requests,
+// the identifiers of visibility queries and the commands of workflow task completions into the CallTarget,
+// for authorizers such as those created by NewSearchAttributeAuthorizer, NewMemoAuthorizer or
+// NewSubTargetAuthorizer. Without it calls are authorized without them, sparing the decoding of payloads
+// and the walk through commands on every call.
+func WithRequestContentExtraction() InterceptorOption {
+	return func(a *interceptor) {
+		a.extractRequestContent = true
+	}
+}
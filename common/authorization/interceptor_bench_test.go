@@ -0,0 +1,69 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"testing"
+
+	"github.com/uber-go/tally"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"go.temporal.io/server/common/log/loggerimpl"
+	"go.temporal.io/server/common/metrics"
+)
+
+/**
+$ go test -run=^$ -bench=Interceptor ./common/authorization
+BenchmarkInterceptorMetrics        	  224607	      5818 ns/op	    2117 B/op	      25 allocs/op
+BenchmarkInterceptorSampledMetrics 	  360042	      3397 ns/op	    1192 B/op	      17 allocs/op
+*/
+
+func BenchmarkInterceptorMetrics(b *testing.B) {
+	benchmarkInterceptor(b)
+}
+
+func BenchmarkInterceptorSampledMetrics(b *testing.B) {
+	benchmarkInterceptor(b, WithMetricsSampleRate(0.01))
+}
+
+func benchmarkInterceptor(b *testing.B, opts ...InterceptorOption) {
+	metricsClient := metrics.NewClient(tally.NewTestScope("test", nil), metrics.Frontend)
+	interceptor := NewAuthorizationInterceptor(
+		nil,
+		NewNoopAuthorizer(),
+		metricsClient,
+		loggerimpl.NewLogger(zap.NewNop()),
+		opts...)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil }
+	info := &grpc.UnaryServerInfo{FullMethod: describeNamespaceInfo.FullMethod}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = interceptor(context.Background(), describeNamespaceRequest, info, handler)
+	}
+}
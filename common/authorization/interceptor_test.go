@@ -26,30 +26,47 @@ package authorization
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/gogo/status"
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"github.com/uber-go/tally"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
 	"go.temporal.io/api/workflowservice/v1"
 	"go.temporal.io/api/workflowservicemock/v1"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 
+	"go.temporal.io/server/common/headers"
+	"go.temporal.io/server/common/log"
 	"go.temporal.io/server/common/log/loggerimpl"
+	"go.temporal.io/server/common/log/tag"
 	"go.temporal.io/server/common/metrics"
 )
 
 const (
-	testNamespace string = "test-namespace"
+	testNamespace           string = "test-namespace"
+	interceptorLatencyTimer string = "interceptor_latency"
 )
 
 var (
 	ctx                           = context.Background()
-	describeNamespaceTarget       = &CallTarget{Namespace: testNamespace, APIName: "/temporal.api.workflowservice.v1.WorkflowService/DescribeNamespace"}
+	describeNamespaceTarget       = &CallTarget{Namespace: testNamespace, APIName: "/temporal.api.workflowservice.v1.WorkflowService/DescribeNamespace", APIGroup: APIGroupRead}
 	describeNamespaceRequest      = &workflowservice.DescribeNamespaceRequest{Namespace: testNamespace}
 	describeNamespaceInfo         = &grpc.UnaryServerInfo{FullMethod: "/temporal.api.workflowservice.v1.WorkflowService/DescribeNamespace"}
-	startWorkflowExecutionTarget  = &CallTarget{Namespace: testNamespace, APIName: "/temporal.api.workflowservice.v1.WorkflowService/StartWorkflowExecution"}
+	startWorkflowExecutionTarget  = &CallTarget{Namespace: testNamespace, APIName: "/temporal.api.workflowservice.v1.WorkflowService/StartWorkflowExecution", APIGroup: APIGroupWrite}
 	startWorkflowExecutionRequest = &workflowservice.StartWorkflowExecutionRequest{Namespace: testNamespace}
 	startWorkflowExecutionInfo    = &grpc.UnaryServerInfo{FullMethod: "/temporal.api.workflowservice.v1.WorkflowService/StartWorkflowExecution"}
 )
@@ -67,6 +84,7 @@ type (
 		interceptor         grpc.UnaryServerInterceptor
 		handler             grpc.UnaryHandler
 		mockClaimMapper     *MockClaimMapper
+		testScope           tally.TestScope
 	}
 )
 
@@ -83,6 +101,9 @@ func (s *authorizerInterceptorSuite) SetupTest() {
 	s.mockAuthorizer = NewMockAuthorizer(s.controller)
 	s.mockMetricsScope = metrics.NewMockScope(s.controller)
 	s.mockMetricsClient = metrics.NewMockClient(s.controller)
+	s.testScope = tally.NewTestScope("test", nil)
+	s.mockMetricsClient.EXPECT().StartTimer(metrics.AuthorizationScope, metrics.ServiceAuthorizationInterceptorLatency).
+		Return(s.testScope.Timer(interceptorLatencyTimer).Start())
 	s.mockMetricsClient.EXPECT().Scope(metrics.AuthorizationScope).Return(s.mockMetricsScope)
 	s.mockMetricsScope.EXPECT().Tagged(metrics.NamespaceTag(testNamespace)).Return(s.mockMetricsScope)
 	s.mockMetricsScope.EXPECT().StartTimer(metrics.ServiceAuthorizationLatency).Return(metrics.Stopwatch{})
@@ -117,16 +138,105 @@ func (s *authorizerInterceptorSuite) TestIsAuthorizedWithNamespace() {
 	s.NoError(err)
 }
 
+func (s *authorizerInterceptorSuite) TestConstraints() {
+	constraints := &Constraints{Namespaces: []string{testNamespace}}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow, Constraints: constraints}, nil).Times(1)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		s.Equal(constraints, ConstraintsFromContext(ctx))
+		return true, nil
+	}
+	res, err := s.interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) TestNoConstraints() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		s.Nil(ConstraintsFromContext(ctx))
+		return true, nil
+	}
+	res, err := s.interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
 func (s *authorizerInterceptorSuite) TestIsUnauthorized() {
 	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
 		Return(Result{Decision: DecisionDeny}, nil).Times(1)
 	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	s.expectDenyReason(reasonTagValueUnspecified)
+
+	res, err := s.interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.Nil(res)
+	s.Error(err)
+}
+
+func (s *authorizerInterceptorSuite) TestIsUnauthorizedWithReason() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil).Times(1)
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	s.expectDenyReason(string(ReasonInsufficientRole))
+
+	res, err := s.interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.Nil(res)
+	s.Error(err)
+}
+
+func (s *authorizerInterceptorSuite) TestIsUnauthorizedWithCustomReason() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionDeny, Reason: "custom"}, nil).Times(1)
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	s.expectDenyReason(reasonTagValueOther)
 
 	res, err := s.interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
 	s.Nil(res)
 	s.Error(err)
 }
 
+func (s *authorizerInterceptorSuite) TestDenyTrailer() {
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil).Times(1)
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	s.expectDenyReason(string(ReasonInsufficientRole))
+	stream := &testServerTransportStream{}
+
+	res, err := s.interceptor(grpc.NewContextWithServerTransportStream(ctx, stream), describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.Nil(res)
+	s.Error(err)
+	s.Equal(metadata.Pairs(
+		DecisionTrailerName, "deny",
+		ReasonTrailerName, string(ReasonInsufficientRole),
+	), stream.trailer)
+}
+
+func (s *authorizerInterceptorSuite) TestDenyTrailerWithoutReason() {
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionDeny}, nil).Times(1)
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	s.expectDenyReason(reasonTagValueUnspecified)
+	stream := &testServerTransportStream{}
+
+	_, err := s.interceptor(grpc.NewContextWithServerTransportStream(ctx, stream), describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.Error(err)
+	s.Equal(metadata.Pairs(DecisionTrailerName, "deny"), stream.trailer)
+}
+
+func (s *authorizerInterceptorSuite) TestNoTrailerWhenAllowed() {
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+	stream := &testServerTransportStream{}
+
+	res, err := s.interceptor(grpc.NewContextWithServerTransportStream(ctx, stream), describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+	s.Nil(stream.trailer)
+}
+
 func (s *authorizerInterceptorSuite) TestAuthorizationFailed() {
 	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
 		Return(Result{Decision: DecisionDeny}, errUnauthorized).Times(1)
@@ -136,3 +246,1637 @@ func (s *authorizerInterceptorSuite) TestAuthorizationFailed() {
 	s.Nil(res)
 	s.Error(err)
 }
+
+func (s *authorizerInterceptorSuite) TestInterceptorLatencyAuthorized() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		// the timer must be stopped before the handler runs
+		s.assertInterceptorLatencyRecorded()
+		return true, nil
+	}
+	res, err := s.interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, handler)
+	s.True(res.(bool))
+	s.NoError(err)
+	s.assertInterceptorLatencyRecorded()
+}
+
+func (s *authorizerInterceptorSuite) TestInterceptorLatencyUnauthorized() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionDeny}, nil).Times(1)
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	s.expectDenyReason(reasonTagValueUnspecified)
+
+	res, err := s.interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.Nil(res)
+	s.Error(err)
+	s.assertInterceptorLatencyRecorded()
+}
+
+func (s *authorizerInterceptorSuite) assertInterceptorLatencyRecorded() {
+	timers := s.testScope.Snapshot().Timers()
+	s.Len(timers, 1)
+	for _, timer := range timers {
+		s.Equal("test."+interceptorLatencyTimer, timer.Name())
+		s.Len(timer.Values(), 1)
+	}
+}
+
+func (s *authorizerInterceptorSuite) expectDenyReason(reason string) {
+	reasonScope := metrics.NewMockScope(s.controller)
+	s.mockMetricsScope.EXPECT().Tagged(metrics.ReasonTag(reason)).Return(reasonScope)
+	reasonScope.EXPECT().IncCounter(metrics.ServiceAuthorizationDenyReasonCounter)
+}
+
+func (s *authorizerInterceptorSuite) TestAuthorizedImpersonation() {
+	caller := &Claims{Subject: "support", System: RoleImpersonator}
+	impersonated := &Claims{Subject: "user", Namespaces: map[string]Role{testNamespace: RoleReader}}
+	interceptor := s.newInterceptorWithImpersonation(map[string]*Claims{"user": impersonated})
+	s.mockClaimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(caller, nil).Times(1)
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), impersonated, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	ctxWithHeaders := metadata.NewIncomingContext(ctx, metadata.Pairs(
+		"authorization", "Bearer token",
+		impersonateHeaderName, "user",
+	))
+	res, err := interceptor(ctxWithHeaders, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) TestUnauthorizedImpersonation() {
+	caller := &Claims{Subject: "user", System: RoleAdmin}
+	interceptor := s.newInterceptorWithImpersonation(map[string]*Claims{"other": {Subject: "other"}})
+	s.mockClaimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(caller, nil).Times(1)
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), caller, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	ctxWithHeaders := metadata.NewIncomingContext(ctx, metadata.Pairs(
+		"authorization", "Bearer token",
+		impersonateHeaderName, "other",
+	))
+	res, err := interceptor(ctxWithHeaders, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) newInterceptorWithImpersonation(subjects map[string]*Claims) grpc.UnaryServerInterceptor {
+	return NewAuthorizationInterceptor(
+		s.mockClaimMapper,
+		s.mockAuthorizer,
+		s.mockMetricsClient,
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithImpersonation(testClaimsLookup(subjects)))
+}
+
+type testClaimsLookup map[string]*Claims
+
+func (l testClaimsLookup) LookupClaims(_ context.Context, subject string) (*Claims, error) {
+	claims, ok := l[subject]
+	if !ok {
+		return nil, fmt.Errorf("unknown subject: %s", subject)
+	}
+	return claims, nil
+}
+
+func (s *authorizerInterceptorSuite) TestMutatingAPIRegisteredNamespace() {
+	interceptor := s.newInterceptorWithNamespaceState(enumspb.NAMESPACE_STATE_REGISTERED)
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, startWorkflowExecutionTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	res, err := interceptor(ctx, startWorkflowExecutionRequest, startWorkflowExecutionInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) TestMutatingAPIDeprecatedNamespace() {
+	interceptor := s.newInterceptorWithNamespaceState(enumspb.NAMESPACE_STATE_DEPRECATED)
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, startWorkflowExecutionTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	res, err := interceptor(ctx, startWorkflowExecutionRequest, startWorkflowExecutionInfo, s.handler)
+	s.Nil(res)
+	s.Equal(codes.FailedPrecondition, status.Code(err))
+}
+
+func (s *authorizerInterceptorSuite) TestMutatingAPIDeletedNamespace() {
+	interceptor := s.newInterceptorWithNamespaceState(enumspb.NAMESPACE_STATE_DELETED)
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, startWorkflowExecutionTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	res, err := interceptor(ctx, startWorkflowExecutionRequest, startWorkflowExecutionInfo, s.handler)
+	s.Nil(res)
+	s.Equal(codes.FailedPrecondition, status.Code(err))
+}
+
+func (s *authorizerInterceptorSuite) TestReadAPIDeprecatedNamespace() {
+	interceptor := s.newInterceptorWithNamespaceState(enumspb.NAMESPACE_STATE_DEPRECATED)
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	res, err := interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) newInterceptorWithNamespaceState(state enumspb.NamespaceState) grpc.UnaryServerInterceptor {
+	return NewAuthorizationInterceptor(
+		s.mockClaimMapper,
+		s.mockAuthorizer,
+		s.mockMetricsClient,
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithNamespaceStateLookup(testNamespaceStateLookup{testNamespace: state}))
+}
+
+type testNamespaceStateLookup map[string]enumspb.NamespaceState
+
+func (l testNamespaceStateLookup) GetNamespaceState(namespace string) (enumspb.NamespaceState, error) {
+	state, ok := l[namespace]
+	if !ok {
+		return enumspb.NAMESPACE_STATE_UNSPECIFIED, fmt.Errorf("unknown namespace: %s", namespace)
+	}
+	return state, nil
+}
+
+func (s *authorizerInterceptorSuite) TestRequestCost() {
+	interceptor := NewAuthorizationInterceptor(
+		s.mockClaimMapper,
+		s.mockAuthorizer,
+		s.mockMetricsClient,
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithRequestCost(RequestSize))
+	target := *startWorkflowExecutionTarget
+	target.Cost = startWorkflowExecutionRequest.Size()
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, &target).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	res, err := interceptor(ctx, startWorkflowExecutionRequest, startWorkflowExecutionInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) TestAnonymousAllowedAPI() {
+	interceptor := s.newInterceptorWithAnonymousAPIs()
+
+	res, err := interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) TestAnonymousDeniedAPI() {
+	interceptor := s.newInterceptorWithAnonymousAPIs()
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	s.expectDenyReason(string(ReasonAnonymous))
+
+	res, err := interceptor(ctx, startWorkflowExecutionRequest, startWorkflowExecutionInfo, s.handler)
+	s.Nil(res)
+	s.Equal(codes.Unauthenticated, serviceerror.ToStatus(err).Code())
+}
+
+func (s *authorizerInterceptorSuite) TestNoClaimsUnauthenticated() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionDeny, Reason: ReasonNoClaims}, nil).Times(1)
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	s.expectDenyReason(string(ReasonNoClaims))
+
+	res, err := s.interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.Nil(res)
+	s.Equal(codes.Unauthenticated, serviceerror.ToStatus(err).Code())
+}
+
+func (s *authorizerInterceptorSuite) TestIdentifiedCallerPermissionDenied() {
+	claims := &Claims{Subject: testSubject}
+	s.mockClaimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(claims, nil).Times(1)
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), claims, describeNamespaceTarget).
+		Return(Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil).Times(1)
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	s.expectDenyReason(string(ReasonInsufficientRole))
+
+	ctxWithHeaders := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer token"))
+	res, err := s.interceptor(ctxWithHeaders, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.Nil(res)
+	s.Equal(codes.PermissionDenied, serviceerror.ToStatus(err).Code())
+}
+
+func (s *authorizerInterceptorSuite) TestDenyReasonChain() {
+	logger := &log.MockLogger{}
+	var loggedReason string
+	logger.On("Info", "authorization denied", mock.Anything).Run(func(args mock.Arguments) {
+		for _, t := range args.Get(1).([]tag.Tag) {
+			if field := t.Field(); field.Key == "auth-reason" {
+				loggedReason = field.String
+			}
+		}
+	}).Once()
+	interceptor := NewAuthorizationInterceptor(s.mockClaimMapper, s.mockAuthorizer, s.mockMetricsClient, logger)
+
+	claims := &Claims{Subject: testSubject}
+	reason := WrapDenyReason(NewDenyReason(ReasonInsufficientRole, "base: insufficient role"), "subject_revoked", "blocklist: subject revoked")
+	s.mockClaimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(claims, nil).Times(1)
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), claims, describeNamespaceTarget).Return(NewDenyResult(reason), nil).Times(1)
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	s.expectDenyReason("other")
+
+	ctxWithHeaders := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer token"))
+	res, err := interceptor(ctxWithHeaders, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.Nil(res)
+	s.Equal(codes.PermissionDenied, serviceerror.ToStatus(err).Code())
+	s.Equal("blocklist: subject revoked", serviceerror.ToStatus(err).Message())
+	s.Equal("blocklist: subject revoked: base: insufficient role", loggedReason)
+	logger.AssertExpectations(s.T())
+}
+
+func (s *authorizerInterceptorSuite) TestIdentifiedCallerWithAnonymousAPIs() {
+	interceptor := s.newInterceptorWithAnonymousAPIs()
+	claims := &Claims{Subject: testSubject}
+	s.mockClaimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(claims, nil).Times(1)
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), claims, startWorkflowExecutionTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	ctxWithHeaders := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer token"))
+	res, err := interceptor(ctxWithHeaders, startWorkflowExecutionRequest, startWorkflowExecutionInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) newInterceptorWithAnonymousAPIs() grpc.UnaryServerInterceptor {
+	return NewAuthorizationInterceptor(
+		s.mockClaimMapper,
+		s.mockAuthorizer,
+		s.mockMetricsClient,
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithAnonymousAPIs(describeNamespaceInfo.FullMethod))
+}
+
+func (s *authorizerInterceptorSuite) TestReadOnlyModeMutatingAPI() {
+	mode := NewMaintenanceMode()
+	mode.SetReadOnly(true)
+	s.expectDenyReason(string(ReasonMaintenance))
+
+	res, err := s.newInterceptorWithMaintenanceMode(mode)(ctx, startWorkflowExecutionRequest, startWorkflowExecutionInfo, s.handler)
+	s.Nil(res)
+	s.IsType(&serviceerror.Unavailable{}, err)
+}
+
+func (s *authorizerInterceptorSuite) TestReadOnlyModeReadAPI() {
+	mode := NewMaintenanceMode()
+	mode.SetReadOnly(true)
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	res, err := s.newInterceptorWithMaintenanceMode(mode)(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) TestReadOnlyModeTurnedOff() {
+	mode := NewMaintenanceMode()
+	mode.SetReadOnly(true)
+	mode.SetReadOnly(false)
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, startWorkflowExecutionTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	res, err := s.newInterceptorWithMaintenanceMode(mode)(ctx, startWorkflowExecutionRequest, startWorkflowExecutionInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) newInterceptorWithMaintenanceMode(mode *MaintenanceMode) grpc.UnaryServerInterceptor {
+	return NewAuthorizationInterceptor(
+		s.mockClaimMapper,
+		s.mockAuthorizer,
+		s.mockMetricsClient,
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithMaintenanceMode(mode))
+}
+
+func (s *authorizerInterceptorSuite) TestDeprecatedAPI() {
+	claims := &Claims{Subject: testSubject, System: RoleAdmin}
+	s.mockClaimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(claims, nil).Times(1)
+	s.expectDenyReason(string(ReasonAPIDeprecated))
+	interceptor := s.newInterceptorWithDeprecatedAPIs(map[string]string{
+		startWorkflowExecutionInfo.FullMethod: "Use SignalWithStartWorkflowExecution instead.",
+	})
+
+	ctxWithHeaders := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer token"))
+	res, err := interceptor(ctxWithHeaders, startWorkflowExecutionRequest, startWorkflowExecutionInfo, s.handler)
+	s.Nil(res)
+	s.Equal(codes.FailedPrecondition, status.Code(err))
+	s.Equal("API "+startWorkflowExecutionInfo.FullMethod+" is deprecated. Use SignalWithStartWorkflowExecution instead.",
+		status.Convert(err).Message())
+}
+
+func (s *authorizerInterceptorSuite) TestDeprecatedAPIWithoutHint() {
+	s.expectDenyReason(string(ReasonAPIDeprecated))
+	interceptor := s.newInterceptorWithDeprecatedAPIs(map[string]string{describeNamespaceInfo.FullMethod: ""})
+
+	res, err := interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.Nil(res)
+	s.Equal(codes.FailedPrecondition, status.Code(err))
+	s.Equal("API "+describeNamespaceInfo.FullMethod+" is deprecated.", status.Convert(err).Message())
+}
+
+func (s *authorizerInterceptorSuite) TestAPINotDeprecated() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+	interceptor := s.newInterceptorWithDeprecatedAPIs(map[string]string{startWorkflowExecutionInfo.FullMethod: ""})
+
+	res, err := interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) newInterceptorWithDeprecatedAPIs(deprecated map[string]string) grpc.UnaryServerInterceptor {
+	return NewAuthorizationInterceptor(
+		s.mockClaimMapper,
+		s.mockAuthorizer,
+		s.mockMetricsClient,
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithDeprecatedAPIs(deprecated))
+}
+
+type testFeatureFlags map[string]bool
+
+func (f testFeatureFlags) IsAPIEnabled(apiName string, _ string) bool {
+	enabled, ok := f[apiName]
+	return !ok || enabled
+}
+
+func (s *authorizerInterceptorSuite) TestFeatureDisabled() {
+	flags := testFeatureFlags{startWorkflowExecutionInfo.FullMethod: false}
+	s.expectDenyReason(string(ReasonFeatureDisabled))
+
+	res, err := s.newInterceptorWithFeatureFlags(flags)(ctx, startWorkflowExecutionRequest, startWorkflowExecutionInfo, s.handler)
+	s.Nil(res)
+	s.Equal(codes.FailedPrecondition, status.Code(err))
+}
+
+func (s *authorizerInterceptorSuite) TestFeatureDisabledForAdmin() {
+	flags := testFeatureFlags{startWorkflowExecutionInfo.FullMethod: false}
+	claims := &Claims{Subject: testSubject, System: RoleAdmin}
+	s.mockClaimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(claims, nil).Times(1)
+	s.expectDenyReason(string(ReasonFeatureDisabled))
+
+	ctxWithHeaders := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer token"))
+	res, err := s.newInterceptorWithFeatureFlags(flags)(ctxWithHeaders, startWorkflowExecutionRequest, startWorkflowExecutionInfo, s.handler)
+	s.Nil(res)
+	s.Equal(codes.FailedPrecondition, status.Code(err))
+}
+
+func (s *authorizerInterceptorSuite) TestFeatureEnabled() {
+	flags := testFeatureFlags{startWorkflowExecutionInfo.FullMethod: true}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, startWorkflowExecutionTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	res, err := s.newInterceptorWithFeatureFlags(flags)(ctx, startWorkflowExecutionRequest, startWorkflowExecutionInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) TestFeatureNotGated() {
+	flags := testFeatureFlags{startWorkflowExecutionInfo.FullMethod: false}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	res, err := s.newInterceptorWithFeatureFlags(flags)(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) newInterceptorWithFeatureFlags(flags FeatureFlagResolver) grpc.UnaryServerInterceptor {
+	return NewAuthorizationInterceptor(
+		s.mockClaimMapper,
+		s.mockAuthorizer,
+		s.mockMetricsClient,
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithFeatureFlags(flags))
+}
+
+func (s *authorizerInterceptorSuite) TestCallerTypeTagAnonymous() {
+	s.mockMetricsScope.EXPECT().Tagged(metrics.CallerTypeTag(metrics.AnonymousCallerTypeTagValue)).Return(s.mockMetricsScope)
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	res, err := s.newInterceptorWithCallerTypeTag()(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) TestCallerTypeTagAnonymousEmptyClaims() {
+	s.testCallerTypeTag(&Claims{}, metrics.AnonymousCallerTypeTagValue)
+}
+
+func (s *authorizerInterceptorSuite) TestCallerTypeTagUser() {
+	s.testCallerTypeTag(&Claims{Subject: testSubject, Namespaces: map[string]Role{testNamespace: RoleAdmin}}, metrics.UserCallerTypeTagValue)
+}
+
+func (s *authorizerInterceptorSuite) TestCallerTypeTagSystem() {
+	s.testCallerTypeTag(&Claims{Subject: "history", System: RoleWorker}, metrics.SystemCallerTypeTagValue)
+}
+
+func (s *authorizerInterceptorSuite) testCallerTypeTag(claims *Claims, expected string) {
+	s.mockClaimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(claims, nil).Times(1)
+	s.mockMetricsScope.EXPECT().Tagged(metrics.CallerTypeTag(expected)).Return(s.mockMetricsScope)
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), claims, describeNamespaceTarget).
+		Return(Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil).Times(1)
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	s.expectDenyReason(string(ReasonInsufficientRole))
+
+	ctxWithHeaders := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer token"))
+	res, err := s.newInterceptorWithCallerTypeTag()(ctxWithHeaders, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.Nil(res)
+	s.Error(err)
+}
+
+func (s *authorizerInterceptorSuite) newInterceptorWithCallerTypeTag() grpc.UnaryServerInterceptor {
+	return NewAuthorizationInterceptor(
+		s.mockClaimMapper,
+		s.mockAuthorizer,
+		s.mockMetricsClient,
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithCallerTypeTag())
+}
+
+func (s *authorizerInterceptorSuite) TestDRBypassedNamespace() {
+	bypass := NewDisasterRecoveryBypass()
+	bypass.UnsafeBypassNamespace(testNamespace)
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceAuthorizationDRBypassCounter)
+
+	res, err := s.newInterceptorWithDRBypass(bypass)(ctx, startWorkflowExecutionRequest, startWorkflowExecutionInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) TestDRBypassOtherNamespaceEnforced() {
+	bypass := NewDisasterRecoveryBypass()
+	bypass.UnsafeBypassNamespace("other-namespace")
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, startWorkflowExecutionTarget).
+		Return(Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil).Times(1)
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	s.expectDenyReason(string(ReasonInsufficientRole))
+
+	res, err := s.newInterceptorWithDRBypass(bypass)(ctx, startWorkflowExecutionRequest, startWorkflowExecutionInfo, s.handler)
+	s.Nil(res)
+	s.Equal(errUnauthorized, err)
+}
+
+func (s *authorizerInterceptorSuite) TestDRBypassTurnedOff() {
+	bypass := NewDisasterRecoveryBypass()
+	bypass.UnsafeBypassNamespace(testNamespace)
+	bypass.EnforceNamespace(testNamespace)
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, startWorkflowExecutionTarget).
+		Return(Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil).Times(1)
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	s.expectDenyReason(string(ReasonInsufficientRole))
+
+	res, err := s.newInterceptorWithDRBypass(bypass)(ctx, startWorkflowExecutionRequest, startWorkflowExecutionInfo, s.handler)
+	s.Nil(res)
+	s.Equal(errUnauthorized, err)
+}
+
+func (s *authorizerInterceptorSuite) newInterceptorWithDRBypass(bypass *DisasterRecoveryBypass) grpc.UnaryServerInterceptor {
+	return NewAuthorizationInterceptor(
+		s.mockClaimMapper,
+		s.mockAuthorizer,
+		s.mockMetricsClient,
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithUnsafeDisasterRecoveryBypass(bypass))
+}
+
+func (s *authorizerInterceptorSuite) TestMetricsSampling() {
+	interceptor := NewAuthorizationInterceptor(
+		s.mockClaimMapper,
+		s.mockAuthorizer,
+		s.mockMetricsClient,
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithMetricsSampleRate(0.25))
+	// timers are started by SetupTest expectations for the first call only
+	s.mockMetricsClient.EXPECT().Scope(metrics.AuthorizationScope).Return(s.mockMetricsScope).Times(2)
+	s.mockMetricsScope.EXPECT().Tagged(metrics.NamespaceTag(testNamespace)).Return(s.mockMetricsScope).Times(2)
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(2)
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionDeny}, nil).Times(1)
+	// unauthorized counters are emitted even if the call is not sampled
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	s.expectDenyReason(reasonTagValueUnspecified)
+
+	for i := 0; i < 2; i++ {
+		res, err := interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+		s.True(res.(bool))
+		s.NoError(err)
+	}
+	res, err := interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.Nil(res)
+	s.Error(err)
+	s.assertInterceptorLatencyRecorded()
+}
+
+func (s *authorizerInterceptorSuite) TestDecisionObserverOnAllow() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+	observed := make(chan Result, 1)
+
+	res, err := s.newInterceptorWithDecisionObserver(func(_ context.Context, _ *Claims, target *CallTarget, result Result) {
+		s.Equal(describeNamespaceTarget, target)
+		observed <- result
+	})(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+	s.Equal(DecisionAllow, (<-observed).Decision)
+}
+
+func (s *authorizerInterceptorSuite) TestDecisionObserverOnDeny() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil).Times(1)
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	s.expectDenyReason(string(ReasonInsufficientRole))
+	observed := make(chan Result, 1)
+
+	res, err := s.newInterceptorWithDecisionObserver(func(_ context.Context, _ *Claims, _ *CallTarget, result Result) {
+		observed <- result
+	})(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.Nil(res)
+	s.Error(err)
+	s.Equal(Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, <-observed)
+}
+
+func (s *authorizerInterceptorSuite) TestDecisionObserverPanic() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+	done := make(chan struct{})
+
+	res, err := s.newInterceptorWithDecisionObserver(func(context.Context, *Claims, *CallTarget, Result) {
+		defer close(done)
+		panic("observer failure")
+	})(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+	<-done
+}
+
+func (s *authorizerInterceptorSuite) newInterceptorWithDecisionObserver(observer DecisionObserver) grpc.UnaryServerInterceptor {
+	return NewAuthorizationInterceptor(
+		s.mockClaimMapper,
+		s.mockAuthorizer,
+		s.mockMetricsClient,
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithDecisionObserver(observer))
+}
+
+func (s *authorizerInterceptorSuite) TestGlobalNamespace() {
+	target := *describeNamespaceTarget
+	target.IsGlobalNamespace = true
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, &target).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	res, err := s.newInterceptorWithGlobalNamespaces(true)(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) TestLocalNamespace() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	res, err := s.newInterceptorWithGlobalNamespaces(false)(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) TestGlobalNamespaceLookupFailure() {
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrAuthorizeFailedCounter)
+	interceptor := NewAuthorizationInterceptor(
+		s.mockClaimMapper,
+		s.mockAuthorizer,
+		s.mockMetricsClient,
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithGlobalNamespaceLookup(testGlobalNamespaceLookup{}))
+
+	res, err := interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.Nil(res)
+	s.Error(err)
+}
+
+func (s *authorizerInterceptorSuite) newInterceptorWithGlobalNamespaces(isGlobal bool) grpc.UnaryServerInterceptor {
+	return NewAuthorizationInterceptor(
+		s.mockClaimMapper,
+		s.mockAuthorizer,
+		s.mockMetricsClient,
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithGlobalNamespaceLookup(testGlobalNamespaceLookup{testNamespace: isGlobal}))
+}
+
+func (s *authorizerInterceptorSuite) TestActiveNamespace() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, startWorkflowExecutionTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	res, err := s.newInterceptorWithNamespaceActivity(s.mockAuthorizer, true)(ctx, startWorkflowExecutionRequest, startWorkflowExecutionInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) TestPassiveNamespace() {
+	target := *startWorkflowExecutionTarget
+	target.IsNamespacePassive = true
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, &target).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	res, err := s.newInterceptorWithNamespaceActivity(s.mockAuthorizer, false)(ctx, startWorkflowExecutionRequest, startWorkflowExecutionInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) TestPassiveNamespaceMutatingAPIDenied() {
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	s.expectDenyReason(string(ReasonNamespaceNotActive))
+	interceptor := s.newInterceptorWithNamespaceActivity(NewActiveClusterAuthorizer(s.mockAuthorizer), false)
+
+	res, err := interceptor(ctx, startWorkflowExecutionRequest, startWorkflowExecutionInfo, s.handler)
+	s.Nil(res)
+	s.Equal(serviceerror.NewPermissionDenied("Namespace is not active in this cluster, mutating requests must be sent to its active cluster."), err)
+}
+
+func (s *authorizerInterceptorSuite) TestPassiveNamespaceReadAPIAllowed() {
+	target := *describeNamespaceTarget
+	target.IsNamespacePassive = true
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, &target).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+	interceptor := s.newInterceptorWithNamespaceActivity(NewActiveClusterAuthorizer(s.mockAuthorizer), false)
+
+	res, err := interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) newInterceptorWithNamespaceActivity(authorizer Authorizer, isActive bool) grpc.UnaryServerInterceptor {
+	return NewAuthorizationInterceptor(
+		s.mockClaimMapper,
+		authorizer,
+		s.mockMetricsClient,
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithNamespaceActivityLookup(testNamespaceActivityLookup{testNamespace: isActive}))
+}
+
+type testNamespaceActivityLookup map[string]bool
+
+func (l testNamespaceActivityLookup) IsNamespaceActive(namespace string) (bool, error) {
+	isActive, ok := l[namespace]
+	if !ok {
+		return false, fmt.Errorf("unknown namespace: %s", namespace)
+	}
+	return isActive, nil
+}
+
+type testGlobalNamespaceLookup map[string]bool
+
+func (l testGlobalNamespaceLookup) IsGlobalNamespace(namespace string) (bool, error) {
+	isGlobal, ok := l[namespace]
+	if !ok {
+		return false, fmt.Errorf("unknown namespace: %s", namespace)
+	}
+	return isGlobal, nil
+}
+
+func (s *authorizerInterceptorSuite) TestTLSState() {
+	tlsInfo := credentials.TLSInfo{State: tls.ConnectionState{Version: tls.VersionTLS13}}
+	tlsCtx := peer.NewContext(ctx, &peer.Peer{AuthInfo: tlsInfo})
+	target := *describeNamespaceTarget
+	target.TLSState = &tlsInfo.State
+	s.mockAuthorizer.EXPECT().Authorize(tlsCtx, nil, &target).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	res, err := s.interceptor(tlsCtx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) TestSourceWorkflow() {
+	sourceCtx := metadata.NewIncomingContext(ctx, metadata.Pairs(
+		SourceNamespaceHeaderName, "source-namespace",
+		SourceWorkflowIDHeaderName, "source-workflow",
+	))
+	target := *describeNamespaceTarget
+	target.SourceNamespace = "source-namespace"
+	target.SourceWorkflowID = "source-workflow"
+	s.mockAuthorizer.EXPECT().Authorize(sourceCtx, nil, &target).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	res, err := s.interceptor(sourceCtx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) TestDenyMessageByAPI() {
+	interceptor := s.newInterceptorWithDenyMessages()
+	authCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "token"))
+	claims := &Claims{Subject: "user"}
+	s.mockClaimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(claims, nil).Times(1)
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), claims, startWorkflowExecutionTarget).
+		Return(Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil).Times(1)
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	s.expectDenyReason(string(ReasonInsufficientRole))
+
+	res, err := interceptor(authCtx, startWorkflowExecutionRequest, startWorkflowExecutionInfo, s.handler)
+	s.Nil(res)
+	s.IsType(&serviceerror.PermissionDenied{}, err)
+	s.Equal("user, contact your admin to request workflow-start access to test-namespace.", err.Error())
+}
+
+func (s *authorizerInterceptorSuite) TestDenyMessageByReason() {
+	interceptor := s.newInterceptorWithDenyMessages()
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionDeny, Reason: ReasonMFARequired}, nil).Times(1)
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	s.expectDenyReason(string(ReasonMFARequired))
+
+	res, err := interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.Nil(res)
+	s.Equal("Sign in with a second factor to access test-namespace.", err.Error())
+}
+
+func (s *authorizerInterceptorSuite) TestDenyMessageDefault() {
+	interceptor := s.newInterceptorWithDenyMessages()
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil).Times(1)
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	s.expectDenyReason(string(ReasonInsufficientRole))
+
+	res, err := interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.Nil(res)
+	s.Equal(errUnauthorized, err)
+}
+
+func (s *authorizerInterceptorSuite) TestBudgetExhausted() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionDeny, Reason: ReasonBudgetExhausted}, nil).Times(1)
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	s.expectDenyReason(string(ReasonBudgetExhausted))
+
+	res, err := s.interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.Nil(res)
+	s.Equal(errBudgetExhausted, err)
+}
+
+func (s *authorizerInterceptorSuite) TestDenyCooldown() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(NewDenyResult(NewDenyReason(ReasonDenyCooldown, "Too many denied requests, retry after 30s.")), nil).Times(1)
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	s.expectDenyReason(string(ReasonDenyCooldown))
+
+	res, err := s.interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.Nil(res)
+	s.Equal(serviceerror.NewResourceExhausted("Too many denied requests, retry after 30s."), err)
+}
+
+func (s *authorizerInterceptorSuite) TestRateClassExhausted() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(NewDenyResult(NewDenyReason(ReasonRateClassExhausted, "Rate limit of read calls to namespace test-namespace exceeded.")), nil).Times(1)
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	s.expectDenyReason(string(ReasonRateClassExhausted))
+
+	res, err := s.interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.Nil(res)
+	s.Equal(serviceerror.NewResourceExhausted("Rate limit of read calls to namespace test-namespace exceeded."), err)
+}
+
+func (s *authorizerInterceptorSuite) TestConcurrencyLimit() {
+	limiter := NewSubjectConcurrencyLimiter(1).(*subjectConcurrencyLimiter)
+	interceptor := s.newInterceptorWithConcurrencyLimiter(limiter)
+	release, ok := limiter.Acquire(testSubject)
+	s.True(ok)
+	defer release()
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationConcurrencyLimitCounter)
+
+	res, err := interceptor(s.authorizedContext(), describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.Nil(res)
+	s.Equal(errConcurrencyLimit, err)
+}
+
+func (s *authorizerInterceptorSuite) TestConcurrencyLimitReleasedOnCompletion() {
+	limiter := NewSubjectConcurrencyLimiter(1).(*subjectConcurrencyLimiter)
+	interceptor := s.newInterceptorWithConcurrencyLimiter(limiter)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		s.Equal(1, limiter.inFlight[testSubject])
+		return true, nil
+	}
+
+	res, err := interceptor(s.authorizedContext(), describeNamespaceRequest, describeNamespaceInfo, handler)
+	s.True(res.(bool))
+	s.NoError(err)
+	s.Empty(limiter.inFlight)
+}
+
+func (s *authorizerInterceptorSuite) TestConcurrencyLimitReleasedOnPanic() {
+	limiter := NewSubjectConcurrencyLimiter(1).(*subjectConcurrencyLimiter)
+	interceptor := s.newInterceptorWithConcurrencyLimiter(limiter)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("handler failed")
+	}
+
+	s.Panics(func() {
+		_, _ = interceptor(s.authorizedContext(), describeNamespaceRequest, describeNamespaceInfo, handler)
+	})
+	s.Empty(limiter.inFlight)
+}
+
+func (s *authorizerInterceptorSuite) newInterceptorWithConcurrencyLimiter(limiter ConcurrencyLimiter) grpc.UnaryServerInterceptor {
+	return NewAuthorizationInterceptor(
+		s.mockClaimMapper,
+		s.mockAuthorizer,
+		s.mockMetricsClient,
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithConcurrencyLimiter(limiter))
+}
+
+// authorizedContext returns a context of a call that the mocks authorize for testSubject
+func (s *authorizerInterceptorSuite) authorizedContext() context.Context {
+	claims := &Claims{Subject: testSubject}
+	s.mockClaimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(claims, nil).Times(1)
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), claims, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+	return metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "token"))
+}
+
+func (s *authorizerInterceptorSuite) TestDryRunAllow() {
+	claims := &Claims{Subject: "diagnostics", System: RoleAdmin}
+	s.mockClaimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(claims, nil).Times(1)
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), claims, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+	dryRunCtx, stream := s.dryRunContext()
+
+	res, err := s.newInterceptorWithDryRun()(dryRunCtx, describeNamespaceRequest, describeNamespaceInfo, s.unexpectedHandler)
+	s.Nil(res)
+	s.Equal(errDryRun, err)
+	s.Equal(metadata.Pairs(DryRunDecisionHeaderName, "allow"), stream.header)
+}
+
+func (s *authorizerInterceptorSuite) TestDryRunDeny() {
+	claims := &Claims{Subject: "diagnostics", System: RoleAdmin}
+	s.mockClaimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(claims, nil).Times(1)
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), claims, describeNamespaceTarget).
+		Return(Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil).Times(1)
+	dryRunCtx, stream := s.dryRunContext()
+
+	res, err := s.newInterceptorWithDryRun()(dryRunCtx, describeNamespaceRequest, describeNamespaceInfo, s.unexpectedHandler)
+	s.Nil(res)
+	s.Equal(errDryRun, err)
+	s.Equal(metadata.Pairs(
+		DryRunDecisionHeaderName, "deny",
+		DryRunReasonHeaderName, string(ReasonInsufficientRole),
+	), stream.header)
+}
+
+func (s *authorizerInterceptorSuite) TestDryRunWithoutDiagnosticRole() {
+	claims := &Claims{Subject: "user", System: RoleWriter, Namespaces: map[string]Role{testNamespace: RoleAdmin}}
+	s.mockClaimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(claims, nil).Times(1)
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	dryRunCtx, stream := s.dryRunContext()
+
+	res, err := s.newInterceptorWithDryRun()(dryRunCtx, describeNamespaceRequest, describeNamespaceInfo, s.unexpectedHandler)
+	s.Nil(res)
+	s.Equal(errUnauthorized, err)
+	s.Nil(stream.header)
+}
+
+func (s *authorizerInterceptorSuite) TestDryRunDisabled() {
+	claims := &Claims{Subject: "diagnostics", System: RoleAdmin}
+	s.mockClaimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(claims, nil).Times(1)
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), claims, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+	dryRunCtx, stream := s.dryRunContext()
+
+	res, err := s.interceptor(dryRunCtx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+	s.Nil(stream.header)
+}
+
+func (s *authorizerInterceptorSuite) newInterceptorWithDryRun() grpc.UnaryServerInterceptor {
+	return NewAuthorizationInterceptor(
+		s.mockClaimMapper,
+		s.mockAuthorizer,
+		s.mockMetricsClient,
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithDryRun(RoleAdmin))
+}
+
+func (s *authorizerInterceptorSuite) dryRunContext() (context.Context, *testServerTransportStream) {
+	stream := &testServerTransportStream{}
+	dryRunCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "token", DryRunHeaderName, "true"))
+	return grpc.NewContextWithServerTransportStream(dryRunCtx, stream), stream
+}
+
+func (s *authorizerInterceptorSuite) unexpectedHandler(context.Context, interface{}) (interface{}, error) {
+	s.Fail("handler must not be called")
+	return nil, nil
+}
+
+// testServerTransportStream captures the response headers and trailers set by the interceptor
+type testServerTransportStream struct {
+	header  metadata.MD
+	trailer metadata.MD
+}
+
+func (t *testServerTransportStream) Method() string {
+	return describeNamespaceInfo.FullMethod
+}
+
+func (t *testServerTransportStream) SetHeader(md metadata.MD) error {
+	t.header = metadata.Join(t.header, md)
+	return nil
+}
+
+func (t *testServerTransportStream) SendHeader(md metadata.MD) error {
+	return t.SetHeader(md)
+}
+
+func (t *testServerTransportStream) SetTrailer(md metadata.MD) error {
+	t.trailer = metadata.Join(t.trailer, md)
+	return nil
+}
+
+func (s *authorizerInterceptorSuite) newInterceptorWithDenyMessages() grpc.UnaryServerInterceptor {
+	return NewAuthorizationInterceptor(
+		s.mockClaimMapper,
+		s.mockAuthorizer,
+		s.mockMetricsClient,
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithDenyMessages(DenyMessages{
+			ByAPI: map[string]string{
+				startWorkflowExecutionTarget.APIName: "{subject}, contact your admin to request workflow-start access to {namespace}.",
+			},
+			ByReason: map[ReasonCode]string{
+				ReasonMFARequired: "Sign in with a second factor to access {namespace}.",
+			},
+		}))
+}
+
+func (s *authorizerInterceptorSuite) TestNamespaceLabels() {
+	labels := map[string]string{ClassificationLabel: "restricted"}
+	target := *describeNamespaceTarget
+	target.NamespaceLabels = labels
+	target.NamespaceLabelsResolved = true
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, &target).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	res, err := s.newInterceptorWithNamespaceLabels(testNamespaceLabelsLookup{testNamespace: labels})(
+		ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) TestNamespaceRegion() {
+	labels := map[string]string{RegionLabel: "eu"}
+	target := *describeNamespaceTarget
+	target.NamespaceLabels = labels
+	target.NamespaceLabelsResolved = true
+	target.NamespaceRegion = "eu"
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, &target).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	res, err := s.newInterceptorWithNamespaceLabels(testNamespaceLabelsLookup{testNamespace: labels})(
+		ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) TestNamespaceTier() {
+	labels := map[string]string{TierLabel: TierPro}
+	target := *describeNamespaceTarget
+	target.NamespaceLabels = labels
+	target.NamespaceLabelsResolved = true
+	target.NamespaceTier = TierPro
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, &target).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	res, err := s.newInterceptorWithNamespaceLabels(testNamespaceLabelsLookup{testNamespace: labels})(
+		ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) TestSubTargetNamespaceLabels() {
+	labels := map[string]string{RegionLabel: "eu"}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *Claims, target *CallTarget) (Result, error) {
+			for _, subTarget := range append([]*CallTarget{target}, target.SubTargets...) {
+				s.True(subTarget.NamespaceLabelsResolved)
+				s.Equal("eu", subTarget.NamespaceRegion)
+			}
+			return Result{Decision: DecisionAllow}, nil
+		})
+
+	res, err := s.newInterceptorWithNamespaceLabels(testNamespaceLabelsLookup{testNamespace: labels})(
+		ctx, &workflowservice.SignalWithStartWorkflowExecutionRequest{Namespace: testNamespace, WorkflowId: "wid"},
+		&grpc.UnaryServerInfo{FullMethod: workflowServicePrefix + "SignalWithStartWorkflowExecution"}, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) TestNamespaceLabelsLookupFailure() {
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrAuthorizeFailedCounter)
+
+	res, err := s.newInterceptorWithNamespaceLabels(testNamespaceLabelsLookup{})(
+		ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.Nil(res)
+	s.Error(err)
+}
+
+func (s *authorizerInterceptorSuite) newInterceptorWithNamespaceLabels(lookup NamespaceLabelsLookup) grpc.UnaryServerInterceptor {
+	return NewAuthorizationInterceptor(
+		s.mockClaimMapper,
+		s.mockAuthorizer,
+		s.mockMetricsClient,
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithNamespaceLabelsLookup(lookup))
+}
+
+type testNamespaceLabelsLookup map[string]map[string]string
+
+func (l testNamespaceLabelsLookup) GetNamespaceLabels(namespace string) (map[string]string, error) {
+	labels, ok := l[namespace]
+	if !ok {
+		return nil, fmt.Errorf("unknown namespace: %s", namespace)
+	}
+	return labels, nil
+}
+
+func (s *authorizerInterceptorSuite) TestEffectiveRoles() {
+	authCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "token"))
+	claims := &Claims{Subject: "user", System: RoleWriter, Namespaces: map[string]Role{testNamespace: RoleAdmin}}
+	s.mockClaimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(claims, nil).Times(1)
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), claims, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+	interceptor := NewAuthorizationInterceptor(
+		s.mockClaimMapper,
+		s.mockAuthorizer,
+		s.mockMetricsClient,
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithEffectiveRoles())
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		// the system writer role takes precedence over the namespace admin role
+		s.Equal(&EffectiveRoles{Role: RoleWriter, Scope: RoleScopeSystem}, EffectiveRolesFromContext(ctx))
+		return true, nil
+	}
+	res, err := interceptor(authCtx, describeNamespaceRequest, describeNamespaceInfo, handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) TestEffectiveRolesDisabled() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		s.Nil(EffectiveRolesFromContext(ctx))
+		return true, nil
+	}
+	res, err := s.interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) TestAuthContextOnAllow() {
+	authCtx := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "token"))
+	claims := &Claims{Subject: "user", Namespaces: map[string]Role{testNamespace: RoleAdmin}}
+	s.mockClaimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(claims, nil).Times(1)
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), claims, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		authContext := AuthContextFromContext(ctx)
+		s.Equal(&AuthContext{Subject: "user", Roles: RoleAdmin, Decision: DecisionAllow}, authContext)
+		s.True(authContext.IsElevated())
+		return true, nil
+	}
+	res, err := s.interceptor(authCtx, describeNamespaceRequest, describeNamespaceInfo, handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) TestAuthContextOnDeny() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionDeny}, nil).Times(1)
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	s.expectDenyReason(reasonTagValueUnspecified)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		s.Fail("handler must not be called for a denied call", "auth context: %v", AuthContextFromContext(ctx))
+		return true, nil
+	}
+	res, err := s.interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, handler)
+	s.Nil(res)
+	s.Error(err)
+	s.Nil(AuthContextFromContext(ctx))
+}
+
+func (s *authorizerInterceptorSuite) TestClientVersion() {
+	clientCtx := metadata.NewIncomingContext(ctx, metadata.Pairs(
+		headers.ClientNameHeaderName, headers.ClientNameGoSDK,
+		headers.ClientVersionHeaderName, "1.5.0",
+	))
+	target := *describeNamespaceTarget
+	target.ClientName = headers.ClientNameGoSDK
+	target.ClientVersion = "1.5.0"
+	s.mockAuthorizer.EXPECT().Authorize(clientCtx, nil, &target).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	res, err := s.interceptor(clientCtx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) TestWarnOnlyAPIAllowsDenied() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil).Times(1)
+	reasonScope := metrics.NewMockScope(s.controller)
+	s.mockMetricsScope.EXPECT().Tagged(metrics.ReasonTag(string(ReasonInsufficientRole))).Return(reasonScope)
+	reasonScope.EXPECT().IncCounter(metrics.ServiceAuthorizationWarnOnlyCounter)
+
+	res, err := s.newInterceptorWithWarnOnlyAPIs()(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) TestWarnOnlyAPIAllowed() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+
+	res, err := s.newInterceptorWithWarnOnlyAPIs()(ctx, describeNamespaceRequest, describeNamespaceInfo, s.handler)
+	s.True(res.(bool))
+	s.NoError(err)
+}
+
+func (s *authorizerInterceptorSuite) TestNotWarnOnlyAPIEnforced() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, startWorkflowExecutionTarget).
+		Return(Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil).Times(1)
+	s.mockMetricsScope.EXPECT().IncCounter(metrics.ServiceErrUnauthorizedCounter)
+	s.expectDenyReason(string(ReasonInsufficientRole))
+
+	res, err := s.newInterceptorWithWarnOnlyAPIs()(ctx, startWorkflowExecutionRequest, startWorkflowExecutionInfo, s.handler)
+	s.Nil(res)
+	s.Equal(errUnauthorized, err)
+}
+
+func (s *authorizerInterceptorSuite) newInterceptorWithWarnOnlyAPIs() grpc.UnaryServerInterceptor {
+	return NewAuthorizationInterceptor(
+		s.mockClaimMapper,
+		s.mockAuthorizer,
+		s.mockMetricsClient,
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithWarnOnlyAPIs(describeNamespaceTarget.APIName))
+}
+
+func TestClaimMappingFailureUnauthenticated(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	claimMapper := NewMockClaimMapper(controller)
+	claimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("invalid token")).Times(1)
+	interceptor := NewAuthorizationInterceptor(
+		claimMapper,
+		NewMockAuthorizer(controller),
+		metrics.NewClient(tally.NoopScope, metrics.Frontend),
+		loggerimpl.NewLogger(zap.NewNop()))
+
+	ctxWithHeaders := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer token"))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return true, nil }
+	res, err := interceptor(ctxWithHeaders, describeNamespaceRequest, describeNamespaceInfo, handler)
+	require.Nil(t, res)
+	require.Equal(t, codes.Unauthenticated, serviceerror.ToStatus(err).Code())
+}
+
+func TestImpersonationOfSystemRolesRejected(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	caller := &Claims{Subject: "support", System: RoleImpersonator}
+	impersonator := &Claims{Subject: "impersonator", System: RoleImpersonator}
+	claimMapper := NewMockClaimMapper(controller)
+	claimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(caller, nil).Times(2)
+	authorizer := NewMockAuthorizer(controller)
+	authorizer.EXPECT().Authorize(gomock.Any(), impersonator, describeNamespaceTarget).
+		Return(Result{Decision: DecisionAllow}, nil).Times(1)
+	interceptor := NewAuthorizationInterceptor(
+		claimMapper,
+		authorizer,
+		metrics.NewClient(tally.NoopScope, metrics.Frontend),
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithImpersonation(testClaimsLookup(map[string]*Claims{
+			"admin":        {Subject: "admin", System: RoleAdmin},
+			"impersonator": impersonator,
+		})))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return true, nil }
+
+	ctxWithHeaders := metadata.NewIncomingContext(ctx, metadata.Pairs(
+		"authorization", "Bearer token",
+		impersonateHeaderName, "admin",
+	))
+	res, err := interceptor(ctxWithHeaders, describeNamespaceRequest, describeNamespaceInfo, handler)
+	require.Nil(t, res)
+	require.Equal(t, errUnauthorized, err)
+
+	// system roles the caller holds itself can be impersonated
+	ctxWithHeaders = metadata.NewIncomingContext(ctx, metadata.Pairs(
+		"authorization", "Bearer token",
+		impersonateHeaderName, "impersonator",
+	))
+	res, err = interceptor(ctxWithHeaders, describeNamespaceRequest, describeNamespaceInfo, handler)
+	require.NoError(t, err)
+	require.Equal(t, true, res)
+}
+
+func TestClaimMappingTimeout(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	claimMapper := NewMockClaimMapper(controller)
+	claimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ *AuthInfo) (*Claims, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+	scope := tally.NewTestScope("", nil)
+	interceptor := NewAuthorizationInterceptor(
+		claimMapper,
+		NewMockAuthorizer(controller), // not called
+		metrics.NewClient(scope, metrics.Frontend),
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithTimeouts(10*time.Millisecond, time.Minute))
+
+	ctxWithHeaders := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer token"))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return true, nil }
+	res, err := interceptor(ctxWithHeaders, describeNamespaceRequest, describeNamespaceInfo, handler)
+	require.Nil(t, res)
+	require.Equal(t, codes.Unauthenticated, serviceerror.ToStatus(err).Code())
+	require.Equal(t, int64(1), counterValue(scope, "service_errors_claim_mapping_timeout"))
+	require.Equal(t, int64(0), counterValue(scope, "service_errors_authorize_timeout"))
+}
+
+func TestAuthorizationTimeout(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	claimMapper := NewMockClaimMapper(controller)
+	claimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(&Claims{Subject: testSubject}, nil)
+	authorizer := NewMockAuthorizer(controller)
+	scope := tally.NewTestScope("", nil)
+	interceptor := NewAuthorizationInterceptor(
+		claimMapper,
+		authorizer,
+		metrics.NewClient(scope, metrics.Frontend),
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithTimeouts(time.Minute, 10*time.Millisecond))
+	ctxWithHeaders := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer token"))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return true, nil }
+
+	authorizer.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ *Claims, _ *CallTarget) (Result, error) {
+			<-ctx.Done()
+			return Result{}, ctx.Err()
+		})
+	res, err := interceptor(ctxWithHeaders, describeNamespaceRequest, describeNamespaceInfo, handler)
+	require.Nil(t, res)
+	require.Equal(t, errUnauthorized, err)
+	require.Equal(t, int64(1), counterValue(scope, "service_errors_authorize_timeout"))
+	require.Equal(t, int64(0), counterValue(scope, "service_errors_claim_mapping_timeout"))
+
+	// an authorizer ignoring the expiry doesn't get its late decision through
+	claimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(&Claims{Subject: testSubject}, nil)
+	authorizer.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ *Claims, _ *CallTarget) (Result, error) {
+			time.Sleep(20 * time.Millisecond)
+			return Result{Decision: DecisionAllow}, nil
+		})
+	res, err = interceptor(ctxWithHeaders, describeNamespaceRequest, describeNamespaceInfo, handler)
+	require.Nil(t, res)
+	require.Equal(t, errUnauthorized, err)
+	require.Equal(t, int64(2), counterValue(scope, "service_errors_authorize_timeout"))
+}
+
+func TestTimeoutsAreIndependent(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	// together the steps take longer than either timeout, each of them within its own
+	claimMapper := NewMockClaimMapper(controller)
+	claimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ *AuthInfo) (*Claims, error) {
+			time.Sleep(60 * time.Millisecond)
+			return &Claims{Subject: testSubject}, ctx.Err()
+		})
+	authorizer := NewMockAuthorizer(controller)
+	authorizer.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ *Claims, _ *CallTarget) (Result, error) {
+			time.Sleep(60 * time.Millisecond)
+			return Result{Decision: DecisionAllow}, ctx.Err()
+		})
+	scope := tally.NewTestScope("", nil)
+	interceptor := NewAuthorizationInterceptor(
+		claimMapper,
+		authorizer,
+		metrics.NewClient(scope, metrics.Frontend),
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithTimeouts(100*time.Millisecond, 100*time.Millisecond))
+
+	ctxWithHeaders := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer token"))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return true, nil }
+	res, err := interceptor(ctxWithHeaders, describeNamespaceRequest, describeNamespaceInfo, handler)
+	require.NoError(t, err)
+	require.Equal(t, true, res)
+	require.Equal(t, int64(0), counterValue(scope, "service_errors_claim_mapping_timeout"))
+	require.Equal(t, int64(0), counterValue(scope, "service_errors_authorize_timeout"))
+}
+
+// counterValue sums the values of the counters named name in scope, regardless of their tags
+func counterValue(scope tally.TestScope, name string) int64 {
+	var value int64
+	for _, counter := range scope.Snapshot().Counters() {
+		if counter.Name() == name {
+			value += counter.Value()
+		}
+	}
+	return value
+}
+
+type testSearchAttributePolicyLookup map[string]*SearchAttributePolicy
+
+func (l testSearchAttributePolicyLookup) GetSearchAttributePolicy(namespace string) (*SearchAttributePolicy, error) {
+	return l[namespace], nil
+}
+
+func TestSearchAttributePolicyResolution(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	claimMapper := NewMockClaimMapper(controller)
+	claimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).
+		Return(&Claims{Subject: testSubject, Namespaces: map[string]Role{testNamespace: RoleWriter}}, nil).Times(2)
+	policy := &SearchAttributePolicy{Restricted: map[string]Role{"CustomerId": RoleAdmin}}
+	interceptor := NewAuthorizationInterceptor(
+		claimMapper,
+		NewSearchAttributeAuthorizer(NewDefaultAuthorizer()),
+		metrics.NewClient(tally.NoopScope, metrics.Frontend),
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithSearchAttributePolicyLookup(testSearchAttributePolicyLookup{testNamespace: policy}))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return true, nil }
+	info := &grpc.UnaryServerInfo{FullMethod: workflowServicePrefix + "ListWorkflowExecutions"}
+	ctxWithHeaders := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer token"))
+
+	res, err := interceptor(ctxWithHeaders, &workflowservice.ListWorkflowExecutionsRequest{
+		Namespace: testNamespace, Query: "OrderId = 'o-1'"}, info, handler)
+	require.NoError(t, err)
+	require.Equal(t, true, res)
+
+	res, err = interceptor(ctxWithHeaders, &workflowservice.ListWorkflowExecutionsRequest{
+		Namespace: testNamespace, Query: "CustomerId = 'acme'"}, info, handler)
+	require.Nil(t, res)
+	require.Equal(t, errUnauthorized, err)
+}
+
+type (
+	testDecisionMetricsObserver struct {
+		observations []testDecisionObservation
+	}
+
+	testDecisionObservation struct {
+		namespace string
+		api       string
+		decision  Decision
+		latency   time.Duration
+	}
+)
+
+func (o *testDecisionMetricsObserver) ObserveDecision(namespace string, api string, decision Decision, latency time.Duration) {
+	o.observations = append(o.observations, testDecisionObservation{namespace: namespace, api: api, decision: decision, latency: latency})
+}
+
+func TestDecisionMetricsObserver(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	authorizer := NewMockAuthorizer(controller)
+	authorizer.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *Claims, target *CallTarget) (Result, error) {
+			time.Sleep(time.Millisecond)
+			if target.APIName == describeNamespaceTarget.APIName {
+				return Result{Decision: DecisionAllow}, nil
+			}
+			return Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil
+		}).Times(2)
+	observer := &testDecisionMetricsObserver{}
+	interceptor := NewAuthorizationInterceptor(
+		nil,
+		authorizer,
+		metrics.NewClient(tally.NoopScope, metrics.Frontend),
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithDecisionMetricsObserver(observer))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return true, nil }
+
+	res, err := interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, handler)
+	require.NoError(t, err)
+	require.Equal(t, true, res)
+	_, err = interceptor(ctx, startWorkflowExecutionRequest, startWorkflowExecutionInfo, handler)
+	require.Equal(t, errUnauthorized, err)
+
+	require.Len(t, observer.observations, 2)
+	allowed, denied := observer.observations[0], observer.observations[1]
+	require.Equal(t, testNamespace, allowed.namespace)
+	require.Equal(t, describeNamespaceTarget.APIName, allowed.api)
+	require.Equal(t, DecisionAllow, allowed.decision)
+	require.True(t, allowed.latency >= time.Millisecond)
+	require.Equal(t, testNamespace, denied.namespace)
+	require.Equal(t, startWorkflowExecutionTarget.APIName, denied.api)
+	require.Equal(t, DecisionDeny, denied.decision)
+	require.True(t, denied.latency >= time.Millisecond)
+}
+
+func TestDecisionMetricsObserverNotCalledOnError(t *testing.T) {
+	observer := &testDecisionMetricsObserver{}
+	interceptor := NewAuthorizationInterceptor(
+		nil,
+		&failingAuthorizer{},
+		metrics.NewClient(tally.NoopScope, metrics.Frontend),
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithDecisionMetricsObserver(observer))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return true, nil }
+
+	_, err := interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, handler)
+	require.Equal(t, errUnauthorized, err)
+	require.Empty(t, observer.observations)
+}
+
+func TestNilDecisionMetricsObserver(t *testing.T) {
+	interceptor := NewAuthorizationInterceptor(
+		nil,
+		NewNoopAuthorizer(),
+		metrics.NewClient(tally.NoopScope, metrics.Frontend),
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithDecisionMetricsObserver(nil))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return true, nil }
+
+	res, err := interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, handler)
+	require.NoError(t, err)
+	require.Equal(t, true, res)
+}
+
+func TestRequestValidationBeforeAuthorization(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	claimMapper := NewMockClaimMapper(controller)
+	authorizer := NewMockAuthorizer(controller)
+	interceptor := NewAuthorizationInterceptor(
+		claimMapper,
+		authorizer,
+		metrics.NewClient(tally.NoopScope, metrics.Frontend),
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithRequestValidation(ValidateRequest, ValidateBeforeAuthorization))
+	ctxWithHeaders := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer token"))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return true, nil }
+	malformed := &workflowservice.DescribeWorkflowExecutionRequest{
+		Namespace: testNamespace, Execution: &commonpb.WorkflowExecution{WorkflowId: "wid", RunId: "not-a-uuid"}}
+
+	// the malformed request is rejected without mapping claims or authorizing it
+	res, err := interceptor(ctxWithHeaders, malformed, &grpc.UnaryServerInfo{FullMethod: workflowServicePrefix + "DescribeWorkflowExecution"}, handler)
+	require.Nil(t, res)
+	require.Equal(t, errInvalidRunID, err)
+
+	claimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(&Claims{Subject: testSubject}, nil)
+	authorizer.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any()).Return(Result{Decision: DecisionAllow}, nil)
+	res, err = interceptor(ctxWithHeaders, describeNamespaceRequest, describeNamespaceInfo, handler)
+	require.True(t, res.(bool))
+	require.NoError(t, err)
+}
+
+func TestRequestValidationAfterAuthorization(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	claimMapper := NewMockClaimMapper(controller)
+	authorizer := NewMockAuthorizer(controller)
+	interceptor := NewAuthorizationInterceptor(
+		claimMapper,
+		authorizer,
+		metrics.NewClient(tally.NoopScope, metrics.Frontend),
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithRequestValidation(ValidateRequest, ValidateAfterAuthorization))
+	ctxWithHeaders := metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer token"))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return true, nil }
+	malformed := &workflowservice.DescribeWorkflowExecutionRequest{
+		Namespace: testNamespace, Execution: &commonpb.WorkflowExecution{WorkflowId: "wid", RunId: "not-a-uuid"}}
+	info := &grpc.UnaryServerInfo{FullMethod: workflowServicePrefix + "DescribeWorkflowExecution"}
+
+	// callers that are not authorized are denied before their request is validated
+	claimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(&Claims{Subject: testSubject}, nil)
+	authorizer.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any()).Return(Result{Decision: DecisionDeny}, nil)
+	res, err := interceptor(ctxWithHeaders, malformed, info, handler)
+	require.Nil(t, res)
+	require.Equal(t, errUnauthorized, err)
+
+	claimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(&Claims{Subject: testSubject}, nil)
+	authorizer.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any()).Return(Result{Decision: DecisionAllow}, nil)
+	res, err = interceptor(ctxWithHeaders, malformed, info, handler)
+	require.Nil(t, res)
+	require.Equal(t, errInvalidRunID, err)
+}
+
+func TestTaskTokenDecoding(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	authorizer := NewMockAuthorizer(controller)
+	scope := tally.NewTestScope("", nil)
+	interceptor := NewAuthorizationInterceptor(
+		nil,
+		authorizer,
+		metrics.NewClient(scope, metrics.Frontend),
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithTaskTokenDecoder(NewTaskTokenDecoder(testNamespaceNameLookup{testNamespaceID: testNamespace})))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return true, nil }
+	info := &grpc.UnaryServerInfo{FullMethod: workflowServicePrefix + "RecordActivityTaskHeartbeat"}
+
+	authorizer.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *Claims, target *CallTarget) (Result, error) {
+			require.Equal(t, testNamespace, target.Namespace)
+			require.Equal(t, "wid", target.WorkflowID)
+			require.Equal(t, "rid", target.RunID)
+			require.Equal(t, "aid", target.ActivityID)
+			return Result{Decision: DecisionAllow}, nil
+		})
+	res, err := interceptor(ctx, &workflowservice.RecordActivityTaskHeartbeatRequest{TaskToken: newTestTaskToken(t, testNamespaceID)}, info, handler)
+	require.True(t, res.(bool))
+	require.NoError(t, err)
+
+	// malformed tokens fail closed, without consulting the authorizer
+	res, err = interceptor(ctx, &workflowservice.RecordActivityTaskHeartbeatRequest{TaskToken: []byte("malformed")}, info, handler)
+	require.Nil(t, res)
+	require.Equal(t, errUnauthorized, err)
+	require.Equal(t, int64(1), counterValue(scope, "service_errors_unauthorized"))
+}
+
+type (
+	testUnauthorizedExemplarCounter struct {
+		exemplars []testUnauthorizedExemplar
+	}
+
+	testUnauthorizedExemplar struct {
+		namespace string
+		traceID   string
+	}
+)
+
+func (c *testUnauthorizedExemplarCounter) IncUnauthorized(namespace string, traceID string) {
+	c.exemplars = append(c.exemplars, testUnauthorizedExemplar{namespace: namespace, traceID: traceID})
+}
+
+func TestUnauthorizedExemplarCounter(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	authorizer := NewMockAuthorizer(controller)
+	authorizer.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil).Times(2)
+	scope := tally.NewTestScope("", nil)
+	counter := &testUnauthorizedExemplarCounter{}
+	interceptor := NewAuthorizationInterceptor(
+		nil,
+		authorizer,
+		metrics.NewClient(scope, metrics.Frontend),
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithUnauthorizedExemplarCounter(counter))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return true, nil }
+
+	tracedCtx := metadata.NewIncomingContext(ctx, metadata.Pairs(
+		TraceParentHeaderName, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"))
+	_, err := interceptor(tracedCtx, describeNamespaceRequest, describeNamespaceInfo, handler)
+	require.Equal(t, errUnauthorized, err)
+	_, err = interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, handler)
+	require.Equal(t, errUnauthorized, err)
+
+	require.Equal(t, []testUnauthorizedExemplar{
+		{namespace: testNamespace, traceID: "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{namespace: testNamespace, traceID: ""},
+	}, counter.exemplars)
+	require.Equal(t, int64(2), counterValue(scope, "service_errors_unauthorized"))
+}
+
+func TestUnauthorizedExemplarCounterNotCalledOnAllow(t *testing.T) {
+	counter := &testUnauthorizedExemplarCounter{}
+	interceptor := NewAuthorizationInterceptor(
+		nil,
+		NewNoopAuthorizer(),
+		metrics.NewClient(tally.NoopScope, metrics.Frontend),
+		loggerimpl.NewLogger(zap.NewNop()),
+		WithUnauthorizedExemplarCounter(counter))
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return true, nil }
+
+	res, err := interceptor(ctx, describeNamespaceRequest, describeNamespaceInfo, handler)
+	require.NoError(t, err)
+	require.Equal(t, true, res)
+	require.Empty(t, counter.exemplars)
+}
@@ -0,0 +1,76 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"fmt"
+
+	"go.temporal.io/api/serviceerror"
+)
+
+type issuerValidatingClaimMapper struct {
+	claimMapper    ClaimMapper
+	allowedIssuers map[string]struct{}
+}
+
+var _ ClaimMapper = (*issuerValidatingClaimMapper)(nil)
+
+// NewIssuerValidatingClaimMapper creates a claim mapper that rejects claims resolved by claimMapper
+// unless they were issued by one of allowedIssuers. Claims without an issuer are rejected as well,
+// except for the empty claims of anonymous callers.
+func NewIssuerValidatingClaimMapper(claimMapper ClaimMapper, allowedIssuers []string) ClaimMapper {
+	issuers := make(map[string]struct{}, len(allowedIssuers))
+	for _, issuer := range allowedIssuers {
+		issuers[issuer] = struct{}{}
+	}
+	return &issuerValidatingClaimMapper{
+		claimMapper:    claimMapper,
+		allowedIssuers: issuers,
+	}
+}
+
+func (a *issuerValidatingClaimMapper) GetClaims(ctx context.Context, authInfo *AuthInfo) (*Claims, error) {
+	claims, err := a.claimMapper.GetClaims(ctx, authInfo)
+	if err != nil || claims == nil {
+		return claims, err
+	}
+	if claims.Issuer == "" {
+		if isEmptyClaims(claims) {
+			return claims, nil
+		}
+		return nil, serviceerror.NewPermissionDenied("token has no issuer")
+	}
+	if _, ok := a.allowedIssuers[claims.Issuer]; !ok {
+		return nil, serviceerror.NewPermissionDenied(fmt.Sprintf("token issuer is not allowed: %s", claims.Issuer))
+	}
+	return claims, nil
+}
+
+// isEmptyClaims checks if the claims of an anonymous caller carry nothing else either, such as groups that
+// claim mappers further down the chain grant roles for
+func isEmptyClaims(claims *Claims) bool {
+	return isAnonymous(claims) && len(claims.Groups) == 0 && len(claims.Attributes) == 0 && claims.OnBehalfOf == nil
+}
@@ -0,0 +1,132 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	issuerValidatingClaimMapperSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller      *gomock.Controller
+		mockClaimMapper *MockClaimMapper
+		claimMapper     ClaimMapper
+	}
+)
+
+func TestIssuerValidatingClaimMapperSuite(t *testing.T) {
+	s := new(issuerValidatingClaimMapperSuite)
+	suite.Run(t, s)
+}
+
+func (s *issuerValidatingClaimMapperSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockClaimMapper = NewMockClaimMapper(s.controller)
+	s.claimMapper = NewIssuerValidatingClaimMapper(s.mockClaimMapper, []string{"trusted", "other-trusted"})
+}
+
+func (s *issuerValidatingClaimMapperSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *issuerValidatingClaimMapperSuite) TestAllowedIssuer() {
+	authInfo := &AuthInfo{AuthToken: "token"}
+	expected := &Claims{Subject: testSubject, Issuer: "other-trusted"}
+	s.mockClaimMapper.EXPECT().GetClaims(ctx, authInfo).Return(expected, nil)
+
+	claims, err := s.claimMapper.GetClaims(ctx, authInfo)
+	s.NoError(err)
+	s.Equal(expected, claims)
+}
+
+func (s *issuerValidatingClaimMapperSuite) TestDisallowedIssuer() {
+	authInfo := &AuthInfo{AuthToken: "token"}
+	s.mockClaimMapper.EXPECT().GetClaims(ctx, authInfo).Return(&Claims{Subject: testSubject, Issuer: "untrusted"}, nil)
+
+	claims, err := s.claimMapper.GetClaims(ctx, authInfo)
+	s.Error(err)
+	s.Nil(claims)
+}
+
+func (s *issuerValidatingClaimMapperSuite) TestMissingIssuer() {
+	authInfo := &AuthInfo{AuthToken: "token"}
+	s.mockClaimMapper.EXPECT().GetClaims(ctx, authInfo).Return(&Claims{Subject: testSubject}, nil)
+
+	claims, err := s.claimMapper.GetClaims(ctx, authInfo)
+	s.Error(err)
+	s.Nil(claims)
+}
+
+func (s *issuerValidatingClaimMapperSuite) TestAnonymous() {
+	authInfo := &AuthInfo{}
+	s.mockClaimMapper.EXPECT().GetClaims(ctx, authInfo).Return(&Claims{}, nil)
+
+	claims, err := s.claimMapper.GetClaims(ctx, authInfo)
+	s.NoError(err)
+	s.Equal(&Claims{}, claims)
+}
+
+func (s *issuerValidatingClaimMapperSuite) TestMissingIssuerWithoutSubject() {
+	// e.g. a token with an empty "sub", no "iss" and the system:admin permission
+	authInfo := &AuthInfo{AuthToken: "token"}
+	s.mockClaimMapper.EXPECT().GetClaims(ctx, authInfo).Return(&Claims{System: RoleAdmin}, nil)
+
+	claims, err := s.claimMapper.GetClaims(ctx, authInfo)
+	s.Error(err)
+	s.Nil(claims)
+}
+
+func (s *issuerValidatingClaimMapperSuite) TestMissingIssuerWithoutRoles() {
+	for _, unsigned := range []*Claims{
+		{Groups: []string{"admins"}},
+		{Attributes: map[string][]string{"teams": {"payments"}}},
+		{OnBehalfOf: &Claims{Subject: testSubject, System: RoleAdmin}},
+	} {
+		authInfo := &AuthInfo{AuthToken: "token"}
+		s.mockClaimMapper.EXPECT().GetClaims(ctx, authInfo).Return(unsigned, nil)
+
+		claims, err := s.claimMapper.GetClaims(ctx, authInfo)
+		s.Error(err)
+		s.Nil(claims)
+	}
+}
+
+func (s *issuerValidatingClaimMapperSuite) TestUnknownIssuerWithoutSubject() {
+	authInfo := &AuthInfo{AuthToken: "token"}
+	s.mockClaimMapper.EXPECT().GetClaims(ctx, authInfo).
+		Return(&Claims{Issuer: "untrusted", Namespaces: map[string]Role{testNamespace: RoleAdmin}}, nil)
+
+	claims, err := s.claimMapper.GetClaims(ctx, authInfo)
+	s.Error(err)
+	s.Nil(claims)
+}
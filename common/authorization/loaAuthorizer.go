@@ -0,0 +1,58 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+)
+
+type (
+	loaAuthorizer struct {
+		authorizer   Authorizer
+		minLoAPerAPI map[string]int
+	}
+)
+
+var _ Authorizer = (*loaAuthorizer)(nil)
+
+// NewLoAAuthorizer creates an authorizer that denies calls to sensitive APIs unless the caller was authenticated
+// with at least the level of assurance required for the API, see Claims.AuthLevel. minLoAPerAPI maps full API
+// names to their required level, APIs not in the map require none. For delegated calls the level the end user
+// was authenticated with applies. All other calls are decided by authorizer.
+func NewLoAAuthorizer(authorizer Authorizer, minLoAPerAPI map[string]int) Authorizer {
+	return &loaAuthorizer{
+		authorizer:   authorizer,
+		minLoAPerAPI: minLoAPerAPI,
+	}
+}
+
+func (a *loaAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if minLoA, ok := a.minLoAPerAPI[target.APIName]; ok && minLoA > 0 {
+		if claims == nil || claims.EffectiveClaims().AuthLevel < minLoA {
+			return Result{Decision: DecisionDeny, Reason: ReasonInsufficientAssurance}, nil
+		}
+	}
+	return a.authorizer.Authorize(ctx, claims, target)
+}
@@ -0,0 +1,114 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	loaAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		mockAuthorizer *MockAuthorizer
+		authorizer     Authorizer
+	}
+)
+
+func TestLoAAuthorizerSuite(t *testing.T) {
+	s := new(loaAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *loaAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.authorizer = NewLoAAuthorizer(s.mockAuthorizer, map[string]int{
+		startWorkflowExecutionTarget.APIName:         2,
+		workflowServicePrefix + "DeprecateNamespace": 3,
+	})
+}
+
+func (s *loaAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *loaAuthorizerSuite) TestLevels() {
+	deprecateNamespaceTarget := &CallTarget{APIName: workflowServicePrefix + "DeprecateNamespace", Namespace: testNamespace}
+	testCases := []struct {
+		authLevel int
+		target    *CallTarget
+		allowed   bool
+	}{
+		{authLevel: 0, target: describeNamespaceTarget, allowed: true},
+		{authLevel: 0, target: startWorkflowExecutionTarget, allowed: false},
+		{authLevel: 1, target: startWorkflowExecutionTarget, allowed: false},
+		{authLevel: 2, target: startWorkflowExecutionTarget, allowed: true},
+		{authLevel: 3, target: startWorkflowExecutionTarget, allowed: true},
+		{authLevel: 2, target: deprecateNamespaceTarget, allowed: false},
+		{authLevel: 3, target: deprecateNamespaceTarget, allowed: true},
+	}
+	for _, tc := range testCases {
+		claims := &Claims{Subject: testSubject, AuthLevel: tc.authLevel}
+		if tc.allowed {
+			s.mockAuthorizer.EXPECT().Authorize(ctx, claims, tc.target).Return(Result{Decision: DecisionAllow}, nil)
+		}
+		result, err := s.authorizer.Authorize(ctx, claims, tc.target)
+		s.NoError(err)
+		if tc.allowed {
+			s.Equal(DecisionAllow, result.Decision, "level %d for %s", tc.authLevel, tc.target.APIName)
+		} else {
+			s.Equal(Result{Decision: DecisionDeny, Reason: ReasonInsufficientAssurance}, result,
+				"level %d for %s", tc.authLevel, tc.target.APIName)
+		}
+	}
+}
+
+func (s *loaAuthorizerSuite) TestNoClaims() {
+	result, err := s.authorizer.Authorize(ctx, nil, startWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(Result{Decision: DecisionDeny, Reason: ReasonInsufficientAssurance}, result)
+}
+
+func (s *loaAuthorizerSuite) TestDelegatedCall() {
+	// the level of the end user applies, not the level of the actor
+	claims := &Claims{Subject: "gateway", AuthLevel: 3, OnBehalfOf: &Claims{Subject: testSubject, AuthLevel: 1}}
+	result, err := s.authorizer.Authorize(ctx, claims, startWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+
+	claims = &Claims{Subject: "gateway", OnBehalfOf: &Claims{Subject: testSubject, AuthLevel: 2}}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, startWorkflowExecutionTarget).Return(Result{Decision: DecisionAllow}, nil)
+	result, err = s.authorizer.Authorize(ctx, claims, startWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
@@ -0,0 +1,50 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"go.uber.org/atomic"
+)
+
+// MaintenanceMode is a switch that puts the cluster into read-only mode,
+// in which the authorization interceptor rejects all mutating APIs. It is safe for concurrent use.
+type MaintenanceMode struct {
+	readOnly *atomic.Bool
+}
+
+// NewMaintenanceMode creates a maintenance mode switch that is initially off
+func NewMaintenanceMode() *MaintenanceMode {
+	return &MaintenanceMode{readOnly: atomic.NewBool(false)}
+}
+
+// SetReadOnly turns read-only mode on or off
+func (m *MaintenanceMode) SetReadOnly(readOnly bool) {
+	m.readOnly.Store(readOnly)
+}
+
+// IsReadOnly checks if read-only mode is on
+func (m *MaintenanceMode) IsReadOnly() bool {
+	return m.readOnly.Load()
+}
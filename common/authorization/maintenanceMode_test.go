@@ -0,0 +1,59 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceModeToggle(t *testing.T) {
+	mode := NewMaintenanceMode()
+	require.False(t, mode.IsReadOnly())
+	mode.SetReadOnly(true)
+	require.True(t, mode.IsReadOnly())
+	mode.SetReadOnly(false)
+	require.False(t, mode.IsReadOnly())
+}
+
+func TestMaintenanceModeConcurrentToggle(t *testing.T) {
+	mode := NewMaintenanceMode()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(readOnly bool) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				mode.SetReadOnly(readOnly)
+				_ = mode.IsReadOnly()
+			}
+		}(i%2 == 0)
+	}
+	wg.Wait()
+	mode.SetReadOnly(true)
+	require.True(t, mode.IsReadOnly())
+}
@@ -0,0 +1,137 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+)
+
+type (
+	// MemoClaim returns the values of the claims a memo field may be set to, see NewMemoAuthorizer
+	MemoClaim func(claims *Claims) []string
+
+	memoAuthorizer struct {
+		authorizer Authorizer
+		bindings   map[string]MemoClaim
+	}
+)
+
+var _ Authorizer = (*memoAuthorizer)(nil)
+
+// memoAPIs are the APIs whose requests, or the commands of, may set a memo
+var memoAPIs = map[string]struct{}{
+	workflowServicePrefix + "StartWorkflowExecution":           {},
+	workflowServicePrefix + "SignalWithStartWorkflowExecution": {},
+	workflowServicePrefix + "RespondWorkflowTaskCompleted":     {},
+}
+
+// MemoSubject binds a memo field to the subject of the claims, e.g. an "owner" field
+func MemoSubject(claims *Claims) []string {
+	return []string{claims.Subject}
+}
+
+// MemoAttribute binds a memo field to the values of an attribute of the claims, e.g. a "team" field to the teams
+// of the subject
+func MemoAttribute(attribute string) MemoClaim {
+	return func(claims *Claims) []string {
+		return claims.Attributes[attribute]
+	}
+}
+
+// NewMemoAuthorizer creates an authorizer that denies calls starting a workflow with a memo inconsistent with
+// the identity of the caller, for teams that encode ownership or tenancy in memos. bindings maps the names of
+// memo fields to the claims their value must be one of, e.g.
+//
+//	NewMemoAuthorizer(authorizer, map[string]MemoClaim{
+//	    "owner": MemoSubject,
+//	    "team":  MemoAttribute("teams"),
+//	})
+//
+// denies starting a workflow whose "owner" memo is not the caller's subject, or whose "team" memo is not one of
+// the caller's teams. The memos of the sub-targets, such as started child workflows, are checked too.
+// For delegated calls the claims of the end user apply. Fields not in bindings, and calls that set no memo,
+// are not checked. All other calls are decided by authorizer. Memos are only extracted by interceptors
+// configured WithRequestContentExtraction, calls that may set a memo are denied if they weren't.
+func NewMemoAuthorizer(authorizer Authorizer, bindings map[string]MemoClaim) Authorizer {
+	return &memoAuthorizer{
+		authorizer: authorizer,
+		bindings:   bindings,
+	}
+}
+
+func (a *memoAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if _, ok := memoAPIs[target.APIName]; ok && !target.ContentExtracted {
+		return Result{Decision: DecisionDeny, Reason: ReasonContentNotExtracted}, nil
+	}
+	if !a.setsBoundMemo(target) {
+		return a.authorizer.Authorize(ctx, claims, target)
+	}
+	if claims == nil {
+		return Result{Decision: DecisionDeny, Reason: ReasonNoClaims}, nil
+	}
+	if !a.isMemoConsistent(claims.EffectiveClaims(), target) {
+		return Result{Decision: DecisionDeny, Reason: ReasonMemoMismatch}, nil
+	}
+	return a.authorizer.Authorize(ctx, claims, target)
+}
+
+// setsBoundMemo checks if target or any of its sub-targets sets a memo field in bindings
+func (a *memoAuthorizer) setsBoundMemo(target *CallTarget) bool {
+	for name := range target.Memo {
+		if _, ok := a.bindings[name]; ok {
+			return true
+		}
+	}
+	for _, subTarget := range target.SubTargets {
+		if a.setsBoundMemo(subTarget) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *memoAuthorizer) isMemoConsistent(claims *Claims, target *CallTarget) bool {
+	for name, value := range target.Memo {
+		binding, ok := a.bindings[name]
+		if ok && !containsValue(binding(claims), value) {
+			return false
+		}
+	}
+	for _, subTarget := range target.SubTargets {
+		if !a.isMemoConsistent(claims, subTarget) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
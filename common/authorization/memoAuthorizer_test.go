@@ -0,0 +1,145 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	memoAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		mockAuthorizer *MockAuthorizer
+		authorizer     Authorizer
+	}
+)
+
+func TestMemoAuthorizerSuite(t *testing.T) {
+	s := new(memoAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *memoAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.authorizer = NewMemoAuthorizer(s.mockAuthorizer, map[string]MemoClaim{
+		"owner": MemoSubject,
+		"team":  MemoAttribute("teams"),
+	})
+}
+
+func (s *memoAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *memoAuthorizerSuite) TestConsistentMemo() {
+	claims := &Claims{Subject: testSubject, Attributes: map[string][]string{"teams": {"payments", "billing"}}}
+	target := &CallTarget{APIName: startWorkflowExecutionTarget.APIName, Namespace: testNamespace, ContentExtracted: true,
+		Memo: map[string]string{"owner": testSubject, "team": "billing", "description": "any"}}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, target).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, target)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *memoAuthorizerSuite) TestInconsistentMemo() {
+	claims := &Claims{Subject: testSubject, Attributes: map[string][]string{"teams": {"payments"}}}
+	for _, memo := range []map[string]string{
+		{"owner": "someone-else"},
+		{"owner": testSubject, "team": "billing"},
+	} {
+		target := &CallTarget{APIName: startWorkflowExecutionTarget.APIName, Namespace: testNamespace, Memo: memo, ContentExtracted: true}
+		result, err := s.authorizer.Authorize(ctx, claims, target)
+		s.NoError(err)
+		s.Equal(Result{Decision: DecisionDeny, Reason: ReasonMemoMismatch}, result)
+	}
+}
+
+func (s *memoAuthorizerSuite) TestInconsistentSubTargetMemo() {
+	target := &CallTarget{APIName: workflowServicePrefix + "RespondWorkflowTaskCompleted", Namespace: testNamespace, ContentExtracted: true,
+		SubTargets: []*CallTarget{
+			{APIName: startWorkflowExecutionTarget.APIName, Namespace: testNamespace, Memo: map[string]string{"owner": "someone-else"},
+				ContentExtracted: true},
+		}}
+
+	result, err := s.authorizer.Authorize(ctx, &Claims{Subject: testSubject}, target)
+	s.NoError(err)
+	s.Equal(Result{Decision: DecisionDeny, Reason: ReasonMemoMismatch}, result)
+}
+
+func (s *memoAuthorizerSuite) TestDelegatedCall() {
+	claims := &Claims{Subject: "worker", OnBehalfOf: &Claims{Subject: testSubject}}
+	target := &CallTarget{APIName: startWorkflowExecutionTarget.APIName, Namespace: testNamespace, ContentExtracted: true,
+		Memo: map[string]string{"owner": testSubject}}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, target).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, target)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *memoAuthorizerSuite) TestNoClaims() {
+	target := &CallTarget{APIName: startWorkflowExecutionTarget.APIName, Namespace: testNamespace, ContentExtracted: true,
+		Memo: map[string]string{"owner": testSubject}}
+
+	result, err := s.authorizer.Authorize(ctx, nil, target)
+	s.NoError(err)
+	s.Equal(Result{Decision: DecisionDeny, Reason: ReasonNoClaims}, result)
+}
+
+func (s *memoAuthorizerSuite) TestWithoutMemo() {
+	for _, target := range []*CallTarget{
+		describeNamespaceTarget,
+		{APIName: startWorkflowExecutionTarget.APIName, Namespace: testNamespace, ContentExtracted: true},
+		{APIName: startWorkflowExecutionTarget.APIName, Namespace: testNamespace, Memo: map[string]string{"description": "any"},
+			ContentExtracted: true},
+	} {
+		s.mockAuthorizer.EXPECT().Authorize(ctx, nil, target).Return(Result{Decision: DecisionAllow}, nil)
+		result, err := s.authorizer.Authorize(ctx, nil, target)
+		s.NoError(err)
+		s.Equal(DecisionAllow, result.Decision)
+	}
+}
+
+func (s *memoAuthorizerSuite) TestContentNotExtracted() {
+	for _, apiName := range []string{
+		startWorkflowExecutionTarget.APIName,
+		workflowServicePrefix + "SignalWithStartWorkflowExecution",
+		workflowServicePrefix + "RespondWorkflowTaskCompleted",
+	} {
+		result, err := s.authorizer.Authorize(ctx, &Claims{Subject: testSubject}, &CallTarget{APIName: apiName, Namespace: testNamespace})
+		s.NoError(err)
+		s.Equal(Result{Decision: DecisionDeny, Reason: ReasonContentNotExtracted}, result)
+	}
+}
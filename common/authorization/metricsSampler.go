@@ -0,0 +1,61 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"math"
+
+	"go.uber.org/atomic"
+)
+
+// metricsSampler deterministically selects one of every interval calls for emitting metrics.
+// A nil sampler selects every call.
+type metricsSampler struct {
+	interval uint64
+	calls    *atomic.Uint64
+}
+
+func newMetricsSampler(rate float64) *metricsSampler {
+	var interval uint64
+	switch {
+	case rate >= 1:
+		interval = 1
+	case rate <= 0:
+		interval = 0 // never sample
+	default:
+		interval = uint64(math.Round(1 / rate))
+	}
+	return &metricsSampler{interval: interval, calls: atomic.NewUint64(0)}
+}
+
+func (s *metricsSampler) sample() bool {
+	if s == nil || s.interval == 1 {
+		return true
+	}
+	if s.interval == 0 {
+		return false
+	}
+	return (s.calls.Inc()-1)%s.interval == 0
+}
@@ -0,0 +1,75 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+)
+
+type (
+	mfaRequiredAuthorizer struct {
+		authorizer        Authorizer
+		apis              map[string]struct{}
+		acceptableMethods map[string]struct{}
+	}
+)
+
+var _ Authorizer = (*mfaRequiredAuthorizer)(nil)
+
+// NewMFARequiredAuthorizer creates an authorizer that denies calls to the given APIs unless the caller
+// was authenticated with one of acceptableMethods. All other calls are decided by authorizer.
+func NewMFARequiredAuthorizer(authorizer Authorizer, apis []string, acceptableMethods []string) Authorizer {
+	a := &mfaRequiredAuthorizer{
+		authorizer:        authorizer,
+		apis:              make(map[string]struct{}, len(apis)),
+		acceptableMethods: make(map[string]struct{}, len(acceptableMethods)),
+	}
+	for _, api := range apis {
+		a.apis[api] = struct{}{}
+	}
+	for _, method := range acceptableMethods {
+		a.acceptableMethods[method] = struct{}{}
+	}
+	return a
+}
+
+func (a *mfaRequiredAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if _, ok := a.apis[target.APIName]; ok && !a.hasAcceptableMethod(claims) {
+		return Result{Decision: DecisionDeny, Reason: ReasonMFARequired}, nil
+	}
+	return a.authorizer.Authorize(ctx, claims, target)
+}
+
+func (a *mfaRequiredAuthorizer) hasAcceptableMethod(claims *Claims) bool {
+	if claims == nil {
+		return false
+	}
+	for _, method := range claims.AuthMethods {
+		if _, ok := a.acceptableMethods[method]; ok {
+			return true
+		}
+	}
+	return false
+}
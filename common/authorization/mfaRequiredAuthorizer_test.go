@@ -0,0 +1,97 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	mfaRequiredAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		mockAuthorizer *MockAuthorizer
+		authorizer     Authorizer
+	}
+)
+
+func TestMFARequiredAuthorizerSuite(t *testing.T) {
+	s := new(mfaRequiredAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *mfaRequiredAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.authorizer = NewMFARequiredAuthorizer(
+		s.mockAuthorizer,
+		[]string{startWorkflowExecutionTarget.APIName},
+		[]string{"otp", "hwk"})
+}
+
+func (s *mfaRequiredAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *mfaRequiredAuthorizerSuite) TestMFAPresent() {
+	claims := &Claims{Subject: testSubject, AuthMethods: []string{"pwd", "otp"}}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, startWorkflowExecutionTarget).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, startWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *mfaRequiredAuthorizerSuite) TestMFAAbsent() {
+	claims := &Claims{Subject: testSubject, System: RoleAdmin, AuthMethods: []string{"pwd"}}
+
+	result, err := s.authorizer.Authorize(ctx, claims, startWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonMFARequired, result.Reason)
+}
+
+func (s *mfaRequiredAuthorizerSuite) TestNoClaims() {
+	result, err := s.authorizer.Authorize(ctx, nil, startWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonMFARequired, result.Reason)
+}
+
+func (s *mfaRequiredAuthorizerSuite) TestNotGatedAPI() {
+	claims := &Claims{Subject: testSubject}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, describeNamespaceTarget).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
@@ -0,0 +1,111 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	enumspb "go.temporal.io/api/enums/v1"
+
+	"go.temporal.io/server/common/cache"
+)
+
+type (
+	// NamespaceCacheLookup resolves namespace metadata for the interceptor options from the namespace cache
+	NamespaceCacheLookup interface {
+		NamespaceStateLookup
+		GlobalNamespaceLookup
+		NamespaceActivityLookup
+		NamespaceNameLookup
+		NamespaceLabelsLookup
+		NamespaceTenantLookup
+		NamespaceSearchAttributePolicyLookup
+	}
+
+	namespaceCacheLookup struct {
+		namespaceCache cache.NamespaceCache
+	}
+)
+
+var _ NamespaceCacheLookup = (*namespaceCacheLookup)(nil)
+
+// NewNamespaceCacheLookup creates a lookup of namespace metadata backed by namespaceCache
+func NewNamespaceCacheLookup(namespaceCache cache.NamespaceCache) NamespaceCacheLookup {
+	return &namespaceCacheLookup{namespaceCache: namespaceCache}
+}
+
+func (l *namespaceCacheLookup) GetNamespaceState(namespace string) (enumspb.NamespaceState, error) {
+	entry, err := l.namespaceCache.GetNamespace(namespace)
+	if err != nil {
+		return enumspb.NAMESPACE_STATE_UNSPECIFIED, err
+	}
+	return entry.GetInfo().GetState(), nil
+}
+
+func (l *namespaceCacheLookup) IsGlobalNamespace(namespace string) (bool, error) {
+	entry, err := l.namespaceCache.GetNamespace(namespace)
+	if err != nil {
+		return false, err
+	}
+	return entry.IsGlobalNamespace(), nil
+}
+
+func (l *namespaceCacheLookup) IsNamespaceActive(namespace string) (bool, error) {
+	entry, err := l.namespaceCache.GetNamespace(namespace)
+	if err != nil {
+		return false, err
+	}
+	return entry.IsNamespaceActive(), nil
+}
+
+func (l *namespaceCacheLookup) GetNamespaceName(id string) (string, error) {
+	return l.namespaceCache.GetNamespaceName(id)
+}
+
+// GetNamespaceLabels returns the data of the namespace, the key-value pairs set with UpdateNamespace
+func (l *namespaceCacheLookup) GetNamespaceLabels(namespace string) (map[string]string, error) {
+	entry, err := l.namespaceCache.GetNamespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return entry.GetInfo().GetData(), nil
+}
+
+// GetNamespaceTenant returns the value of the TenantLabel in the data of the namespace
+func (l *namespaceCacheLookup) GetNamespaceTenant(namespace string) (string, error) {
+	labels, err := l.GetNamespaceLabels(namespace)
+	if err != nil {
+		return "", err
+	}
+	return labels[TenantLabel], nil
+}
+
+// GetSearchAttributePolicy returns the policy of the labels with the RestrictedSearchAttributeLabelPrefix
+// in the data of the namespace
+func (l *namespaceCacheLookup) GetSearchAttributePolicy(namespace string) (*SearchAttributePolicy, error) {
+	labels, err := l.GetNamespaceLabels(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return newSearchAttributePolicy(labels), nil
+}
@@ -0,0 +1,144 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common/cache"
+)
+
+type (
+	namespaceCacheLookupSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller         *gomock.Controller
+		mockNamespaceCache *cache.MockNamespaceCache
+		lookup             NamespaceCacheLookup
+	}
+)
+
+func TestNamespaceCacheLookupSuite(t *testing.T) {
+	s := new(namespaceCacheLookupSuite)
+	suite.Run(t, s)
+}
+
+func (s *namespaceCacheLookupSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockNamespaceCache = cache.NewMockNamespaceCache(s.controller)
+	s.lookup = NewNamespaceCacheLookup(s.mockNamespaceCache)
+}
+
+func (s *namespaceCacheLookupSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *namespaceCacheLookupSuite) TestGlobalNamespace() {
+	entry := cache.NewGlobalNamespaceCacheEntryForTest(
+		&persistencespb.NamespaceInfo{Name: testNamespace}, nil, nil, 0, nil)
+	s.mockNamespaceCache.EXPECT().GetNamespace(testNamespace).Return(entry, nil)
+
+	isGlobal, err := s.lookup.IsGlobalNamespace(testNamespace)
+	s.NoError(err)
+	s.True(isGlobal)
+}
+
+func (s *namespaceCacheLookupSuite) TestLocalNamespace() {
+	entry := cache.NewLocalNamespaceCacheEntryForTest(
+		&persistencespb.NamespaceInfo{Name: testNamespace}, nil, "active", nil)
+	s.mockNamespaceCache.EXPECT().GetNamespace(testNamespace).Return(entry, nil)
+
+	isGlobal, err := s.lookup.IsGlobalNamespace(testNamespace)
+	s.NoError(err)
+	s.False(isGlobal)
+}
+
+func (s *namespaceCacheLookupSuite) TestNamespaceState() {
+	entry := cache.NewLocalNamespaceCacheEntryForTest(
+		&persistencespb.NamespaceInfo{Name: testNamespace, State: enumspb.NAMESPACE_STATE_DEPRECATED}, nil, "active", nil)
+	s.mockNamespaceCache.EXPECT().GetNamespace(testNamespace).Return(entry, nil)
+
+	state, err := s.lookup.GetNamespaceState(testNamespace)
+	s.NoError(err)
+	s.Equal(enumspb.NAMESPACE_STATE_DEPRECATED, state)
+}
+
+func (s *namespaceCacheLookupSuite) TestNamespaceLabels() {
+	labels := map[string]string{ClassificationLabel: "restricted"}
+	entry := cache.NewLocalNamespaceCacheEntryForTest(
+		&persistencespb.NamespaceInfo{Name: testNamespace, Data: labels}, nil, "active", nil)
+	s.mockNamespaceCache.EXPECT().GetNamespace(testNamespace).Return(entry, nil)
+
+	actual, err := s.lookup.GetNamespaceLabels(testNamespace)
+	s.NoError(err)
+	s.Equal(labels, actual)
+}
+
+func (s *namespaceCacheLookupSuite) TestNamespaceTenant() {
+	entry := cache.NewLocalNamespaceCacheEntryForTest(
+		&persistencespb.NamespaceInfo{Name: testNamespace, Data: map[string]string{TenantLabel: "acme"}}, nil, "active", nil)
+	s.mockNamespaceCache.EXPECT().GetNamespace(testNamespace).Return(entry, nil)
+
+	tenantID, err := s.lookup.GetNamespaceTenant(testNamespace)
+	s.NoError(err)
+	s.Equal("acme", tenantID)
+}
+
+func (s *namespaceCacheLookupSuite) TestNamespaceSearchAttributePolicy() {
+	entry := cache.NewLocalNamespaceCacheEntryForTest(
+		&persistencespb.NamespaceInfo{Name: testNamespace, Data: map[string]string{
+			RestrictedSearchAttributeLabelPrefix + "CustomerId": "admin, write",
+			TenantLabel: "acme",
+		}}, nil, "active", nil)
+	s.mockNamespaceCache.EXPECT().GetNamespace(testNamespace).Return(entry, nil)
+
+	policy, err := s.lookup.GetSearchAttributePolicy(testNamespace)
+	s.NoError(err)
+	s.Equal(&SearchAttributePolicy{Restricted: map[string]Role{"CustomerId": RoleAdmin | RoleWriter}}, policy)
+}
+
+func (s *namespaceCacheLookupSuite) TestUnknownNamespace() {
+	s.mockNamespaceCache.EXPECT().GetNamespace(testNamespace).Return(nil, serviceerror.NewNotFound("not found")).Times(5)
+
+	_, err := s.lookup.IsGlobalNamespace(testNamespace)
+	s.Error(err)
+	_, err = s.lookup.GetNamespaceState(testNamespace)
+	s.Error(err)
+	_, err = s.lookup.GetNamespaceLabels(testNamespace)
+	s.Error(err)
+	_, err = s.lookup.GetNamespaceTenant(testNamespace)
+	s.Error(err)
+	_, err = s.lookup.GetSearchAttributePolicy(testNamespace)
+	s.Error(err)
+}
@@ -0,0 +1,154 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/metrics"
+)
+
+type (
+	// NamespaceFanOutConfig configures the authorizer created by NewNamespaceFanOutAuthorizer
+	NamespaceFanOutConfig struct {
+		// MaxNamespaces is the number of distinct namespaces a subject may access within Window
+		MaxNamespaces int
+		// Window is the sliding window distinct namespaces are counted over
+		Window time.Duration
+		// MaxSubjects bounds the number of tracked subjects, the least recently active are evicted first.
+		// Zero disables tracking.
+		MaxSubjects int
+		// FlagOnly only counts calls exceeding MaxNamespaces in a metric instead of denying them
+		FlagOnly bool
+	}
+
+	namespaceFanOutAuthorizer struct {
+		authorizer    Authorizer
+		config        NamespaceFanOutConfig
+		metricsClient metrics.Client
+		timeSource    clock.TimeSource
+
+		sync.Mutex
+		subjects map[string]*list.Element // values are *subjectNamespaces
+		byAccess *list.List               // most recently active subject first
+	}
+
+	// subjectNamespaces tracks when a subject last accessed each namespace within the window
+	subjectNamespaces struct {
+		subject    string
+		lastAccess time.Time
+		namespaces map[string]time.Time
+	}
+)
+
+var _ Authorizer = (*namespaceFanOutAuthorizer)(nil)
+
+// NewNamespaceFanOutAuthorizer creates an authorizer that tracks the distinct namespaces each subject accesses
+// within a sliding window, and denies or flags calls that would exceed the configured maximum, such as
+// a stolen token probing many namespaces. Delegated calls count towards the end user. All other calls are
+// decided by authorizer.
+// Subjects idle for longer than the window are forgotten, so memory use is bounded by
+// MaxSubjects * MaxNamespaces entries.
+func NewNamespaceFanOutAuthorizer(
+	authorizer Authorizer,
+	config NamespaceFanOutConfig,
+	metricsClient metrics.Client,
+	timeSource clock.TimeSource,
+) Authorizer {
+	return &namespaceFanOutAuthorizer{
+		authorizer:    authorizer,
+		config:        config,
+		metricsClient: metricsClient,
+		timeSource:    timeSource,
+		subjects:      make(map[string]*list.Element),
+		byAccess:      list.New(),
+	}
+}
+
+func (a *namespaceFanOutAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if claims != nil && claims.EffectiveClaims().Subject != "" && target.Namespace != "" &&
+		!a.track(claims.EffectiveClaims().Subject, target.Namespace) {
+		a.metricsClient.IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationNamespaceFanOutCounter)
+		if !a.config.FlagOnly {
+			return Result{Decision: DecisionDeny, Reason: ReasonNamespaceFanOut}, nil
+		}
+	}
+	return a.authorizer.Authorize(ctx, claims, target)
+}
+
+// track records the access of subject to namespace and returns false if it exceeds the maximum
+// number of distinct namespaces, in which case the access is not recorded
+func (a *namespaceFanOutAuthorizer) track(subject string, namespace string) bool {
+	if a.config.MaxSubjects <= 0 {
+		return true
+	}
+	now := a.timeSource.Now()
+	windowStart := now.Add(-a.config.Window)
+
+	a.Lock()
+	defer a.Unlock()
+
+	a.expireIdleSubjects(windowStart)
+	element, ok := a.subjects[subject]
+	if ok {
+		a.byAccess.MoveToFront(element)
+	} else {
+		if len(a.subjects) >= a.config.MaxSubjects {
+			oldest := a.byAccess.Remove(a.byAccess.Back()).(*subjectNamespaces)
+			delete(a.subjects, oldest.subject)
+		}
+		element = a.byAccess.PushFront(&subjectNamespaces{subject: subject, namespaces: make(map[string]time.Time)})
+		a.subjects[subject] = element
+	}
+
+	tracked := element.Value.(*subjectNamespaces)
+	tracked.lastAccess = now
+	for ns, lastAccess := range tracked.namespaces {
+		if lastAccess.Before(windowStart) {
+			delete(tracked.namespaces, ns)
+		}
+	}
+	if _, ok := tracked.namespaces[namespace]; !ok && len(tracked.namespaces) >= a.config.MaxNamespaces {
+		return false
+	}
+	tracked.namespaces[namespace] = now
+	return true
+}
+
+// expireIdleSubjects forgets the subjects that have not accessed any namespace since windowStart
+func (a *namespaceFanOutAuthorizer) expireIdleSubjects(windowStart time.Time) {
+	for element := a.byAccess.Back(); element != nil; element = a.byAccess.Back() {
+		tracked := element.Value.(*subjectNamespaces)
+		if !tracked.lastAccess.Before(windowStart) {
+			return
+		}
+		a.byAccess.Remove(element)
+		delete(a.subjects, tracked.subject)
+	}
+}
@@ -0,0 +1,164 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/metrics"
+)
+
+type (
+	namespaceFanOutAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller        *gomock.Controller
+		mockAuthorizer    *MockAuthorizer
+		mockMetricsClient *metrics.MockClient
+		timeSource        *clock.EventTimeSource
+		config            NamespaceFanOutConfig
+	}
+)
+
+func TestNamespaceFanOutAuthorizerSuite(t *testing.T) {
+	s := new(namespaceFanOutAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *namespaceFanOutAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.mockMetricsClient = metrics.NewMockClient(s.controller)
+	s.timeSource = clock.NewEventTimeSource().Update(time.Unix(0, 0))
+	s.config = NamespaceFanOutConfig{MaxNamespaces: 3, Window: time.Minute, MaxSubjects: 10}
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(Result{Decision: DecisionAllow}, nil).AnyTimes()
+}
+
+func (s *namespaceFanOutAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *namespaceFanOutAuthorizerSuite) TestCrossingThreshold() {
+	authorizer := NewNamespaceFanOutAuthorizer(s.mockAuthorizer, s.config, s.mockMetricsClient, s.timeSource)
+	for i := 0; i < 3; i++ {
+		s.assertDecision(authorizer, "alice", fmt.Sprintf("namespace-%d", i), DecisionAllow)
+	}
+	// namespaces accessed before don't count again
+	s.assertDecision(authorizer, "alice", "namespace-0", DecisionAllow)
+
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationNamespaceFanOutCounter)
+	result := s.assertDecision(authorizer, "alice", "namespace-3", DecisionDeny)
+	s.Equal(ReasonNamespaceFanOut, result.Reason)
+
+	// other subjects are counted separately
+	s.assertDecision(authorizer, "bob", "namespace-3", DecisionAllow)
+}
+
+func (s *namespaceFanOutAuthorizerSuite) TestSlidingWindow() {
+	authorizer := NewNamespaceFanOutAuthorizer(s.mockAuthorizer, s.config, s.mockMetricsClient, s.timeSource)
+	s.assertDecision(authorizer, "alice", "namespace-0", DecisionAllow)
+	s.timeSource.Update(time.Unix(30, 0))
+	s.assertDecision(authorizer, "alice", "namespace-1", DecisionAllow)
+	s.assertDecision(authorizer, "alice", "namespace-2", DecisionAllow)
+
+	// namespace-0 left the window
+	s.timeSource.Update(time.Unix(61, 0))
+	s.assertDecision(authorizer, "alice", "namespace-3", DecisionAllow)
+
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationNamespaceFanOutCounter)
+	s.assertDecision(authorizer, "alice", "namespace-4", DecisionDeny)
+}
+
+func (s *namespaceFanOutAuthorizerSuite) TestFlagOnly() {
+	s.config.FlagOnly = true
+	authorizer := NewNamespaceFanOutAuthorizer(s.mockAuthorizer, s.config, s.mockMetricsClient, s.timeSource)
+	for i := 0; i < 3; i++ {
+		s.assertDecision(authorizer, "alice", fmt.Sprintf("namespace-%d", i), DecisionAllow)
+	}
+
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationNamespaceFanOutCounter).Times(2)
+	s.assertDecision(authorizer, "alice", "namespace-3", DecisionAllow)
+	s.assertDecision(authorizer, "alice", "namespace-4", DecisionAllow)
+}
+
+func (s *namespaceFanOutAuthorizerSuite) TestIdleSubjectsExpire() {
+	authorizer := NewNamespaceFanOutAuthorizer(s.mockAuthorizer, s.config, s.mockMetricsClient, s.timeSource).(*namespaceFanOutAuthorizer)
+	s.assertDecision(authorizer, "alice", "namespace-0", DecisionAllow)
+	s.assertDecision(authorizer, "bob", "namespace-0", DecisionAllow)
+	s.Len(authorizer.subjects, 2)
+
+	s.timeSource.Update(time.Unix(61, 0))
+	s.assertDecision(authorizer, "carol", "namespace-0", DecisionAllow)
+	s.Len(authorizer.subjects, 1)
+	s.Equal(1, authorizer.byAccess.Len())
+}
+
+func (s *namespaceFanOutAuthorizerSuite) TestMaxSubjects() {
+	s.config.MaxSubjects = 2
+	authorizer := NewNamespaceFanOutAuthorizer(s.mockAuthorizer, s.config, s.mockMetricsClient, s.timeSource).(*namespaceFanOutAuthorizer)
+	for _, subject := range []string{"alice", "bob", "carol"} {
+		s.assertDecision(authorizer, subject, "namespace-0", DecisionAllow)
+	}
+	s.Len(authorizer.subjects, 2)
+	s.NotContains(authorizer.subjects, "alice")
+}
+
+func (s *namespaceFanOutAuthorizerSuite) TestNoSubject() {
+	authorizer := NewNamespaceFanOutAuthorizer(s.mockAuthorizer, s.config, s.mockMetricsClient, s.timeSource).(*namespaceFanOutAuthorizer)
+	for i := 0; i < 5; i++ {
+		result, err := authorizer.Authorize(ctx, nil, &CallTarget{Namespace: fmt.Sprintf("namespace-%d", i)})
+		s.NoError(err)
+		s.Equal(DecisionAllow, result.Decision)
+	}
+	s.Empty(authorizer.subjects)
+}
+
+func (s *namespaceFanOutAuthorizerSuite) TestDelegatedCalls() {
+	authorizer := NewNamespaceFanOutAuthorizer(s.mockAuthorizer, s.config, s.mockMetricsClient, s.timeSource)
+	// an actor making calls for many end users isn't flagged for the namespaces of all of them
+	for i := 0; i < 4; i++ {
+		claims := &Claims{Subject: "gateway", OnBehalfOf: &Claims{Subject: fmt.Sprintf("user-%d", i)}}
+		result, err := authorizer.Authorize(ctx, claims, &CallTarget{Namespace: fmt.Sprintf("namespace-%d", i)})
+		s.NoError(err)
+		s.Equal(DecisionAllow, result.Decision)
+	}
+}
+
+func (s *namespaceFanOutAuthorizerSuite) assertDecision(authorizer Authorizer, subject string, namespace string, decision Decision) Result {
+	result, err := authorizer.Authorize(ctx, &Claims{Subject: subject}, &CallTarget{Namespace: namespace})
+	s.NoError(err)
+	s.Equal(decision, result.Decision)
+	return result
+}
@@ -0,0 +1,109 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"time"
+
+	"go.temporal.io/server/common/cache"
+	"go.temporal.io/server/common/clock"
+)
+
+type (
+	// NamespaceLabelsCache is a NamespaceLabelsLookup that caches the labels resolved by another lookup
+	NamespaceLabelsCache interface {
+		NamespaceLabelsLookup
+		// Invalidate discards the cached labels of namespace
+		Invalidate(namespace string)
+		// OnNamespaceChange invalidates the changed namespaces. It is a cache.CallbackFn, so the cache can be kept
+		// up to date by registering it with cache.NamespaceCache.RegisterNamespaceChangeCallback.
+		OnNamespaceChange(prevNamespaces []*cache.NamespaceCacheEntry, nextNamespaces []*cache.NamespaceCacheEntry)
+	}
+
+	namespaceLabelsCache struct {
+		lookup     NamespaceLabelsLookup
+		ttl        time.Duration
+		timeSource clock.TimeSource
+		entries    cache.Cache
+	}
+
+	namespaceLabelsCacheEntry struct {
+		labels    map[string]string
+		expiresAt time.Time
+	}
+)
+
+var _ NamespaceLabelsCache = (*namespaceLabelsCache)(nil)
+
+// NewNamespaceLabelsCache creates a NamespaceLabelsCache that resolves labels with lookup and reuses them for ttl,
+// keeping the labels of at most maxSize namespaces. Lookup errors are not cached.
+func NewNamespaceLabelsCache(
+	lookup NamespaceLabelsLookup,
+	maxSize int,
+	ttl time.Duration,
+	timeSource clock.TimeSource,
+) NamespaceLabelsCache {
+	return &namespaceLabelsCache{
+		lookup:     lookup,
+		ttl:        ttl,
+		timeSource: timeSource,
+		entries:    cache.New(maxSize, nil),
+	}
+}
+
+func (c *namespaceLabelsCache) GetNamespaceLabels(namespace string) (map[string]string, error) {
+	now := c.timeSource.Now()
+	if value := c.entries.Get(namespace); value != nil {
+		entry := value.(*namespaceLabelsCacheEntry)
+		if now.Before(entry.expiresAt) {
+			return entry.labels, nil
+		}
+		c.entries.Delete(namespace)
+	}
+
+	labels, err := c.lookup.GetNamespaceLabels(namespace)
+	if err != nil {
+		return nil, err
+	}
+	c.entries.Put(namespace, &namespaceLabelsCacheEntry{labels: labels, expiresAt: now.Add(c.ttl)})
+	return labels, nil
+}
+
+func (c *namespaceLabelsCache) Invalidate(namespace string) {
+	c.entries.Delete(namespace)
+}
+
+func (c *namespaceLabelsCache) OnNamespaceChange(
+	prevNamespaces []*cache.NamespaceCacheEntry,
+	nextNamespaces []*cache.NamespaceCacheEntry,
+) {
+	for _, entries := range [][]*cache.NamespaceCacheEntry{prevNamespaces, nextNamespaces} {
+		for _, entry := range entries {
+			if entry != nil {
+				c.entries.Delete(entry.GetInfo().GetName())
+			}
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common/cache"
+	"go.temporal.io/server/common/clock"
+)
+
+type (
+	namespaceLabelsCacheSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		lookup     *countingNamespaceLabelsLookup
+		timeSource *clock.EventTimeSource
+		cache      NamespaceLabelsCache
+	}
+
+	// countingNamespaceLabelsLookup counts the lookups of the labels in namespaces
+	countingNamespaceLabelsLookup struct {
+		namespaces testNamespaceLabelsLookup
+		lookups    int
+	}
+)
+
+func TestNamespaceLabelsCacheSuite(t *testing.T) {
+	s := new(namespaceLabelsCacheSuite)
+	suite.Run(t, s)
+}
+
+func (s *namespaceLabelsCacheSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.lookup = &countingNamespaceLabelsLookup{namespaces: testNamespaceLabelsLookup{
+		testNamespace: {ClassificationLabel: "restricted"},
+	}}
+	s.timeSource = clock.NewEventTimeSource().Update(time.Unix(0, 0))
+	s.cache = NewNamespaceLabelsCache(s.lookup, 2, time.Minute, s.timeSource)
+}
+
+func (s *namespaceLabelsCacheSuite) TestRefreshOnTTL() {
+	s.assertLabels("restricted")
+	s.assertLabels("restricted")
+	s.Equal(1, s.lookup.lookups)
+
+	s.lookup.namespaces[testNamespace] = map[string]string{ClassificationLabel: "public"}
+	s.timeSource.Update(time.Unix(59, 0))
+	s.assertLabels("restricted")
+
+	s.timeSource.Update(time.Unix(60, 0))
+	s.assertLabels("public")
+	s.Equal(2, s.lookup.lookups)
+}
+
+func (s *namespaceLabelsCacheSuite) TestMaxSize() {
+	s.lookup.namespaces["second"] = map[string]string{}
+	s.lookup.namespaces["third"] = map[string]string{}
+	for _, namespace := range []string{testNamespace, "second", "third"} {
+		_, err := s.cache.GetNamespaceLabels(namespace)
+		s.NoError(err)
+	}
+	s.Equal(3, s.lookup.lookups)
+
+	// the least recently used namespace was evicted
+	s.assertLabels("restricted")
+	s.Equal(4, s.lookup.lookups)
+}
+
+func (s *namespaceLabelsCacheSuite) TestInvalidate() {
+	s.assertLabels("restricted")
+	s.lookup.namespaces[testNamespace] = map[string]string{ClassificationLabel: "public"}
+
+	s.cache.Invalidate(testNamespace)
+	s.assertLabels("public")
+	s.Equal(2, s.lookup.lookups)
+}
+
+func (s *namespaceLabelsCacheSuite) TestOnNamespaceChange() {
+	s.assertLabels("restricted")
+	s.lookup.namespaces[testNamespace] = map[string]string{ClassificationLabel: "public"}
+
+	entry := cache.NewLocalNamespaceCacheEntryForTest(
+		&persistencespb.NamespaceInfo{Name: testNamespace, Data: s.lookup.namespaces[testNamespace]}, nil, "active", nil)
+	s.cache.OnNamespaceChange([]*cache.NamespaceCacheEntry{nil}, []*cache.NamespaceCacheEntry{entry})
+	s.assertLabels("public")
+}
+
+func (s *namespaceLabelsCacheSuite) TestLookupErrorNotCached() {
+	_, err := s.cache.GetNamespaceLabels("unknown")
+	s.Error(err)
+
+	s.lookup.namespaces["unknown"] = map[string]string{}
+	labels, err := s.cache.GetNamespaceLabels("unknown")
+	s.NoError(err)
+	s.Empty(labels)
+	s.Equal(2, s.lookup.lookups)
+}
+
+func (s *namespaceLabelsCacheSuite) TestLabelBasedDecision() {
+	authorizer := NewClassificationAuthorizer(NewNoopAuthorizer(), map[string]Role{"restricted": RoleAdmin, "public": RoleReader})
+	claims := &Claims{Namespaces: map[string]Role{testNamespace: RoleWriter}}
+	authorize := func() Decision {
+		labels, err := s.cache.GetNamespaceLabels(testNamespace)
+		s.NoError(err)
+		result, err := authorizer.Authorize(ctx, claims, &CallTarget{Namespace: testNamespace, NamespaceLabels: labels})
+		s.NoError(err)
+		return result.Decision
+	}
+
+	s.Equal(DecisionDeny, authorize())
+
+	s.lookup.namespaces[testNamespace] = map[string]string{ClassificationLabel: "public"}
+	s.cache.Invalidate(testNamespace)
+	s.Equal(DecisionAllow, authorize())
+}
+
+func (s *namespaceLabelsCacheSuite) assertLabels(classification string) {
+	labels, err := s.cache.GetNamespaceLabels(testNamespace)
+	s.NoError(err)
+	s.Equal(classification, labels[ClassificationLabel])
+}
+
+func (l *countingNamespaceLabelsLookup) GetNamespaceLabels(namespace string) (map[string]string, error) {
+	l.lookups++
+	return l.namespaces.GetNamespaceLabels(namespace)
+}
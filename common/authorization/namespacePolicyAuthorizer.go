@@ -0,0 +1,92 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+)
+
+type (
+	// NamespacePolicy specifies the minimum role a caller needs within a namespace to call the APIs of a group.
+	// A caller holds a role if it has the role or a higher one among the roles granted in the namespace, see Claims.GrantedRoles.
+	NamespacePolicy struct {
+		MinimumRoles map[APIGroup]Role
+	}
+
+	// NamespacePolicyProvider resolves the current policy of a namespace.
+	// It's consulted on every call, so implementations backed by a remote source should cache or poll.
+	NamespacePolicyProvider interface {
+		PolicyFor(namespace string) (NamespacePolicy, bool)
+	}
+
+	namespacePolicyAuthorizer struct {
+		authorizer Authorizer
+		provider   NamespacePolicyProvider
+	}
+)
+
+var _ Authorizer = (*namespacePolicyAuthorizer)(nil)
+
+// NewNamespacePolicyAuthorizer creates an authorizer that decides calls to API groups covered by the policy
+// of the target namespace. Calls not covered by a policy, including all calls to APIs of APIGroupUnknown,
+// are decided by authorizer.
+func NewNamespacePolicyAuthorizer(authorizer Authorizer, provider NamespacePolicyProvider) Authorizer {
+	return &namespacePolicyAuthorizer{
+		authorizer: authorizer,
+		provider:   provider,
+	}
+}
+
+func (a *namespacePolicyAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if target.Namespace == "" {
+		return a.authorizer.Authorize(ctx, claims, target)
+	}
+	policy, ok := a.provider.PolicyFor(target.Namespace)
+	if !ok {
+		return a.authorizer.Authorize(ctx, claims, target)
+	}
+	minimumRole, ok := policy.MinimumRoles[target.APIGroup]
+	if !ok || target.APIGroup == APIGroupUnknown {
+		return a.authorizer.Authorize(ctx, claims, target)
+	}
+
+	if claims == nil {
+		return Result{Decision: DecisionDeny, Reason: ReasonNoClaims}, nil
+	}
+	if !hasMinimumRole(claims.GrantedRoles(target.Namespace), minimumRole) {
+		return Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil
+	}
+	return Result{Decision: DecisionAllow}, nil
+}
+
+// hasMinimumRole checks if roles include minimum or a role ranked above it
+func hasMinimumRole(roles Role, minimum Role) bool {
+	const rankedRoles = RoleWorker | RoleReader | RoleWriter | RoleAdmin
+	if minimum == RoleUndefined {
+		return true
+	}
+	// ranked roles are ordered by their bit, so any bit at or above the minimum bit is sufficient
+	return roles&rankedRoles&^(minimum-1) != 0
+}
@@ -0,0 +1,151 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	namespacePolicyAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		mockAuthorizer *MockAuthorizer
+		provider       *testNamespacePolicyProvider
+		authorizer     Authorizer
+	}
+
+	testNamespacePolicyProvider struct {
+		sync.RWMutex
+		policies map[string]NamespacePolicy
+	}
+)
+
+func TestNamespacePolicyAuthorizerSuite(t *testing.T) {
+	s := new(namespacePolicyAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *namespacePolicyAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.provider = &testNamespacePolicyProvider{policies: make(map[string]NamespacePolicy)}
+	s.authorizer = NewNamespacePolicyAuthorizer(s.mockAuthorizer, s.provider)
+}
+
+func (s *namespacePolicyAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *namespacePolicyAuthorizerSuite) TestNoPolicy() {
+	claims := &Claims{Subject: testSubject}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, startWorkflowExecutionTarget).Return(Result{Decision: DecisionDeny}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, startWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+}
+
+func (s *namespacePolicyAuthorizerSuite) TestGroupNotCovered() {
+	s.provider.set(testNamespace, NamespacePolicy{MinimumRoles: map[APIGroup]Role{APIGroupWrite: RoleWriter}})
+	claims := &Claims{Subject: testSubject}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, describeNamespaceTarget).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *namespacePolicyAuthorizerSuite) TestPolicyChange() {
+	claims := &Claims{Subject: testSubject, Namespaces: map[string]Role{testNamespace: RoleWriter}}
+
+	s.provider.set(testNamespace, NamespacePolicy{MinimumRoles: map[APIGroup]Role{APIGroupWrite: RoleWriter}})
+	result, err := s.authorizer.Authorize(ctx, claims, startWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+
+	s.provider.set(testNamespace, NamespacePolicy{MinimumRoles: map[APIGroup]Role{APIGroupWrite: RoleAdmin}})
+	result, err = s.authorizer.Authorize(ctx, claims, startWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonInsufficientRole, result.Reason)
+
+	s.provider.set(testNamespace, NamespacePolicy{MinimumRoles: map[APIGroup]Role{APIGroupWrite: RoleReader}})
+	result, err = s.authorizer.Authorize(ctx, claims, startWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *namespacePolicyAuthorizerSuite) TestSystemRole() {
+	s.provider.set(testNamespace, NamespacePolicy{MinimumRoles: map[APIGroup]Role{APIGroupRead: RoleReader}})
+
+	result, err := s.authorizer.Authorize(ctx, &Claims{System: RoleAdmin}, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+
+	result, err = s.authorizer.Authorize(ctx, &Claims{System: RoleWorker}, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+}
+
+func (s *namespacePolicyAuthorizerSuite) TestNoClaims() {
+	s.provider.set(testNamespace, NamespacePolicy{MinimumRoles: map[APIGroup]Role{APIGroupRead: RoleReader}})
+
+	result, err := s.authorizer.Authorize(ctx, nil, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonNoClaims, result.Reason)
+}
+
+func TestHasMinimumRole(t *testing.T) {
+	require.True(t, hasMinimumRole(RoleAdmin, RoleReader))
+	require.True(t, hasMinimumRole(RoleReader, RoleReader))
+	require.True(t, hasMinimumRole(RoleWorker|RoleWriter, RoleReader))
+	require.False(t, hasMinimumRole(RoleWorker, RoleReader))
+	require.False(t, hasMinimumRole(RoleImpersonator, RoleReader))
+	require.False(t, hasMinimumRole(RoleUndefined, RoleWorker))
+	require.True(t, hasMinimumRole(RoleUndefined, RoleUndefined))
+}
+
+func (p *testNamespacePolicyProvider) set(namespace string, policy NamespacePolicy) {
+	p.Lock()
+	defer p.Unlock()
+	p.policies[namespace] = policy
+}
+
+func (p *testNamespacePolicyProvider) PolicyFor(namespace string) (NamespacePolicy, bool) {
+	p.RLock()
+	defer p.RUnlock()
+	policy, ok := p.policies[namespace]
+	return policy, ok
+}
@@ -0,0 +1,177 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"container/list"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	"go.temporal.io/server/common/clock"
+)
+
+const (
+	// NonceHeaderName is the metadata header carrying the signed nonce, see SignNonce for its format
+	NonceHeaderName = "x-temporal-nonce"
+)
+
+var errNonceCacheFull = errors.New("too many recent nonces, request cannot be checked for replay")
+
+type (
+	nonceAuthorizer struct {
+		authorizer      Authorizer
+		apis            map[string]struct{}
+		key             []byte
+		freshnessWindow time.Duration
+		maxNonces       int
+		timeSource      clock.TimeSource
+
+		sync.Mutex
+		seen     map[string]struct{}
+		byExpiry *list.List // of *seenNonce, earliest expiry first
+	}
+
+	seenNonce struct {
+		nonce  string
+		expiry time.Time
+	}
+)
+
+var _ Authorizer = (*nonceAuthorizer)(nil)
+
+// NewNonceAuthorizer creates an authorizer that requires calls to the given APIs to carry a fresh nonce
+// signed with key in the NonceHeaderName header, and denies calls replaying a nonce that was already used.
+// A nonce is fresh if its timestamp is within freshnessWindow of the current time. Used nonces are remembered
+// until they can no longer be fresh, up to maxNonces of them; calls are failed while that many are remembered.
+// All other calls, and calls with a valid nonce, are decided by authorizer.
+func NewNonceAuthorizer(
+	authorizer Authorizer,
+	apis []string,
+	key []byte,
+	freshnessWindow time.Duration,
+	maxNonces int,
+	timeSource clock.TimeSource,
+) Authorizer {
+	a := &nonceAuthorizer{
+		authorizer:      authorizer,
+		apis:            make(map[string]struct{}, len(apis)),
+		key:             key,
+		freshnessWindow: freshnessWindow,
+		maxNonces:       maxNonces,
+		timeSource:      timeSource,
+		seen:            make(map[string]struct{}),
+		byExpiry:        list.New(),
+	}
+	for _, api := range apis {
+		a.apis[api] = struct{}{}
+	}
+	return a
+}
+
+// SignNonce formats a nonce header value as "<nonce>.<unix seconds>.<signature>", where the signature is
+// the unpadded base64url encoded HMAC-SHA256 of "<nonce>.<unix seconds>" with key. The nonce must not contain dots.
+func SignNonce(key []byte, nonce string, timestamp time.Time) string {
+	payload := nonce + "." + strconv.FormatInt(timestamp.Unix(), 10)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(nonceSignature(key, payload))
+}
+
+func (a *nonceAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if _, ok := a.apis[target.APIName]; !ok {
+		return a.authorizer.Authorize(ctx, claims, target)
+	}
+
+	nonce, timestamp, ok := a.parseNonce(ctx)
+	now := a.timeSource.Now()
+	if !ok || timestamp.Before(now.Add(-a.freshnessWindow)) || timestamp.After(now.Add(a.freshnessWindow)) {
+		return Result{Decision: DecisionDeny, Reason: ReasonInvalidNonce}, nil
+	}
+	replayed, err := a.markSeen(nonce, now)
+	if err != nil {
+		return Result{}, err
+	}
+	if replayed {
+		return Result{Decision: DecisionDeny, Reason: ReasonReplayedNonce}, nil
+	}
+	return a.authorizer.Authorize(ctx, claims, target)
+}
+
+// parseNonce extracts the nonce and its timestamp from the header if the header has a valid signature
+func (a *nonceAuthorizer) parseNonce(ctx context.Context) (string, time.Time, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	values := headerValues(md, NonceHeaderName)
+	if !ok || len(values) == 0 {
+		return "", time.Time{}, false
+	}
+	parts := strings.Split(values[0], ".")
+	if len(parts) != 3 || parts[0] == "" {
+		return "", time.Time{}, false
+	}
+	signature := base64.RawURLEncoding.EncodeToString(nonceSignature(a.key, parts[0]+"."+parts[1]))
+	if !secureCompare(parts[2], signature) {
+		return "", time.Time{}, false
+	}
+	seconds, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return parts[0], time.Unix(seconds, 0), true
+}
+
+// markSeen records the nonce as used and reports whether it was used before
+func (a *nonceAuthorizer) markSeen(nonce string, now time.Time) (bool, error) {
+	a.Lock()
+	defer a.Unlock()
+
+	for front := a.byExpiry.Front(); front != nil && !front.Value.(*seenNonce).expiry.After(now); front = a.byExpiry.Front() {
+		delete(a.seen, a.byExpiry.Remove(front).(*seenNonce).nonce)
+	}
+
+	if _, ok := a.seen[nonce]; ok {
+		return true, nil
+	}
+	if len(a.seen) >= a.maxNonces {
+		return false, errNonceCacheFull
+	}
+	a.seen[nonce] = struct{}{}
+	// A nonce accepted now has a timestamp of at most now + window and stays fresh until the timestamp + window.
+	// Using the same retention for all nonces keeps the list ordered by expiry.
+	a.byExpiry.PushBack(&seenNonce{nonce: nonce, expiry: now.Add(2 * a.freshnessWindow)})
+	return false, nil
+}
+
+func nonceSignature(key []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
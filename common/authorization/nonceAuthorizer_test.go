@@ -0,0 +1,164 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/metadata"
+
+	"go.temporal.io/server/common/clock"
+)
+
+type (
+	nonceAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		mockAuthorizer *MockAuthorizer
+		timeSource     *clock.EventTimeSource
+		authorizer     Authorizer
+	}
+)
+
+var (
+	testNonceKey = []byte("nonce-key")
+	testNonceNow = time.Unix(1600000000, 0)
+)
+
+func TestNonceAuthorizerSuite(t *testing.T) {
+	s := new(nonceAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *nonceAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.timeSource = clock.NewEventTimeSource().Update(testNonceNow)
+	s.authorizer = NewNonceAuthorizer(
+		s.mockAuthorizer,
+		[]string{startWorkflowExecutionTarget.APIName},
+		testNonceKey,
+		time.Minute,
+		2,
+		s.timeSource)
+}
+
+func (s *nonceAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *nonceAuthorizerSuite) TestFreshNonce() {
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), nil, startWorkflowExecutionTarget).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorize(SignNonce(testNonceKey, "n1", testNonceNow.Add(-30*time.Second)))
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *nonceAuthorizerSuite) TestReplayedNonce() {
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), nil, startWorkflowExecutionTarget).Return(Result{Decision: DecisionAllow}, nil)
+	nonce := SignNonce(testNonceKey, "n1", testNonceNow)
+
+	result, err := s.authorize(nonce)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+
+	s.timeSource.Update(testNonceNow.Add(30 * time.Second))
+	result, err = s.authorize(nonce)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonReplayedNonce, result.Reason)
+}
+
+func (s *nonceAuthorizerSuite) TestExpiredNonce() {
+	result, err := s.authorize(SignNonce(testNonceKey, "n1", testNonceNow.Add(-2*time.Minute)))
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonInvalidNonce, result.Reason)
+}
+
+func (s *nonceAuthorizerSuite) TestFutureNonce() {
+	result, err := s.authorize(SignNonce(testNonceKey, "n1", testNonceNow.Add(2*time.Minute)))
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonInvalidNonce, result.Reason)
+}
+
+func (s *nonceAuthorizerSuite) TestInvalidSignature() {
+	result, err := s.authorize(SignNonce([]byte("other-key"), "n1", testNonceNow))
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonInvalidNonce, result.Reason)
+
+	result, err = s.authorize("n1.1600000000")
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonInvalidNonce, result.Reason)
+}
+
+func (s *nonceAuthorizerSuite) TestMissingNonce() {
+	result, err := s.authorizer.Authorize(ctx, nil, startWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonInvalidNonce, result.Reason)
+}
+
+func (s *nonceAuthorizerSuite) TestNotGatedAPI() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, nil, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *nonceAuthorizerSuite) TestSeenNoncesBounded() {
+	s.mockAuthorizer.EXPECT().Authorize(gomock.Any(), nil, startWorkflowExecutionTarget).Return(Result{Decision: DecisionAllow}, nil).Times(3)
+
+	for _, nonce := range []string{"n1", "n2"} {
+		_, err := s.authorize(SignNonce(testNonceKey, nonce, testNonceNow))
+		s.NoError(err)
+	}
+	_, err := s.authorize(SignNonce(testNonceKey, "n3", testNonceNow))
+	s.Error(err)
+
+	// seen nonces expire once they can no longer be fresh, making room for new ones
+	s.timeSource.Update(testNonceNow.Add(2 * time.Minute))
+	result, err := s.authorize(SignNonce(testNonceKey, "n3", testNonceNow.Add(2*time.Minute)))
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *nonceAuthorizerSuite) authorize(nonce string) (Result, error) {
+	nonceCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(NonceHeaderName, nonce))
+	return s.authorizer.Authorize(nonceCtx, nil, startWorkflowExecutionTarget)
+}
@@ -0,0 +1,61 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+)
+
+type onBehalfOfAuthorizer struct {
+	authorizer    Authorizer
+	trustedActors map[string]struct{}
+}
+
+var _ Authorizer = (*onBehalfOfAuthorizer)(nil)
+
+// NewOnBehalfOfAuthorizer creates an authorizer for delegated calls, i.e. calls with claims carrying
+// Claims.OnBehalfOf. A delegated call is denied unless the actor's subject is one of trustedActors,
+// otherwise it is decided by authorizer based on the claims of the end user it is made for.
+// Calls that are not delegated are decided by authorizer as they are.
+func NewOnBehalfOfAuthorizer(authorizer Authorizer, trustedActors []string) Authorizer {
+	a := &onBehalfOfAuthorizer{
+		authorizer:    authorizer,
+		trustedActors: make(map[string]struct{}, len(trustedActors)),
+	}
+	for _, actor := range trustedActors {
+		a.trustedActors[actor] = struct{}{}
+	}
+	return a
+}
+
+func (a *onBehalfOfAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if claims == nil || claims.OnBehalfOf == nil {
+		return a.authorizer.Authorize(ctx, claims, target)
+	}
+	if _, ok := a.trustedActors[claims.Subject]; !ok {
+		return Result{Decision: DecisionDeny, Reason: ReasonUntrustedActor}, nil
+	}
+	return a.authorizer.Authorize(ctx, claims.EffectiveClaims(), target)
+}
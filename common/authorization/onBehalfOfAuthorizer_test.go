@@ -0,0 +1,110 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	testGatewaySubject = "gateway"
+)
+
+type (
+	onBehalfOfAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		mockAuthorizer *MockAuthorizer
+		authorizer     Authorizer
+		user           *Claims
+	}
+)
+
+func TestOnBehalfOfAuthorizerSuite(t *testing.T) {
+	s := new(onBehalfOfAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *onBehalfOfAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.authorizer = NewOnBehalfOfAuthorizer(s.mockAuthorizer, []string{testGatewaySubject})
+	s.user = &Claims{Subject: testSubject, Namespaces: map[string]Role{testNamespace: RoleReader}}
+}
+
+func (s *onBehalfOfAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *onBehalfOfAuthorizerSuite) TestTrustedActor() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, s.user, describeNamespaceTarget).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, &Claims{Subject: testGatewaySubject, OnBehalfOf: s.user}, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *onBehalfOfAuthorizerSuite) TestTrustedActorEndUserDenied() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, s.user, startWorkflowExecutionTarget).
+		Return(Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, &Claims{Subject: testGatewaySubject, OnBehalfOf: s.user}, startWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonInsufficientRole, result.Reason)
+}
+
+func (s *onBehalfOfAuthorizerSuite) TestUntrustedActor() {
+	result, err := s.authorizer.Authorize(ctx, &Claims{Subject: "intruder", OnBehalfOf: s.user}, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonUntrustedActor, result.Reason)
+}
+
+func (s *onBehalfOfAuthorizerSuite) TestNotDelegated() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, s.user, describeNamespaceTarget).Return(Result{Decision: DecisionAllow}, nil)
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).Return(Result{Decision: DecisionDeny}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, s.user, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+	result, err = s.authorizer.Authorize(ctx, nil, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+}
+
+func (s *onBehalfOfAuthorizerSuite) TestEffectiveClaims() {
+	var claims *Claims
+	s.Nil(claims.EffectiveClaims())
+	s.Equal(s.user, s.user.EffectiveClaims())
+	s.Equal(s.user, (&Claims{Subject: testGatewaySubject, OnBehalfOf: s.user}).EffectiveClaims())
+}
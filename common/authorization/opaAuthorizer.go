@@ -0,0 +1,144 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"sync"
+
+	"go.temporal.io/server/common/metrics"
+)
+
+type (
+	// RegoCompiler compiles a Rego policy to a query prepared for evaluation, typically by calling PrepareForEval
+	// on rego.New(rego.Query("data.temporal.authz.allow"), rego.Module("policy.rego", policy)).
+	// It keeps the module independent of a particular OPA version.
+	RegoCompiler interface {
+		Compile(ctx context.Context, policy string) (RegoPreparedQuery, error)
+	}
+
+	// RegoPreparedQuery is a compiled Rego query, e.g. wrapping rego.PreparedEvalQuery. Eval reports whether
+	// the query evaluates to true for input and must be safe for concurrent use.
+	RegoPreparedQuery interface {
+		Eval(ctx context.Context, input map[string]interface{}) (bool, error)
+	}
+
+	// OPAAuthorizer is an Authorizer deciding calls with a Rego policy that is compiled once and recompiled
+	// only when the policy changes
+	OPAAuthorizer interface {
+		Authorizer
+		// UpdatePolicy compiles policy and switches to it for the calls authorized after it returns.
+		// Updates to the current policy are ignored. If policy fails to compile, the error is returned,
+		// counted, and the current policy is kept.
+		UpdatePolicy(ctx context.Context, policy string) error
+	}
+
+	opaAuthorizer struct {
+		compiler      RegoCompiler
+		metricsClient metrics.Client
+		updateLock    sync.Mutex
+
+		sync.RWMutex
+		policy string
+		query  RegoPreparedQuery
+	}
+)
+
+var _ OPAAuthorizer = (*opaAuthorizer)(nil)
+
+// NewOPAAuthorizer creates an OPAAuthorizer that allows a call if the query compiled from policy by compiler
+// evaluates to true for the input of the call, see OPAInput. An error is returned if policy fails to compile.
+// Errors of the evaluation fail closed: the call is denied and counted as a failed authorization.
+func NewOPAAuthorizer(ctx context.Context, compiler RegoCompiler, policy string, metricsClient metrics.Client) (OPAAuthorizer, error) {
+	a := &opaAuthorizer{
+		compiler:      compiler,
+		metricsClient: metricsClient,
+	}
+	if err := a.UpdatePolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// OPAInput returns the input a Rego policy is evaluated with for a call: the caller's "subject",
+// "system_role" and "namespace_role" in the target namespace as role bitmasks, and the "namespace",
+// "api_name" and "api_group" of the call
+func OPAInput(claims *Claims, target *CallTarget) map[string]interface{} {
+	input := map[string]interface{}{
+		"subject":        "",
+		"system_role":    int(RoleUndefined),
+		"namespace_role": int(RoleUndefined),
+		"namespace":      target.Namespace,
+		"api_name":       target.APIName,
+		"api_group":      string(target.APIGroup),
+	}
+	if claims != nil {
+		input["subject"] = claims.Subject
+		input["system_role"] = int(claims.System)
+		input["namespace_role"] = int(claims.Namespaces[target.Namespace])
+	}
+	return input
+}
+
+func (a *opaAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	a.RLock()
+	query := a.query
+	a.RUnlock()
+
+	allowed, err := query.Eval(ctx, OPAInput(claims, target))
+	if err != nil {
+		a.metricsClient.IncCounter(metrics.AuthorizationScope, metrics.ServiceErrAuthorizeFailedCounter)
+		return Result{Decision: DecisionDeny}, nil
+	}
+	if !allowed {
+		return Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil
+	}
+	return Result{Decision: DecisionAllow}, nil
+}
+
+func (a *opaAuthorizer) UpdatePolicy(ctx context.Context, policy string) error {
+	// serializes updates, calls keep being authorized with the current query while compiling
+	a.updateLock.Lock()
+	defer a.updateLock.Unlock()
+
+	a.RLock()
+	unchanged := a.query != nil && policy == a.policy
+	a.RUnlock()
+	if unchanged {
+		return nil
+	}
+	sw := a.metricsClient.StartTimer(metrics.AuthorizationScope, metrics.ServiceAuthorizationPolicyCompileLatency)
+	query, err := a.compiler.Compile(ctx, policy)
+	sw.Stop()
+	if err != nil {
+		a.metricsClient.IncCounter(metrics.AuthorizationScope, metrics.ServiceErrPolicyCompileCounter)
+		return err
+	}
+	a.Lock()
+	a.policy = policy
+	a.query = query
+	a.Unlock()
+	return nil
+}
@@ -0,0 +1,196 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"go.temporal.io/server/common/metrics"
+)
+
+type (
+	opaAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller        *gomock.Controller
+		mockMetricsClient *metrics.MockClient
+		compiler          *testRegoCompiler
+	}
+
+	// testRegoCompiler compiles policies listing the allowed API names, one per line,
+	// standing in for a Rego compiler
+	testRegoCompiler struct {
+		sync.Mutex
+		compiles int
+	}
+
+	testRegoQuery struct {
+		allowed map[string]struct{}
+		evals   *int
+	}
+)
+
+var errTestRegoCompile = errors.New("rego_parse_error")
+
+func (c *testRegoCompiler) Compile(_ context.Context, policy string) (RegoPreparedQuery, error) {
+	c.Lock()
+	defer c.Unlock()
+	c.compiles++
+	if strings.Contains(policy, "invalid") {
+		return nil, errTestRegoCompile
+	}
+	query := &testRegoQuery{allowed: make(map[string]struct{}), evals: new(int)}
+	for _, api := range strings.Split(policy, "\n") {
+		query.allowed[api] = struct{}{}
+	}
+	return query, nil
+}
+
+func (q *testRegoQuery) Eval(_ context.Context, input map[string]interface{}) (bool, error) {
+	*q.evals++
+	if input["subject"] != testSubject {
+		return false, nil
+	}
+	_, ok := q.allowed[input["api_name"].(string)]
+	return ok, nil
+}
+
+func TestOPAAuthorizerSuite(t *testing.T) {
+	s := new(opaAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *opaAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockMetricsClient = metrics.NewMockClient(s.controller)
+	s.compiler = &testRegoCompiler{}
+}
+
+func (s *opaAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *opaAuthorizerSuite) expectCompile() {
+	s.mockMetricsClient.EXPECT().StartTimer(metrics.AuthorizationScope, metrics.ServiceAuthorizationPolicyCompileLatency).
+		Return(metrics.NopStopwatch())
+}
+
+func (s *opaAuthorizerSuite) TestCompiledQueryReused() {
+	s.expectCompile()
+	authorizer, err := NewOPAAuthorizer(ctx, s.compiler, describeNamespaceTarget.APIName, s.mockMetricsClient)
+	s.NoError(err)
+
+	claims := &Claims{Subject: testSubject}
+	for i := 0; i < 3; i++ {
+		result, err := authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+		s.NoError(err)
+		s.Equal(DecisionAllow, result.Decision)
+		result, err = authorizer.Authorize(ctx, claims, startWorkflowExecutionTarget)
+		s.NoError(err)
+		s.Equal(DecisionDeny, result.Decision)
+		s.Equal(ReasonInsufficientRole, result.Reason)
+	}
+	s.Equal(1, s.compiler.compiles)
+	s.Equal(6, *authorizer.(*opaAuthorizer).query.(*testRegoQuery).evals)
+}
+
+func (s *opaAuthorizerSuite) TestPolicyUpdateRecompilesOnce() {
+	s.expectCompile()
+	authorizer, err := NewOPAAuthorizer(ctx, s.compiler, describeNamespaceTarget.APIName, s.mockMetricsClient)
+	s.NoError(err)
+
+	s.expectCompile()
+	policy := describeNamespaceTarget.APIName + "\n" + startWorkflowExecutionTarget.APIName
+	s.NoError(authorizer.UpdatePolicy(ctx, policy))
+	s.NoError(authorizer.UpdatePolicy(ctx, policy)) // unchanged
+	s.Equal(2, s.compiler.compiles)
+
+	for i := 0; i < 3; i++ {
+		result, err := authorizer.Authorize(ctx, &Claims{Subject: testSubject}, startWorkflowExecutionTarget)
+		s.NoError(err)
+		s.Equal(DecisionAllow, result.Decision)
+	}
+	s.Equal(2, s.compiler.compiles)
+}
+
+func (s *opaAuthorizerSuite) TestCompileError() {
+	s.expectCompile()
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceErrPolicyCompileCounter)
+	_, err := NewOPAAuthorizer(ctx, s.compiler, "invalid", s.mockMetricsClient)
+	s.Equal(errTestRegoCompile, err)
+
+	s.expectCompile()
+	authorizer, err := NewOPAAuthorizer(ctx, s.compiler, describeNamespaceTarget.APIName, s.mockMetricsClient)
+	s.NoError(err)
+
+	// the current policy is kept
+	s.expectCompile()
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceErrPolicyCompileCounter)
+	s.Equal(errTestRegoCompile, authorizer.UpdatePolicy(ctx, "invalid"))
+	result, err := authorizer.Authorize(ctx, &Claims{Subject: testSubject}, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *opaAuthorizerSuite) TestEvalError() {
+	s.expectCompile()
+	authorizer, err := NewOPAAuthorizer(ctx, s.compiler, describeNamespaceTarget.APIName, s.mockMetricsClient)
+	s.NoError(err)
+	authorizer.(*opaAuthorizer).query = &failingRegoQuery{}
+
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceErrAuthorizeFailedCounter)
+	result, err := authorizer.Authorize(ctx, &Claims{Subject: testSubject}, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+}
+
+func TestOPAInput(t *testing.T) {
+	claims := &Claims{Subject: testSubject, System: RoleReader, Namespaces: map[string]Role{testNamespace: RoleWriter}}
+	require.Equal(t, map[string]interface{}{
+		"subject":        testSubject,
+		"system_role":    int(RoleReader),
+		"namespace_role": int(RoleWriter),
+		"namespace":      testNamespace,
+		"api_name":       startWorkflowExecutionTarget.APIName,
+		"api_group":      "write",
+	}, OPAInput(claims, startWorkflowExecutionTarget))
+	require.Equal(t, "", OPAInput(nil, startWorkflowExecutionTarget)["subject"])
+}
+
+type failingRegoQuery struct{}
+
+func (q *failingRegoQuery) Eval(context.Context, map[string]interface{}) (bool, error) {
+	return false, errors.New("eval_conflict_error")
+}
@@ -0,0 +1,76 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+
+	"go.temporal.io/api/serviceerror"
+)
+
+type (
+	// OwnershipResolver looks up the subject that owns, i.e. started, a workflow.
+	// It returns serviceerror.NotFound if the workflow or its owner is not recorded.
+	OwnershipResolver interface {
+		GetWorkflowOwner(ctx context.Context, namespace string, workflowID string) (string, error)
+	}
+
+	ownershipAuthorizer struct {
+		authorizer Authorizer
+		resolver   OwnershipResolver
+	}
+)
+
+var _ Authorizer = (*ownershipAuthorizer)(nil)
+
+// NewOwnershipAuthorizer creates an authorizer that allows a call to a workflow-scoped API, i.e. an API
+// whose CallTarget has a WorkflowID, only if authorizer allows it and the caller is the owner of the workflow.
+// Calls to workflows without a recorded owner, e.g. workflows being started, are decided by authorizer alone.
+// Delegated calls must be made on behalf of the owner.
+func NewOwnershipAuthorizer(authorizer Authorizer, resolver OwnershipResolver) Authorizer {
+	return &ownershipAuthorizer{
+		authorizer: authorizer,
+		resolver:   resolver,
+	}
+}
+
+func (a *ownershipAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	result, err := a.authorizer.Authorize(ctx, claims, target)
+	if err != nil || result.Decision != DecisionAllow || target.WorkflowID == "" {
+		return result, err
+	}
+
+	owner, err := a.resolver.GetWorkflowOwner(ctx, target.Namespace, target.WorkflowID)
+	if err != nil {
+		if _, notFound := err.(*serviceerror.NotFound); notFound {
+			return result, nil
+		}
+		return Result{}, err
+	}
+	if claims == nil || claims.EffectiveClaims().Subject != owner {
+		return Result{Decision: DecisionDeny, Reason: ReasonNotOwner}, nil
+	}
+	return result, nil
+}
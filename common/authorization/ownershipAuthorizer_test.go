@@ -0,0 +1,151 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.temporal.io/api/serviceerror"
+)
+
+type (
+	ownershipAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		mockAuthorizer *MockAuthorizer
+		authorizer     Authorizer
+	}
+
+	testOwnershipResolver map[string]string
+)
+
+var (
+	workflowTarget = &CallTarget{Namespace: testNamespace, WorkflowID: "wid", APIName: "API"}
+)
+
+func TestOwnershipAuthorizerSuite(t *testing.T) {
+	s := new(ownershipAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *ownershipAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.authorizer = NewOwnershipAuthorizer(s.mockAuthorizer, testOwnershipResolver{"wid": testSubject, "broken": ""})
+}
+
+func (s *ownershipAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *ownershipAuthorizerSuite) TestMatchingOwner() {
+	claims := &Claims{Subject: testSubject}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, workflowTarget).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, workflowTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *ownershipAuthorizerSuite) TestMismatchingOwner() {
+	claims := &Claims{Subject: "other", System: RoleAdmin}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, workflowTarget).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, workflowTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonNotOwner, result.Reason)
+}
+
+func (s *ownershipAuthorizerSuite) TestDelegatedCall() {
+	onBehalfOfOwner := &Claims{Subject: "gateway", OnBehalfOf: &Claims{Subject: testSubject}}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, onBehalfOfOwner, workflowTarget).Return(Result{Decision: DecisionAllow}, nil)
+	result, err := s.authorizer.Authorize(ctx, onBehalfOfOwner, workflowTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+
+	// an actor that owns the workflow can't act on it on behalf of others
+	onBehalfOfOther := &Claims{Subject: testSubject, OnBehalfOf: &Claims{Subject: "other"}}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, onBehalfOfOther, workflowTarget).Return(Result{Decision: DecisionAllow}, nil)
+	result, err = s.authorizer.Authorize(ctx, onBehalfOfOther, workflowTarget)
+	s.NoError(err)
+	s.Equal(ReasonNotOwner, result.Reason)
+}
+
+func (s *ownershipAuthorizerSuite) TestWorkflowNotFound() {
+	claims := &Claims{Subject: testSubject}
+	target := &CallTarget{Namespace: testNamespace, WorkflowID: "unknown", APIName: "API"}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, target).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, target)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *ownershipAuthorizerSuite) TestResolverError() {
+	claims := &Claims{Subject: testSubject}
+	target := &CallTarget{Namespace: testNamespace, WorkflowID: "broken", APIName: "API"}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, target).Return(Result{Decision: DecisionAllow}, nil)
+
+	_, err := s.authorizer.Authorize(ctx, claims, target)
+	s.Error(err)
+}
+
+func (s *ownershipAuthorizerSuite) TestNotWorkflowScoped() {
+	claims := &Claims{Subject: "other"}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, describeNamespaceTarget).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *ownershipAuthorizerSuite) TestDeniedByAuthorizer() {
+	claims := &Claims{Subject: testSubject}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, workflowTarget).Return(Result{Decision: DecisionDeny}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, workflowTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+}
+
+func (r testOwnershipResolver) GetWorkflowOwner(_ context.Context, _ string, workflowID string) (string, error) {
+	owner, ok := r[workflowID]
+	if !ok {
+		return "", serviceerror.NewNotFound("workflow not found")
+	}
+	if owner == "" {
+		return "", errors.New("store unavailable")
+	}
+	return owner, nil
+}
@@ -0,0 +1,176 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	permissionBitmapAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller      *gomock.Controller
+		mockClaimMapper *MockClaimMapper
+		authorizer      PermissionBitmapAuthorizer
+		claimMapper     ClaimMapper
+	}
+)
+
+func TestPermissionBitmapAuthorizerSuite(t *testing.T) {
+	s := new(permissionBitmapAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *permissionBitmapAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockClaimMapper = NewMockClaimMapper(s.controller)
+	s.authorizer = NewPermissionBitmapAuthorizer(map[Role][]APIGroup{
+		RoleReader: {APIGroupRead},
+		RoleWriter: {APIGroupRead, APIGroupWrite},
+		RoleAdmin:  {APIGroupRead, APIGroupWrite, APIGroupAdmin},
+	})
+	s.claimMapper = s.authorizer.WrapClaimMapper(s.mockClaimMapper)
+}
+
+func (s *permissionBitmapAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *permissionBitmapAuthorizerSuite) TestClaimMapperPrecomputesBitmaps() {
+	mapped := &Claims{Subject: testSubject, System: RoleReader, Namespaces: map[string]Role{testNamespace: RoleWriter}}
+	s.mockClaimMapper.EXPECT().GetClaims(ctx, gomock.Any()).Return(mapped, nil)
+
+	claims, err := s.claimMapper.GetClaims(ctx, &AuthInfo{AuthToken: "token"})
+	s.NoError(err)
+	s.Equal(permissionBitmapRead, claims.PermissionBitmaps.System)
+	s.Equal(map[string]PermissionBitmap{testNamespace: permissionBitmapRead | permissionBitmapWrite},
+		claims.PermissionBitmaps.Namespaces)
+	s.Nil(mapped.PermissionBitmaps, "claims of the wrapped claim mapper are not modified")
+
+	s.assertDecision(DecisionAllow, claims, startWorkflowExecutionTarget)
+	s.assertDecision(DecisionDeny, claims, &CallTarget{APIName: startWorkflowExecutionTarget.APIName, APIGroup: APIGroupWrite, Namespace: "other"})
+	// system readers only read system level APIs, not every namespace
+	s.assertDecision(DecisionDeny, claims, &CallTarget{APIName: describeNamespaceTarget.APIName, APIGroup: APIGroupRead, Namespace: "other"})
+	s.assertDecision(DecisionAllow, claims, &CallTarget{APIName: workflowServicePrefix + "ListNamespaces", APIGroup: APIGroupRead})
+	s.assertDecision(DecisionDeny, claims, &CallTarget{APIName: workflowServicePrefix + "UpdateNamespace", APIGroup: APIGroupAdmin, Namespace: testNamespace})
+}
+
+func (s *permissionBitmapAuthorizerSuite) TestClaimMapperPrecomputesBitmapsOfEndUser() {
+	mapped := &Claims{Subject: "gateway", System: RoleAdmin, OnBehalfOf: &Claims{Subject: testSubject, Namespaces: map[string]Role{testNamespace: RoleReader}}}
+	s.mockClaimMapper.EXPECT().GetClaims(ctx, gomock.Any()).Return(mapped, nil)
+
+	claims, err := s.claimMapper.GetClaims(ctx, &AuthInfo{AuthToken: "token"})
+	s.NoError(err)
+	s.assertDecision(DecisionAllow, claims, describeNamespaceTarget)
+	s.assertDecision(DecisionDeny, claims, startWorkflowExecutionTarget)
+}
+
+func (s *permissionBitmapAuthorizerSuite) TestClaimMapperError() {
+	s.mockClaimMapper.EXPECT().GetClaims(ctx, gomock.Any()).Return(nil, errTestInvalidToken)
+
+	claims, err := s.claimMapper.GetClaims(ctx, &AuthInfo{AuthToken: "token"})
+	s.Equal(errTestInvalidToken, err)
+	s.Nil(claims)
+}
+
+func (s *permissionBitmapAuthorizerSuite) TestClaimsWithoutBitmaps() {
+	s.assertDecision(DecisionAllow, &Claims{System: RoleAdmin}, startWorkflowExecutionTarget)
+	s.assertDecision(DecisionDeny, &Claims{Namespaces: map[string]Role{testNamespace: RoleReader}}, startWorkflowExecutionTarget)
+
+	result, err := s.authorizer.Authorize(ctx, nil, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(ReasonNoClaims, result.Reason)
+}
+
+func (s *permissionBitmapAuthorizerSuite) TestBitmapsOfOtherAuthorizerIgnored() {
+	other := NewPermissionBitmapAuthorizer(map[Role][]APIGroup{RoleReader: {APIGroupWrite}})
+	s.mockClaimMapper.EXPECT().GetClaims(ctx, gomock.Any()).Return(&Claims{Namespaces: map[string]Role{testNamespace: RoleReader}}, nil)
+	claims, err := other.WrapClaimMapper(s.mockClaimMapper).GetClaims(ctx, &AuthInfo{AuthToken: "token"})
+	s.NoError(err)
+
+	s.assertDecision(DecisionDeny, claims, startWorkflowExecutionTarget)
+	s.assertDecision(DecisionAllow, claims, describeNamespaceTarget)
+}
+
+func (s *permissionBitmapAuthorizerSuite) TestCrossCheckWithStaticAuthorizer() {
+	random := rand.New(rand.NewSource(1))
+	groups := []APIGroup{APIGroupRead, APIGroupWrite, APIGroupAdmin}
+	roles := []Role{RoleReader, RoleWriter, RoleWorker, RoleAdmin, RoleImpersonator, RoleReader | RoleWorker}
+	apiNames := append([]string{"/other.Service/Method"}, WorkflowServiceAPIs...)
+
+	for i := 0; i < 20; i++ {
+		groupPolicies := make(map[Role][]APIGroup)
+		staticPolicies := make(map[Role][]string)
+		for _, role := range roles {
+			for j := random.Intn(3); j > 0; j-- {
+				group := groups[random.Intn(len(groups))]
+				groupPolicies[role] = append(groupPolicies[role], group)
+				staticPolicies[role] = append(staticPolicies[role], apiGroupPrefix+string(group))
+			}
+		}
+		bitmap := NewPermissionBitmapAuthorizer(groupPolicies)
+		static := NewStaticAuthorizer(staticPolicies)
+		claimMapper := NewMockClaimMapper(s.controller)
+
+		for j := 0; j < 50; j++ {
+			claims := &Claims{
+				System:     Role(random.Intn(32)),
+				Namespaces: map[string]Role{testNamespace: Role(random.Intn(32))},
+			}
+			claimMapper.EXPECT().GetClaims(ctx, gomock.Any()).Return(claims, nil)
+			withBitmaps, err := bitmap.WrapClaimMapper(claimMapper).GetClaims(ctx, &AuthInfo{AuthToken: "token"})
+			s.NoError(err)
+
+			for _, apiName := range apiNames {
+				for _, namespace := range []string{testNamespace, "other"} {
+					target := &CallTarget{APIName: apiName, APIGroup: GetAPIGroup(apiName), Namespace: namespace}
+					expected, err := static.Authorize(ctx, claims, target)
+					s.NoError(err)
+					actual, err := bitmap.Authorize(ctx, withBitmaps, target)
+					s.NoError(err)
+					s.Equal(expected, actual, "policies %v, claims %v, API %v", groupPolicies, claims, apiName)
+					actual, err = bitmap.Authorize(ctx, claims, target)
+					s.NoError(err)
+					s.Equal(expected, actual, "policies %v, claims %v, API %v", groupPolicies, claims, apiName)
+				}
+			}
+		}
+	}
+}
+
+func (s *permissionBitmapAuthorizerSuite) assertDecision(expected Decision, claims *Claims, target *CallTarget) {
+	result, err := s.authorizer.Authorize(context.Background(), claims, target)
+	s.NoError(err)
+	s.Equal(expected, result.Decision)
+}
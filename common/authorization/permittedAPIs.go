@@ -0,0 +1,144 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+)
+
+const (
+	permittedAPIsNamespaceParam = "namespace"
+)
+
+// ErrNotSupported is returned by PermittedAPIs for authorizers that can't enumerate the APIs they allow
+var ErrNotSupported = errors.New("authorizer does not support enumerating permitted APIs")
+
+type (
+	// APIEnumerator is implemented by authorizers that can list the APIs a subject is allowed to call,
+	// so that clients such as the UI can disable actions up front
+	APIEnumerator interface {
+		// PermittedAPIs returns the full names of the APIs the subject is allowed to call in the namespace.
+		// An empty namespace lists the APIs that don't target a namespace.
+		PermittedAPIs(ctx context.Context, claims *Claims, namespace string) ([]string, error)
+	}
+
+	// PermittedAPIsResponse is the body of the responses of the handler created by NewPermittedAPIsHandler
+	PermittedAPIsResponse struct {
+		Namespace string   `json:"namespace"`
+		APIs      []string `json:"apis"`
+	}
+
+	permittedAPIsHandler struct {
+		claimMapper ClaimMapper
+		authorizer  Authorizer
+		logger      log.Logger
+	}
+)
+
+// PermittedAPIs returns the APIs the subject is allowed to call in the namespace,
+// or ErrNotSupported if authorizer doesn't implement APIEnumerator
+func PermittedAPIs(ctx context.Context, authorizer Authorizer, claims *Claims, namespace string) ([]string, error) {
+	enumerator, ok := authorizer.(APIEnumerator)
+	if !ok {
+		return nil, ErrNotSupported
+	}
+	return enumerator.PermittedAPIs(ctx, claims, namespace)
+}
+
+// NewPermittedAPIsHandler creates a diagnostic HTTP handler that responds with the APIs the caller is allowed
+// to call in the namespace given by the "namespace" query parameter, as a JSON encoded PermittedAPIsResponse.
+// The caller's claims are mapped by claimMapper from the Authorization headers and the client certificate
+// of the request. Responds with 501 Not Implemented if authorizer can't enumerate APIs.
+func NewPermittedAPIsHandler(claimMapper ClaimMapper, authorizer Authorizer, logger log.Logger) http.Handler {
+	return &permittedAPIsHandler{
+		claimMapper: claimMapper,
+		authorizer:  authorizer,
+		logger:      logger,
+	}
+}
+
+func (h *permittedAPIsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	var claims *Claims
+	if authInfo := newAuthInfoFromHTTPRequest(r); authInfo != nil {
+		mappedClaims, err := h.claimMapper.GetClaims(r.Context(), authInfo)
+		if err != nil {
+			h.logger.Error("authorization error", tag.Error(err))
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		claims = mappedClaims
+	}
+
+	namespace := r.URL.Query().Get(permittedAPIsNamespaceParam)
+	apis, err := PermittedAPIs(r.Context(), h.authorizer, claims, namespace)
+	if err == ErrNotSupported {
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to enumerate permitted APIs", tag.WorkflowNamespace(namespace), tag.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if apis == nil {
+		apis = []string{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(PermittedAPIsResponse{Namespace: namespace, APIs: apis}); err != nil {
+		h.logger.Warn("failed to write permitted APIs response", tag.Error(err))
+	}
+}
+
+// newAuthInfoFromHTTPRequest extracts the auth info of the caller from the request,
+// returning nil if it has neither an Authorization header nor a verified client certificate
+func newAuthInfoFromHTTPRequest(r *http.Request) *AuthInfo {
+	var tlsSubject *pkix.Name
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+		tlsSubject = &r.TLS.VerifiedChains[0][0].Subject
+	}
+	authToken := r.Header.Get("Authorization")
+	if tlsSubject == nil && authToken == "" {
+		return nil
+	}
+	return &AuthInfo{
+		AuthToken:   authToken,
+		TLSSubject:  tlsSubject,
+		ExtraData:   r.Header.Get("Authorization-Extras"),
+		PeerAddress: r.RemoteAddr,
+	}
+}
@@ -0,0 +1,120 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"go.temporal.io/server/common/log"
+)
+
+type (
+	permittedAPIsSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller      *gomock.Controller
+		mockClaimMapper *MockClaimMapper
+		authorizer      StaticAuthorizer
+	}
+)
+
+func TestPermittedAPIsSuite(t *testing.T) {
+	s := new(permittedAPIsSuite)
+	suite.Run(t, s)
+}
+
+func (s *permittedAPIsSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockClaimMapper = NewMockClaimMapper(s.controller)
+	s.authorizer = NewStaticAuthorizer(map[Role][]string{
+		RoleReader: {describeNamespaceTarget.APIName},
+		RoleWriter: {startWorkflowExecutionTarget.APIName},
+	})
+}
+
+func (s *permittedAPIsSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *permittedAPIsSuite) TestPermittedAPIsNotSupported() {
+	apis, err := PermittedAPIs(ctx, NewDefaultAuthorizer(), &Claims{System: RoleAdmin}, testNamespace)
+	s.Equal(ErrNotSupported, err)
+	s.Nil(apis)
+}
+
+func (s *permittedAPIsSuite) TestHandler() {
+	s.mockClaimMapper.EXPECT().GetClaims(gomock.Any(), &AuthInfo{AuthToken: "Bearer token", PeerAddress: "192.0.2.1:1234"}).
+		Return(&Claims{Namespaces: map[string]Role{testNamespace: RoleReader | RoleWriter}}, nil)
+
+	response := s.serve(NewPermittedAPIsHandler(s.mockClaimMapper, s.authorizer, log.NewNoop()), "Bearer token")
+	s.Equal(http.StatusOK, response.Code)
+	s.Equal("application/json", response.Header().Get("Content-Type"))
+	var body PermittedAPIsResponse
+	s.NoError(json.Unmarshal(response.Body.Bytes(), &body))
+	s.Equal(testNamespace, body.Namespace)
+	s.ElementsMatch([]string{describeNamespaceTarget.APIName, startWorkflowExecutionTarget.APIName}, body.APIs)
+}
+
+func (s *permittedAPIsSuite) TestHandlerNoAuthInfo() {
+	response := s.serve(NewPermittedAPIsHandler(s.mockClaimMapper, s.authorizer, log.NewNoop()), "")
+	s.Equal(http.StatusOK, response.Code)
+	s.JSONEq(`{"namespace":"test-namespace","apis":[]}`, response.Body.String())
+}
+
+func (s *permittedAPIsSuite) TestHandlerNotSupported() {
+	s.mockClaimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(&Claims{System: RoleAdmin}, nil)
+
+	response := s.serve(NewPermittedAPIsHandler(s.mockClaimMapper, NewDefaultAuthorizer(), log.NewNoop()), "Bearer token")
+	s.Equal(http.StatusNotImplemented, response.Code)
+}
+
+func (s *permittedAPIsSuite) TestHandlerClaimMapperError() {
+	s.mockClaimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(nil, errors.New("invalid token"))
+
+	response := s.serve(NewPermittedAPIsHandler(s.mockClaimMapper, s.authorizer, log.NewNoop()), "Bearer token")
+	s.Equal(http.StatusForbidden, response.Code)
+	s.NotContains(response.Body.String(), "invalid token")
+}
+
+func (s *permittedAPIsSuite) serve(handler http.Handler, authorization string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, "/permitted-apis?namespace="+testNamespace, nil)
+	request.RemoteAddr = "192.0.2.1:1234"
+	if authorization != "" {
+		request.Header.Set("Authorization", authorization)
+	}
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	return response
+}
@@ -0,0 +1,121 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"sync"
+)
+
+type (
+	// ProgrammableAuthorizer is an in-memory Authorizer whose grants are changed at runtime, e.g. by integration tests
+	ProgrammableAuthorizer interface {
+		Authorizer
+		// Grant adds role to the roles of subject within namespace, or at the system level if namespace is empty
+		Grant(subject string, namespace string, role Role)
+		// Revoke removes role from the roles of subject within namespace, or at the system level if namespace is empty
+		Revoke(subject string, namespace string, role Role)
+	}
+
+	programmableAuthorizer struct {
+		authorizer Authorizer
+
+		sync.RWMutex
+		grants map[string]*Claims // by subject
+	}
+)
+
+var _ ProgrammableAuthorizer = (*programmableAuthorizer)(nil)
+
+// NewProgrammableAuthorizer creates a ProgrammableAuthorizer without grants. Calls are decided like the default
+// authorizer decides them for the subject of the caller holding the roles currently granted to it, the roles
+// of the caller's claims are ignored. It is safe for concurrent use, grants apply to calls authorized after them.
+func NewProgrammableAuthorizer() ProgrammableAuthorizer {
+	return &programmableAuthorizer{
+		authorizer: NewDefaultAuthorizer(),
+		grants:     make(map[string]*Claims),
+	}
+}
+
+func (a *programmableAuthorizer) Grant(subject string, namespace string, role Role) {
+	a.Lock()
+	defer a.Unlock()
+
+	grants, ok := a.grants[subject]
+	if !ok {
+		grants = &Claims{Subject: subject, Namespaces: make(map[string]Role)}
+		a.grants[subject] = grants
+	}
+	if namespace == "" {
+		grants.System |= role
+	} else {
+		grants.Namespaces[namespace] |= role
+	}
+}
+
+func (a *programmableAuthorizer) Revoke(subject string, namespace string, role Role) {
+	a.Lock()
+	defer a.Unlock()
+
+	grants, ok := a.grants[subject]
+	if !ok {
+		return
+	}
+	if namespace == "" {
+		grants.System &^= role
+	} else if remaining := grants.Namespaces[namespace] &^ role; remaining == RoleUndefined {
+		delete(grants.Namespaces, namespace)
+	} else {
+		grants.Namespaces[namespace] = remaining
+	}
+	if grants.System == RoleUndefined && len(grants.Namespaces) == 0 {
+		delete(a.grants, subject)
+	}
+}
+
+func (a *programmableAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if claims == nil {
+		return a.authorizer.Authorize(ctx, nil, target)
+	}
+	return a.authorizer.Authorize(ctx, a.grantedClaims(claims.Subject), target)
+}
+
+// grantedClaims returns a copy of the roles currently granted to subject
+func (a *programmableAuthorizer) grantedClaims(subject string) *Claims {
+	a.RLock()
+	defer a.RUnlock()
+
+	claims := &Claims{Subject: subject}
+	grants, ok := a.grants[subject]
+	if !ok {
+		return claims
+	}
+	claims.System = grants.System
+	claims.Namespaces = make(map[string]Role, len(grants.Namespaces))
+	for namespace, role := range grants.Namespaces {
+		claims.Namespaces[namespace] = role
+	}
+	return claims
+}
@@ -0,0 +1,142 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	programmableAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		authorizer ProgrammableAuthorizer
+	}
+)
+
+func TestProgrammableAuthorizerSuite(t *testing.T) {
+	s := new(programmableAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *programmableAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.authorizer = NewProgrammableAuthorizer()
+}
+
+func (s *programmableAuthorizerSuite) TestNoGrants() {
+	result, err := s.authorizer.Authorize(ctx, &Claims{Subject: testSubject, System: RoleAdmin}, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonInsufficientRole, result.Reason)
+}
+
+func (s *programmableAuthorizerSuite) TestNoClaims() {
+	s.authorizer.Grant(testSubject, testNamespace, RoleReader)
+
+	result, err := s.authorizer.Authorize(ctx, nil, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(ReasonNoClaims, result.Reason)
+}
+
+func (s *programmableAuthorizerSuite) TestGrantRevoke() {
+	claims := &Claims{Subject: testSubject}
+	s.authorizer.Grant(testSubject, testNamespace, RoleReader)
+	s.authorizer.Grant(testSubject, testNamespace, RoleWriter)
+
+	result, err := s.authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+
+	s.authorizer.Revoke(testSubject, testNamespace, RoleReader)
+	result, err = s.authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+
+	s.authorizer.Revoke(testSubject, testNamespace, RoleWriter)
+	result, err = s.authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+}
+
+func (s *programmableAuthorizerSuite) TestSystemGrant() {
+	claims := &Claims{Subject: testSubject}
+	s.authorizer.Grant(testSubject, "", RoleAdmin)
+
+	result, err := s.authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+
+	s.authorizer.Revoke(testSubject, "", RoleAdmin)
+	result, err = s.authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+}
+
+func (s *programmableAuthorizerSuite) TestGrantsOfOtherSubjects() {
+	s.authorizer.Grant("other", testNamespace, RoleAdmin)
+	s.authorizer.Revoke(testSubject, testNamespace, RoleAdmin)
+
+	result, err := s.authorizer.Authorize(ctx, &Claims{Subject: testSubject}, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+}
+
+func (s *programmableAuthorizerSuite) TestConcurrentGrantRevoke() {
+	const iterations = 1000
+	claims := &Claims{Subject: testSubject}
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < iterations; i++ {
+			s.authorizer.Grant(testSubject, testNamespace, RoleReader)
+			s.authorizer.Revoke(testSubject, testNamespace, RoleReader)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < iterations; i++ {
+			_, err := s.authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+			s.NoError(err)
+		}
+	}()
+	wg.Wait()
+
+	// the last revoke is visible once the writer is done
+	result, err := s.authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+
+	s.authorizer.Grant(testSubject, testNamespace, RoleReader)
+	result, err = s.authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
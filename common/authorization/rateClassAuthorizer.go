@@ -0,0 +1,152 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/quotas"
+)
+
+type (
+	// RateClass is the rate limit of a class of APIs in a namespace
+	RateClass struct {
+		// Rate is the number of calls per second
+		Rate float64
+		// Burst is the number of calls that can be made at once
+		Burst int
+	}
+
+	// RateClassConfig configures the authorizer created by NewRateClassAuthorizer
+	RateClassConfig struct {
+		// Classes maps API groups to their limits, calls to APIs of other groups are not limited.
+		// APIGroupUnknown limits the calls to unclassified APIs.
+		Classes map[APIGroup]RateClass
+		// MaxNamespaces is the number of namespaces whose limits are tracked: at most MaxNamespaces limiters
+		// of each class are held, the least recently called are evicted first. Zero disables limiting.
+		MaxNamespaces int
+	}
+
+	rateClassAuthorizer struct {
+		authorizer    Authorizer
+		config        RateClassConfig
+		metricsClient metrics.Client
+		timeSource    clock.TimeSource
+
+		sync.Mutex
+		classes map[APIGroup]*rateClassLimiters
+	}
+
+	// rateClassLimiters are the limiters of the namespaces of a class, bounded separately from other classes
+	// so that calls of one class can't evict the limiters of another
+	rateClassLimiters struct {
+		limiters map[string]*list.Element // values are *rateClassLimiter
+		byAccess *list.List               // most recently called first
+	}
+
+	rateClassLimiter struct {
+		namespace string
+		limiter   quotas.RateLimiter
+	}
+)
+
+var _ Authorizer = (*rateClassAuthorizer)(nil)
+
+// NewRateClassAuthorizer creates an authorizer that limits the rate of calls to each namespace separately
+// for each API group, e.g. reads, writes and admin calls, so that a flood of calls of one class does not
+// starve the others. Calls are decided by authorizer, and only the calls it allows are counted against
+// the limit, so callers that are denied can neither spend the budget of a namespace nor make the authorizer
+// track made up namespaces. Allowed calls over the limit fail with a ResourceExhausted error.
+// The limits of namespaces evicted to stay within MaxNamespaces start over with a full burst, the limiters of each
+// class are evicted separately, so a flood of calls of one class to many namespaces can't reset another class.
+func NewRateClassAuthorizer(
+	authorizer Authorizer,
+	config RateClassConfig,
+	metricsClient metrics.Client,
+	timeSource clock.TimeSource,
+) Authorizer {
+	classes := make(map[APIGroup]*rateClassLimiters, len(config.Classes))
+	for group := range config.Classes {
+		classes[group] = &rateClassLimiters{
+			limiters: make(map[string]*list.Element),
+			byAccess: list.New(),
+		}
+	}
+	return &rateClassAuthorizer{
+		authorizer:    authorizer,
+		config:        config,
+		metricsClient: metricsClient,
+		timeSource:    timeSource,
+		classes:       classes,
+	}
+}
+
+func (a *rateClassAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	group := target.APIGroup
+	if group == "" {
+		group = GetAPIGroup(target.APIName)
+	}
+	class, limited := a.config.Classes[group]
+	if !limited || target.Namespace == "" || a.config.MaxNamespaces <= 0 {
+		return a.authorizer.Authorize(ctx, claims, target)
+	}
+
+	result, err := a.authorizer.Authorize(ctx, claims, target)
+	if err != nil || result.Decision != DecisionAllow {
+		return result, err
+	}
+	limiter := a.limiter(group, target.Namespace, class)
+	if !limiter.AllowN(a.timeSource.Now(), 1) {
+		a.metricsClient.IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationRateClassExhaustedCounter)
+		return NewDenyResult(NewDenyReason(ReasonRateClassExhausted,
+			fmt.Sprintf("Rate limit of %s calls to namespace %s exceeded.", group, target.Namespace))), nil
+	}
+	return result, nil
+}
+
+// limiter returns the rate limiter of the class of group in namespace, creating it on the first allowed call
+// and evicting the least recently called limiter of the class once it tracks MaxNamespaces namespaces
+func (a *rateClassAuthorizer) limiter(group APIGroup, namespace string, class RateClass) quotas.RateLimiter {
+	a.Lock()
+	defer a.Unlock()
+
+	limiters := a.classes[group]
+	if element, ok := limiters.limiters[namespace]; ok {
+		limiters.byAccess.MoveToFront(element)
+		return element.Value.(*rateClassLimiter).limiter
+	}
+	if len(limiters.limiters) >= a.config.MaxNamespaces {
+		oldest := limiters.byAccess.Remove(limiters.byAccess.Back()).(*rateClassLimiter)
+		delete(limiters.limiters, oldest.namespace)
+	}
+	limiter := quotas.NewRateLimiter(class.Rate, class.Burst)
+	limiters.limiters[namespace] = limiters.byAccess.PushFront(&rateClassLimiter{namespace: namespace, limiter: limiter})
+	return limiter
+}
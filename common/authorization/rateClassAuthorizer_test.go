@@ -0,0 +1,192 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/metrics"
+)
+
+type (
+	rateClassAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller        *gomock.Controller
+		mockMetricsClient *metrics.MockClient
+		timeSource        *clock.EventTimeSource
+		config            RateClassConfig
+		authorizer        Authorizer
+	}
+)
+
+func TestRateClassAuthorizerSuite(t *testing.T) {
+	s := new(rateClassAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *rateClassAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockMetricsClient = metrics.NewMockClient(s.controller)
+	s.timeSource = clock.NewEventTimeSource().Update(time.Unix(0, 0))
+	s.config = RateClassConfig{
+		Classes: map[APIGroup]RateClass{
+			APIGroupRead:  {Rate: 10, Burst: 10},
+			APIGroupWrite: {Rate: 1, Burst: 2},
+		},
+		MaxNamespaces: 10,
+	}
+	s.authorizer = NewRateClassAuthorizer(NewNoopAuthorizer(), s.config, s.mockMetricsClient, s.timeSource)
+}
+
+func (s *rateClassAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *rateClassAuthorizerSuite) TestExhaustOneClass() {
+	for i := 0; i < 2; i++ {
+		s.assertDecision(startWorkflowExecutionTarget, DecisionAllow)
+	}
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationRateClassExhaustedCounter)
+	result := s.assertDecision(startWorkflowExecutionTarget, DecisionDeny)
+	s.Equal(NewDenyResult(NewDenyReason(ReasonRateClassExhausted,
+		"Rate limit of write calls to namespace test-namespace exceeded.")), result)
+
+	// reads have their own budget
+	for i := 0; i < 10; i++ {
+		s.assertDecision(describeNamespaceTarget, DecisionAllow)
+	}
+}
+
+func (s *rateClassAuthorizerSuite) TestNamespacesLimitedSeparately() {
+	for i := 0; i < 2; i++ {
+		s.assertDecision(startWorkflowExecutionTarget, DecisionAllow)
+	}
+	other := *startWorkflowExecutionTarget
+	other.Namespace = "other-namespace"
+	s.assertDecision(&other, DecisionAllow)
+}
+
+func (s *rateClassAuthorizerSuite) TestBudgetReplenished() {
+	for i := 0; i < 2; i++ {
+		s.assertDecision(startWorkflowExecutionTarget, DecisionAllow)
+	}
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationRateClassExhaustedCounter)
+	s.assertDecision(startWorkflowExecutionTarget, DecisionDeny)
+
+	s.timeSource.Update(time.Unix(1, 0))
+	s.assertDecision(startWorkflowExecutionTarget, DecisionAllow)
+}
+
+func (s *rateClassAuthorizerSuite) TestUnlimitedClass() {
+	target := &CallTarget{Namespace: testNamespace, APIName: workflowServicePrefix + "RegisterNamespace", APIGroup: APIGroupAdmin}
+	for i := 0; i < 20; i++ {
+		s.assertDecision(target, DecisionAllow)
+	}
+}
+
+func (s *rateClassAuthorizerSuite) TestDeniedCallsNotCounted() {
+	// only the caller's own namespace is granted
+	s.authorizer = NewRateClassAuthorizer(NewStaticAuthorizer(map[Role][]string{
+		RoleWriter: {apiGroupPrefix + string(APIGroupWrite)},
+	}), s.config, s.mockMetricsClient, s.timeSource)
+	writer := &Claims{Subject: testSubject, Namespaces: map[string]Role{testNamespace: RoleWriter}}
+	other := &Claims{Subject: "other", Namespaces: map[string]Role{"other-namespace": RoleWriter}}
+
+	for i := 0; i < 10; i++ {
+		result, err := s.authorizer.Authorize(ctx, other, startWorkflowExecutionTarget)
+		s.NoError(err)
+		s.Equal(ReasonInsufficientRole, result.Reason)
+		result, err = s.authorizer.Authorize(ctx, nil, startWorkflowExecutionTarget)
+		s.NoError(err)
+		s.Equal(ReasonNoClaims, result.Reason)
+	}
+	for _, limiters := range s.authorizer.(*rateClassAuthorizer).classes {
+		s.Empty(limiters.limiters)
+	}
+
+	for i := 0; i < 2; i++ {
+		result, err := s.authorizer.Authorize(ctx, writer, startWorkflowExecutionTarget)
+		s.NoError(err)
+		s.Equal(DecisionAllow, result.Decision)
+	}
+}
+
+func (s *rateClassAuthorizerSuite) TestMaxNamespaces() {
+	s.config.MaxNamespaces = 2
+	authorizer := NewRateClassAuthorizer(NewNoopAuthorizer(), s.config, s.mockMetricsClient, s.timeSource).(*rateClassAuthorizer)
+	for i := 0; i < 10; i++ {
+		target := *startWorkflowExecutionTarget
+		target.Namespace = fmt.Sprintf("namespace-%d", i)
+		result, err := authorizer.Authorize(ctx, nil, &target)
+		s.NoError(err)
+		s.Equal(DecisionAllow, result.Decision)
+	}
+	writes := authorizer.classes[APIGroupWrite]
+	s.Len(writes.limiters, 2)
+	s.Equal(2, writes.byAccess.Len())
+	s.NotContains(writes.limiters, "namespace-0")
+	s.Contains(writes.limiters, "namespace-9")
+}
+
+func (s *rateClassAuthorizerSuite) TestReadChurnKeepsWriteBudget() {
+	s.config.MaxNamespaces = 2
+	authorizer := NewRateClassAuthorizer(NewNoopAuthorizer(), s.config, s.mockMetricsClient, s.timeSource)
+	for i := 0; i < 2; i++ {
+		result, err := authorizer.Authorize(ctx, nil, startWorkflowExecutionTarget)
+		s.NoError(err)
+		s.Equal(DecisionAllow, result.Decision)
+	}
+
+	// reads to many namespaces don't evict the exhausted write limiter
+	for i := 0; i < 10; i++ {
+		target := *describeNamespaceTarget
+		target.Namespace = fmt.Sprintf("namespace-%d", i)
+		result, err := authorizer.Authorize(ctx, nil, &target)
+		s.NoError(err)
+		s.Equal(DecisionAllow, result.Decision)
+	}
+
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceAuthorizationRateClassExhaustedCounter)
+	result, err := authorizer.Authorize(ctx, nil, startWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(ReasonRateClassExhausted, result.Reason)
+}
+
+func (s *rateClassAuthorizerSuite) assertDecision(target *CallTarget, decision Decision) Result {
+	result, err := s.authorizer.Authorize(ctx, nil, target)
+	s.NoError(err)
+	s.Equal(decision, result.Decision)
+	return result
+}
@@ -0,0 +1,158 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+const (
+	// ReasonUnspecified is used when an authorizer doesn't explain its decision
+	ReasonUnspecified ReasonCode = ""
+	// ReasonNoClaims means the caller presented no credentials
+	ReasonNoClaims ReasonCode = "no_claims"
+	// ReasonInsufficientRole means the caller's roles don't permit the call
+	ReasonInsufficientRole ReasonCode = "insufficient_role"
+	// ReasonCostExceeded means the request is more expensive than the caller's budget
+	ReasonCostExceeded ReasonCode = "cost_exceeded"
+	// ReasonAnonymous means the API is not accessible to callers without credentials
+	ReasonAnonymous ReasonCode = "anonymous"
+	// ReasonNotOwner means the caller is not the owner of the target workflow
+	ReasonNotOwner ReasonCode = "not_owner"
+	// ReasonMFARequired means the API requires the caller to be authenticated with multiple factors
+	ReasonMFARequired ReasonCode = "mfa_required"
+	// ReasonMaintenance means the cluster is in read-only mode and the API is mutating
+	ReasonMaintenance ReasonCode = "maintenance"
+	// ReasonInvalidNonce means the request nonce is missing, incorrectly signed or not fresh
+	ReasonInvalidNonce ReasonCode = "invalid_nonce"
+	// ReasonReplayedNonce means the request nonce was already used
+	ReasonReplayedNonce ReasonCode = "replayed_nonce"
+	// ReasonTLSRequired means the API requires a TLS connection of at least a minimum version
+	ReasonTLSRequired ReasonCode = "tls_required"
+	// ReasonWorkflowPairing means the calling workflow is not allowed to call the target workflow
+	ReasonWorkflowPairing ReasonCode = "workflow_pairing"
+	// ReasonNamespaceFanOut means the subject accessed too many distinct namespaces within a short time
+	ReasonNamespaceFanOut ReasonCode = "namespace_fan_out"
+	// ReasonClientVersion means the client is older than the minimum version allowed, or unknown
+	ReasonClientVersion ReasonCode = "client_version"
+	// ReasonUntrustedActor means the caller is not allowed to act on behalf of other subjects
+	ReasonUntrustedActor ReasonCode = "untrusted_actor"
+	// ReasonBudgetExhausted means the caller spent its cost budget for the current window
+	ReasonBudgetExhausted ReasonCode = "budget_exhausted"
+	// ReasonApprovalRequired means the API requires an unexpired approval of the call, and there is none
+	ReasonApprovalRequired ReasonCode = "approval_required"
+	// ReasonNotClusterMember means the caller of a replication API is not identified as a member cluster
+	ReasonNotClusterMember ReasonCode = "not_cluster_member"
+	// ReasonAudienceMismatch means the caller's token was not issued for the target namespace or the cluster
+	ReasonAudienceMismatch ReasonCode = "audience_mismatch"
+	// ReasonFeatureDisabled means the API is gated by a feature flag that is off
+	ReasonFeatureDisabled ReasonCode = "feature_disabled"
+	// ReasonRequestLimit means a parameter of the request, such as the execution timeout, exceeds the limit of the caller's role
+	ReasonRequestLimit ReasonCode = "request_limit"
+	// ReasonMissingJustification means the call lacks a required metadata header, such as a ticket, or its value is invalid
+	ReasonMissingJustification ReasonCode = "missing_justification"
+	// ReasonDataResidency means the caller is not authorized for the region the data of the namespace resides in
+	ReasonDataResidency ReasonCode = "data_residency"
+	// ReasonCrossTenant means the caller belongs to another tenant than the target namespace
+	ReasonCrossTenant ReasonCode = "cross_tenant"
+	// ReasonRestrictedSearchAttribute means the call sets or queries a search attribute restricted
+	// to roles the caller doesn't hold
+	ReasonRestrictedSearchAttribute ReasonCode = "restricted_search_attribute"
+	// ReasonTierInsufficient means the API requires a higher quota tier than the tier of the target namespace
+	ReasonTierInsufficient ReasonCode = "tier_insufficient"
+	// ReasonTokenRevoked means the token the caller presented was revoked
+	ReasonTokenRevoked ReasonCode = "token_revoked"
+	// ReasonDenyCooldown means the subject was denied too many times within a short time, and is in a cooldown
+	// during which all its calls are denied
+	ReasonDenyCooldown ReasonCode = "deny_cooldown"
+	// ReasonNamespaceNotActive means the API is mutating and the target namespace is not active in the current cluster
+	ReasonNamespaceNotActive ReasonCode = "namespace_not_active"
+	// ReasonMemoMismatch means the call sets a memo field to a value inconsistent with the caller's claims
+	ReasonMemoMismatch ReasonCode = "memo_mismatch"
+	// ReasonInsufficientAssurance means the API requires a higher level of assurance than the caller was authenticated with
+	ReasonInsufficientAssurance ReasonCode = "insufficient_assurance"
+	// ReasonAPIDeprecated means the API is deprecated and calls to it are rejected
+	ReasonAPIDeprecated ReasonCode = "api_deprecated"
+	// ReasonRateClassExhausted means the rate limit of the class of the API in the target namespace is exhausted
+	ReasonRateClassExhausted ReasonCode = "rate_class_exhausted"
+	// ReasonUnclassifiedAPI means no entry of the policy matches the API, see DecisionUnknown
+	ReasonUnclassifiedAPI ReasonCode = "unclassified_api"
+	// ReasonContentNotExtracted means the authorizer checks the content of requests, but it wasn't extracted
+	ReasonContentNotExtracted ReasonCode = "content_not_extracted"
+	// ReasonNamespaceLabelsNotResolved means the authorizer checks the labels of the target namespace, but they weren't resolved
+	ReasonNamespaceLabelsNotResolved ReasonCode = "namespace_labels_not_resolved"
+)
+
+const (
+	reasonTagValueUnspecified = "unspecified"
+	reasonTagValueOther       = "other"
+)
+
+// knownReasonCodes bounds the values of the deny reason metric tag
+var knownReasonCodes = map[ReasonCode]struct{}{
+	ReasonNoClaims:                   {},
+	ReasonInsufficientRole:           {},
+	ReasonCostExceeded:               {},
+	ReasonAnonymous:                  {},
+	ReasonNotOwner:                   {},
+	ReasonMFARequired:                {},
+	ReasonMaintenance:                {},
+	ReasonInvalidNonce:               {},
+	ReasonReplayedNonce:              {},
+	ReasonTLSRequired:                {},
+	ReasonWorkflowPairing:            {},
+	ReasonNamespaceFanOut:            {},
+	ReasonClientVersion:              {},
+	ReasonUntrustedActor:             {},
+	ReasonBudgetExhausted:            {},
+	ReasonApprovalRequired:           {},
+	ReasonNotClusterMember:           {},
+	ReasonAudienceMismatch:           {},
+	ReasonFeatureDisabled:            {},
+	ReasonRequestLimit:               {},
+	ReasonMissingJustification:       {},
+	ReasonDataResidency:              {},
+	ReasonCrossTenant:                {},
+	ReasonRestrictedSearchAttribute:  {},
+	ReasonTierInsufficient:           {},
+	ReasonTokenRevoked:               {},
+	ReasonDenyCooldown:               {},
+	ReasonNamespaceNotActive:         {},
+	ReasonMemoMismatch:               {},
+	ReasonInsufficientAssurance:      {},
+	ReasonAPIDeprecated:              {},
+	ReasonRateClassExhausted:         {},
+	ReasonUnclassifiedAPI:            {},
+	ReasonContentNotExtracted:        {},
+	ReasonNamespaceLabelsNotResolved: {},
+}
+
+// metricTagValue returns the value of the reason metric tag for the reason code.
+// Codes unknown to this package, e.g. defined by custom authorizers, are all reported as "other".
+func (r ReasonCode) metricTagValue() string {
+	if r == ReasonUnspecified {
+		return reasonTagValueUnspecified
+	}
+	if _, ok := knownReasonCodes[r]; !ok {
+		return reasonTagValueOther
+	}
+	return string(r)
+}
@@ -0,0 +1,127 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+)
+
+const (
+	recentDecisionsSubjectParam = "subject"
+)
+
+type (
+	// RecentDecisionsResponse is the body of the responses of the handler created by NewRecentDecisionsHandler
+	RecentDecisionsResponse struct {
+		Subject   string           `json:"subject"`
+		Decisions []RecentDecision `json:"decisions"`
+	}
+
+	// RecentDecision is the JSON encoding of an AuthorizationEvent
+	RecentDecision struct {
+		Time       time.Time `json:"time"`
+		APIName    string    `json:"apiName"`
+		Namespace  string    `json:"namespace,omitempty"`
+		WorkflowID string    `json:"workflowId,omitempty"`
+		RunID      string    `json:"runId,omitempty"`
+		Decision   string    `json:"decision"`
+		Reason     string    `json:"reason,omitempty"`
+		Error      string    `json:"error,omitempty"`
+	}
+
+	recentDecisionsHandler struct {
+		claimMapper ClaimMapper
+		authorizer  AuditingAuthorizer
+		logger      log.Logger
+	}
+)
+
+// NewRecentDecisionsHandler creates a diagnostic HTTP handler that responds with the decisions authorizer retained
+// for the subject given by the "subject" query parameter, as a JSON encoded RecentDecisionsResponse, oldest first.
+// The caller's claims are mapped by claimMapper from the Authorization headers and the client certificate
+// of the request, only callers granted the system admin role, see Claims.GrantedRoles, are served.
+func NewRecentDecisionsHandler(claimMapper ClaimMapper, authorizer AuditingAuthorizer, logger log.Logger) http.Handler {
+	return &recentDecisionsHandler{
+		claimMapper: claimMapper,
+		authorizer:  authorizer,
+		logger:      logger,
+	}
+}
+
+func (h *recentDecisionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	authInfo := newAuthInfoFromHTTPRequest(r)
+	if authInfo == nil {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	claims, err := h.claimMapper.GetClaims(r.Context(), authInfo)
+	if err != nil {
+		h.logger.Error("authorization error", tag.Error(err))
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+	if claims.GrantedRoles("")&RoleAdmin == 0 {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	subject := r.URL.Query().Get(recentDecisionsSubjectParam)
+	if subject == "" {
+		http.Error(w, "missing subject", http.StatusBadRequest)
+		return
+	}
+	events := h.authorizer.RecentDecisions(subject)
+	decisions := make([]RecentDecision, 0, len(events))
+	for _, event := range events {
+		decision := RecentDecision{
+			Time:       event.Time,
+			APIName:    event.APIName,
+			Namespace:  event.Namespace,
+			WorkflowID: event.WorkflowID,
+			RunID:      event.RunID,
+			Decision:   decisionName(event.Decision),
+			Reason:     string(event.Reason),
+		}
+		if event.Err != nil {
+			decision.Error = event.Err.Error()
+		}
+		decisions = append(decisions, decision)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(RecentDecisionsResponse{Subject: subject, Decisions: decisions}); err != nil {
+		h.logger.Warn("failed to write recent decisions response", tag.Error(err))
+	}
+}
@@ -0,0 +1,154 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/log"
+)
+
+type (
+	recentDecisionsSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller      *gomock.Controller
+		mockClaimMapper *MockClaimMapper
+		mockAuthorizer  *MockAuthorizer
+		authorizer      AuditingAuthorizer
+	}
+)
+
+func TestRecentDecisionsSuite(t *testing.T) {
+	s := new(recentDecisionsSuite)
+	suite.Run(t, s)
+}
+
+func (s *recentDecisionsSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockClaimMapper = NewMockClaimMapper(s.controller)
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.authorizer = NewAuditingAuthorizer(s.mockAuthorizer, 3, 10, clock.NewEventTimeSource().Update(time.Unix(0, 0)))
+}
+
+func (s *recentDecisionsSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *recentDecisionsSuite) TestHandler() {
+	claims := &Claims{Subject: testSubject}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, describeNamespaceTarget).Return(Result{Decision: DecisionAllow}, nil)
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, startWorkflowExecutionTarget).Return(Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil)
+	_, err := s.authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+	s.NoError(err)
+	_, err = s.authorizer.Authorize(ctx, claims, startWorkflowExecutionTarget)
+	s.NoError(err)
+	s.mockClaimMapper.EXPECT().GetClaims(gomock.Any(), &AuthInfo{AuthToken: "Bearer token", PeerAddress: "192.0.2.1:1234"}).
+		Return(&Claims{Subject: "admin", System: RoleAdmin}, nil)
+
+	response := s.serve("Bearer token", testSubject)
+	s.Equal(http.StatusOK, response.Code)
+	s.Equal("application/json", response.Header().Get("Content-Type"))
+	var body RecentDecisionsResponse
+	s.NoError(json.Unmarshal(response.Body.Bytes(), &body))
+	s.Equal(testSubject, body.Subject)
+	s.Len(body.Decisions, 2)
+	s.Equal(describeNamespaceTarget.APIName, body.Decisions[0].APIName)
+	s.Equal(decisionNameAllow, body.Decisions[0].Decision)
+	s.Equal(startWorkflowExecutionTarget.APIName, body.Decisions[1].APIName)
+	s.Equal(decisionNameDeny, body.Decisions[1].Decision)
+	s.Equal(string(ReasonInsufficientRole), body.Decisions[1].Reason)
+}
+
+func (s *recentDecisionsSuite) TestHandlerUnknownSubject() {
+	s.mockClaimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(&Claims{System: RoleAdmin}, nil)
+
+	response := s.serve("Bearer token", "other")
+	s.Equal(http.StatusOK, response.Code)
+	s.JSONEq(`{"subject":"other","decisions":[]}`, response.Body.String())
+}
+
+func (s *recentDecisionsSuite) TestHandlerMissingSubject() {
+	s.mockClaimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(&Claims{System: RoleAdmin}, nil)
+
+	response := s.serve("Bearer token", "")
+	s.Equal(http.StatusBadRequest, response.Code)
+}
+
+func (s *recentDecisionsSuite) TestHandlerNoAuthInfo() {
+	response := s.serve("", testSubject)
+	s.Equal(http.StatusUnauthorized, response.Code)
+}
+
+func (s *recentDecisionsSuite) TestHandlerNotAdmin() {
+	for _, claims := range []*Claims{
+		nil,
+		{System: RoleWriter, Namespaces: map[string]Role{testNamespace: RoleAdmin}},
+		{System: RoleAdmin, OnBehalfOf: &Claims{Subject: testSubject}},
+	} {
+		s.mockClaimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(claims, nil)
+
+		response := s.serve("Bearer token", testSubject)
+		s.Equal(http.StatusForbidden, response.Code)
+	}
+}
+
+func (s *recentDecisionsSuite) TestHandlerClaimMapperError() {
+	s.mockClaimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(nil, errors.New("invalid token"))
+
+	response := s.serve("Bearer token", testSubject)
+	s.Equal(http.StatusForbidden, response.Code)
+	s.NotContains(response.Body.String(), "invalid token")
+}
+
+func (s *recentDecisionsSuite) TestHandlerMethodNotAllowed() {
+	request := httptest.NewRequest(http.MethodPost, "/recent-decisions?subject="+testSubject, nil)
+	response := httptest.NewRecorder()
+	NewRecentDecisionsHandler(s.mockClaimMapper, s.authorizer, log.NewNoop()).ServeHTTP(response, request)
+	s.Equal(http.StatusMethodNotAllowed, response.Code)
+}
+
+func (s *recentDecisionsSuite) serve(authorization string, subject string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, "/recent-decisions?subject="+subject, nil)
+	request.RemoteAddr = "192.0.2.1:1234"
+	if authorization != "" {
+		request.Header.Set("Authorization", authorization)
+	}
+	response := httptest.NewRecorder()
+	NewRecentDecisionsHandler(s.mockClaimMapper, s.authorizer, log.NewNoop()).ServeHTTP(response, request)
+	return response
+}
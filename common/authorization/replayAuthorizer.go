@@ -0,0 +1,94 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrDecisionNotRecorded is returned by replay authorizers for calls without a recorded decision
+var ErrDecisionNotRecorded = errors.New("no recorded decision for the call")
+
+type (
+	replayAuthorizer struct {
+		records map[replayKey][]*RecordedDecision // in recording order
+	}
+
+	replayKey struct {
+		subject    string
+		apiName    string
+		namespace  string
+		workflowID string
+		runID      string
+	}
+)
+
+var _ Authorizer = (*replayAuthorizer)(nil)
+
+// NewReplayAuthorizer creates an authorizer that decides calls as they were decided when records were recorded,
+// e.g. as read with ReadDecisionLog, to reproduce the authorization of recorded traffic in tests. A call is
+// decided by the latest record of the same subject, API, namespace and workflow execution made for the same
+// roles; records without roles match any roles. Calls without a matching record fail with ErrDecisionNotRecorded.
+func NewReplayAuthorizer(records []*RecordedDecision) Authorizer {
+	a := &replayAuthorizer{records: make(map[replayKey][]*RecordedDecision)}
+	for _, record := range records {
+		key := replayKey{
+			subject:    record.Subject,
+			apiName:    record.Target.APIName,
+			namespace:  record.Target.Namespace,
+			workflowID: record.Target.WorkflowID,
+			runID:      record.Target.RunID,
+		}
+		a.records[key] = append(a.records[key], record)
+	}
+	return a
+}
+
+func (a *replayAuthorizer) Authorize(_ context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	key := replayKey{
+		apiName:    target.APIName,
+		namespace:  target.Namespace,
+		workflowID: target.WorkflowID,
+		runID:      target.RunID,
+	}
+	roles := RecordedRoles{}
+	if claims != nil {
+		key.subject = claims.Subject
+		roles.System = claims.System
+		roles.Namespace = claims.Namespaces[target.Namespace]
+	}
+	records := a.records[key]
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].Roles != nil && *records[i].Roles != roles {
+			continue
+		}
+		if records[i].Decision == decisionNameAllow {
+			return Result{Decision: DecisionAllow, Reason: ReasonCode(records[i].Reason)}, nil
+		}
+		return Result{Decision: DecisionDeny, Reason: ReasonCode(records[i].Reason)}, nil
+	}
+	return Result{}, ErrDecisionNotRecorded
+}
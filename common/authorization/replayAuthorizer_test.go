@@ -0,0 +1,114 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	replayAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+	}
+)
+
+func TestReplayAuthorizerSuite(t *testing.T) {
+	s := new(replayAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *replayAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *replayAuthorizerSuite) TestReplayVersion1() {
+	records, err := ReadDecisionLog(strings.NewReader(decisionLogV1))
+	s.NoError(err)
+	authorizer := NewReplayAuthorizer(records)
+	claims := &Claims{Subject: testSubject, Namespaces: map[string]Role{testNamespace: RoleReader}}
+
+	result, err := authorizer.Authorize(ctx, claims,
+		&CallTarget{APIName: startWorkflowExecutionTarget.APIName, Namespace: testNamespace, WorkflowID: "wid"})
+	s.NoError(err)
+	s.Equal(Result{Decision: DecisionAllow}, result)
+
+	// version 1 didn't record roles, so its decisions replay for any roles
+	result, err = authorizer.Authorize(ctx, &Claims{Subject: testSubject, System: RoleAdmin},
+		&CallTarget{APIName: describeNamespaceTarget.APIName, Namespace: testNamespace})
+	s.NoError(err)
+	s.Equal(Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, result)
+}
+
+func (s *replayAuthorizerSuite) TestRolesMustMatch() {
+	target := &CallTarget{APIName: startWorkflowExecutionTarget.APIName, Namespace: testNamespace}
+	authorizer := NewReplayAuthorizer([]*RecordedDecision{
+		{
+			Subject:  testSubject,
+			Roles:    &RecordedRoles{Namespace: RoleWriter},
+			Target:   RecordedTarget{APIName: target.APIName, Namespace: testNamespace},
+			Decision: decisionNameAllow,
+		},
+		{
+			Subject:  testSubject,
+			Roles:    &RecordedRoles{Namespace: RoleReader},
+			Target:   RecordedTarget{APIName: target.APIName, Namespace: testNamespace},
+			Decision: decisionNameDeny,
+			Reason:   string(ReasonInsufficientRole),
+		},
+	})
+
+	result, err := authorizer.Authorize(ctx, &Claims{Subject: testSubject, Namespaces: map[string]Role{testNamespace: RoleWriter}}, target)
+	s.NoError(err)
+	s.Equal(Result{Decision: DecisionAllow}, result)
+
+	result, err = authorizer.Authorize(ctx, &Claims{Subject: testSubject, Namespaces: map[string]Role{testNamespace: RoleReader}}, target)
+	s.NoError(err)
+	s.Equal(Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, result)
+
+	_, err = authorizer.Authorize(ctx, &Claims{Subject: testSubject, System: RoleAdmin}, target)
+	s.Equal(ErrDecisionNotRecorded, err)
+}
+
+func (s *replayAuthorizerSuite) TestLatestRecordWins() {
+	authorizer := NewReplayAuthorizer([]*RecordedDecision{
+		{Target: RecordedTarget{APIName: describeNamespaceTarget.APIName}, Decision: decisionNameDeny},
+		{Target: RecordedTarget{APIName: describeNamespaceTarget.APIName}, Decision: decisionNameAllow},
+	})
+
+	result, err := authorizer.Authorize(ctx, nil, &CallTarget{APIName: describeNamespaceTarget.APIName})
+	s.NoError(err)
+	s.Equal(Result{Decision: DecisionAllow}, result)
+}
+
+func (s *replayAuthorizerSuite) TestNotRecorded() {
+	authorizer := NewReplayAuthorizer(nil)
+	_, err := authorizer.Authorize(ctx, &Claims{Subject: testSubject}, startWorkflowExecutionTarget)
+	s.Equal(ErrDecisionNotRecorded, err)
+}
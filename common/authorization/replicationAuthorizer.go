@@ -0,0 +1,87 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+)
+
+type (
+	replicationRoutingAuthorizer struct {
+		authorizer            Authorizer
+		replicationAuthorizer Authorizer
+	}
+
+	clusterMemberAuthorizer struct {
+		memberNames map[string]struct{}
+	}
+)
+
+var _ Authorizer = (*replicationRoutingAuthorizer)(nil)
+var _ Authorizer = (*clusterMemberAuthorizer)(nil)
+
+// NewReplicationAuthorizer creates an authorizer that decides calls to ReplicationAPIs with replicationAuthorizer,
+// e.g. one created by NewClusterMemberAuthorizer, and all other calls with authorizer, so that the user policy
+// never applies to replication between clusters.
+func NewReplicationAuthorizer(authorizer Authorizer, replicationAuthorizer Authorizer) Authorizer {
+	return &replicationRoutingAuthorizer{
+		authorizer:            authorizer,
+		replicationAuthorizer: replicationAuthorizer,
+	}
+}
+
+func (a *replicationRoutingAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if IsReplicationAPI(target.APIName) {
+		return a.replicationAuthorizer.Authorize(ctx, claims, target)
+	}
+	return a.authorizer.Authorize(ctx, claims, target)
+}
+
+// NewClusterMemberAuthorizer creates an authorizer that allows calls arriving on a TLS connection whose verified
+// client certificate names one of memberNames, in its common name or DNS names, i.e. calls of member clusters.
+// All other calls are denied regardless of the caller's claims.
+func NewClusterMemberAuthorizer(memberNames []string) Authorizer {
+	names := make(map[string]struct{}, len(memberNames))
+	for _, name := range memberNames {
+		names[name] = struct{}{}
+	}
+	return &clusterMemberAuthorizer{memberNames: names}
+}
+
+func (a *clusterMemberAuthorizer) Authorize(_ context.Context, _ *Claims, target *CallTarget) (Result, error) {
+	if target.TLSState == nil || len(target.TLSState.VerifiedChains) == 0 || len(target.TLSState.VerifiedChains[0]) == 0 {
+		return Result{Decision: DecisionDeny, Reason: ReasonNotClusterMember}, nil
+	}
+	certificate := target.TLSState.VerifiedChains[0][0]
+	if _, ok := a.memberNames[certificate.Subject.CommonName]; ok {
+		return Result{Decision: DecisionAllow}, nil
+	}
+	for _, name := range certificate.DNSNames {
+		if _, ok := a.memberNames[name]; ok {
+			return Result{Decision: DecisionAllow}, nil
+		}
+	}
+	return Result{Decision: DecisionDeny, Reason: ReasonNotClusterMember}, nil
+}
@@ -0,0 +1,123 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	replicationAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller          *gomock.Controller
+		mockUserAuthorizer  *MockAuthorizer
+		replicationTarget   *CallTarget
+		clusterCertificates *tls.ConnectionState
+		authorizer          Authorizer
+	}
+)
+
+func TestReplicationAuthorizerSuite(t *testing.T) {
+	s := new(replicationAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *replicationAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockUserAuthorizer = NewMockAuthorizer(s.controller)
+	s.clusterCertificates = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{
+		Subject:  pkix.Name{CommonName: "cluster-b"},
+		DNSNames: []string{"frontend.cluster-c.example.com"},
+	}}}}
+	s.replicationTarget = &CallTarget{APIName: adminServicePrefix + "GetReplicationMessages", TLSState: s.clusterCertificates}
+	s.authorizer = NewReplicationAuthorizer(s.mockUserAuthorizer,
+		NewClusterMemberAuthorizer([]string{"cluster-a", "frontend.cluster-c.example.com"}))
+}
+
+func (s *replicationAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *replicationAuthorizerSuite) TestReplicationAPIsBypassUserAuthorizer() {
+	// the user authorizer would allow the call, but it's never consulted
+	s.mockUserAuthorizer.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(Result{Decision: DecisionAllow}, nil).Times(0)
+
+	for api := range ReplicationAPIs {
+		target := &CallTarget{APIName: api}
+		result, err := s.authorizer.Authorize(ctx, &Claims{System: RoleAdmin}, target)
+		s.NoError(err)
+		s.Equal(DecisionDeny, result.Decision, api)
+		s.Equal(ReasonNotClusterMember, result.Reason, api)
+	}
+}
+
+func (s *replicationAuthorizerSuite) TestClusterMember() {
+	result, err := s.authorizer.Authorize(ctx, nil, s.replicationTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *replicationAuthorizerSuite) TestNotClusterMember() {
+	s.clusterCertificates.VerifiedChains[0][0].DNSNames = nil
+
+	result, err := s.authorizer.Authorize(ctx, nil, s.replicationTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonNotClusterMember, result.Reason)
+}
+
+func (s *replicationAuthorizerSuite) TestUnverifiedCertificate() {
+	s.replicationTarget.TLSState = &tls.ConnectionState{PeerCertificates: s.clusterCertificates.VerifiedChains[0]}
+
+	result, err := s.authorizer.Authorize(ctx, nil, s.replicationTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+}
+
+func (s *replicationAuthorizerSuite) TestOtherAPIsUseUserAuthorizer() {
+	adminTarget := &CallTarget{APIName: adminServicePrefix + "CloseShard", TLSState: s.clusterCertificates}
+	claims := &Claims{System: RoleAdmin}
+	s.mockUserAuthorizer.EXPECT().Authorize(ctx, claims, adminTarget).Return(Result{Decision: DecisionAllow}, nil)
+	s.mockUserAuthorizer.EXPECT().Authorize(ctx, claims, startWorkflowExecutionTarget).Return(Result{Decision: DecisionDeny}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, adminTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+
+	result, err = s.authorizer.Authorize(ctx, claims, startWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+}
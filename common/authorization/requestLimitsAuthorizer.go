@@ -0,0 +1,81 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"time"
+)
+
+type (
+	// RequestLimits are the limits of request parameters for a role, zero values mean unlimited
+	RequestLimits struct {
+		// MaxExecutionTimeout limits CallTarget.ExecutionTimeout, how long started workflows may run
+		MaxExecutionTimeout time.Duration
+		// MaxRetentionPeriod limits CallTarget.RetentionPeriod, how long the closed workflows of a namespace are kept
+		MaxRetentionPeriod time.Duration
+		// MaxSignalInputCount limits CallTarget.SignalInputCount, the number of payloads attached to a signal
+		MaxSignalInputCount int
+	}
+
+	requestLimitsAuthorizer struct {
+		authorizer Authorizer
+		limits     map[Role]RequestLimits
+	}
+)
+
+var _ Authorizer = (*requestLimitsAuthorizer)(nil)
+
+// NewRequestLimitsAuthorizer creates an authorizer that denies calls whose parameters exceed the limits of the
+// caller's effective role in the target namespace, e.g. to prevent low-privilege subjects from starting
+// workflows that run for a year or registering namespaces that retain closed workflows for a year. Callers without a role are limited by the limits of RoleUndefined. Roles without
+// limits and calls that don't carry the parameters are not limited. All other calls are decided by authorizer.
+func NewRequestLimitsAuthorizer(authorizer Authorizer, limits map[Role]RequestLimits) Authorizer {
+	return &requestLimitsAuthorizer{
+		authorizer: authorizer,
+		limits:     limits,
+	}
+}
+
+func (a *requestLimitsAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	role, _ := claims.EffectiveClaims().EffectiveRole(target.Namespace)
+	if limits, ok := a.limits[role]; ok && exceedsLimits(target, limits) {
+		return Result{Decision: DecisionDeny, Reason: ReasonRequestLimit}, nil
+	}
+	return a.authorizer.Authorize(ctx, claims, target)
+}
+
+func exceedsLimits(target *CallTarget, limits RequestLimits) bool {
+	if limits.MaxExecutionTimeout > 0 && target.ExecutionTimeout > limits.MaxExecutionTimeout {
+		return true
+	}
+	if limits.MaxRetentionPeriod > 0 && target.RetentionPeriod > limits.MaxRetentionPeriod {
+		return true
+	}
+	if limits.MaxSignalInputCount > 0 && target.SignalInputCount > limits.MaxSignalInputCount {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	requestLimitsAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		mockAuthorizer *MockAuthorizer
+		authorizer     Authorizer
+	}
+)
+
+func TestRequestLimitsAuthorizerSuite(t *testing.T) {
+	s := new(requestLimitsAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *requestLimitsAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.authorizer = NewRequestLimitsAuthorizer(s.mockAuthorizer, map[Role]RequestLimits{
+		RoleUndefined: {MaxExecutionTimeout: time.Hour, MaxSignalInputCount: 1},
+		RoleWriter:    {MaxExecutionTimeout: 24 * time.Hour, MaxRetentionPeriod: 7 * 24 * time.Hour, MaxSignalInputCount: 10},
+	})
+}
+
+func (s *requestLimitsAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *requestLimitsAuthorizerSuite) TestWithinLimits() {
+	claims := &Claims{Subject: testSubject, Namespaces: map[string]Role{testNamespace: RoleWriter}}
+	target := &CallTarget{APIName: startWorkflowExecutionTarget.APIName, Namespace: testNamespace, ExecutionTimeout: 24 * time.Hour}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, target).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, target)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *requestLimitsAuthorizerSuite) TestExecutionTimeoutOverLimit() {
+	claims := &Claims{Subject: testSubject, Namespaces: map[string]Role{testNamespace: RoleWriter}}
+	target := &CallTarget{APIName: startWorkflowExecutionTarget.APIName, Namespace: testNamespace, ExecutionTimeout: 25 * time.Hour}
+
+	result, err := s.authorizer.Authorize(ctx, claims, target)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonRequestLimit, result.Reason)
+}
+
+func (s *requestLimitsAuthorizerSuite) TestRetentionPeriodWithinLimit() {
+	claims := &Claims{Subject: testSubject, System: RoleWriter}
+	target := &CallTarget{APIName: workflowServicePrefix + "RegisterNamespace", Namespace: "new", RetentionPeriod: 7 * 24 * time.Hour}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, target).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, target)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *requestLimitsAuthorizerSuite) TestRetentionPeriodOverLimit() {
+	claims := &Claims{Subject: testSubject, System: RoleWriter}
+	target := &CallTarget{APIName: workflowServicePrefix + "RegisterNamespace", Namespace: "new", RetentionPeriod: 8 * 24 * time.Hour}
+
+	result, err := s.authorizer.Authorize(ctx, claims, target)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonRequestLimit, result.Reason)
+}
+
+func (s *requestLimitsAuthorizerSuite) TestSignalInputCountOverLimit() {
+	target := &CallTarget{APIName: workflowServicePrefix + "SignalWorkflowExecution", Namespace: testNamespace, SignalInputCount: 2}
+
+	result, err := s.authorizer.Authorize(ctx, nil, target)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonRequestLimit, result.Reason)
+}
+
+func (s *requestLimitsAuthorizerSuite) TestRoleWithoutLimits() {
+	claims := &Claims{Subject: testSubject, System: RoleAdmin}
+	target := &CallTarget{APIName: startWorkflowExecutionTarget.APIName, Namespace: testNamespace, ExecutionTimeout: 365 * 24 * time.Hour}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, target).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, target)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *requestLimitsAuthorizerSuite) TestParametersAbsent() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, startWorkflowExecutionTarget).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, nil, startWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
@@ -0,0 +1,99 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"unicode/utf8"
+
+	"github.com/pborman/uuid"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/api/serviceerror"
+)
+
+// MaxRequestIDLength is the maximum length of the namespace and workflow ID of requests accepted by ValidateRequest,
+// the default of the frontend's limit on ID lengths
+const MaxRequestIDLength = 1000
+
+var (
+	errRequestNotSet     = serviceerror.NewInvalidArgument("Request is not set on request.")
+	errInvalidNamespace  = serviceerror.NewInvalidArgument("Namespace is too long or not valid UTF-8.")
+	errInvalidWorkflowID = serviceerror.NewInvalidArgument("WorkflowId is too long or not valid UTF-8.")
+	errInvalidRunID      = serviceerror.NewInvalidArgument("Invalid RunId.")
+)
+
+type (
+	// RequestValidator validates a request to the given API, returning an InvalidArgument error
+	// if the request is malformed
+	RequestValidator func(apiName string, req interface{}) error
+
+	// ValidationOrder is the order of the request validation and the authorization of a call, see WithRequestValidation
+	ValidationOrder int
+)
+
+const (
+	// ValidateAfterAuthorization validates the requests of authorized calls only, before they are handled
+	ValidateAfterAuthorization ValidationOrder = iota
+	// ValidateBeforeAuthorization rejects malformed requests before their claims are mapped and they are authorized
+	ValidateBeforeAuthorization
+)
+
+// ValidateRequest is a RequestValidator performing the basic validation of the fields the interceptor extracts
+// from requests: the request must be set, the namespace and workflow ID must be valid UTF-8 of at most
+// MaxRequestIDLength bytes, and the run ID, if set, must be a UUID. APIs validate their requests in full
+// when they are handled.
+func ValidateRequest(_ string, req interface{}) error {
+	if req == nil {
+		return errRequestNotSet
+	}
+	if r, ok := req.(requestWithNamespace); ok && !isValidRequestID(r.GetNamespace()) {
+		return errInvalidNamespace
+	}
+
+	var execution *commonpb.WorkflowExecution
+	switch r := req.(type) {
+	case requestWithExecution:
+		execution = r.GetExecution()
+	case requestWithWorkflowExecution:
+		execution = r.GetWorkflowExecution()
+	}
+	workflowID, runID := execution.GetWorkflowId(), execution.GetRunId()
+	if r, ok := req.(requestWithWorkflowID); ok {
+		workflowID = r.GetWorkflowId()
+	}
+	if r, ok := req.(requestWithRunID); ok {
+		runID = r.GetRunId()
+	}
+	if !isValidRequestID(workflowID) {
+		return errInvalidWorkflowID
+	}
+	if runID != "" && uuid.Parse(runID) == nil {
+		return errInvalidRunID
+	}
+	return nil
+}
+
+func isValidRequestID(id string) bool {
+	return len(id) <= MaxRequestIDLength && utf8.ValidString(id)
+}
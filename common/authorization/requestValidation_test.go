@@ -0,0 +1,87 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/api/workflowservice/v1"
+)
+
+func TestValidateRequest(t *testing.T) {
+	testCases := []struct {
+		name     string
+		request  interface{}
+		expected error
+	}{
+		{
+			name:    "valid",
+			request: &workflowservice.StartWorkflowExecutionRequest{Namespace: testNamespace, WorkflowId: "wid"},
+		},
+		{
+			name: "valid run ID",
+			request: &workflowservice.DescribeWorkflowExecutionRequest{Namespace: testNamespace,
+				Execution: &commonpb.WorkflowExecution{WorkflowId: "wid", RunId: "b2a8a52e-4f43-4b7c-8f3e-1c7bdf5f0f57"}},
+		},
+		{
+			name:    "request without namespace",
+			request: &workflowservice.GetClusterInfoRequest{},
+		},
+		{
+			name:     "request not set",
+			request:  nil,
+			expected: errRequestNotSet,
+		},
+		{
+			name:     "namespace too long",
+			request:  &workflowservice.DescribeNamespaceRequest{Namespace: strings.Repeat("n", MaxRequestIDLength+1)},
+			expected: errInvalidNamespace,
+		},
+		{
+			name:     "namespace not UTF-8",
+			request:  &workflowservice.DescribeNamespaceRequest{Namespace: "\xff"},
+			expected: errInvalidNamespace,
+		},
+		{
+			name:     "workflow ID too long",
+			request:  &workflowservice.StartWorkflowExecutionRequest{Namespace: testNamespace, WorkflowId: strings.Repeat("w", MaxRequestIDLength+1)},
+			expected: errInvalidWorkflowID,
+		},
+		{
+			name: "invalid run ID",
+			request: &workflowservice.DescribeWorkflowExecutionRequest{Namespace: testNamespace,
+				Execution: &commonpb.WorkflowExecution{WorkflowId: "wid", RunId: "not-a-uuid"}},
+			expected: errInvalidRunID,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, ValidateRequest("API", tc.request))
+		})
+	}
+}
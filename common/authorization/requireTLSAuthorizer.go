@@ -0,0 +1,59 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+)
+
+type requireTLSAuthorizer struct {
+	authorizer Authorizer
+	apis       map[string]struct{}
+	minVersion uint16
+}
+
+var _ Authorizer = (*requireTLSAuthorizer)(nil)
+
+// NewRequireTLSAuthorizer creates an authorizer that denies calls to the given APIs arriving on plaintext
+// connections or on TLS connections older than minVersion, e.g. tls.VersionTLS12. All other calls
+// are decided by authorizer.
+func NewRequireTLSAuthorizer(authorizer Authorizer, apis []string, minVersion uint16) Authorizer {
+	a := &requireTLSAuthorizer{
+		authorizer: authorizer,
+		apis:       make(map[string]struct{}, len(apis)),
+		minVersion: minVersion,
+	}
+	for _, api := range apis {
+		a.apis[api] = struct{}{}
+	}
+	return a
+}
+
+func (a *requireTLSAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if _, ok := a.apis[target.APIName]; ok && (target.TLSState == nil || target.TLSState.Version < a.minVersion) {
+		return Result{Decision: DecisionDeny, Reason: ReasonTLSRequired}, nil
+	}
+	return a.authorizer.Authorize(ctx, claims, target)
+}
@@ -0,0 +1,86 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"regexp"
+
+	"google.golang.org/grpc/metadata"
+)
+
+type (
+	requiredMetadataAuthorizer struct {
+		authorizer    Authorizer
+		apis          map[string]struct{}
+		requiredKeys  []string
+		valuePatterns map[string]*regexp.Regexp
+	}
+)
+
+var _ Authorizer = (*requiredMetadataAuthorizer)(nil)
+
+// NewRequiredMetadataAuthorizer creates an authorizer that denies calls to the given APIs unless they carry
+// a non-empty value for each of requiredKeys in their metadata, e.g. the ticket justifying a regulated operation.
+// Values of the keys in valuePatterns, which may be nil, must also match their pattern. All other calls,
+// and calls carrying the required metadata, are decided by authorizer.
+func NewRequiredMetadataAuthorizer(
+	authorizer Authorizer,
+	apis []string,
+	requiredKeys []string,
+	valuePatterns map[string]*regexp.Regexp,
+) Authorizer {
+	a := &requiredMetadataAuthorizer{
+		authorizer:    authorizer,
+		apis:          make(map[string]struct{}, len(apis)),
+		requiredKeys:  requiredKeys,
+		valuePatterns: valuePatterns,
+	}
+	for _, api := range apis {
+		a.apis[api] = struct{}{}
+	}
+	return a
+}
+
+func (a *requiredMetadataAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if _, ok := a.apis[target.APIName]; ok && !a.hasRequiredMetadata(ctx) {
+		return Result{Decision: DecisionDeny, Reason: ReasonMissingJustification}, nil
+	}
+	return a.authorizer.Authorize(ctx, claims, target)
+}
+
+func (a *requiredMetadataAuthorizer) hasRequiredMetadata(ctx context.Context) bool {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, key := range a.requiredKeys {
+		values := headerValues(md, key)
+		if len(values) == 0 || values[0] == "" {
+			return false
+		}
+		if pattern, ok := a.valuePatterns[key]; ok && !pattern.MatchString(values[0]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,120 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/metadata"
+)
+
+type (
+	requiredMetadataAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		mockAuthorizer *MockAuthorizer
+		authorizer     Authorizer
+	}
+)
+
+const (
+	testTicketHeaderName        = "x-ticket"
+	testJustificationHeaderName = "x-justification"
+)
+
+func TestRequiredMetadataAuthorizerSuite(t *testing.T) {
+	s := new(requiredMetadataAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *requiredMetadataAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.authorizer = NewRequiredMetadataAuthorizer(
+		s.mockAuthorizer,
+		[]string{terminateWorkflowExecutionTarget.APIName},
+		[]string{testTicketHeaderName, testJustificationHeaderName},
+		map[string]*regexp.Regexp{testTicketHeaderName: regexp.MustCompile(`^OPS-[0-9]+$`)})
+}
+
+func (s *requiredMetadataAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *requiredMetadataAuthorizerSuite) TestMetadataPresent() {
+	mdCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		testTicketHeaderName, "OPS-1234",
+		testJustificationHeaderName, "stuck workflow"))
+	s.mockAuthorizer.EXPECT().Authorize(mdCtx, nil, terminateWorkflowExecutionTarget).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(mdCtx, nil, terminateWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *requiredMetadataAuthorizerSuite) TestMetadataMissing() {
+	for _, md := range []metadata.MD{
+		nil,
+		metadata.Pairs(testTicketHeaderName, "OPS-1234"),
+		metadata.Pairs(testTicketHeaderName, "OPS-1234", testJustificationHeaderName, ""),
+	} {
+		mdCtx := metadata.NewIncomingContext(context.Background(), md)
+		result, err := s.authorizer.Authorize(mdCtx, &Claims{Subject: testSubject, System: RoleAdmin}, terminateWorkflowExecutionTarget)
+		s.NoError(err)
+		s.Equal(DecisionDeny, result.Decision)
+		s.Equal(ReasonMissingJustification, result.Reason)
+	}
+
+	result, err := s.authorizer.Authorize(ctx, nil, terminateWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(ReasonMissingJustification, result.Reason)
+}
+
+func (s *requiredMetadataAuthorizerSuite) TestInvalidMetadataValue() {
+	mdCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		testTicketHeaderName, "none",
+		testJustificationHeaderName, "stuck workflow"))
+
+	result, err := s.authorizer.Authorize(mdCtx, nil, terminateWorkflowExecutionTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonMissingJustification, result.Reason)
+}
+
+func (s *requiredMetadataAuthorizerSuite) TestNotListedAPI() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, nil, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
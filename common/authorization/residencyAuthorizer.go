@@ -0,0 +1,78 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"strings"
+)
+
+// RegionLabel is the namespace label holding the region the data of the namespace resides in, e.g. "eu"
+const RegionLabel = "region"
+
+type residencyAuthorizer struct {
+	authorizer         Authorizer
+	subjectRegionClaim string
+}
+
+var _ Authorizer = (*residencyAuthorizer)(nil)
+
+// NewResidencyAuthorizer creates an authorizer that denies calls to namespaces with a region unless the region is
+// one of the regions the caller is authorized for, the values of the subjectRegionClaim attribute of its claims,
+// before delegating to authorizer. Regions are compared case-insensitively. For delegated calls the regions of the
+// end user apply. The region of the namespace is read from CallTarget.NamespaceRegion, see WithNamespaceLabelsLookup;
+// calls to namespaces without a region are decided by authorizer alone. Calls to namespaces whose labels weren't
+// resolved are denied, as their region is unknown.
+func NewResidencyAuthorizer(authorizer Authorizer, subjectRegionClaim string) Authorizer {
+	return &residencyAuthorizer{
+		authorizer:         authorizer,
+		subjectRegionClaim: subjectRegionClaim,
+	}
+}
+
+func (a *residencyAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if target.Namespace != "" && !target.NamespaceLabelsResolved {
+		return Result{Decision: DecisionDeny, Reason: ReasonNamespaceLabelsNotResolved}, nil
+	}
+	if target.NamespaceRegion == "" {
+		return a.authorizer.Authorize(ctx, claims, target)
+	}
+	if claims == nil {
+		return Result{Decision: DecisionDeny, Reason: ReasonNoClaims}, nil
+	}
+	if !a.isAuthorizedForRegion(claims.EffectiveClaims(), target.NamespaceRegion) {
+		return Result{Decision: DecisionDeny, Reason: ReasonDataResidency}, nil
+	}
+	return a.authorizer.Authorize(ctx, claims, target)
+}
+
+func (a *residencyAuthorizer) isAuthorizedForRegion(claims *Claims, region string) bool {
+	for _, authorized := range claims.Attributes[a.subjectRegionClaim] {
+		if strings.EqualFold(authorized, region) {
+			return true
+		}
+	}
+	return false
+}
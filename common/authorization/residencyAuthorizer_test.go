@@ -0,0 +1,127 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const testRegionClaim = "regions"
+
+type (
+	residencyAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		mockAuthorizer *MockAuthorizer
+		authorizer     Authorizer
+	}
+)
+
+func TestResidencyAuthorizerSuite(t *testing.T) {
+	s := new(residencyAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *residencyAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.authorizer = NewResidencyAuthorizer(s.mockAuthorizer, testRegionClaim)
+}
+
+func (s *residencyAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *residencyAuthorizerSuite) TestMatchingRegion() {
+	claims := &Claims{Subject: testSubject, Attributes: map[string][]string{testRegionClaim: {"us", "EU"}}}
+	target := &CallTarget{APIName: describeNamespaceTarget.APIName, Namespace: testNamespace, NamespaceRegion: "eu",
+		NamespaceLabelsResolved: true}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, target).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, target)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *residencyAuthorizerSuite) TestMismatchingRegion() {
+	target := &CallTarget{APIName: describeNamespaceTarget.APIName, Namespace: testNamespace, NamespaceRegion: "eu",
+		NamespaceLabelsResolved: true}
+	for _, claims := range []*Claims{
+		{Subject: testSubject, Attributes: map[string][]string{testRegionClaim: {"us"}}},
+		{Subject: testSubject, Attributes: map[string][]string{"other": {"eu"}}},
+		{Subject: testSubject, System: RoleAdmin},
+	} {
+		result, err := s.authorizer.Authorize(ctx, claims, target)
+		s.NoError(err)
+		s.Equal(DecisionDeny, result.Decision)
+		s.Equal(ReasonDataResidency, result.Reason)
+	}
+
+	result, err := s.authorizer.Authorize(ctx, nil, target)
+	s.NoError(err)
+	s.Equal(ReasonNoClaims, result.Reason)
+}
+
+func (s *residencyAuthorizerSuite) TestDelegatedCall() {
+	target := &CallTarget{APIName: describeNamespaceTarget.APIName, Namespace: testNamespace, NamespaceRegion: "eu",
+		NamespaceLabelsResolved: true}
+	claims := &Claims{
+		Subject:    "gateway",
+		Attributes: map[string][]string{testRegionClaim: {"eu"}},
+		OnBehalfOf: &Claims{Subject: testSubject, Attributes: map[string][]string{testRegionClaim: {"us"}}},
+	}
+
+	result, err := s.authorizer.Authorize(ctx, claims, target)
+	s.NoError(err)
+	s.Equal(ReasonDataResidency, result.Reason)
+}
+
+func (s *residencyAuthorizerSuite) TestNamespaceWithoutRegion() {
+	for _, target := range []*CallTarget{
+		{APIName: describeNamespaceTarget.APIName, Namespace: testNamespace, NamespaceLabelsResolved: true},
+		{APIName: workflowServicePrefix + "ListNamespaces"},
+	} {
+		s.mockAuthorizer.EXPECT().Authorize(ctx, nil, target).Return(Result{Decision: DecisionAllow}, nil)
+
+		result, err := s.authorizer.Authorize(ctx, nil, target)
+		s.NoError(err)
+		s.Equal(DecisionAllow, result.Decision)
+	}
+}
+
+func (s *residencyAuthorizerSuite) TestNamespaceLabelsNotResolved() {
+	claims := &Claims{Subject: testSubject, Attributes: map[string][]string{testRegionClaim: {"eu"}}}
+
+	result, err := s.authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(Result{Decision: DecisionDeny, Reason: ReasonNamespaceLabelsNotResolved}, result)
+}
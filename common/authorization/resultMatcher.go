@@ -0,0 +1,128 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"fmt"
+
+	"github.com/golang/mock/gomock"
+)
+
+type (
+	// ResultMatcher is a gomock matcher for Result values. By default only the decision is compared,
+	// so that expectations keep working as fields are added to Result.
+	ResultMatcher struct {
+		expected        Result
+		matchReason     bool
+		matchDenyReason bool
+		matchCacheTTL   bool
+	}
+
+	// ResultMatcherOption enables comparison of additional Result fields
+	ResultMatcherOption func(*ResultMatcher)
+)
+
+var _ gomock.Matcher = (*ResultMatcher)(nil)
+
+// NewResultMatcher creates a matcher for results with the same decision as expected
+func NewResultMatcher(expected Result, opts ...ResultMatcherOption) *ResultMatcher {
+	m := &ResultMatcher{expected: expected}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// MatchReason makes the matcher compare Result.Reason as well
+func MatchReason() ResultMatcherOption {
+	return func(m *ResultMatcher) {
+		m.matchReason = true
+	}
+}
+
+// MatchDenyReason makes the matcher compare Result.DenyReason as well, including the reasons it wraps
+func MatchDenyReason() ResultMatcherOption {
+	return func(m *ResultMatcher) {
+		m.matchDenyReason = true
+	}
+}
+
+// MatchCacheTTL makes the matcher compare Result.CacheTTL as well
+func MatchCacheTTL() ResultMatcherOption {
+	return func(m *ResultMatcher) {
+		m.matchCacheTTL = true
+	}
+}
+
+// Matches accepts both Result and *Result values
+func (m *ResultMatcher) Matches(x interface{}) bool {
+	var actual Result
+	switch r := x.(type) {
+	case Result:
+		actual = r
+	case *Result:
+		if r == nil {
+			return false
+		}
+		actual = *r
+	default:
+		return false
+	}
+
+	if actual.Decision != m.expected.Decision {
+		return false
+	}
+	if m.matchReason && actual.Reason != m.expected.Reason {
+		return false
+	}
+	if m.matchDenyReason && !actual.DenyReason.Equal(m.expected.DenyReason) {
+		return false
+	}
+	if m.matchCacheTTL && actual.CacheTTL != m.expected.CacheTTL {
+		return false
+	}
+	return true
+}
+
+func (m *ResultMatcher) String() string {
+	s := fmt.Sprintf("has decision %v", m.expected.Decision)
+	if m.matchReason {
+		s += fmt.Sprintf(", reason %q", m.expected.Reason)
+	}
+	if m.matchDenyReason {
+		s += fmt.Sprintf(", deny reason %q", denyReasonString(m.expected.DenyReason))
+	}
+	if m.matchCacheTTL {
+		s += fmt.Sprintf(", cache TTL %v", m.expected.CacheTTL)
+	}
+	return s
+}
+
+func denyReasonString(reason *DenyReason) string {
+	if reason == nil {
+		return ""
+	}
+	return reason.Error()
+}
@@ -0,0 +1,83 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultEqual(t *testing.T) {
+	allow := Result{Decision: DecisionAllow, Reason: "foo", CacheTTL: time.Minute}
+	require.True(t, allow.Equal(Result{Decision: DecisionAllow, Reason: "foo", CacheTTL: time.Minute}))
+	require.False(t, allow.Equal(Result{Decision: DecisionDeny, Reason: "foo", CacheTTL: time.Minute}))
+	require.False(t, allow.Equal(Result{Decision: DecisionAllow, Reason: "bar", CacheTTL: time.Minute}))
+	require.False(t, allow.Equal(Result{Decision: DecisionAllow, Reason: "foo", CacheTTL: time.Second}))
+	require.False(t, allow.Equal(Result{Decision: DecisionAllow, Reason: "foo", CacheTTL: time.Minute, Constraints: &Constraints{}}))
+
+	deny := NewDenyResult(WrapDenyReason(NewDenyReason(ReasonInsufficientRole, "base"), ReasonTokenRevoked, "blocklist"))
+	require.True(t, deny.Equal(NewDenyResult(WrapDenyReason(NewDenyReason(ReasonInsufficientRole, "base"), ReasonTokenRevoked, "blocklist"))))
+	require.False(t, deny.Equal(Result{Decision: DecisionDeny, Reason: ReasonTokenRevoked}))
+	require.False(t, deny.Equal(NewDenyResult(NewDenyReason(ReasonTokenRevoked, "blocklist"))))
+	require.False(t, deny.Equal(NewDenyResult(WrapDenyReason(NewDenyReason(ReasonInsufficientRole, "other"), ReasonTokenRevoked, "blocklist"))))
+}
+
+func TestResultMatcherDecisionOnly(t *testing.T) {
+	m := NewResultMatcher(Result{Decision: DecisionDeny})
+	require.True(t, m.Matches(Result{Decision: DecisionDeny, Reason: "foo", CacheTTL: time.Minute}))
+	require.True(t, m.Matches(&Result{Decision: DecisionDeny}))
+	require.False(t, m.Matches(Result{Decision: DecisionAllow}))
+	require.False(t, m.Matches((*Result)(nil)))
+	require.False(t, m.Matches(DecisionDeny))
+}
+
+func TestResultMatcherWithReason(t *testing.T) {
+	m := NewResultMatcher(Result{Decision: DecisionDeny, Reason: "foo"}, MatchReason())
+	require.True(t, m.Matches(Result{Decision: DecisionDeny, Reason: "foo", CacheTTL: time.Minute}))
+	require.False(t, m.Matches(Result{Decision: DecisionDeny, Reason: "bar"}))
+	require.False(t, m.Matches(Result{Decision: DecisionAllow, Reason: "foo"}))
+}
+
+func TestResultMatcherWithCacheTTL(t *testing.T) {
+	m := NewResultMatcher(Result{Decision: DecisionAllow, CacheTTL: time.Minute}, MatchCacheTTL())
+	require.True(t, m.Matches(Result{Decision: DecisionAllow, Reason: "foo", CacheTTL: time.Minute}))
+	require.False(t, m.Matches(Result{Decision: DecisionAllow, CacheTTL: time.Second}))
+
+	m = NewResultMatcher(Result{Decision: DecisionAllow, Reason: "foo", CacheTTL: time.Minute}, MatchReason(), MatchCacheTTL())
+	require.True(t, m.Matches(Result{Decision: DecisionAllow, Reason: "foo", CacheTTL: time.Minute}))
+	require.False(t, m.Matches(Result{Decision: DecisionAllow, Reason: "bar", CacheTTL: time.Minute}))
+	require.Equal(t, `has decision 2, reason "foo", cache TTL 1m0s`, m.String())
+}
+
+func TestResultMatcherWithDenyReason(t *testing.T) {
+	reason := NewDenyReason(ReasonInsufficientRole, "missing role")
+	m := NewResultMatcher(NewDenyResult(reason), MatchDenyReason())
+	require.True(t, m.Matches(NewDenyResult(NewDenyReason(ReasonInsufficientRole, "missing role"))))
+	require.False(t, m.Matches(NewDenyResult(NewDenyReason(ReasonInsufficientRole, "other"))))
+	require.False(t, m.Matches(Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}))
+	require.Equal(t, `has decision 1, deny reason "missing role"`, m.String())
+}
@@ -24,6 +24,8 @@
 
 package authorization
 
+import "time"
+
 type Role int16
 
 // @@@SNIPSTART temporal-common-authorization-role-enum
@@ -34,6 +36,8 @@ const (
 	RoleReader
 	RoleWriter
 	RoleAdmin
+	// RoleImpersonator allows a system-level subject to act on behalf of another subject
+	RoleImpersonator
 	RoleUndefined = Role(0)
 )
 
@@ -41,18 +45,109 @@ const (
 
 // Checks if the provided role bitmask represents a valid combination of authz
 func (b Role) IsValid() bool {
-	return b&^(RoleWorker|RoleReader|RoleWriter|RoleAdmin) == 0
+	return b&^(RoleWorker|RoleReader|RoleWriter|RoleAdmin|RoleImpersonator) == 0
+}
+
+// Highest returns the highest ranked role in the bitmask, ranking RoleAdmin > RoleWriter > RoleReader > RoleWorker.
+// Roles that are not ranked, such as RoleImpersonator, are ignored.
+func (b Role) Highest() Role {
+	for _, role := range []Role{RoleAdmin, RoleWriter, RoleReader, RoleWorker} {
+		if b&role != 0 {
+			return role
+		}
+	}
+	return RoleUndefined
 }
 
+// RoleScope identifies the scope a role was granted in
+type RoleScope int
+
+const (
+	RoleScopeNone = RoleScope(iota)
+	RoleScopeSystem
+	RoleScopeNamespace
+)
+
 // @@@SNIPSTART temporal-common-authorization-claims
 // Claims contains the identity of the subject and subject's roles at the system level and for individual namespaces
 type Claims struct {
 	// Identity of the subject
 	Subject string
+	// Issuer of the token the claims were extracted from, if any
+	Issuer string
+	// TokenID is the unique ID of the token the claims were extracted from, such as the "jti" claim of a JWT token,
+	// used to revoke the token, see NewTokenRevocationAuthorizer
+	TokenID string
+	// TenantID is the tenant, or account, the subject belongs to, if any
+	TenantID string
+	// ExpiresAt is the expiry of the token the claims were extracted from, such as the "exp" claim of a JWT token,
+	// zero if the token doesn't expire or the claim mapper doesn't set it. Claims must not be reused past it.
+	ExpiresAt time.Time
+	// Audience the token the claims were extracted from was issued for, such as the "aud" claim of a JWT token
+	Audience []string
 	// Role within the context of the whole Temporal cluster or a multi-cluster setup
 	System Role
 	// Roles within specific namespaces
 	Namespaces map[string]Role
+	// Methods used to authenticate the subject, such as the "amr" claim of a JWT token
+	AuthMethods []string
+	// AuthLevel is the level of assurance (LoA) of the authentication of the subject, such as the "loa" claim
+	// of a JWT token, see NewLoAAuthorizer. Zero if the identity provider didn't assert one.
+	AuthLevel int
+	// Groups the subject is a member of, as asserted by the identity provider
+	Groups []string
+	// Attributes are further claims about the subject by claim name, such as the regions the subject is
+	// authorized to access data in. The default JWT claim mapper sets the claims of the token whose values
+	// are strings or lists of strings.
+	Attributes map[string][]string
+	// PermissionBitmaps are the API groups granted to the roles above, precomputed by the claim mapper of a
+	// PermissionBitmapAuthorizer. Nil if the claims were mapped by another claim mapper.
+	PermissionBitmaps *PermissionBitmaps
+	// OnBehalfOf are the claims of the end user a delegated caller, such as a gateway, acts for.
+	// The claims holding it are those of the caller itself, the actor. Nil for calls that are not delegated.
+	OnBehalfOf *Claims
 }
 
 // @@@SNIPEND
+
+// EffectiveRole resolves the role of the subject within the namespace and the scope it was granted in.
+// A system role of RoleWriter or RoleAdmin applies to every namespace and takes precedence over the
+// subject's role within the namespace; lower system roles only apply to system level APIs. Within a scope
+// the highest ranked role wins. RoleUndefined and RoleScopeNone are returned if the subject has no role.
+func (c *Claims) EffectiveRole(namespace string) (Role, RoleScope) {
+	if c == nil {
+		return RoleUndefined, RoleScopeNone
+	}
+	if role := c.System.Highest(); role == RoleAdmin || role == RoleWriter {
+		return role, RoleScopeSystem
+	}
+	if role := c.Namespaces[namespace].Highest(); role != RoleUndefined {
+		return role, RoleScopeNamespace
+	}
+	return RoleUndefined, RoleScopeNone
+}
+
+// GrantedRoles returns the roles the subject the call is made for, see EffectiveClaims, holds within the namespace,
+// for authorizers whose policies grant APIs per role and allow a call if any of the roles is granted it.
+// The roles apply in the scopes EffectiveRole resolves: system roles of RoleWriter or RoleAdmin in every namespace,
+// lower system roles only to system level APIs, i.e. if namespace is empty. For a non-empty namespace the result
+// includes the role EffectiveRole resolves, and no ranked role if EffectiveRole resolves RoleUndefined.
+func (c *Claims) GrantedRoles(namespace string) Role {
+	c = c.EffectiveClaims()
+	if c == nil {
+		return RoleUndefined
+	}
+	if namespace == "" || c.System&(RoleWriter|RoleAdmin) != 0 {
+		return c.System | c.Namespaces[namespace]
+	}
+	return c.Namespaces[namespace]
+}
+
+// EffectiveClaims returns the claims of the subject the call is made for: the claims of the end user
+// for delegated calls, and the claims themselves otherwise
+func (c *Claims) EffectiveClaims() *Claims {
+	if c != nil && c.OnBehalfOf != nil {
+		return c.OnBehalfOf
+	}
+	return c
+}
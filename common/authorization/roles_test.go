@@ -25,7 +25,10 @@
 package authorization
 
 import (
+	"fmt"
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestInvalidRoles(t *testing.T) {
@@ -50,6 +53,123 @@ func TestValidRoles(t *testing.T) {
 	testValid(t, RoleReader|RoleWriter)
 	testValid(t, RoleReader|RoleAdmin)
 	testValid(t, RoleReader|RoleWriter|RoleAdmin)
+	testValid(t, RoleImpersonator)
+	testValid(t, RoleAdmin|RoleImpersonator)
+}
+
+func TestHighestRole(t *testing.T) {
+	require.Equal(t, RoleUndefined, RoleUndefined.Highest())
+	require.Equal(t, RoleUndefined, RoleImpersonator.Highest())
+	require.Equal(t, RoleWorker, RoleWorker.Highest())
+	require.Equal(t, RoleReader, (RoleWorker | RoleReader).Highest())
+	require.Equal(t, RoleWriter, (RoleReader | RoleWriter).Highest())
+	require.Equal(t, RoleAdmin, (RoleWorker | RoleReader | RoleWriter | RoleAdmin).Highest())
+	require.Equal(t, RoleAdmin, (RoleAdmin | RoleImpersonator).Highest())
+}
+
+// TestEffectiveRolePrecedence covers every combination of system and namespace roles and is the
+// reference for how the default authorizer resolves permissions.
+func TestEffectiveRolePrecedence(t *testing.T) {
+	testCases := []struct {
+		system        Role
+		namespace     Role
+		expectedRole  Role
+		expectedScope RoleScope
+	}{
+		{RoleUndefined, RoleUndefined, RoleUndefined, RoleScopeNone},
+		{RoleUndefined, RoleReader, RoleReader, RoleScopeNamespace},
+		{RoleUndefined, RoleWriter, RoleWriter, RoleScopeNamespace},
+		{RoleUndefined, RoleAdmin, RoleAdmin, RoleScopeNamespace},
+		{RoleReader, RoleUndefined, RoleUndefined, RoleScopeNone},
+		{RoleReader, RoleReader, RoleReader, RoleScopeNamespace},
+		{RoleReader, RoleWriter, RoleWriter, RoleScopeNamespace},
+		{RoleReader, RoleAdmin, RoleAdmin, RoleScopeNamespace},
+		{RoleWriter, RoleUndefined, RoleWriter, RoleScopeSystem},
+		{RoleWriter, RoleReader, RoleWriter, RoleScopeSystem},
+		{RoleWriter, RoleWriter, RoleWriter, RoleScopeSystem},
+		{RoleWriter, RoleAdmin, RoleWriter, RoleScopeSystem},
+		{RoleAdmin, RoleUndefined, RoleAdmin, RoleScopeSystem},
+		{RoleAdmin, RoleReader, RoleAdmin, RoleScopeSystem},
+		{RoleAdmin, RoleWriter, RoleAdmin, RoleScopeSystem},
+		{RoleAdmin, RoleAdmin, RoleAdmin, RoleScopeSystem},
+		// within a scope the highest ranked role wins
+		{RoleReader | RoleWriter, RoleUndefined, RoleWriter, RoleScopeSystem},
+		{RoleUndefined, RoleWorker | RoleReader | RoleAdmin, RoleAdmin, RoleScopeNamespace},
+		{RoleImpersonator, RoleUndefined, RoleUndefined, RoleScopeNone},
+	}
+	authorizer := NewDefaultAuthorizer()
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("system=%d,namespace=%d", tc.system, tc.namespace), func(t *testing.T) {
+			claims := &Claims{System: tc.system, Namespaces: map[string]Role{targetFooBar.Namespace: tc.namespace}}
+			role, scope := claims.EffectiveRole(targetFooBar.Namespace)
+			require.Equal(t, tc.expectedRole, role)
+			require.Equal(t, tc.expectedScope, scope)
+
+			result, err := authorizer.Authorize(nil, claims, &targetFooBar)
+			require.NoError(t, err)
+			if tc.expectedRole == RoleUndefined {
+				require.Equal(t, Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, result)
+			} else {
+				require.Equal(t, Result{Decision: DecisionAllow}, result)
+			}
+		})
+	}
+}
+
+func TestGrantedRolesParity(t *testing.T) {
+	// policies granting the API to every ranked role allow exactly the calls the default authorizer allows
+	rankedRoles := []Role{RoleWorker, RoleReader, RoleWriter, RoleAdmin}
+	policies := make(map[Role][]string)
+	for _, role := range rankedRoles {
+		policies[role] = []string{targetFooBar.APIName}
+	}
+	authorizers := []Authorizer{
+		NewStaticAuthorizer(policies),
+		NewTrieAuthorizer(policies),
+		NewClassificationAuthorizer(NewNoopAuthorizer(), map[string]Role{DefaultClassificationTier: RoleWorker}),
+	}
+	defaultAuthorizer := NewDefaultAuthorizer()
+
+	for system := RoleUndefined; system.IsValid(); system++ {
+		for namespace := RoleUndefined; namespace.IsValid(); namespace++ {
+			claims := &Claims{System: system, Namespaces: map[string]Role{targetFooBar.Namespace: namespace}}
+			for _, c := range []*Claims{claims, {Subject: "gateway", System: RoleAdmin, OnBehalfOf: claims}} {
+				role, _ := c.EffectiveClaims().EffectiveRole(targetFooBar.Namespace)
+				granted := c.GrantedRoles(targetFooBar.Namespace)
+				require.Equal(t, role, granted&role, "system=%d,namespace=%d", system, namespace)
+				require.Equal(t, role == RoleUndefined, granted.Highest() == RoleUndefined, "system=%d,namespace=%d", system, namespace)
+
+				expected, err := defaultAuthorizer.Authorize(nil, c, &targetFooBar)
+				require.NoError(t, err)
+				for _, authorizer := range authorizers {
+					actual, err := authorizer.Authorize(nil, c, &targetFooBar)
+					require.NoError(t, err)
+					require.Equal(t, expected, actual, "%T system=%d,namespace=%d", authorizer, system, namespace)
+				}
+			}
+		}
+	}
+}
+
+func TestGrantedRolesOfSystemLevelAPIs(t *testing.T) {
+	claims := &Claims{System: RoleReader, Namespaces: map[string]Role{targetFooBar.Namespace: RoleWorker}}
+	require.Equal(t, RoleReader, claims.GrantedRoles(""))
+	require.Equal(t, RoleWorker, claims.GrantedRoles(targetFooBar.Namespace))
+	require.Equal(t, RoleUndefined, claims.GrantedRoles("other"))
+
+	claims = &Claims{System: RoleWriter, Namespaces: map[string]Role{targetFooBar.Namespace: RoleWorker}}
+	require.Equal(t, RoleWriter|RoleWorker, claims.GrantedRoles(targetFooBar.Namespace))
+	require.Equal(t, RoleWriter, claims.GrantedRoles("other"))
+
+	var nilClaims *Claims
+	require.Equal(t, RoleUndefined, nilClaims.GrantedRoles(targetFooBar.Namespace))
+}
+
+func TestEffectiveRoleNilClaims(t *testing.T) {
+	var claims *Claims
+	role, scope := claims.EffectiveRole(targetFooBar.Namespace)
+	require.Equal(t, RoleUndefined, role)
+	require.Equal(t, RoleScopeNone, scope)
 }
 
 func testValid(t *testing.T, value Role) {
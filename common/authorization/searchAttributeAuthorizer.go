@@ -0,0 +1,147 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"strings"
+)
+
+// RestrictedSearchAttributeLabelPrefix prefixes the namespace labels restricting the use of a custom search
+// attribute, e.g. "restricted-search-attribute.CustomerId" set to "admin,write" restricts CustomerId to subjects
+// holding the admin or the writer role. Labels are resolved to a SearchAttributePolicy by NamespaceCacheLookup.
+const RestrictedSearchAttributeLabelPrefix = "restricted-search-attribute."
+
+type (
+	// SearchAttributePolicy restricts which search attributes subjects may set or query in a namespace
+	SearchAttributePolicy struct {
+		// Restricted maps the restricted search attribute keys to the roles allowed to use them,
+		// keys not in the map are unrestricted
+		Restricted map[string]Role
+	}
+
+	searchAttributeAuthorizer struct {
+		authorizer Authorizer
+	}
+)
+
+var _ Authorizer = (*searchAttributeAuthorizer)(nil)
+
+// NewSearchAttributeAuthorizer creates an authorizer that denies calls setting or querying a search attribute
+// restricted by the SearchAttributePolicy of the target namespace, or of the namespaces of the sub-targets,
+// to subjects not holding any of the roles allowed to use it there. The policies must have been resolved by
+// an interceptor configured WithSearchAttributePolicyLookup, calls without a policy are not restricted.
+// All other calls are decided by authorizer.
+func NewSearchAttributeAuthorizer(authorizer Authorizer) Authorizer {
+	return &searchAttributeAuthorizer{authorizer: authorizer}
+}
+
+func (a *searchAttributeAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if usesRestrictedSearchAttribute(claims.EffectiveClaims(), target) {
+		return Result{Decision: DecisionDeny, Reason: ReasonRestrictedSearchAttribute}, nil
+	}
+	return a.authorizer.Authorize(ctx, claims, target)
+}
+
+func usesRestrictedSearchAttribute(claims *Claims, target *CallTarget) bool {
+	if target.SearchAttributePolicy != nil && len(target.SearchAttributes) > 0 {
+		role, _ := claims.EffectiveRole(target.Namespace)
+		for _, key := range target.SearchAttributes {
+			if allowed, ok := target.SearchAttributePolicy.Restricted[key]; ok && role&allowed == 0 {
+				return true
+			}
+		}
+	}
+	for _, subTarget := range target.SubTargets {
+		if usesRestrictedSearchAttribute(claims, subTarget) {
+			return true
+		}
+	}
+	return false
+}
+
+// newSearchAttributePolicy creates the policy of a namespace from its labels, see
+// RestrictedSearchAttributeLabelPrefix. Returns nil if no search attribute is restricted.
+func newSearchAttributePolicy(labels map[string]string) *SearchAttributePolicy {
+	var policy *SearchAttributePolicy
+	for label, value := range labels {
+		name := strings.TrimPrefix(label, RestrictedSearchAttributeLabelPrefix)
+		if name == label || name == "" {
+			continue
+		}
+		var allowed Role
+		for _, permission := range strings.Split(value, ",") {
+			allowed |= permissionToRole(strings.TrimSpace(permission))
+		}
+		if policy == nil {
+			policy = &SearchAttributePolicy{Restricted: make(map[string]Role)}
+		}
+		policy.Restricted[name] = allowed
+	}
+	return policy
+}
+
+// queryKeys returns the identifiers a visibility query refers to, the search attributes it filters or orders by
+// and keywords such as AND. String literals are skipped, identifiers quoted with backticks are included.
+func queryKeys(query string) []string {
+	keys := make(map[string]struct{})
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"':
+			// skip the literal, quotes within it are escaped with a backslash
+			for i++; i < len(query) && query[i] != c; i++ {
+				if query[i] == '\\' {
+					i++
+				}
+			}
+			i++
+		case isIdentifierStart(c) || c == '`':
+			start := i
+			if c == '`' {
+				start++
+			}
+			for i++; i < len(query) && isIdentifierPart(query[i]); i++ {
+			}
+			if start < i {
+				keys[query[start:i]] = struct{}{}
+			}
+			if i < len(query) && query[i] == '`' {
+				i++
+			}
+		default:
+			i++
+		}
+	}
+	return sortedKeys(keys)
+}
+
+func isIdentifierStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentifierPart(c byte) bool {
+	return isIdentifierStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,140 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	searchAttributeAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		mockAuthorizer *MockAuthorizer
+		authorizer     Authorizer
+		policy         *SearchAttributePolicy
+		claims         *Claims
+	}
+)
+
+func TestSearchAttributeAuthorizerSuite(t *testing.T) {
+	s := new(searchAttributeAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *searchAttributeAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.authorizer = NewSearchAttributeAuthorizer(s.mockAuthorizer)
+	s.policy = &SearchAttributePolicy{Restricted: map[string]Role{"CustomerId": RoleAdmin}}
+	s.claims = &Claims{Subject: testSubject, Namespaces: map[string]Role{testNamespace: RoleWriter}}
+}
+
+func (s *searchAttributeAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *searchAttributeAuthorizerSuite) TestAllowedKeys() {
+	target := &CallTarget{APIName: startWorkflowExecutionTarget.APIName, Namespace: testNamespace,
+		SearchAttributes: []string{"OrderId"}, SearchAttributePolicy: s.policy}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, s.claims, target).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, s.claims, target)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *searchAttributeAuthorizerSuite) TestRestrictedKey() {
+	target := &CallTarget{APIName: startWorkflowExecutionTarget.APIName, Namespace: testNamespace,
+		SearchAttributes: []string{"CustomerId", "OrderId"}, SearchAttributePolicy: s.policy}
+
+	result, err := s.authorizer.Authorize(ctx, s.claims, target)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonRestrictedSearchAttribute, result.Reason)
+}
+
+func (s *searchAttributeAuthorizerSuite) TestRestrictedKeyWithAllowedRole() {
+	claims := &Claims{Subject: testSubject, Namespaces: map[string]Role{testNamespace: RoleAdmin}}
+	target := &CallTarget{APIName: startWorkflowExecutionTarget.APIName, Namespace: testNamespace,
+		SearchAttributes: []string{"CustomerId"}, SearchAttributePolicy: s.policy}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, target).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, claims, target)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *searchAttributeAuthorizerSuite) TestRestrictedKeyInSubTarget() {
+	target := &CallTarget{APIName: workflowServicePrefix + "RespondWorkflowTaskCompleted", Namespace: testNamespace,
+		SubTargets: []*CallTarget{{APIName: startWorkflowExecutionTarget.APIName, Namespace: testNamespace,
+			SearchAttributes: []string{"CustomerId"}, SearchAttributePolicy: s.policy}}}
+
+	result, err := s.authorizer.Authorize(ctx, s.claims, target)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonRestrictedSearchAttribute, result.Reason)
+}
+
+func (s *searchAttributeAuthorizerSuite) TestNoSearchAttributes() {
+	target := &CallTarget{APIName: startWorkflowExecutionTarget.APIName, Namespace: testNamespace, SearchAttributePolicy: s.policy}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, target).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, nil, target)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *searchAttributeAuthorizerSuite) TestNamespaceWithoutPolicy() {
+	target := &CallTarget{APIName: startWorkflowExecutionTarget.APIName, Namespace: testNamespace, SearchAttributes: []string{"CustomerId"}}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, s.claims, target).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, s.claims, target)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func TestQueryKeys(t *testing.T) {
+	testCases := []struct {
+		query    string
+		expected []string
+	}{
+		{query: "", expected: nil},
+		{query: "CustomerId = 'acme' and `OrderId` > 10", expected: []string{"CustomerId", "OrderId", "and"}},
+		{query: `WorkflowType = "CustomerId" or Amount<5 order by StartTime`,
+			expected: []string{"Amount", "StartTime", "WorkflowType", "by", "or", "order"}},
+		{query: `Note = 'it\'s CustomerId'`, expected: []string{"Note"}},
+	}
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, queryKeys(tc.query), tc.query)
+	}
+}
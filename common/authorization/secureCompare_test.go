@@ -0,0 +1,111 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// secretIdentifiers are the names of variables and fields holding secret material.
+// They may only be compared with secureCompare, or checked for being empty.
+var secretIdentifiers = map[string]struct{}{
+	"AuthToken": {},
+	"authToken": {},
+	"key":       {},
+	"secret":    {},
+	"signature": {},
+	"token":     {},
+}
+
+func TestSecureCompare(t *testing.T) {
+	require.True(t, secureCompare("", ""))
+	require.True(t, secureCompare("secret", "secret"))
+	require.False(t, secureCompare("secret", "secreT"))
+	require.False(t, secureCompare("secret", "secret2"))
+	require.False(t, secureCompare("", "secret"))
+}
+
+// TestNoDirectSecretComparison fails if a secret is compared with == or != anywhere in the package
+func TestNoDirectSecretComparison(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+
+	fileSet := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") || strings.HasSuffix(file, "_mock.go") {
+			continue
+		}
+		src, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		parsed, err := parser.ParseFile(fileSet, file, src, 0)
+		require.NoError(t, err)
+
+		ast.Inspect(parsed, func(node ast.Node) bool {
+			expr, ok := node.(*ast.BinaryExpr)
+			if !ok || (expr.Op != token.EQL && expr.Op != token.NEQ) {
+				return true
+			}
+			if isEmptyCheck(expr.X) || isEmptyCheck(expr.Y) {
+				return true
+			}
+			if isSecret(expr.X) || isSecret(expr.Y) {
+				t.Errorf("%s: secret compared with %s, use secureCompare", fileSet.Position(expr.Pos()), expr.Op)
+			}
+			return true
+		})
+	}
+}
+
+func isSecret(expr ast.Expr) bool {
+	var name string
+	switch e := expr.(type) {
+	case *ast.Ident:
+		name = e.Name
+	case *ast.SelectorExpr:
+		name = e.Sel.Name
+	default:
+		return false
+	}
+	_, ok := secretIdentifiers[name]
+	return ok
+}
+
+func isEmptyCheck(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return e.Value == `""`
+	case *ast.Ident:
+		return e.Name == "nil"
+	}
+	return false
+}
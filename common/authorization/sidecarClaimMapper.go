@@ -0,0 +1,155 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	// SidecarClaimsPath is the HTTP path the sidecar serves claims on
+	SidecarClaimsPath = "/claims"
+
+	defaultSidecarTimeout = 500 * time.Millisecond
+	// the host of sidecar URLs is ignored, requests are always sent to the socket
+	sidecarURL = "http://sidecar" + SidecarClaimsPath
+)
+
+type (
+	// SidecarClaimsRequest is the body of the requests to the identity sidecar, the raw credentials of the caller
+	SidecarClaimsRequest struct {
+		AuthToken   string `json:"authToken,omitempty"`
+		ExtraData   string `json:"extraData,omitempty"`
+		Cookie      string `json:"cookie,omitempty"`
+		TLSSubject  string `json:"tlsSubject,omitempty"`
+		PeerAddress string `json:"peerAddress,omitempty"`
+	}
+
+	// SidecarClaimsResponse is the body of the responses of the identity sidecar, the claims of the caller
+	SidecarClaimsResponse struct {
+		Subject     string          `json:"subject"`
+		Issuer      string          `json:"issuer,omitempty"`
+		Audience    []string        `json:"audience,omitempty"`
+		System      Role            `json:"system,omitempty"`
+		Namespaces  map[string]Role `json:"namespaces,omitempty"`
+		AuthMethods []string        `json:"authMethods,omitempty"`
+		AuthLevel   int             `json:"authLevel,omitempty"`
+		Groups      []string        `json:"groups,omitempty"`
+	}
+
+	sidecarClaimMapper struct {
+		client  *http.Client
+		timeout time.Duration
+	}
+)
+
+var _ ClaimMapper = (*sidecarClaimMapper)(nil)
+
+// NewSidecarClaimMapper creates a claim mapper that resolves the claims of callers with an identity sidecar
+// listening on the unix domain socket at socketPath, with the default timeout of 500ms
+func NewSidecarClaimMapper(socketPath string) ClaimMapper {
+	return NewSidecarClaimMapperWithTimeout(socketPath, defaultSidecarTimeout)
+}
+
+// NewSidecarClaimMapperWithTimeout creates a claim mapper that posts the credentials of each caller as
+// a SidecarClaimsRequest to SidecarClaimsPath of the HTTP server of an identity sidecar listening on
+// the unix domain socket at socketPath, and maps the SidecarClaimsResponse the sidecar returns to claims.
+// Connections to the sidecar are kept alive and reused. Calls fail if the sidecar can't be reached,
+// doesn't respond with 200 OK within timeout, or returns a malformed response.
+func NewSidecarClaimMapperWithTimeout(socketPath string, timeout time.Duration) ClaimMapper {
+	dialer := net.Dialer{Timeout: timeout}
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", socketPath)
+		},
+		MaxIdleConnsPerHost: 16,
+	}
+	return &sidecarClaimMapper{
+		client:  &http.Client{Transport: transport},
+		timeout: timeout,
+	}
+}
+
+func (m *sidecarClaimMapper) GetClaims(ctx context.Context, authInfo *AuthInfo) (*Claims, error) {
+	body, err := json.Marshal(newSidecarClaimsRequest(authInfo))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, sidecarURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := m.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get claims from identity sidecar: %w", err)
+	}
+	defer response.Body.Close()
+
+	content, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read claims from identity sidecar: %w", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("identity sidecar responded with status %v", response.StatusCode)
+	}
+	var claims SidecarClaimsResponse
+	if err := json.Unmarshal(content, &claims); err != nil {
+		return nil, fmt.Errorf("identity sidecar returned malformed claims: %w", err)
+	}
+	return &Claims{
+		Subject:     claims.Subject,
+		Issuer:      claims.Issuer,
+		Audience:    claims.Audience,
+		System:      claims.System,
+		Namespaces:  claims.Namespaces,
+		AuthMethods: claims.AuthMethods,
+		AuthLevel:   claims.AuthLevel,
+		Groups:      claims.Groups,
+	}, nil
+}
+
+func newSidecarClaimsRequest(authInfo *AuthInfo) *SidecarClaimsRequest {
+	request := &SidecarClaimsRequest{
+		AuthToken:   authInfo.AuthToken,
+		ExtraData:   authInfo.ExtraData,
+		Cookie:      authInfo.Cookie,
+		PeerAddress: authInfo.PeerAddress,
+	}
+	if authInfo.TLSSubject != nil {
+		request.TLSSubject = authInfo.TLSSubject.String()
+	}
+	return request
+}
@@ -0,0 +1,181 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	sidecarClaimMapperSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		dir         string
+		socketPath  string
+		server      *http.Server
+		handler     http.HandlerFunc
+		connections int32
+	}
+)
+
+func TestSidecarClaimMapperSuite(t *testing.T) {
+	s := new(sidecarClaimMapperSuite)
+	suite.Run(t, s)
+}
+
+func (s *sidecarClaimMapperSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	dir, err := ioutil.TempDir("", "sidecar")
+	s.NoError(err)
+	s.dir = dir
+	s.socketPath = filepath.Join(dir, "sidecar.sock")
+	s.connections = 0
+
+	listener, err := net.Listen("unix", s.socketPath)
+	s.NoError(err)
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { s.handler(w, r) }),
+		ConnState: func(_ net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				atomic.AddInt32(&s.connections, 1)
+			}
+		},
+	}
+	s.server = server
+	go func() { _ = server.Serve(listener) }()
+}
+
+func (s *sidecarClaimMapperSuite) TearDownTest() {
+	s.NoError(s.server.Close())
+	s.NoError(os.RemoveAll(s.dir))
+}
+
+func (s *sidecarClaimMapperSuite) TestClaims() {
+	s.handler = func(w http.ResponseWriter, r *http.Request) {
+		s.Equal(http.MethodPost, r.Method)
+		s.Equal(SidecarClaimsPath, r.URL.Path)
+		var request SidecarClaimsRequest
+		s.NoError(json.NewDecoder(r.Body).Decode(&request))
+		s.Equal("Bearer token", request.AuthToken)
+		s.Equal("CN="+testSubject, request.TLSSubject)
+		s.NoError(json.NewEncoder(w).Encode(SidecarClaimsResponse{
+			Subject:    testSubject,
+			System:     RoleReader,
+			Namespaces: map[string]Role{testNamespace: RoleWriter | RoleWorker},
+			AuthLevel:  2,
+			Groups:     []string{"developers"},
+		}))
+	}
+	mapper := NewSidecarClaimMapper(s.socketPath)
+	authInfo := &AuthInfo{AuthToken: "Bearer token", TLSSubject: &pkix.Name{CommonName: testSubject}}
+
+	for i := 0; i < 3; i++ {
+		claims, err := mapper.GetClaims(ctx, authInfo)
+		s.NoError(err)
+		s.Equal(&Claims{
+			Subject:    testSubject,
+			System:     RoleReader,
+			Namespaces: map[string]Role{testNamespace: RoleWriter | RoleWorker},
+			AuthLevel:  2,
+			Groups:     []string{"developers"},
+		}, claims)
+	}
+	// the connection is reused
+	s.Equal(int32(1), atomic.LoadInt32(&s.connections))
+}
+
+func (s *sidecarClaimMapperSuite) TestTimeout() {
+	done := make(chan struct{})
+	defer close(done)
+	s.handler = func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}
+	mapper := NewSidecarClaimMapperWithTimeout(s.socketPath, 50*time.Millisecond)
+
+	start := time.Now()
+	claims, err := mapper.GetClaims(ctx, &AuthInfo{AuthToken: "Bearer token"})
+	s.Error(err)
+	s.Nil(claims)
+	s.True(time.Since(start) < time.Second)
+}
+
+func (s *sidecarClaimMapperSuite) TestCallerDeadline() {
+	s.handler = func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}
+	mapper := NewSidecarClaimMapper(s.socketPath)
+	callCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	_, err := mapper.GetClaims(callCtx, &AuthInfo{AuthToken: "Bearer token"})
+	s.Error(err)
+}
+
+func (s *sidecarClaimMapperSuite) TestError() {
+	s.handler = func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+	}
+	mapper := NewSidecarClaimMapper(s.socketPath)
+
+	claims, err := mapper.GetClaims(ctx, &AuthInfo{AuthToken: "Bearer token"})
+	s.Error(err)
+	s.Nil(claims)
+}
+
+func (s *sidecarClaimMapperSuite) TestMalformedResponse() {
+	s.handler = func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("not json"))
+	}
+	mapper := NewSidecarClaimMapper(s.socketPath)
+
+	claims, err := mapper.GetClaims(ctx, &AuthInfo{AuthToken: "Bearer token"})
+	s.Error(err)
+	s.Nil(claims)
+}
+
+func (s *sidecarClaimMapperSuite) TestSidecarUnavailable() {
+	mapper := NewSidecarClaimMapper(filepath.Join(s.dir, "missing.sock"))
+
+	claims, err := mapper.GetClaims(ctx, &AuthInfo{AuthToken: "Bearer token"})
+	s.Error(err)
+	s.Nil(claims)
+}
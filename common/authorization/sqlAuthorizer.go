@@ -0,0 +1,105 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+const (
+	// sqlAuthorizerMaxConcurrentQueries bounds the connections the authorizer takes from the pool at once
+	sqlAuthorizerMaxConcurrentQueries = 8
+)
+
+type sqlAuthorizer struct {
+	db    *sql.DB
+	query string
+	// semaphore limits the number of queries in flight
+	semaphore chan struct{}
+
+	sync.Mutex
+	stmt *sql.Stmt
+}
+
+var _ Authorizer = (*sqlAuthorizer)(nil)
+
+// NewSQLAuthorizer creates an authorizer that looks up permissions in a SQL database. The query takes the subject,
+// namespace and API name as its three parameters, in this order, and must return a single boolean column
+// that is true if the call is allowed. A call is denied if the query returns no rows. The query
+// is prepared once and reused for all calls.
+func NewSQLAuthorizer(db *sql.DB, query string) Authorizer {
+	return &sqlAuthorizer{
+		db:        db,
+		query:     query,
+		semaphore: make(chan struct{}, sqlAuthorizerMaxConcurrentQueries),
+	}
+}
+
+func (a *sqlAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if claims == nil {
+		return Result{Decision: DecisionDeny, Reason: ReasonNoClaims}, nil
+	}
+
+	select {
+	case a.semaphore <- struct{}{}:
+		defer func() { <-a.semaphore }()
+	case <-ctx.Done():
+		return Result{}, ctx.Err()
+	}
+
+	stmt, err := a.prepare(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var allowed bool
+	err = stmt.QueryRowContext(ctx, claims.Subject, target.Namespace, target.APIName).Scan(&allowed)
+	switch {
+	case err == sql.ErrNoRows:
+		return Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil
+	case err != nil:
+		return Result{}, err
+	case !allowed:
+		return Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil
+	}
+	return Result{Decision: DecisionAllow}, nil
+}
+
+// prepare returns the prepared query, preparing it on first use or if an earlier attempt failed
+func (a *sqlAuthorizer) prepare(ctx context.Context) (*sql.Stmt, error) {
+	a.Lock()
+	defer a.Unlock()
+
+	if a.stmt == nil {
+		stmt, err := a.db.PrepareContext(ctx, a.query)
+		if err != nil {
+			return nil, err
+		}
+		a.stmt = stmt
+	}
+	return a.stmt, nil
+}
@@ -0,0 +1,237 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/atomic"
+)
+
+const (
+	testPermissionQuery = "SELECT allowed FROM permissions WHERE subject = $1 AND namespace = $2 AND api = $3"
+)
+
+type (
+	sqlAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		driver     *testPermissionDriver
+		db         *sql.DB
+		authorizer Authorizer
+	}
+
+	// testPermissionDriver is a database/sql driver serving permissions from a map keyed by subject
+	testPermissionDriver struct {
+		permissions map[string]bool
+		queryErr    error
+		prepares    *atomic.Int32
+		inFlight    *atomic.Int32
+		maxInFlight *atomic.Int32
+		block       chan struct{}
+	}
+
+	testPermissionConn struct {
+		driver *testPermissionDriver
+	}
+
+	testPermissionStmt struct {
+		driver *testPermissionDriver
+	}
+
+	testPermissionRows struct {
+		values []bool
+	}
+)
+
+var testPermissionDriverCount = atomic.NewInt32(0)
+
+func TestSQLAuthorizerSuite(t *testing.T) {
+	s := new(sqlAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *sqlAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.driver = &testPermissionDriver{
+		permissions: map[string]bool{"allowed": true, "denied": false},
+		prepares:    atomic.NewInt32(0),
+		inFlight:    atomic.NewInt32(0),
+		maxInFlight: atomic.NewInt32(0),
+	}
+	// each test gets its own driver since registered drivers can't be replaced
+	name := fmt.Sprintf("testPermissions%d", testPermissionDriverCount.Inc())
+	sql.Register(name, s.driver)
+	db, err := sql.Open(name, "")
+	s.NoError(err)
+	s.db = db
+	s.authorizer = NewSQLAuthorizer(db, testPermissionQuery)
+}
+
+func (s *sqlAuthorizerSuite) TearDownTest() {
+	s.NoError(s.db.Close())
+}
+
+func (s *sqlAuthorizerSuite) TestAllowed() {
+	result, err := s.authorizer.Authorize(ctx, &Claims{Subject: "allowed"}, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *sqlAuthorizerSuite) TestDenied() {
+	result, err := s.authorizer.Authorize(ctx, &Claims{Subject: "denied"}, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonInsufficientRole, result.Reason)
+}
+
+func (s *sqlAuthorizerSuite) TestNoRows() {
+	result, err := s.authorizer.Authorize(ctx, &Claims{Subject: "unknown"}, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+}
+
+func (s *sqlAuthorizerSuite) TestNoClaims() {
+	result, err := s.authorizer.Authorize(ctx, nil, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonNoClaims, result.Reason)
+	s.Equal(int32(0), s.driver.prepares.Load())
+}
+
+func (s *sqlAuthorizerSuite) TestQueryError() {
+	s.driver.queryErr = errors.New("connection reset")
+
+	result, err := s.authorizer.Authorize(ctx, &Claims{Subject: "allowed"}, describeNamespaceTarget)
+	s.Error(err)
+	s.NotEqual(DecisionAllow, result.Decision)
+}
+
+func (s *sqlAuthorizerSuite) TestStatementReused() {
+	for i := 0; i < 5; i++ {
+		_, err := s.authorizer.Authorize(ctx, &Claims{Subject: "allowed"}, describeNamespaceTarget)
+		s.NoError(err)
+	}
+	s.Equal(int32(1), s.driver.prepares.Load())
+}
+
+func (s *sqlAuthorizerSuite) TestBoundedConcurrency() {
+	s.driver.block = make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 3*sqlAuthorizerMaxConcurrentQueries; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.authorizer.Authorize(context.Background(), &Claims{Subject: "allowed"}, describeNamespaceTarget)
+			s.NoError(err)
+		}()
+	}
+	close(s.driver.block)
+	wg.Wait()
+	s.LessOrEqual(s.driver.maxInFlight.Load(), int32(sqlAuthorizerMaxConcurrentQueries))
+}
+
+func (d *testPermissionDriver) Open(_ string) (driver.Conn, error) {
+	return &testPermissionConn{driver: d}, nil
+}
+
+func (c *testPermissionConn) Prepare(query string) (driver.Stmt, error) {
+	if query != testPermissionQuery {
+		return nil, errors.New("unexpected query")
+	}
+	c.driver.prepares.Inc()
+	return &testPermissionStmt{driver: c.driver}, nil
+}
+
+func (c *testPermissionConn) Close() error {
+	return nil
+}
+
+func (c *testPermissionConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+func (s *testPermissionStmt) Close() error {
+	return nil
+}
+
+func (s *testPermissionStmt) NumInput() int {
+	return 3
+}
+
+func (s *testPermissionStmt) Exec(_ []driver.Value) (driver.Result, error) {
+	return nil, errors.New("exec is not supported")
+}
+
+func (s *testPermissionStmt) Query(args []driver.Value) (driver.Rows, error) {
+	d := s.driver
+	inFlight := d.inFlight.Inc()
+	defer d.inFlight.Dec()
+	for {
+		maxInFlight := d.maxInFlight.Load()
+		if inFlight <= maxInFlight || d.maxInFlight.CAS(maxInFlight, inFlight) {
+			break
+		}
+	}
+	if d.block != nil {
+		<-d.block
+	}
+
+	if d.queryErr != nil {
+		return nil, d.queryErr
+	}
+	allowed, ok := d.permissions[args[0].(string)]
+	if !ok {
+		return &testPermissionRows{}, nil
+	}
+	return &testPermissionRows{values: []bool{allowed}}, nil
+}
+
+func (r *testPermissionRows) Columns() []string {
+	return []string{"allowed"}
+}
+
+func (r *testPermissionRows) Close() error {
+	return nil
+}
+
+func (r *testPermissionRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	dest[0] = r.values[0]
+	r.values = r.values[1:]
+	return nil
+}
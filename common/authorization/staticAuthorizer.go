@@ -0,0 +1,144 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+const (
+	apiNameWildcard = "*"
+	apiGroupPrefix  = "group:"
+)
+
+type (
+	// StaticAuthorizer is an Authorizer with a role-to-API policy that can be replaced at runtime,
+	// e.g. by a watcher of the file the policy is loaded from
+	StaticAuthorizer interface {
+		Authorizer
+		APIEnumerator
+		PolicySnapshotter
+		// UpdatePolicies atomically replaces the policy. Authorize calls in flight complete with the old policy.
+		UpdatePolicies(policies map[Role][]string)
+	}
+
+	staticAuthorizer struct {
+		sync.RWMutex
+		policies map[Role][]string
+	}
+)
+
+var _ StaticAuthorizer = (*staticAuthorizer)(nil)
+
+// NewStaticAuthorizer creates an authorizer that allows a call if any of the caller's roles in the target namespace,
+// see Claims.GrantedRoles, is granted the API by policies. API names in policies are either full API names or
+// prefixes followed by "*", e.g. "/temporal.api.workflowservice.v1.WorkflowService/*", or API groups prefixed
+// with "group:", e.g. "group:read". Calls to APIs that no entry of policies matches, for any role, are
+// decided DecisionUnknown, they are denied like other calls that are not granted, see ValidateCoverage.
+func NewStaticAuthorizer(policies map[Role][]string) StaticAuthorizer {
+	a := &staticAuthorizer{}
+	a.UpdatePolicies(policies)
+	return a
+}
+
+func (a *staticAuthorizer) UpdatePolicies(policies map[Role][]string) {
+	// copy, so that the caller can't modify the policy without holding the lock
+	copied := make(map[Role][]string, len(policies))
+	for role, apiNames := range policies {
+		copied[role] = append([]string(nil), apiNames...)
+	}
+
+	a.Lock()
+	defer a.Unlock()
+	a.policies = copied
+}
+
+func (a *staticAuthorizer) Authorize(_ context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if claims == nil {
+		return Result{Decision: DecisionDeny, Reason: ReasonNoClaims}, nil
+	}
+	roles := claims.GrantedRoles(target.Namespace)
+
+	a.RLock()
+	defer a.RUnlock()
+	if a.isGranted(roles, target.APIName) {
+		return Result{Decision: DecisionAllow}, nil
+	}
+	if !a.isGranted(^RoleUndefined, target.APIName) {
+		return Result{Decision: DecisionUnknown, Reason: ReasonUnclassifiedAPI}, nil
+	}
+	return Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil
+}
+
+// PermittedAPIs lists the workflow service APIs granted to the caller's roles by the policy
+func (a *staticAuthorizer) PermittedAPIs(_ context.Context, claims *Claims, namespace string) ([]string, error) {
+	if claims == nil {
+		return nil, nil
+	}
+	roles := claims.GrantedRoles(namespace)
+
+	a.RLock()
+	defer a.RUnlock()
+	var permitted []string
+	for _, api := range WorkflowServiceAPIs {
+		if a.isGranted(roles, api) {
+			permitted = append(permitted, api)
+		}
+	}
+	return permitted, nil
+}
+
+func (a *staticAuthorizer) PolicySnapshot() *PolicySnapshot {
+	a.RLock()
+	defer a.RUnlock()
+	return newPolicySnapshot(a.policies)
+}
+
+func (a *staticAuthorizer) isGranted(roles Role, api string) bool {
+	for role, apiNames := range a.policies {
+		if roles&role == 0 {
+			continue
+		}
+		for _, apiName := range apiNames {
+			if matchAPIName(apiName, api) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchAPIName(pattern string, apiName string) bool {
+	if strings.HasPrefix(pattern, apiGroupPrefix) {
+		group := GetAPIGroup(apiName)
+		return group != APIGroupUnknown && group == APIGroup(strings.TrimPrefix(pattern, apiGroupPrefix))
+	}
+	if strings.HasSuffix(pattern, apiNameWildcard) {
+		return strings.HasPrefix(apiName, strings.TrimSuffix(pattern, apiNameWildcard))
+	}
+	return pattern == apiName
+}
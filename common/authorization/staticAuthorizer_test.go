@@ -0,0 +1,182 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	staticAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		authorizer StaticAuthorizer
+	}
+)
+
+func TestStaticAuthorizerSuite(t *testing.T) {
+	s := new(staticAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *staticAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.authorizer = NewStaticAuthorizer(map[Role][]string{
+		RoleReader: {describeNamespaceTarget.APIName},
+		RoleAdmin:  {workflowServicePrefix + "*"},
+	})
+}
+
+func (s *staticAuthorizerSuite) TestAllowed() {
+	s.assertDecision(DecisionAllow, &Claims{Namespaces: map[string]Role{testNamespace: RoleReader}}, describeNamespaceTarget)
+	s.assertDecision(DecisionAllow, &Claims{System: RoleAdmin}, startWorkflowExecutionTarget)
+	s.assertDecision(DecisionAllow, &Claims{Namespaces: map[string]Role{testNamespace: RoleWorker | RoleAdmin}}, startWorkflowExecutionTarget)
+}
+
+func (s *staticAuthorizerSuite) TestDenied() {
+	s.assertDecision(DecisionDeny, nil, describeNamespaceTarget)
+	s.assertDecision(DecisionDeny, &Claims{Namespaces: map[string]Role{testNamespace: RoleReader}}, startWorkflowExecutionTarget)
+	s.assertDecision(DecisionDeny, &Claims{Namespaces: map[string]Role{"other": RoleAdmin}}, startWorkflowExecutionTarget)
+}
+
+func (s *staticAuthorizerSuite) TestPermittedAPIs() {
+	apis, err := s.authorizer.PermittedAPIs(ctx, &Claims{Namespaces: map[string]Role{testNamespace: RoleReader}}, testNamespace)
+	s.NoError(err)
+	s.Equal([]string{describeNamespaceTarget.APIName}, apis)
+
+	apis, err = s.authorizer.PermittedAPIs(ctx, &Claims{Namespaces: map[string]Role{testNamespace: RoleReader}}, "other")
+	s.NoError(err)
+	s.Empty(apis)
+
+	apis, err = s.authorizer.PermittedAPIs(ctx, &Claims{System: RoleAdmin}, testNamespace)
+	s.NoError(err)
+	s.Equal(WorkflowServiceAPIs, apis)
+
+	apis, err = s.authorizer.PermittedAPIs(ctx, nil, testNamespace)
+	s.NoError(err)
+	s.Empty(apis)
+}
+
+func (s *staticAuthorizerSuite) TestAPIGroups() {
+	s.authorizer.UpdatePolicies(map[Role][]string{
+		RoleReader: {apiGroupPrefix + string(APIGroupRead)},
+		RoleWriter: {apiGroupPrefix + string(APIGroupWrite)},
+	})
+	reader := &Claims{Namespaces: map[string]Role{testNamespace: RoleReader}}
+	writer := &Claims{Namespaces: map[string]Role{testNamespace: RoleWriter}}
+	s.assertDecision(DecisionAllow, reader, describeNamespaceTarget)
+	s.assertDecision(DecisionDeny, reader, startWorkflowExecutionTarget)
+	s.assertDecision(DecisionAllow, writer, startWorkflowExecutionTarget)
+	s.assertDecision(DecisionUnknown, writer, &CallTarget{
+		APIName: workflowServicePrefix + "UpdateNamespace", APIGroup: APIGroupAdmin, Namespace: testNamespace})
+
+	apis, err := s.authorizer.PermittedAPIs(ctx, writer, testNamespace)
+	s.NoError(err)
+	s.Contains(apis, startWorkflowExecutionTarget.APIName)
+	s.NotContains(apis, describeNamespaceTarget.APIName)
+}
+
+func (s *staticAuthorizerSuite) TestUpdatePolicies() {
+	claims := &Claims{Namespaces: map[string]Role{testNamespace: RoleReader}}
+	policies := map[Role][]string{RoleReader: {startWorkflowExecutionTarget.APIName}}
+	s.authorizer.UpdatePolicies(policies)
+	s.assertDecision(DecisionAllow, claims, startWorkflowExecutionTarget)
+	s.assertDecision(DecisionUnknown, claims, describeNamespaceTarget)
+
+	// policy is copied on update
+	policies[RoleReader][0] = describeNamespaceTarget.APIName
+	s.assertDecision(DecisionAllow, claims, startWorkflowExecutionTarget)
+}
+
+func (s *staticAuthorizerSuite) TestPolicySnapshot() {
+	s.Equal(&PolicySnapshot{Roles: map[string][]string{
+		"read":  {describeNamespaceTarget.APIName},
+		"admin": {workflowServicePrefix + "*"},
+	}}, s.authorizer.PolicySnapshot())
+
+	s.authorizer.UpdatePolicies(map[Role][]string{
+		RoleReader | RoleWriter: {startWorkflowExecutionTarget.APIName, describeNamespaceTarget.APIName},
+	})
+	s.Equal(&PolicySnapshot{Roles: map[string][]string{
+		"read|write": {describeNamespaceTarget.APIName, startWorkflowExecutionTarget.APIName},
+	}}, s.authorizer.PolicySnapshot())
+}
+
+func (s *staticAuthorizerSuite) TestConcurrentUpdatePolicies() {
+	claims := &Claims{Namespaces: map[string]Role{testNamespace: RoleReader}}
+	readerPolicies := map[Role][]string{RoleReader: {describeNamespaceTarget.APIName}}
+	writerPolicies := map[Role][]string{RoleWriter: {describeNamespaceTarget.APIName}}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			if i%2 == 0 {
+				s.authorizer.UpdatePolicies(writerPolicies)
+			} else {
+				s.authorizer.UpdatePolicies(readerPolicies)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			_, err := s.authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+			s.NoError(err)
+		}
+	}()
+	wg.Wait()
+
+	s.authorizer.UpdatePolicies(readerPolicies)
+	s.assertDecision(DecisionAllow, claims, describeNamespaceTarget)
+}
+
+func (s *staticAuthorizerSuite) assertDecision(expected Decision, claims *Claims, target *CallTarget) {
+	result, err := s.authorizer.Authorize(ctx, claims, target)
+	s.NoError(err)
+	s.Equal(expected, result.Decision)
+}
+
+func (s *staticAuthorizerSuite) TestUnknownAPIGroup() {
+	s.authorizer.UpdatePolicies(map[Role][]string{
+		RoleReader: {apiGroupPrefix + string(APIGroupRead), apiGroupPrefix + string(APIGroupUnknown)},
+	})
+	reader := &Claims{System: RoleReader}
+	s.assertDecision(DecisionAllow, reader, &CallTarget{APIName: adminServicePrefix + "DescribeCluster"})
+	s.assertDecision(DecisionUnknown, reader, &CallTarget{APIName: adminServicePrefix + "ReapplyEvents"})
+	s.assertDecision(DecisionUnknown, reader, &CallTarget{APIName: "/grpc.health.v1.Health/Check"})
+}
+
+func (s *staticAuthorizerSuite) TestUnclassifiedAPI() {
+	result, err := s.authorizer.Authorize(ctx, &Claims{System: RoleAdmin}, &CallTarget{APIName: adminServicePrefix + "CloseShard"})
+	s.NoError(err)
+	s.Equal(Result{Decision: DecisionUnknown, Reason: ReasonUnclassifiedAPI}, result)
+}
@@ -0,0 +1,140 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/metrics"
+)
+
+type authorizingServerStream struct {
+	grpc.ServerStream
+	ctx         context.Context
+	interceptor *interceptor
+	method      string
+	extractor   StreamMessageTargetExtractor
+}
+
+// NewAuthorizationStreamInterceptor creates an authorization interceptor for streaming calls. The stream open is
+// authorized like a unary call without a request, messages received on the stream are authorized only
+// for the methods registered with WithStreamMessageTargets.
+func NewAuthorizationStreamInterceptor(
+	claimMapper ClaimMapper,
+	authorizer Authorizer,
+	metrics metrics.Client,
+	logger log.Logger,
+	opts ...InterceptorOption,
+) grpc.StreamServerInterceptor {
+	a := &interceptor{
+		claimMapper:   claimMapper,
+		authorizer:    authorizer,
+		metricsClient: metrics,
+		logger:        logger,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a.StreamInterceptor
+}
+
+func (a *interceptor) StreamInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+
+	if a.authorizer == nil {
+		return handler(srv, ss)
+	}
+
+	ctx, err := a.authorizeCall(ss.Context(), nil, &grpc.UnaryServerInfo{Server: srv, FullMethod: info.FullMethod})
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authorizingServerStream{
+		ServerStream: ss,
+		ctx:          ctx,
+		interceptor:  a,
+		method:       info.FullMethod,
+		extractor:    a.streamMessageTargets[info.FullMethod],
+	})
+}
+
+// authorizeMessage authorizes a message received on a stream of method with the claims the stream was opened with
+func (a *interceptor) authorizeMessage(ctx context.Context, method string, target *CallTarget) error {
+	if target.APIName == "" {
+		target.APIName = method
+	}
+	if target.APIGroup == "" {
+		target.APIGroup = GetAPIGroup(target.APIName)
+	}
+	claims, _ := ctx.Value(ContextKeyMappedClaims).(*Claims)
+
+	scope := a.getMetricsScope(metrics.AuthorizationScope, target.Namespace)
+	startTime := time.Now()
+	result, err := a.authorize(ctx, claims, target)
+	if err != nil {
+		if err == errAuthorizationTimeout {
+			scope.IncCounter(metrics.ServiceErrAuthorizeTimeoutCounter)
+		}
+		scope.IncCounter(metrics.ServiceErrAuthorizeFailedCounter)
+		return a.logAuthError(err)
+	}
+	a.observeDecisionMetrics(target, result, time.Since(startTime))
+	if a.decisionObservations != nil {
+		a.decisionObservations.enqueue(ctx, claims, target, result)
+	}
+	if result.Decision != DecisionAllow {
+		a.incUnauthorized(ctx, scope, target.Namespace)
+		scope.Tagged(metrics.ReasonTag(result.Reason.metricTagValue())).IncCounter(metrics.ServiceAuthorizationDenyReasonCounter)
+		a.logDenyReason(claims, target, result)
+		return a.denyError(claims, target, result)
+	}
+	return nil
+}
+
+func (s *authorizingServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *authorizingServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if s.extractor == nil {
+		return nil
+	}
+	target := s.extractor(m)
+	if target == nil {
+		return nil
+	}
+	return s.interceptor.authorizeMessage(s.ctx, s.method, target)
+}
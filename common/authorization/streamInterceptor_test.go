@@ -0,0 +1,165 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+
+	"go.temporal.io/server/common/log/loggerimpl"
+	"go.temporal.io/server/common/metrics"
+)
+
+const testStreamMethod = "/temporal.api.workflowservice.v1.WorkflowService/SignalStream"
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	messages []*workflowservice.SignalWorkflowExecutionRequest
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testServerStream) RecvMsg(m interface{}) error {
+	if len(s.messages) == 0 {
+		return io.EOF
+	}
+	*m.(*workflowservice.SignalWorkflowExecutionRequest) = *s.messages[0]
+	s.messages = s.messages[1:]
+	return nil
+}
+
+func signalStreamTarget(msg interface{}) *CallTarget {
+	return &CallTarget{Namespace: msg.(*workflowservice.SignalWorkflowExecutionRequest).GetNamespace()}
+}
+
+// signalStreamHandler receives until the stream ends or a receive fails, returning the namespaces it received
+func signalStreamHandler(received *[]string) grpc.StreamHandler {
+	return func(srv interface{}, stream grpc.ServerStream) error {
+		for {
+			var msg workflowservice.SignalWorkflowExecutionRequest
+			if err := stream.RecvMsg(&msg); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+			*received = append(*received, msg.GetNamespace())
+		}
+	}
+}
+
+func newTestStreamInterceptor(controller *gomock.Controller, authorizer Authorizer, opts ...InterceptorOption) grpc.StreamServerInterceptor {
+	claimMapper := NewMockClaimMapper(controller)
+	claimMapper.EXPECT().GetClaims(gomock.Any(), gomock.Any()).Return(&Claims{Subject: testSubject}, nil).AnyTimes()
+	return NewAuthorizationStreamInterceptor(
+		claimMapper,
+		authorizer,
+		metrics.NewClient(tally.NoopScope, metrics.Frontend),
+		loggerimpl.NewLogger(zap.NewNop()),
+		opts...)
+}
+
+func TestStreamInterceptorAuthorizesMessages(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	claims := &Claims{Subject: testSubject}
+	authorizer := NewMockAuthorizer(controller)
+	gomock.InOrder(
+		authorizer.EXPECT().Authorize(gomock.Any(), claims, &CallTarget{APIName: testStreamMethod, APIGroup: APIGroupUnknown}).
+			Return(Result{Decision: DecisionAllow}, nil),
+		authorizer.EXPECT().Authorize(gomock.Any(), claims, &CallTarget{APIName: testStreamMethod, APIGroup: APIGroupUnknown, Namespace: testNamespace}).
+			Return(Result{Decision: DecisionAllow}, nil),
+		authorizer.EXPECT().Authorize(gomock.Any(), claims, &CallTarget{APIName: testStreamMethod, APIGroup: APIGroupUnknown, Namespace: "other"}).
+			Return(Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil),
+	)
+	interceptor := newTestStreamInterceptor(controller, authorizer, WithStreamMessageTargets(testStreamMethod, signalStreamTarget))
+
+	stream := &testServerStream{
+		ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer token")),
+		messages: []*workflowservice.SignalWorkflowExecutionRequest{
+			{Namespace: testNamespace},
+			{Namespace: "other"},
+			{Namespace: testNamespace},
+		},
+	}
+	var received []string
+	err := interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: testStreamMethod}, signalStreamHandler(&received))
+	require.Equal(t, codes.PermissionDenied, serviceerror.ToStatus(err).Code())
+	require.Equal(t, []string{testNamespace}, received)
+	require.Len(t, stream.messages, 1)
+}
+
+func TestStreamInterceptorDeniesStreamOpen(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	authorizer := NewMockAuthorizer(controller)
+	authorizer.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil)
+	interceptor := newTestStreamInterceptor(controller, authorizer, WithStreamMessageTargets(testStreamMethod, signalStreamTarget))
+
+	stream := &testServerStream{ctx: context.Background()}
+	err := interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: testStreamMethod}, func(interface{}, grpc.ServerStream) error {
+		require.Fail(t, "handler called for denied stream")
+		return nil
+	})
+	require.Equal(t, codes.PermissionDenied, serviceerror.ToStatus(err).Code())
+}
+
+func TestStreamInterceptorWithoutMessageTargets(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	authorizer := NewMockAuthorizer(controller)
+	authorizer.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any()).Return(Result{Decision: DecisionAllow}, nil).Times(1)
+	interceptor := newTestStreamInterceptor(controller, authorizer)
+
+	stream := &testServerStream{
+		ctx: context.Background(),
+		messages: []*workflowservice.SignalWorkflowExecutionRequest{
+			{Namespace: testNamespace},
+			{Namespace: "other"},
+		},
+	}
+	var received []string
+	err := interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: testStreamMethod}, signalStreamHandler(&received))
+	require.NoError(t, err)
+	require.Equal(t, []string{testNamespace, "other"}, received)
+}
@@ -0,0 +1,62 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+)
+
+type subTargetAuthorizer struct {
+	authorizer Authorizer
+}
+
+var _ Authorizer = (*subTargetAuthorizer)(nil)
+
+const respondWorkflowTaskCompletedAPIName = workflowServicePrefix + "RespondWorkflowTaskCompleted"
+
+// NewSubTargetAuthorizer creates an authorizer that allows a call to a composite API only if authorizer allows
+// both the call itself and each of the operations embedded in it, see CallTarget.SubTargets. The commands of
+// workflow task completions are only extracted by interceptors configured WithRequestContentExtraction,
+// workflow task completions are denied if they weren't.
+func NewSubTargetAuthorizer(authorizer Authorizer) Authorizer {
+	return &subTargetAuthorizer{authorizer: authorizer}
+}
+
+func (a *subTargetAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if target.APIName == respondWorkflowTaskCompletedAPIName && !target.ContentExtracted {
+		return Result{Decision: DecisionDeny, Reason: ReasonContentNotExtracted}, nil
+	}
+	result, err := a.authorizer.Authorize(ctx, claims, target)
+	if err != nil || result.Decision != DecisionAllow {
+		return result, err
+	}
+	for _, subTarget := range target.SubTargets {
+		subResult, err := a.Authorize(ctx, claims, subTarget)
+		if err != nil || subResult.Decision != DecisionAllow {
+			return subResult, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,160 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	commandpb "go.temporal.io/api/command/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	taskqueuepb "go.temporal.io/api/taskqueue/v1"
+	"go.temporal.io/api/workflowservice/v1"
+)
+
+type (
+	subTargetAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		mockAuthorizer *MockAuthorizer
+		authorizer     Authorizer
+		target         *CallTarget
+	}
+)
+
+func TestSubTargetAuthorizerSuite(t *testing.T) {
+	s := new(subTargetAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *subTargetAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.authorizer = NewSubTargetAuthorizer(s.mockAuthorizer)
+	s.target = newCallTarget(
+		workflowServicePrefix+"SignalWithStartWorkflowExecution",
+		&workflowservice.SignalWithStartWorkflowExecutionRequest{Namespace: testNamespace, WorkflowId: "wid"}, true)
+	s.Len(s.target.SubTargets, 2)
+}
+
+func (s *subTargetAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *subTargetAuthorizerSuite) TestAllAllowed() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, s.target).Return(Result{Decision: DecisionAllow}, nil)
+	for _, subTarget := range s.target.SubTargets {
+		s.mockAuthorizer.EXPECT().Authorize(ctx, nil, subTarget).Return(Result{Decision: DecisionAllow}, nil)
+	}
+
+	result, err := s.authorizer.Authorize(ctx, nil, s.target)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *subTargetAuthorizerSuite) TestSubTargetDenied() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, s.target).Return(Result{Decision: DecisionAllow}, nil)
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, s.target.SubTargets[0]).Return(Result{Decision: DecisionAllow}, nil)
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, s.target.SubTargets[1]).
+		Return(Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, nil, s.target)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonInsufficientRole, result.Reason)
+}
+
+func (s *subTargetAuthorizerSuite) TestOuterDenied() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, s.target).Return(Result{Decision: DecisionDeny}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, nil, s.target)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+}
+
+func (s *subTargetAuthorizerSuite) TestNotComposite() {
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, describeNamespaceTarget).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, nil, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *subTargetAuthorizerSuite) TestWorkflowTaskCommands() {
+	// a worker may only schedule activities on its own task queue
+	authorizer := NewSubTargetAuthorizer(&taskQueueAuthorizer{taskQueue: "permitted"})
+	for _, tc := range []struct {
+		taskQueue string
+		expected  Decision
+	}{
+		{taskQueue: "permitted", expected: DecisionAllow},
+		{taskQueue: "other", expected: DecisionDeny},
+	} {
+		target := newCallTarget(workflowServicePrefix+"RespondWorkflowTaskCompleted", &workflowservice.RespondWorkflowTaskCompletedRequest{
+			Namespace: testNamespace,
+			Commands: []*commandpb.Command{
+				{CommandType: enumspb.COMMAND_TYPE_START_TIMER},
+				{CommandType: enumspb.COMMAND_TYPE_SCHEDULE_ACTIVITY_TASK, Attributes: &commandpb.Command_ScheduleActivityTaskCommandAttributes{
+					ScheduleActivityTaskCommandAttributes: &commandpb.ScheduleActivityTaskCommandAttributes{
+						ActivityId: "aid", TaskQueue: &taskqueuepb.TaskQueue{Name: tc.taskQueue}}}},
+			},
+		}, true)
+		s.Len(target.SubTargets, 1)
+
+		result, err := authorizer.Authorize(ctx, nil, target)
+		s.NoError(err)
+		s.Equal(tc.expected, result.Decision, tc.taskQueue)
+	}
+}
+
+func (s *subTargetAuthorizerSuite) TestContentNotExtracted() {
+	target := newCallTarget(workflowServicePrefix+"RespondWorkflowTaskCompleted", &workflowservice.RespondWorkflowTaskCompletedRequest{
+		Namespace: testNamespace,
+		Commands: []*commandpb.Command{
+			{CommandType: enumspb.COMMAND_TYPE_SIGNAL_EXTERNAL_WORKFLOW_EXECUTION},
+		},
+	}, false)
+
+	result, err := s.authorizer.Authorize(ctx, nil, target)
+	s.NoError(err)
+	s.Equal(Result{Decision: DecisionDeny, Reason: ReasonContentNotExtracted}, result)
+}
+
+type taskQueueAuthorizer struct {
+	taskQueue string
+}
+
+func (a *taskQueueAuthorizer) Authorize(_ context.Context, _ *Claims, target *CallTarget) (Result, error) {
+	if target.Command == enumspb.COMMAND_TYPE_SCHEDULE_ACTIVITY_TASK && target.TaskQueue != a.taskQueue {
+		return Result{Decision: DecisionDeny, Reason: ReasonInsufficientRole}, nil
+	}
+	return Result{Decision: DecisionAllow}, nil
+}
@@ -0,0 +1,101 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"fmt"
+)
+
+// TaskTokenAPIs are the APIs identifying their target with a task token instead of the namespace and workflow
+// execution in the request, see WithTaskTokenDecoder
+var TaskTokenAPIs = map[string]struct{}{
+	workflowServicePrefix + "RespondWorkflowTaskCompleted": {},
+	workflowServicePrefix + "RespondWorkflowTaskFailed":    {},
+	workflowServicePrefix + "RespondActivityTaskCompleted": {},
+	workflowServicePrefix + "RespondActivityTaskFailed":    {},
+	workflowServicePrefix + "RespondActivityTaskCanceled":  {},
+	workflowServicePrefix + "RecordActivityTaskHeartbeat":  {},
+}
+
+type (
+	// TaskTokenTarget is the namespace, workflow execution and activity a task token was issued for
+	TaskTokenTarget struct {
+		Namespace  string
+		WorkflowID string
+		RunID      string
+		ActivityID string
+	}
+
+	requestWithTaskToken interface {
+		GetTaskToken() []byte
+	}
+)
+
+// NewTaskTokenDecoder creates a TaskTokenDecoder of the task tokens issued by this server, see DecodeTaskToken.
+// Task tokens identify their namespace by ID, lookup resolves it to the name of the namespace.
+func NewTaskTokenDecoder(lookup NamespaceNameLookup) TaskTokenDecoder {
+	return func(taskToken []byte) (*TaskTokenTarget, error) {
+		token, err := DecodeTaskToken(taskToken)
+		if err != nil {
+			return nil, err
+		}
+		namespace, err := lookup.GetNamespaceName(token.GetNamespaceId())
+		if err != nil {
+			return nil, err
+		}
+		return &TaskTokenTarget{
+			Namespace:  namespace,
+			WorkflowID: token.GetWorkflowId(),
+			RunID:      token.GetRunId(),
+			ActivityID: token.GetActivityId(),
+		}, nil
+	}
+}
+
+// setTaskTokenTarget sets the namespace, workflow execution and activity of target to those of the task token
+// of the request of a TaskTokenAPIs call. It fails for requests without a task token or with a malformed one,
+// and for requests naming another namespace than their task token.
+func setTaskTokenTarget(target *CallTarget, req interface{}, decoder TaskTokenDecoder) error {
+	if _, ok := TaskTokenAPIs[target.APIName]; !ok {
+		return nil
+	}
+	r, ok := req.(requestWithTaskToken)
+	if !ok || len(r.GetTaskToken()) == 0 {
+		return fmt.Errorf("request of %s has no task token", target.APIName)
+	}
+	tokenTarget, err := decoder(r.GetTaskToken())
+	if err != nil {
+		return fmt.Errorf("unable to decode task token of %s request: %w", target.APIName, err)
+	}
+	if target.Namespace != "" && target.Namespace != tokenTarget.Namespace {
+		return fmt.Errorf("request of %s names namespace %q, its task token was issued for %q",
+			target.APIName, target.Namespace, tokenTarget.Namespace)
+	}
+	target.Namespace = tokenTarget.Namespace
+	target.WorkflowID = tokenTarget.WorkflowID
+	target.RunID = tokenTarget.RunID
+	target.ActivityID = tokenTarget.ActivityID
+	return nil
+}
@@ -0,0 +1,124 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/api/workflowservice/v1"
+
+	tokenspb "go.temporal.io/server/api/token/v1"
+)
+
+const testNamespaceID = "test-namespace-id"
+
+type testNamespaceNameLookup map[string]string
+
+func (l testNamespaceNameLookup) GetNamespaceName(id string) (string, error) {
+	name, ok := l[id]
+	if !ok {
+		return "", fmt.Errorf("unknown namespace ID: %s", id)
+	}
+	return name, nil
+}
+
+func newTestTaskToken(t *testing.T, namespaceID string) []byte {
+	data, err := taskTokenSerializer.Serialize(&tokenspb.Task{
+		NamespaceId: namespaceID, WorkflowId: "wid", RunId: "rid", ActivityId: "aid"})
+	require.NoError(t, err)
+	return data
+}
+
+func TestTaskTokenDecoder(t *testing.T) {
+	decoder := NewTaskTokenDecoder(testNamespaceNameLookup{testNamespaceID: testNamespace})
+
+	tokenTarget, err := decoder(newTestTaskToken(t, testNamespaceID))
+	require.NoError(t, err)
+	require.Equal(t, &TaskTokenTarget{Namespace: testNamespace, WorkflowID: "wid", RunID: "rid", ActivityID: "aid"}, tokenTarget)
+
+	_, err = decoder([]byte("malformed"))
+	require.Error(t, err)
+	_, err = decoder(newTestTaskToken(t, "unknown-id"))
+	require.Error(t, err)
+}
+
+func TestSetTaskTokenTarget(t *testing.T) {
+	decoder := NewTaskTokenDecoder(testNamespaceNameLookup{testNamespaceID: testNamespace})
+	apiName := workflowServicePrefix + "RespondActivityTaskCompleted"
+	testCases := []struct {
+		name     string
+		request  interface{}
+		expected *CallTarget
+	}{
+		{
+			name:     "valid task token",
+			request:  &workflowservice.RespondActivityTaskCompletedRequest{TaskToken: newTestTaskToken(t, testNamespaceID)},
+			expected: &CallTarget{APIName: apiName, Namespace: testNamespace, WorkflowID: "wid", RunID: "rid", ActivityID: "aid"},
+		},
+		{
+			name: "request naming the namespace of the task token",
+			request: &workflowservice.RespondActivityTaskCompletedRequest{
+				Namespace: testNamespace, TaskToken: newTestTaskToken(t, testNamespaceID)},
+			expected: &CallTarget{APIName: apiName, Namespace: testNamespace, WorkflowID: "wid", RunID: "rid", ActivityID: "aid"},
+		},
+		{
+			name:    "malformed task token",
+			request: &workflowservice.RespondActivityTaskCompletedRequest{TaskToken: []byte("malformed")},
+		},
+		{
+			name:    "missing task token",
+			request: &workflowservice.RespondActivityTaskCompletedRequest{Namespace: testNamespace},
+		},
+		{
+			name: "request naming another namespace than the task token",
+			request: &workflowservice.RespondActivityTaskCompletedRequest{
+				Namespace: "other", TaskToken: newTestTaskToken(t, testNamespaceID)},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			target := newCallTarget(apiName, tc.request, true)
+			err := setTaskTokenTarget(target, tc.request, decoder)
+			if tc.expected == nil {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			tc.expected.APIGroup = GetAPIGroup(apiName)
+			tc.expected.ContentExtracted = true
+			require.Equal(t, tc.expected, target)
+		})
+	}
+}
+
+func TestSetTaskTokenTargetOfOtherAPIs(t *testing.T) {
+	decoder := func([]byte) (*TaskTokenTarget, error) { return nil, errors.New("not called") }
+	target := newCallTarget(describeNamespaceTarget.APIName, describeNamespaceRequest, false)
+	require.NoError(t, setTaskTokenTarget(target, describeNamespaceRequest, decoder))
+	require.Equal(t, describeNamespaceTarget, target)
+}
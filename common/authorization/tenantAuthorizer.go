@@ -0,0 +1,92 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+
+	"go.temporal.io/server/common/metrics"
+)
+
+// TenantLabel is the namespace label holding the tenant the namespace belongs to
+const TenantLabel = "tenant"
+
+type (
+	// NamespaceTenantLookup resolves the tenant a namespace belongs to, empty if it belongs to none
+	NamespaceTenantLookup interface {
+		GetNamespaceTenant(namespace string) (string, error)
+	}
+
+	tenantAuthorizer struct {
+		authorizer    Authorizer
+		lookup        NamespaceTenantLookup
+		metricsClient metrics.Client
+	}
+)
+
+var _ Authorizer = (*tenantAuthorizer)(nil)
+
+// NewTenantAuthorizer creates an authorizer that denies calls to namespaces of another tenant than the caller's
+// Claims.TenantID, as resolved by lookup, regardless of the roles of the caller, and counts them. Callers without
+// a tenant can only access namespaces without a tenant. For delegated calls the tenants of both the end user and,
+// if it has one, the actor must match. Calls without a namespace and calls to namespaces of the caller's
+// tenant are decided by authorizer.
+func NewTenantAuthorizer(authorizer Authorizer, lookup NamespaceTenantLookup, metricsClient metrics.Client) Authorizer {
+	return &tenantAuthorizer{
+		authorizer:    authorizer,
+		lookup:        lookup,
+		metricsClient: metricsClient,
+	}
+}
+
+func (a *tenantAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if target.Namespace == "" {
+		return a.authorizer.Authorize(ctx, claims, target)
+	}
+	tenantID, err := a.lookup.GetNamespaceTenant(target.Namespace)
+	if err != nil {
+		return Result{}, err
+	}
+	if !isSameTenant(claims, tenantID) {
+		a.metricsClient.IncCounter(metrics.AuthorizationScope, metrics.ServiceErrCrossTenantCounter)
+		return Result{Decision: DecisionDeny, Reason: ReasonCrossTenant}, nil
+	}
+	return a.authorizer.Authorize(ctx, claims, target)
+}
+
+func isSameTenant(claims *Claims, tenantID string) bool {
+	var callerTenantID string
+	if claims != nil {
+		callerTenantID = claims.EffectiveClaims().TenantID
+	}
+	if callerTenantID != tenantID {
+		return false
+	}
+	// the actor of a delegated call may only act within its own tenant, if it has one
+	if claims != nil && claims.OnBehalfOf != nil && claims.TenantID != "" && claims.TenantID != tenantID {
+		return false
+	}
+	return true
+}
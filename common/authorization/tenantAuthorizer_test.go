@@ -0,0 +1,138 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"go.temporal.io/server/common/metrics"
+)
+
+type (
+	tenantAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller        *gomock.Controller
+		mockAuthorizer    *MockAuthorizer
+		mockMetricsClient *metrics.MockClient
+		authorizer        Authorizer
+	}
+
+	testNamespaceTenantLookup map[string]string
+)
+
+func (l testNamespaceTenantLookup) GetNamespaceTenant(namespace string) (string, error) {
+	tenantID, ok := l[namespace]
+	if !ok {
+		return "", fmt.Errorf("unknown namespace %s", namespace)
+	}
+	return tenantID, nil
+}
+
+func TestTenantAuthorizerSuite(t *testing.T) {
+	s := new(tenantAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *tenantAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.mockMetricsClient = metrics.NewMockClient(s.controller)
+	s.authorizer = NewTenantAuthorizer(s.mockAuthorizer, testNamespaceTenantLookup{
+		testNamespace: "acme",
+		"other":       "globex",
+		"shared":      "",
+	}, s.mockMetricsClient)
+}
+
+func (s *tenantAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *tenantAuthorizerSuite) TestSameTenant() {
+	for _, claims := range []*Claims{
+		{Subject: testSubject, TenantID: "acme", Namespaces: map[string]Role{testNamespace: RoleWriter}},
+		{Subject: testSubject, TenantID: "acme", System: RoleAdmin},
+		{Subject: "gateway", OnBehalfOf: &Claims{Subject: testSubject, TenantID: "acme"}},
+		{Subject: "gateway", TenantID: "acme", OnBehalfOf: &Claims{Subject: testSubject, TenantID: "acme"}},
+	} {
+		s.mockAuthorizer.EXPECT().Authorize(ctx, claims, describeNamespaceTarget).Return(Result{Decision: DecisionAllow}, nil)
+		result, err := s.authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+		s.NoError(err)
+		s.Equal(DecisionAllow, result.Decision)
+	}
+}
+
+func (s *tenantAuthorizerSuite) TestCrossTenant() {
+	for _, claims := range []*Claims{
+		{Subject: testSubject, TenantID: "globex", Namespaces: map[string]Role{testNamespace: RoleWriter}},
+		{Subject: testSubject, TenantID: "globex", System: RoleAdmin},
+		{Subject: testSubject, System: RoleAdmin},
+		{Subject: "gateway", TenantID: "acme", OnBehalfOf: &Claims{Subject: testSubject, TenantID: "globex"}},
+		{Subject: "gateway", TenantID: "globex", OnBehalfOf: &Claims{Subject: testSubject, TenantID: "acme"}},
+		nil,
+	} {
+		s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceErrCrossTenantCounter)
+		result, err := s.authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+		s.NoError(err)
+		s.Equal(DecisionDeny, result.Decision)
+		s.Equal(ReasonCrossTenant, result.Reason)
+	}
+}
+
+func (s *tenantAuthorizerSuite) TestNamespaceWithoutTenant() {
+	target := &CallTarget{APIName: describeNamespaceTarget.APIName, Namespace: "shared"}
+	claims := &Claims{Subject: testSubject, System: RoleAdmin}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, claims, target).Return(Result{Decision: DecisionAllow}, nil)
+	result, err := s.authorizer.Authorize(ctx, claims, target)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+
+	s.mockMetricsClient.EXPECT().IncCounter(metrics.AuthorizationScope, metrics.ServiceErrCrossTenantCounter)
+	result, err = s.authorizer.Authorize(ctx, &Claims{Subject: testSubject, TenantID: "acme", System: RoleAdmin}, target)
+	s.NoError(err)
+	s.Equal(ReasonCrossTenant, result.Reason)
+}
+
+func (s *tenantAuthorizerSuite) TestWithoutNamespace() {
+	target := &CallTarget{APIName: workflowServicePrefix + "ListNamespaces"}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, target).Return(Result{Decision: DecisionAllow}, nil)
+	result, err := s.authorizer.Authorize(ctx, nil, target)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *tenantAuthorizerSuite) TestLookupFailure() {
+	_, err := s.authorizer.Authorize(ctx, &Claims{Subject: testSubject, TenantID: "acme"},
+		&CallTarget{APIName: describeNamespaceTarget.APIName, Namespace: "unknown"})
+	s.Error(err)
+}
@@ -0,0 +1,84 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"strings"
+)
+
+// TierLabel is the namespace label holding the quota tier the namespace is provisioned at, e.g. "pro"
+const TierLabel = "tier"
+
+// Quota tiers from the lowest to the highest, namespaces without a tier are at the free tier
+const (
+	TierFree       = "free"
+	TierPro        = "pro"
+	TierEnterprise = "enterprise"
+)
+
+// tierRanks orders the quota tiers, unknown tiers rank below the free tier
+var tierRanks = map[string]int{
+	"":             1,
+	TierFree:       1,
+	TierPro:        2,
+	TierEnterprise: 3,
+}
+
+type tierAuthorizer struct {
+	authorizer  Authorizer
+	minTierRank map[string]int
+}
+
+var _ Authorizer = (*tierAuthorizer)(nil)
+
+// NewTierAuthorizer creates an authorizer that denies calls to the APIs of minTierPerAPI, keyed by full API name,
+// targeting a namespace provisioned at a lower tier than the minimum tier of the API, before delegating
+// to authorizer. Tiers are compared case-insensitively; a minimum tier that is not one of the known tiers
+// denies the API to all namespaces. The tier of the namespace is read from CallTarget.NamespaceTier, see
+// WithNamespaceLabelsLookup. Calls that don't target a namespace are decided by authorizer alone.
+func NewTierAuthorizer(authorizer Authorizer, minTierPerAPI map[string]string) Authorizer {
+	minTierRank := make(map[string]int, len(minTierPerAPI))
+	for api, tier := range minTierPerAPI {
+		rank, ok := tierRanks[strings.ToLower(tier)]
+		if !ok {
+			rank = len(tierRanks) + 1
+		}
+		minTierRank[api] = rank
+	}
+	return &tierAuthorizer{
+		authorizer:  authorizer,
+		minTierRank: minTierRank,
+	}
+}
+
+func (a *tierAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if minRank, ok := a.minTierRank[target.APIName]; ok && target.Namespace != "" {
+		if tierRanks[strings.ToLower(target.NamespaceTier)] < minRank {
+			return Result{Decision: DecisionDeny, Reason: ReasonTierInsufficient}, nil
+		}
+	}
+	return a.authorizer.Authorize(ctx, claims, target)
+}
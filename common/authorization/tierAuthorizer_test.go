@@ -0,0 +1,113 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const testListWorkflowExecutionsAPI = workflowServicePrefix + "ListWorkflowExecutions"
+
+type (
+	tierAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		mockAuthorizer *MockAuthorizer
+		authorizer     Authorizer
+	}
+)
+
+func TestTierAuthorizerSuite(t *testing.T) {
+	s := new(tierAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *tierAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.authorizer = NewTierAuthorizer(s.mockAuthorizer, map[string]string{
+		testListWorkflowExecutionsAPI:        TierPro,
+		startWorkflowExecutionTarget.APIName: "Enterprise",
+	})
+}
+
+func (s *tierAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *tierAuthorizerSuite) TestTiers() {
+	testCases := []struct {
+		api      string
+		tier     string
+		expected Decision
+	}{
+		{api: describeNamespaceTarget.APIName, tier: TierFree, expected: DecisionAllow},
+		{api: describeNamespaceTarget.APIName, tier: "", expected: DecisionAllow},
+		{api: testListWorkflowExecutionsAPI, tier: TierFree, expected: DecisionDeny},
+		{api: testListWorkflowExecutionsAPI, tier: "", expected: DecisionDeny},
+		{api: testListWorkflowExecutionsAPI, tier: TierPro, expected: DecisionAllow},
+		{api: testListWorkflowExecutionsAPI, tier: "PRO", expected: DecisionAllow},
+		{api: testListWorkflowExecutionsAPI, tier: TierEnterprise, expected: DecisionAllow},
+		{api: testListWorkflowExecutionsAPI, tier: "platinum", expected: DecisionDeny},
+		{api: startWorkflowExecutionTarget.APIName, tier: TierPro, expected: DecisionDeny},
+		{api: startWorkflowExecutionTarget.APIName, tier: TierEnterprise, expected: DecisionAllow},
+	}
+	for _, tc := range testCases {
+		target := &CallTarget{APIName: tc.api, Namespace: testNamespace, NamespaceTier: tc.tier}
+		if tc.expected == DecisionAllow {
+			s.mockAuthorizer.EXPECT().Authorize(ctx, nil, target).Return(Result{Decision: DecisionAllow}, nil)
+		}
+		result, err := s.authorizer.Authorize(ctx, nil, target)
+		s.NoError(err)
+		s.Equal(tc.expected, result.Decision, "%s at tier %q", tc.api, tc.tier)
+		if tc.expected == DecisionDeny {
+			s.Equal(ReasonTierInsufficient, result.Reason)
+		}
+	}
+}
+
+func (s *tierAuthorizerSuite) TestWithoutNamespace() {
+	target := &CallTarget{APIName: testListWorkflowExecutionsAPI}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, target).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, nil, target)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *tierAuthorizerSuite) TestUnknownMinimumTier() {
+	authorizer := NewTierAuthorizer(s.mockAuthorizer, map[string]string{testListWorkflowExecutionsAPI: "platinum"})
+	result, err := authorizer.Authorize(ctx, nil,
+		&CallTarget{APIName: testListWorkflowExecutionsAPI, Namespace: testNamespace, NamespaceTier: TierEnterprise})
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+}
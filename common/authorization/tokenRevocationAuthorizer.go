@@ -0,0 +1,130 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.temporal.io/server/common/clock"
+)
+
+// ErrTokenBlocklistFull is returned by TokenBlocklist.Revoke when the blocklist holds as many unexpired tokens
+// as it may, see NewTokenBlocklist
+var ErrTokenBlocklistFull = errors.New("too many revoked tokens, token cannot be revoked")
+
+type (
+	// TokenBlocklist holds the IDs of revoked tokens until the tokens expire, see Claims.TokenID
+	TokenBlocklist interface {
+		// Revoke adds the token with the given ID to the blocklist until expiresAt, the expiry of the token
+		Revoke(tokenID string, expiresAt time.Time) error
+		// IsRevoked checks if the token with the given ID is revoked and not expired
+		IsRevoked(tokenID string) bool
+	}
+
+	tokenBlocklist struct {
+		maxTokens  int
+		timeSource clock.TimeSource
+
+		sync.RWMutex
+		revoked map[string]time.Time // expiry by token ID
+	}
+
+	tokenRevocationAuthorizer struct {
+		authorizer Authorizer
+		blocklist  TokenBlocklist
+	}
+)
+
+var _ TokenBlocklist = (*tokenBlocklist)(nil)
+var _ Authorizer = (*tokenRevocationAuthorizer)(nil)
+
+// NewTokenBlocklist creates an in-memory TokenBlocklist holding up to maxTokens unexpired tokens. Expired tokens
+// are dropped when the blocklist is full; revoking a token fails while maxTokens unexpired tokens are held, instead of
+// dropping an unexpired token. It is safe for concurrent use.
+func NewTokenBlocklist(maxTokens int, timeSource clock.TimeSource) TokenBlocklist {
+	return &tokenBlocklist{
+		maxTokens:  maxTokens,
+		timeSource: timeSource,
+		revoked:    make(map[string]time.Time),
+	}
+}
+
+func (b *tokenBlocklist) Revoke(tokenID string, expiresAt time.Time) error {
+	now := b.timeSource.Now()
+	if !expiresAt.After(now) {
+		return nil // expired tokens are rejected anyway
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	if expiry, ok := b.revoked[tokenID]; ok {
+		if expiresAt.After(expiry) {
+			b.revoked[tokenID] = expiresAt
+		}
+		return nil
+	}
+	if len(b.revoked) >= b.maxTokens {
+		for revokedID, expiry := range b.revoked {
+			if !expiry.After(now) {
+				delete(b.revoked, revokedID)
+			}
+		}
+		if len(b.revoked) >= b.maxTokens {
+			return ErrTokenBlocklistFull
+		}
+	}
+	b.revoked[tokenID] = expiresAt
+	return nil
+}
+
+func (b *tokenBlocklist) IsRevoked(tokenID string) bool {
+	b.RLock()
+	expiry, ok := b.revoked[tokenID]
+	b.RUnlock()
+	return ok && expiry.After(b.timeSource.Now())
+}
+
+// NewTokenRevocationAuthorizer creates an authorizer that denies calls made with a token whose ID is in blocklist,
+// the token of the caller or, for delegated calls, of the end user. All other calls, including calls with claims
+// of tokens without an ID, are decided by authorizer.
+func NewTokenRevocationAuthorizer(authorizer Authorizer, blocklist TokenBlocklist) Authorizer {
+	return &tokenRevocationAuthorizer{
+		authorizer: authorizer,
+		blocklist:  blocklist,
+	}
+}
+
+func (a *tokenRevocationAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	for c := claims; c != nil; c = c.OnBehalfOf {
+		if c.TokenID != "" && a.blocklist.IsRevoked(c.TokenID) {
+			return Result{Decision: DecisionDeny, Reason: ReasonTokenRevoked}, nil
+		}
+	}
+	return a.authorizer.Authorize(ctx, claims, target)
+}
@@ -0,0 +1,149 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"go.temporal.io/server/common/clock"
+)
+
+var testRevocationNow = time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+
+type (
+	tokenRevocationAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		mockAuthorizer *MockAuthorizer
+		timeSource     *clock.EventTimeSource
+		blocklist      TokenBlocklist
+		authorizer     Authorizer
+	}
+)
+
+func TestTokenRevocationAuthorizerSuite(t *testing.T) {
+	s := new(tokenRevocationAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *tokenRevocationAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.timeSource = clock.NewEventTimeSource().Update(testRevocationNow)
+	s.blocklist = NewTokenBlocklist(2, s.timeSource)
+	s.authorizer = NewTokenRevocationAuthorizer(s.mockAuthorizer, s.blocklist)
+}
+
+func (s *tokenRevocationAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *tokenRevocationAuthorizerSuite) TestRevokedToken() {
+	s.NoError(s.blocklist.Revoke("revoked", testRevocationNow.Add(time.Hour)))
+
+	result, err := s.authorizer.Authorize(ctx, &Claims{Subject: testSubject, TokenID: "revoked"}, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonTokenRevoked, result.Reason)
+}
+
+func (s *tokenRevocationAuthorizerSuite) TestNotRevokedToken() {
+	s.NoError(s.blocklist.Revoke("revoked", testRevocationNow.Add(time.Hour)))
+	for _, claims := range []*Claims{
+		{Subject: testSubject, TokenID: "other"},
+		{Subject: testSubject},
+		nil,
+	} {
+		s.mockAuthorizer.EXPECT().Authorize(ctx, claims, describeNamespaceTarget).Return(Result{Decision: DecisionAllow}, nil)
+
+		result, err := s.authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+		s.NoError(err)
+		s.Equal(DecisionAllow, result.Decision)
+	}
+}
+
+func (s *tokenRevocationAuthorizerSuite) TestRevokedEndUserToken() {
+	s.NoError(s.blocklist.Revoke("revoked", testRevocationNow.Add(time.Hour)))
+	claims := &Claims{Subject: "gateway", OnBehalfOf: &Claims{Subject: testSubject, TokenID: "revoked"}}
+
+	result, err := s.authorizer.Authorize(ctx, claims, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonTokenRevoked, result.Reason)
+}
+
+func (s *tokenRevocationAuthorizerSuite) TestRevocationExpires() {
+	s.NoError(s.blocklist.Revoke("revoked", testRevocationNow.Add(time.Hour)))
+	s.True(s.blocklist.IsRevoked("revoked"))
+
+	s.timeSource.Update(testRevocationNow.Add(time.Hour))
+	s.False(s.blocklist.IsRevoked("revoked"))
+
+	// already expired tokens are not held
+	s.NoError(s.blocklist.Revoke("expired", testRevocationNow))
+	s.False(s.blocklist.IsRevoked("expired"))
+}
+
+func (s *tokenRevocationAuthorizerSuite) TestBlocklistBounded() {
+	s.NoError(s.blocklist.Revoke("token-1", testRevocationNow.Add(time.Minute)))
+	s.NoError(s.blocklist.Revoke("token-2", testRevocationNow.Add(time.Hour)))
+	s.NoError(s.blocklist.Revoke("token-2", testRevocationNow.Add(2*time.Hour))) // extends, doesn't take room
+	s.Equal(ErrTokenBlocklistFull, s.blocklist.Revoke("token-3", testRevocationNow.Add(time.Hour)))
+	s.True(s.blocklist.IsRevoked("token-1"))
+
+	// room is made by dropping expired tokens
+	s.timeSource.Update(testRevocationNow.Add(time.Minute))
+	s.NoError(s.blocklist.Revoke("token-3", testRevocationNow.Add(time.Hour)))
+	s.True(s.blocklist.IsRevoked("token-2"))
+	s.True(s.blocklist.IsRevoked("token-3"))
+	s.Equal(2, len(s.blocklist.(*tokenBlocklist).revoked))
+}
+
+func TestTokenBlocklistConcurrentUse(t *testing.T) {
+	blocklist := NewTokenBlocklist(1000, clock.NewRealTimeSource())
+	done := make(chan struct{})
+	for i := 0; i < 10; i++ {
+		go func(i int) {
+			defer func() { done <- struct{}{} }()
+			for j := 0; j < 50; j++ {
+				tokenID := fmt.Sprintf("token-%d-%d", i, j)
+				require.NoError(t, blocklist.Revoke(tokenID, time.Now().Add(time.Hour)))
+				require.True(t, blocklist.IsRevoked(tokenID))
+			}
+		}(i)
+	}
+	for i := 0; i < 10; i++ {
+		<-done
+	}
+}
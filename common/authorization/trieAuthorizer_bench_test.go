@@ -0,0 +1,72 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+/**
+$ go test -run=^$ -bench=Policy ./common/authorization
+BenchmarkStaticAuthorizerLargePolicy 	   23352	     56587 ns/op	       0 B/op	       0 allocs/op
+BenchmarkTrieAuthorizerLargePolicy   	 8892168	       133.5 ns/op	       0 B/op	       0 allocs/op
+*/
+
+const benchmarkPolicySize = 10000
+
+func BenchmarkStaticAuthorizerLargePolicy(b *testing.B) {
+	benchmarkLargePolicy(b, NewStaticAuthorizer(largeBenchmarkPolicy()))
+}
+
+func BenchmarkTrieAuthorizerLargePolicy(b *testing.B) {
+	benchmarkLargePolicy(b, NewTrieAuthorizer(largeBenchmarkPolicy()))
+}
+
+// largeBenchmarkPolicy grants the reader role a mix of benchmarkPolicySize full API names and prefixes
+func largeBenchmarkPolicy() map[Role][]string {
+	apiNames := make([]string, 0, benchmarkPolicySize)
+	for i := 0; i < benchmarkPolicySize; i++ {
+		if i%10 == 0 {
+			apiNames = append(apiNames, fmt.Sprintf("/custom.v1.Service%d/*", i))
+		} else {
+			apiNames = append(apiNames, fmt.Sprintf("/custom.v1.Service/Method%d", i))
+		}
+	}
+	return map[Role][]string{RoleReader: apiNames}
+}
+
+func benchmarkLargePolicy(b *testing.B, authorizer Authorizer) {
+	claims := &Claims{Namespaces: map[string]Role{testNamespace: RoleReader}}
+	// a call that is not granted, so that the static authorizer has to check every API name
+	target := &CallTarget{APIName: describeNamespaceTarget.APIName, Namespace: testNamespace}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = authorizer.Authorize(context.Background(), claims, target)
+	}
+}
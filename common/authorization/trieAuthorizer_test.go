@@ -0,0 +1,172 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type (
+	trieAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		authorizer StaticAuthorizer
+	}
+)
+
+func TestTrieAuthorizerSuite(t *testing.T) {
+	s := new(trieAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *trieAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.authorizer = NewTrieAuthorizer(map[Role][]string{
+		RoleReader: {describeNamespaceTarget.APIName},
+		RoleWorker: {"group:write"},
+		RoleAdmin:  {workflowServicePrefix + "*"},
+	})
+}
+
+func (s *trieAuthorizerSuite) TestAllowed() {
+	s.assertDecision(DecisionAllow, &Claims{Namespaces: map[string]Role{testNamespace: RoleReader}}, describeNamespaceTarget)
+	s.assertDecision(DecisionAllow, &Claims{Namespaces: map[string]Role{testNamespace: RoleWorker}}, startWorkflowExecutionTarget)
+	s.assertDecision(DecisionAllow, &Claims{System: RoleAdmin}, startWorkflowExecutionTarget)
+}
+
+func (s *trieAuthorizerSuite) TestDenied() {
+	s.assertDecision(DecisionDeny, &Claims{Namespaces: map[string]Role{testNamespace: RoleReader}}, startWorkflowExecutionTarget)
+	s.assertDecision(DecisionDeny, &Claims{Namespaces: map[string]Role{"other": RoleAdmin}}, describeNamespaceTarget)
+	s.assertDecision(DecisionUnknown, &Claims{System: RoleAdmin}, &CallTarget{APIName: "/temporal.server.api.adminservice.v1.AdminService/DescribeCluster"})
+
+	result, err := s.authorizer.Authorize(ctx, nil, describeNamespaceTarget)
+	s.NoError(err)
+	s.Equal(ReasonNoClaims, result.Reason)
+}
+
+func (s *trieAuthorizerSuite) TestPermittedAPIs() {
+	permitted, err := s.authorizer.PermittedAPIs(ctx, &Claims{Namespaces: map[string]Role{testNamespace: RoleReader}}, testNamespace)
+	s.NoError(err)
+	s.Equal([]string{describeNamespaceTarget.APIName}, permitted)
+}
+
+func (s *trieAuthorizerSuite) TestUpdatePolicies() {
+	claims := &Claims{Namespaces: map[string]Role{testNamespace: RoleReader}}
+	s.authorizer.UpdatePolicies(map[Role][]string{RoleReader: {workflowServicePrefix + "Start*"}})
+
+	s.assertDecision(DecisionAllow, claims, startWorkflowExecutionTarget)
+	s.assertDecision(DecisionUnknown, claims, describeNamespaceTarget)
+}
+
+func (s *trieAuthorizerSuite) TestPolicySnapshot() {
+	snapshot := s.authorizer.PolicySnapshot()
+	s.Equal(&PolicySnapshot{Roles: map[string][]string{
+		"read":   {describeNamespaceTarget.APIName},
+		"worker": {"group:write"},
+		"admin":  {workflowServicePrefix + "*"},
+	}}, snapshot)
+
+	// snapshots are copies
+	snapshot.Roles["read"][0] = startWorkflowExecutionTarget.APIName
+	s.Equal([]string{describeNamespaceTarget.APIName}, s.authorizer.PolicySnapshot().Roles["read"])
+
+	s.authorizer.UpdatePolicies(map[Role][]string{RoleReader: {workflowServicePrefix + "Start*"}})
+	s.Equal(&PolicySnapshot{Roles: map[string][]string{
+		"read": {workflowServicePrefix + "Start*"},
+	}}, s.authorizer.PolicySnapshot())
+}
+
+func (s *trieAuthorizerSuite) TestCrossCheckWithStaticAuthorizer() {
+	random := rand.New(rand.NewSource(1))
+	apiNames := append([]string{"", "/", "/other.Service/Method"}, WorkflowServiceAPIs...)
+	roles := []Role{RoleReader, RoleWriter, RoleWorker, RoleAdmin, RoleReader | RoleWorker}
+
+	for i := 0; i < 20; i++ {
+		policies := make(map[Role][]string)
+		for _, role := range roles {
+			for j := random.Intn(8); j > 0; j-- {
+				policies[role] = append(policies[role], randomAPIPattern(random, apiNames))
+			}
+		}
+		trie := NewTrieAuthorizer(policies)
+		static := NewStaticAuthorizer(policies)
+
+		for j := 0; j < 50; j++ {
+			claims := &Claims{
+				System:     Role(random.Intn(16)),
+				Namespaces: map[string]Role{testNamespace: Role(random.Intn(16))},
+			}
+			for _, apiName := range apiNames {
+				target := &CallTarget{APIName: apiName, Namespace: testNamespace}
+				expected, err := static.Authorize(ctx, claims, target)
+				s.NoError(err)
+				actual, err := trie.Authorize(ctx, claims, target)
+				s.NoError(err)
+				s.Equal(expected, actual, "policies %v, claims %v, API %v", policies, claims, apiName)
+			}
+			expected, err := static.PermittedAPIs(ctx, claims, testNamespace)
+			s.NoError(err)
+			actual, err := trie.PermittedAPIs(ctx, claims, testNamespace)
+			s.NoError(err)
+			s.Equal(expected, actual)
+		}
+	}
+}
+
+// randomAPIPattern returns a full API name, a prefix of an API name followed by the wildcard, or an API group
+func randomAPIPattern(random *rand.Rand, apiNames []string) string {
+	apiName := apiNames[random.Intn(len(apiNames))]
+	switch random.Intn(3) {
+	case 0:
+		return apiName
+	case 1:
+		return apiName[:random.Intn(len(apiName)+1)] + apiNameWildcard
+	default:
+		groups := []APIGroup{APIGroupRead, APIGroupWrite, APIGroupAdmin, "unknown"}
+		return fmt.Sprintf("%v%v", apiGroupPrefix, groups[random.Intn(len(groups))])
+	}
+}
+
+func (s *trieAuthorizerSuite) assertDecision(expected Decision, claims *Claims, target *CallTarget) {
+	result, err := s.authorizer.Authorize(ctx, claims, target)
+	s.NoError(err)
+	s.Equal(expected, result.Decision)
+}
+
+func (s *trieAuthorizerSuite) TestUnknownAPIGroup() {
+	s.authorizer.UpdatePolicies(map[Role][]string{
+		RoleReader: {apiGroupPrefix + string(APIGroupRead), apiGroupPrefix + string(APIGroupUnknown)},
+	})
+	reader := &Claims{System: RoleReader}
+	s.assertDecision(DecisionAllow, reader, &CallTarget{APIName: adminServicePrefix + "DescribeCluster"})
+	s.assertDecision(DecisionUnknown, reader, &CallTarget{APIName: adminServicePrefix + "ReapplyEvents"})
+	s.assertDecision(DecisionUnknown, reader, &CallTarget{APIName: "/grpc.health.v1.Health/Check"})
+}
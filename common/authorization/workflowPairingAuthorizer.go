@@ -0,0 +1,87 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+)
+
+const (
+	// SourceNamespaceHeaderName is the metadata header carrying the namespace of the calling workflow
+	SourceNamespaceHeaderName = "x-temporal-source-namespace"
+	// SourceWorkflowIDHeaderName is the metadata header carrying the workflow ID of the calling workflow
+	SourceWorkflowIDHeaderName = "x-temporal-source-workflow-id"
+)
+
+type (
+	// WorkflowPairing allows workflows in SourceNamespace to signal and query workflows in TargetNamespace
+	WorkflowPairing struct {
+		SourceNamespace string
+		TargetNamespace string
+	}
+
+	workflowPairingAuthorizer struct {
+		authorizer Authorizer
+		pairings   map[WorkflowPairing]struct{}
+	}
+)
+
+var _ Authorizer = (*workflowPairingAuthorizer)(nil)
+
+var workflowPairingAPIs = map[string]struct{}{
+	workflowServicePrefix + "SignalWorkflowExecution": {},
+	workflowServicePrefix + "QueryWorkflow":           {},
+}
+
+// NewWorkflowPairingAuthorizer creates an authorizer that only allows a workflow to signal or query
+// workflows in its own namespace or in a namespace it is paired with. Calls without a source workflow
+// are decided by authorizer, as are all calls the pairing rules allow.
+// Note that the source workflow identity is asserted by the caller, so the pairing rules only provide
+// isolation between workflows when callers are trusted to set it honestly, e.g. workers authenticated with mTLS.
+func NewWorkflowPairingAuthorizer(authorizer Authorizer, pairings []WorkflowPairing) Authorizer {
+	a := &workflowPairingAuthorizer{
+		authorizer: authorizer,
+		pairings:   make(map[WorkflowPairing]struct{}, len(pairings)),
+	}
+	for _, pairing := range pairings {
+		a.pairings[pairing] = struct{}{}
+	}
+	return a
+}
+
+func (a *workflowPairingAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if _, ok := workflowPairingAPIs[target.APIName]; ok && target.SourceWorkflowID != "" && !a.isPaired(target) {
+		return Result{Decision: DecisionDeny, Reason: ReasonWorkflowPairing}, nil
+	}
+	return a.authorizer.Authorize(ctx, claims, target)
+}
+
+func (a *workflowPairingAuthorizer) isPaired(target *CallTarget) bool {
+	if target.SourceNamespace == target.Namespace {
+		return true
+	}
+	_, ok := a.pairings[WorkflowPairing{SourceNamespace: target.SourceNamespace, TargetNamespace: target.Namespace}]
+	return ok
+}
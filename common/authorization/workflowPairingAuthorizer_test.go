@@ -0,0 +1,139 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	pairedNamespace   = "paired-namespace"
+	unpairedNamespace = "unpaired-namespace"
+)
+
+type (
+	workflowPairingAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		mockAuthorizer *MockAuthorizer
+		authorizer     Authorizer
+	}
+)
+
+func TestWorkflowPairingAuthorizerSuite(t *testing.T) {
+	s := new(workflowPairingAuthorizerSuite)
+	suite.Run(t, s)
+}
+
+func (s *workflowPairingAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.mockAuthorizer = NewMockAuthorizer(s.controller)
+	s.authorizer = NewWorkflowPairingAuthorizer(s.mockAuthorizer, []WorkflowPairing{
+		{SourceNamespace: pairedNamespace, TargetNamespace: testNamespace},
+	})
+}
+
+func (s *workflowPairingAuthorizerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *workflowPairingAuthorizerSuite) TestSameNamespace() {
+	target := s.signalTarget(testNamespace)
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, target).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, nil, target)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *workflowPairingAuthorizerSuite) TestPairedNamespace() {
+	target := s.signalTarget(pairedNamespace)
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, target).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, nil, target)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *workflowPairingAuthorizerSuite) TestUnpairedNamespace() {
+	result, err := s.authorizer.Authorize(ctx, nil, s.signalTarget(unpairedNamespace))
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonWorkflowPairing, result.Reason)
+}
+
+func (s *workflowPairingAuthorizerSuite) TestPairingIsDirectional() {
+	target := s.signalTarget(testNamespace)
+	target.Namespace, target.SourceNamespace = pairedNamespace, testNamespace
+
+	result, err := s.authorizer.Authorize(ctx, nil, target)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(ReasonWorkflowPairing, result.Reason)
+}
+
+func (s *workflowPairingAuthorizerSuite) TestQueryFromUnpairedNamespace() {
+	target := &CallTarget{
+		APIName:          workflowServicePrefix + "QueryWorkflow",
+		Namespace:        testNamespace,
+		WorkflowID:       "target-workflow",
+		SourceNamespace:  unpairedNamespace,
+		SourceWorkflowID: "source-workflow",
+	}
+
+	result, err := s.authorizer.Authorize(ctx, nil, target)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+}
+
+func (s *workflowPairingAuthorizerSuite) TestNoSourceWorkflow() {
+	target := &CallTarget{
+		APIName:    workflowServicePrefix + "SignalWorkflowExecution",
+		Namespace:  testNamespace,
+		WorkflowID: "target-workflow",
+	}
+	s.mockAuthorizer.EXPECT().Authorize(ctx, nil, target).Return(Result{Decision: DecisionAllow}, nil)
+
+	result, err := s.authorizer.Authorize(ctx, nil, target)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *workflowPairingAuthorizerSuite) signalTarget(sourceNamespace string) *CallTarget {
+	return &CallTarget{
+		APIName:          workflowServicePrefix + "SignalWorkflowExecution",
+		Namespace:        testNamespace,
+		WorkflowID:       "target-workflow",
+		SourceNamespace:  sourceNamespace,
+		SourceWorkflowID: "source-workflow",
+	}
+}
@@ -711,6 +711,48 @@ func TokenLastEventVersion(version int64) Tag {
 	return newInt64("xdc-token-last-event-version", version)
 }
 
+///////////////////  Authorization tags defined here: auth- ///////////////////
+
+// AuthSubject returns tag for the subject of the caller's claims
+func AuthSubject(subject string) Tag {
+	return newStringTag("auth-subject", subject)
+}
+
+// AuthImpersonatedSubject returns tag for the subject being impersonated by the caller
+func AuthImpersonatedSubject(subject string) Tag {
+	return newStringTag("auth-impersonated-subject", subject)
+}
+
+// AuthAPIName returns tag for the full name of the API being authorized
+func AuthAPIName(apiName string) Tag {
+	return newStringTag("auth-api-name", apiName)
+}
+
+// AuthReason returns tag for the reason of an authorization decision
+func AuthReason(reason string) Tag {
+	return newStringTag("auth-reason", reason)
+}
+
+// AuthDecision returns tag for an authorization decision, "allow" or "deny"
+func AuthDecision(decision string) Tag {
+	return newStringTag("auth-decision", decision)
+}
+
+// AuthChainMember returns tag for the name of a member of an authorizer chain
+func AuthChainMember(name string) Tag {
+	return newStringTag("auth-chain-member", name)
+}
+
+// AuthChainDepth returns tag for the depth of a member of an authorizer chain, zero for the outermost member
+func AuthChainDepth(depth int) Tag {
+	return newInt("auth-chain-depth", depth)
+}
+
+// AuthLatency returns tag for the time an authorization decision took
+func AuthLatency(latency time.Duration) Tag {
+	return newDurationTag("auth-latency", latency)
+}
+
 ///////////////////  Archival tags defined here: archival- ///////////////////
 // archival request tags
 
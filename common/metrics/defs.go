@@ -80,6 +80,8 @@ const (
 	CacheTypeTagName   = "cache_type"
 	FailureTagName     = "failure"
 	ValidatorTagName   = "validator"
+	ReasonTagName      = "reason"
+	CallerTypeTagName  = "caller_type"
 )
 
 // This package should hold all the metrics and tags for temporal
@@ -96,6 +98,10 @@ const (
 
 	MutableStateCacheTypeTagValue = "mutablestate"
 	EventsCacheTypeTagValue       = "events"
+
+	SystemCallerTypeTagValue    = "system"
+	UserCallerTypeTagValue      = "user"
+	AnonymousCallerTypeTagValue = "anonymous"
 )
 
 // Common service base metrics
@@ -1642,6 +1648,29 @@ const (
 	ClientRedirectionLatency
 
 	ServiceAuthorizationLatency
+	ServiceAuthorizationInterceptorLatency
+	ServiceAuthorizationPolicyCompileLatency
+	ServiceAuthorizationKeyRefreshCounter
+	ServiceErrAuthorizationKeyRefreshFailedCounter
+	ServiceAuthorizationDenyReasonCounter
+	ServiceAuthorizationFallbackCounter
+	ServiceAuthorizationNamespaceFanOutCounter
+	ServiceAuthorizationClockSkewToleratedCounter
+	ServiceAuthorizationWarnOnlyCounter
+	ServiceAuthorizationCostBudgetUsageGauge
+	ServiceAuthorizationCostBudgetExhaustedCounter
+	ServiceAuthorizationAuditBufferSizeGauge
+	ServiceAuthorizationAuditDroppedCounter
+	ServiceAuthorizationAuditSinkFailedCounter
+	ServiceAuthorizationConcurrencyLimitCounter
+	ServiceAuthorizationDRBypassCounter
+	ServiceAuthorizationDenyCooldownCounter
+	ServiceAuthorizationDecisionObserverDroppedCounter
+	ServiceAuthorizationRateClassExhaustedCounter
+	ServiceErrCrossTenantCounter
+	ServiceErrClaimMappingTimeoutCounter
+	ServiceErrAuthorizeTimeoutCounter
+	ServiceErrPolicyCompileCounter
 
 	NamespaceCachePrepareCallbacksLatency
 	NamespaceCacheCallbacksLatency
@@ -2054,6 +2083,29 @@ var MetricDefs = map[ServiceIdx]map[int]metricDefinition{
 		ClientRedirectionFailures:                           {metricName: "client_redirection_errors", metricType: Counter},
 		ClientRedirectionLatency:                            {metricName: "client_redirection_latency", metricType: Timer},
 		ServiceAuthorizationLatency:                         {metricName: "service_authorization_latency", metricType: Timer},
+		ServiceAuthorizationInterceptorLatency:              {metricName: "service_authorization_interceptor_latency", metricType: Timer},
+		ServiceAuthorizationPolicyCompileLatency:            {metricName: "service_authorization_policy_compile_latency", metricType: Timer},
+		ServiceAuthorizationKeyRefreshCounter:               {metricName: "service_authorization_key_refresh", metricType: Counter},
+		ServiceErrAuthorizationKeyRefreshFailedCounter:      {metricName: "service_errors_authorization_key_refresh_failed", metricType: Counter},
+		ServiceAuthorizationDenyReasonCounter:               {metricName: "service_authorization_deny_reason", metricType: Counter},
+		ServiceAuthorizationFallbackCounter:                 {metricName: "service_authorization_fallback", metricType: Counter},
+		ServiceAuthorizationNamespaceFanOutCounter:          {metricName: "service_authorization_namespace_fan_out", metricType: Counter},
+		ServiceAuthorizationClockSkewToleratedCounter:       {metricName: "service_authorization_clock_skew_tolerated", metricType: Counter},
+		ServiceAuthorizationWarnOnlyCounter:                 {metricName: "service_authorization_warn_only", metricType: Counter},
+		ServiceAuthorizationCostBudgetUsageGauge:            {metricName: "service_authorization_cost_budget_usage", metricType: Gauge},
+		ServiceAuthorizationCostBudgetExhaustedCounter:      {metricName: "service_authorization_cost_budget_exhausted", metricType: Counter},
+		ServiceAuthorizationAuditBufferSizeGauge:            {metricName: "service_authorization_audit_buffer_size", metricType: Gauge},
+		ServiceAuthorizationAuditDroppedCounter:             {metricName: "service_authorization_audit_dropped", metricType: Counter},
+		ServiceAuthorizationAuditSinkFailedCounter:          {metricName: "service_authorization_audit_sink_failed", metricType: Counter},
+		ServiceAuthorizationConcurrencyLimitCounter:         {metricName: "service_authorization_concurrency_limit", metricType: Counter},
+		ServiceAuthorizationDRBypassCounter:                 {metricName: "service_authorization_dr_bypass", metricType: Counter},
+		ServiceAuthorizationDenyCooldownCounter:             {metricName: "service_authorization_deny_cooldown", metricType: Counter},
+		ServiceAuthorizationDecisionObserverDroppedCounter:  {metricName: "service_authorization_decision_observer_dropped", metricType: Counter},
+		ServiceAuthorizationRateClassExhaustedCounter:       {metricName: "service_authorization_rate_class_exhausted", metricType: Counter},
+		ServiceErrCrossTenantCounter:                        {metricName: "service_errors_cross_tenant", metricType: Counter},
+		ServiceErrClaimMappingTimeoutCounter:                {metricName: "service_errors_claim_mapping_timeout", metricType: Counter},
+		ServiceErrAuthorizeTimeoutCounter:                   {metricName: "service_errors_authorize_timeout", metricType: Counter},
+		ServiceErrPolicyCompileCounter:                      {metricName: "service_errors_policy_compile", metricType: Counter},
 		NamespaceCachePrepareCallbacksLatency:               {metricName: "namespace_cache_prepare_callbacks_latency", metricType: Timer},
 		NamespaceCacheCallbacksLatency:                      {metricName: "namespace_cache_callbacks_latency", metricType: Timer},
 		HistorySize:                                         {metricName: "history_size", metricType: Timer},
@@ -99,6 +99,14 @@ type (
 	validatorTag struct {
 		value string
 	}
+
+	reasonTag struct {
+		value string
+	}
+
+	callerTypeTag struct {
+		value string
+	}
 )
 
 // NamespaceTag returns a new namespace tag. For timers, this also ensures that we
@@ -327,3 +335,40 @@ func (d validatorTag) Key() string {
 func (d validatorTag) Value() string {
 	return d.value
 }
+
+// ReasonTag returns a new reason tag. Callers must ensure value comes from a bounded set.
+func ReasonTag(value string) Tag {
+	if len(value) == 0 {
+		value = unknownValue
+	}
+	return reasonTag{value}
+}
+
+// Key returns the key of the tag
+func (d reasonTag) Key() string {
+	return ReasonTagName
+}
+
+// Value returns the value of the tag
+func (d reasonTag) Value() string {
+	return d.value
+}
+
+// CallerTypeTag returns a new caller type tag, one of SystemCallerTypeTagValue, UserCallerTypeTagValue
+// and AnonymousCallerTypeTagValue
+func CallerTypeTag(value string) Tag {
+	if len(value) == 0 {
+		value = unknownValue
+	}
+	return callerTypeTag{value}
+}
+
+// Key returns the key of the tag
+func (d callerTypeTag) Key() string {
+	return CallerTypeTagName
+}
+
+// Value returns the value of the tag
+func (d callerTypeTag) Value() string {
+	return d.value
+}
@@ -53,25 +53,26 @@ type (
 		Logger          log.Logger
 		ThrottledLogger log.Logger
 
-		MetricsScope                 tally.Scope
-		MembershipFactoryInitializer MembershipFactoryInitializerFunc
-		RPCFactory                   common.RPCFactory
-		AbstractDatastoreFactory     persistenceClient.AbstractDataStoreFactory
-		PersistenceConfig            config.Persistence
-		ClusterMetadata              cluster.Metadata
-		ReplicatorConfig             config.Replicator
-		MetricsClient                metrics.Client
-		MessagingClient              messaging.Client
-		ESClient                     elasticsearch.Client
-		ESConfig                     *elasticsearch.Config
-		DynamicConfig                dynamicconfig.Client
-		DCRedirectionPolicy          config.DCRedirectionPolicy
-		PublicClient                 sdkclient.Client
-		ArchivalMetadata             archiver.ArchivalMetadata
-		ArchiverProvider             provider.ArchiverProvider
-		Authorizer                   authorization.Authorizer
-		ClaimMapper                  authorization.ClaimMapper
-		PersistenceServiceResolver   resolver.ServiceResolver
+		MetricsScope                    tally.Scope
+		MembershipFactoryInitializer    MembershipFactoryInitializerFunc
+		RPCFactory                      common.RPCFactory
+		AbstractDatastoreFactory        persistenceClient.AbstractDataStoreFactory
+		PersistenceConfig               config.Persistence
+		ClusterMetadata                 cluster.Metadata
+		ReplicatorConfig                config.Replicator
+		MetricsClient                   metrics.Client
+		MessagingClient                 messaging.Client
+		ESClient                        elasticsearch.Client
+		ESConfig                        *elasticsearch.Config
+		DynamicConfig                   dynamicconfig.Client
+		DCRedirectionPolicy             config.DCRedirectionPolicy
+		PublicClient                    sdkclient.Client
+		ArchivalMetadata                archiver.ArchivalMetadata
+		ArchiverProvider                provider.ArchiverProvider
+		Authorizer                      authorization.Authorizer
+		ClaimMapper                     authorization.ClaimMapper
+		AuthorizationInterceptorOptions []authorization.InterceptorOption
+		PersistenceServiceResolver      resolver.ServiceResolver
 	}
 
 	// MembershipMonitorFactory provides a bootstrapped membership monitor
@@ -514,6 +514,9 @@ type (
 		Authorizer string `yaml:"authorizer"`
 		// Empty string for noopClaimMapper or "default" for defaultJWTClaimMapper
 		ClaimMapper string `yaml:"claimMapper"`
+		// Tolerated clock difference between token issuers and Temporal when checking token expiry,
+		// 60s if not set, 0s to tolerate none
+		ClockSkew *time.Duration `yaml:"clockSkew"`
 	}
 
 	// @@@SNIPSTART temporal-common-service-config-jwtkeyprovider
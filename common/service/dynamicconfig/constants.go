@@ -118,6 +118,7 @@ var keys = map[Key]string{
 	VisibilityArchivalQueryMaxQPS:         "frontend.visibilityArchivalQueryMaxQPS",
 	EnableServerVersionCheck:              "frontend.enableServerVersionCheck",
 	EnableTokenNamespaceEnforcement:       "frontend.enableTokenNamespaceEnforcement",
+	EnableAuthorizationNamespaceLabels:    "frontend.enableAuthorizationNamespaceLabels",
 
 	// matching settings
 	MatchingRPS:                             "matching.rps",
@@ -439,6 +440,9 @@ const (
 	EnableServerVersionCheck
 	// EnableTokenNamespaceEnforcement enables enforcement that namespace in completion token matches namespace of the request
 	EnableTokenNamespaceEnforcement
+	// EnableAuthorizationNamespaceLabels makes frontend authorization resolve the labels of the target namespace,
+	// for authorizers deciding by them. Read when frontend starts.
+	EnableAuthorizationNamespaceLabels
 
 	// key for matching
 
@@ -25,7 +25,9 @@
 package frontend
 
 import (
+	"net/http"
 	"os"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -37,6 +39,7 @@ import (
 	"go.temporal.io/server/api/adminservice/v1"
 	"go.temporal.io/server/common"
 	"go.temporal.io/server/common/authorization"
+	"go.temporal.io/server/common/clock"
 	"go.temporal.io/server/common/definition"
 	"go.temporal.io/server/common/log"
 	"go.temporal.io/server/common/log/tag"
@@ -53,6 +56,21 @@ import (
 	"go.temporal.io/server/common/service/dynamicconfig"
 )
 
+const (
+	// namespaceLabelsCallbackID identifies the namespace change callback of the namespace labels cache of
+	// authorization. Namespace change callbacks are registered by shard ID, frontend has no shards.
+	namespaceLabelsCallbackID   int32 = -1
+	namespaceLabelsCacheMaxSize       = 10000
+	namespaceLabelsCacheTTL           = time.Minute
+
+	// recentDecisionsPath is the path of the handler serving the recent decisions of an auditing authorizer
+	recentDecisionsPath = "/debug/authorization/recent-decisions"
+)
+
+// registerRecentDecisionsOnce guards the registration of the recent decisions handler, the default serve mux
+// is shared by all services of the process
+var registerRecentDecisionsOnce sync.Once
+
 // Config represents configuration for frontend service
 type Config struct {
 	NumHistoryShards             int32
@@ -112,6 +130,9 @@ type Config struct {
 
 	// EnableTokenNamespaceEnforcement enables enforcement that namespace in completion token matches namespace of the request
 	EnableTokenNamespaceEnforcement dynamicconfig.BoolPropertyFn
+
+	// EnableAuthorizationNamespaceLabels makes authorization resolve the labels of the target namespace
+	EnableAuthorizationNamespaceLabels dynamicconfig.BoolPropertyFn
 }
 
 // NewConfig returns new service config with default values
@@ -152,6 +173,7 @@ func NewConfig(dc *dynamicconfig.Collection, numHistoryShards int32, enableReadF
 		DefaultWorkflowTaskTimeout:             dc.GetDurationPropertyFilteredByNamespace(dynamicconfig.DefaultWorkflowTaskTimeout, common.DefaultWorkflowTaskTimeout),
 		EnableServerVersionCheck:               dc.GetBoolProperty(dynamicconfig.EnableServerVersionCheck, os.Getenv("TEMPORAL_VERSION_CHECK_DISABLED") == ""),
 		EnableTokenNamespaceEnforcement:        dc.GetBoolProperty(dynamicconfig.EnableTokenNamespaceEnforcement, false),
+		EnableAuthorizationNamespaceLabels:     dc.GetBoolProperty(dynamicconfig.EnableAuthorizationNamespaceLabels, false),
 	}
 }
 
@@ -281,6 +303,7 @@ func (s *Service) Start() {
 				s.params.Authorizer,
 				s.Resource.GetMetricsClient(),
 				s.GetLogger(),
+				s.authorizationInterceptorOptions()...,
 			),
 		),
 	)
@@ -297,6 +320,8 @@ func (s *Service) Start() {
 
 	reflection.Register(s.server)
 
+	s.registerRecentDecisionsHandler()
+
 	s.versionChecker = NewVersionChecker(s, s.params, s.config)
 
 	// must start resource first
@@ -311,6 +336,48 @@ func (s *Service) Start() {
 	}
 }
 
+// authorizationInterceptorOptions returns the options of the authorization interceptor. If enabled, the labels
+// of namespaces are resolved through a cache that is kept up to date by the namespace change notifications.
+func (s *Service) authorizationInterceptorOptions() []authorization.InterceptorOption {
+	if !s.config.EnableAuthorizationNamespaceLabels() {
+		return s.params.AuthorizationInterceptorOptions
+	}
+
+	labelsCache := authorization.NewNamespaceLabelsCache(
+		authorization.NewNamespaceCacheLookup(s.GetNamespaceCache()),
+		namespaceLabelsCacheMaxSize,
+		namespaceLabelsCacheTTL,
+		clock.NewRealTimeSource(),
+	)
+	s.GetNamespaceCache().RegisterNamespaceChangeCallback(
+		namespaceLabelsCallbackID,
+		0,
+		func() {},
+		labelsCache.OnNamespaceChange,
+	)
+	// a labels lookup configured with the server options takes precedence
+	return append(
+		[]authorization.InterceptorOption{authorization.WithNamespaceLabelsLookup(labelsCache)},
+		s.params.AuthorizationInterceptorOptions...,
+	)
+}
+
+// registerRecentDecisionsHandler serves the recent decisions of the authorizer at recentDecisionsPath of the
+// default serve mux, if it is an auditing authorizer. The default serve mux is served on localhost by pprof,
+// see config.PProf, and the handler only serves callers holding the system admin role.
+func (s *Service) registerRecentDecisionsHandler() {
+	auditing, ok := s.params.Authorizer.(authorization.AuditingAuthorizer)
+	if !ok {
+		return
+	}
+	registerRecentDecisionsOnce.Do(func() {
+		http.Handle(
+			recentDecisionsPath,
+			authorization.NewRecentDecisionsHandler(s.params.ClaimMapper, auditing, s.GetLogger()),
+		)
+	})
+}
+
 // Stop stops the service
 func (s *Service) Stop() {
 	if !atomic.CompareAndSwapInt32(&s.status, common.DaemonStatusStarted, common.DaemonStatusStopped) {
@@ -341,6 +408,7 @@ func (s *Service) Stop() {
 
 	// TODO: Change this to GracefulStop when integration tests are refactored.
 	s.server.Stop()
+	s.GetNamespaceCache().UnregisterNamespaceChangeCallback(namespaceLabelsCallbackID)
 	s.Resource.Stop()
 	s.params.Logger.Info("frontend stopped")
 }
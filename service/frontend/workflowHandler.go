@@ -46,6 +46,7 @@ import (
 	tokenspb "go.temporal.io/server/api/token/v1"
 	"go.temporal.io/server/common"
 	"go.temporal.io/server/common/archiver"
+	"go.temporal.io/server/common/authorization"
 	"go.temporal.io/server/common/backoff"
 	"go.temporal.io/server/common/cache"
 	"go.temporal.io/server/common/convert"
@@ -287,6 +288,10 @@ func (wh *WorkflowHandler) ListNamespaces(ctx context.Context, request *workflow
 	if err != nil {
 		return resp, err
 	}
+	// return only the namespaces visible to the caller, if the authorizer limited them
+	if constraints := authorization.ConstraintsFromContext(ctx); constraints != nil {
+		resp.Namespaces = constraints.FilterNamespaces(resp.Namespaces)
+	}
 	return resp, err
 }
 
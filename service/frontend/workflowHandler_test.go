@@ -52,6 +52,7 @@ import (
 	"go.temporal.io/server/common"
 	"go.temporal.io/server/common/archiver"
 	"go.temporal.io/server/common/archiver/provider"
+	"go.temporal.io/server/common/authorization"
 	"go.temporal.io/server/common/cache"
 	"go.temporal.io/server/common/cluster"
 	"go.temporal.io/server/common/convert"
@@ -152,6 +153,34 @@ func (s *workflowHandlerSuite) getWorkflowHandler(config *Config) *WorkflowHandl
 	return NewWorkflowHandler(s.mockResource, config, s.mockProducer).(*WorkflowHandler)
 }
 
+func (s *workflowHandlerSuite) TestListNamespaces_FilteredByConstraints() {
+	wh := s.getWorkflowHandler(s.newConfig())
+	mockNamespaceHandler := namespace.NewMockHandler(s.controller)
+	wh.namespaceHandler = mockNamespaceHandler
+
+	var namespaces []*workflowservice.DescribeNamespaceResponse
+	for _, name := range []string{"visible-1", "hidden", "visible-2"} {
+		namespaces = append(namespaces, &workflowservice.DescribeNamespaceResponse{
+			NamespaceInfo: &namespacepb.NamespaceInfo{Name: name},
+		})
+	}
+	request := &workflowservice.ListNamespacesRequest{}
+	mockNamespaceHandler.EXPECT().ListNamespaces(gomock.Any(), request).DoAndReturn(
+		func(context.Context, *workflowservice.ListNamespacesRequest) (*workflowservice.ListNamespacesResponse, error) {
+			return &workflowservice.ListNamespacesResponse{Namespaces: namespaces}, nil
+		}).Times(2)
+
+	resp, err := wh.ListNamespaces(context.Background(), request)
+	s.NoError(err)
+	s.Len(resp.Namespaces, 3)
+
+	ctx := context.WithValue(context.Background(), authorization.ContextKeyConstraints,
+		&authorization.Constraints{Namespaces: []string{"visible-1", "visible-2"}})
+	resp, err = wh.ListNamespaces(ctx, request)
+	s.NoError(err)
+	s.Equal([]*workflowservice.DescribeNamespaceResponse{namespaces[0], namespaces[2]}, resp.Namespaces)
+}
+
 func (s *workflowHandlerSuite) TestDisableListVisibilityByFilter() {
 	testNamespace := "test-namespace"
 	namespaceID := uuid.New()
@@ -351,9 +351,17 @@ func (s *Server) getServiceParams(
 	}
 	if s.so.claimMapper != nil {
 		params.ClaimMapper = s.so.claimMapper
+	} else if svcName == primitives.FrontendService {
+		// only the frontend maps claims, with the claim mapper of the config reporting to the frontend metrics
+		claimMapper, err := authorization.GetClaimMapperFromConfig(s.so.config, metricsClient)
+		if err != nil {
+			return nil, fmt.Errorf("unable to instantiate claim mapper: %w", err)
+		}
+		params.ClaimMapper = claimMapper
 	} else {
 		params.ClaimMapper = authorization.NewNoopClaimMapper(s.so.config)
 	}
+	params.AuthorizationInterceptorOptions = s.so.authorizationInterceptorOptions
 
 	params.PersistenceServiceResolver = s.so.persistenceServiceResolver
 
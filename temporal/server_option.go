@@ -88,6 +88,13 @@ func WithClaimMapper(claimMapper func(cfg *config.Config) authorization.ClaimMap
 	})
 }
 
+// Configures the authorization interceptor of the frontend service
+func WithAuthorizationInterceptorOptions(opts ...authorization.InterceptorOption) ServerOption {
+	return newApplyFuncContainer(func(s *serverOptions) {
+		s.authorizationInterceptorOptions = append(s.authorizationInterceptorOptions, opts...)
+	})
+}
+
 // Set custom tally metric reporter
 func WithCustomMetricsReporter(reporter tally.BaseStatsReporter) ServerOption {
 	return newApplyFuncContainer(func(s *serverOptions) {
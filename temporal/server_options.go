@@ -48,12 +48,13 @@ type (
 		interruptCh   <-chan interface{}
 		blockingStart bool
 
-		authorizer                 authorization.Authorizer
-		tlsConfigProvider          encryption.TLSConfigProvider
-		claimMapper                authorization.ClaimMapper
-		metricsReporter            tally.BaseStatsReporter
-		persistenceServiceResolver resolver.ServiceResolver
-		elasticseachHttpClient     *http.Client
+		authorizer                      authorization.Authorizer
+		tlsConfigProvider               encryption.TLSConfigProvider
+		claimMapper                     authorization.ClaimMapper
+		authorizationInterceptorOptions []authorization.InterceptorOption
+		metricsReporter                 tally.BaseStatsReporter
+		persistenceServiceResolver      resolver.ServiceResolver
+		elasticseachHttpClient          *http.Client
 	}
 )
 